@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"phoenix-alliance-be/internal/config"
+	"phoenix-alliance-be/internal/database"
+	"phoenix-alliance-be/internal/repository"
+	"phoenix-alliance-be/internal/service"
+)
+
+// Deletes a user's account and all their data, as DELETE /me does, for deletion requests made
+// outside the app:
+//
+//	go run ./cmd/delete-user -email user@example.com [-now]
+//
+// Without -now the account is purged after the grace period, like a deletion from the app.
+func main() {
+	email := flag.String("email", "", "email of the user to delete (required)")
+	now := flag.Bool("now", false, "delete immediately instead of after the grace period")
+	flag.Parse()
+
+	if *email == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Connect to database
+	if err := database.Connect(&cfg.Database); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	userRepo := repository.NewUserRepository(database.DB)
+	sessionRepo := repository.NewRefreshTokenRepository(database.DB)
+
+	user, err := userRepo.GetByEmail(*email)
+	if err != nil {
+		log.Fatalf("Failed to find user %s: %v", *email, err)
+	}
+
+	if *now {
+		userService := service.NewUserService(userRepo, 0)
+		if err := userService.DeleteAccount(user.ID); err != nil {
+			log.Fatalf("Failed to delete user %s: %v", *email, err)
+		}
+		fmt.Printf("Deleted user %d (%s) and all their data\n", user.ID, user.Email)
+		return
+	}
+
+	at := time.Now().Add(time.Duration(cfg.Account.DeletionGraceDays) * 24 * time.Hour)
+	if user.DeletionScheduledAt != nil {
+		at = *user.DeletionScheduledAt
+	} else if err := userRepo.ScheduleDeletion(user.ID, at); err != nil {
+		log.Fatalf("Failed to schedule the deletion of user %s: %v", *email, err)
+	}
+	if err := sessionRepo.RevokeAllForUser(user.ID); err != nil {
+		log.Fatalf("Failed to sign out user %s: %v", *email, err)
+	}
+	fmt.Printf("User %d (%s) is signed out and will be deleted on %s\n", user.ID, user.Email, at.Format(time.RFC3339))
+}
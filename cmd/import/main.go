@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	_ "time/tzdata" // User time zones must resolve on hosts without a zoneinfo database
+
+	"phoenix-alliance-be/internal/config"
+	"phoenix-alliance-be/internal/database"
+	"phoenix-alliance-be/internal/models"
+	"phoenix-alliance-be/internal/repository"
+	"phoenix-alliance-be/internal/service"
+)
+
+// Imports a CSV export of Strong, Hevy or FitNotes into a user's training log, as POST /import does:
+//
+//	go run ./cmd/import -email user@example.com -file strong.csv [-format strong] [-dry-run]
+func main() {
+	email := flag.String("email", "", "email of the user to import into (required)")
+	path := flag.String("file", "", "CSV export to import (required)")
+	format := flag.String("format", "", "strong, hevy or fitnotes (default: detected from the header)")
+	dryRun := flag.Bool("dry-run", false, "report what would be created without storing anything")
+	flag.Parse()
+
+	if *email == "" || *path == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Connect to database
+	if err := database.Connect(&cfg.Database); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	userRepo := repository.NewUserRepository(database.DB)
+	importService := service.NewImportService(
+		repository.NewImportRepository(database.DB),
+		repository.NewSetRepository(database.DB),
+		repository.NewPersonalRecordRepository(database.DB),
+		userRepo,
+	)
+
+	user, err := userRepo.GetByEmail(*email)
+	if err != nil {
+		log.Fatalf("Failed to find user %s: %v", *email, err)
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", *path, err)
+	}
+	defer file.Close()
+
+	result, err := importService.Import(user.ID, file, models.ImportOptions{
+		Format: models.ImportFormat(*format),
+		DryRun: *dryRun,
+	})
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	report, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to format the report: %v", err)
+	}
+	fmt.Println(string(report))
+}
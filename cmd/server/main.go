@@ -9,9 +9,12 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // User time zones must resolve on hosts without a zoneinfo database
 
+	"phoenix-alliance-be/internal/auth"
 	"phoenix-alliance-be/internal/config"
 	"phoenix-alliance-be/internal/database"
+	"phoenix-alliance-be/internal/mail"
 	"phoenix-alliance-be/internal/repository"
 	"phoenix-alliance-be/internal/router"
 	"phoenix-alliance-be/internal/service"
@@ -24,6 +27,18 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Load the keys access tokens are signed and verified with
+	keys, err := auth.LoadKeySet(&cfg.JWT)
+	if err != nil {
+		log.Fatalf("Failed to load JWT keys: %v", err)
+	}
+
+	// Create the mailer of account emails
+	mailer, err := mail.New(&cfg.Mail)
+	if err != nil {
+		log.Fatalf("Failed to create mailer: %v", err)
+	}
+
 	// Connect to database
 	if err := database.Connect(&cfg.Database); err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
@@ -35,15 +50,66 @@ func main() {
 	exerciseRepo := repository.NewExerciseRepository(database.DB)
 	workoutRepo := repository.NewWorkoutRepository(database.DB)
 	setRepo := repository.NewSetRepository(database.DB)
+	recordRepo := repository.NewPersonalRecordRepository(database.DB)
+	templateRepo := repository.NewWorkoutTemplateRepository(database.DB)
+	plannedSetRepo := repository.NewPlannedSetRepository(database.DB)
+	programRepo := repository.NewProgramRepository(database.DB)
+	exportRepo := repository.NewExportRepository(database.DB)
+	importRepo := repository.NewImportRepository(database.DB)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(database.DB)
+	accountTokenRepo := repository.NewAccountTokenRepository(database.DB)
+	securityRepo := repository.NewSecurityRepository(database.DB)
 
 	// Initialize services
-	userService := service.NewUserService(userRepo)
+	userService := service.NewUserService(userRepo, time.Duration(cfg.Account.DeletionGraceDays)*24*time.Hour)
+	sessionService := service.NewSessionService(refreshTokenRepo, userRepo, service.TokenSettings{
+		Keys:          keys,
+		AccessExpiry:  time.Duration(cfg.JWT.Expiry) * time.Minute,
+		RefreshExpiry: time.Duration(cfg.JWT.RefreshExpiry) * 24 * time.Hour,
+	})
+	accountService := service.NewAccountService(accountTokenRepo, userRepo, sessionService, mailer, cfg.Mail.AppURL)
+	securityService := service.NewSecurityService(securityRepo, userRepo)
 	exerciseService := service.NewExerciseService(exerciseRepo)
 	workoutService := service.NewWorkoutService(workoutRepo)
-	setService := service.NewSetService(setRepo, exerciseRepo, workoutRepo)
+	setService := service.NewSetService(setRepo, exerciseRepo, workoutRepo, recordRepo, userRepo)
+	recordService := service.NewPersonalRecordService(recordRepo, exerciseRepo)
+	templateService := service.NewWorkoutTemplateService(templateRepo, plannedSetRepo, exerciseRepo, workoutRepo, setRepo)
+	programService := service.NewProgramService(programRepo, plannedSetRepo, exerciseRepo, setRepo, workoutService)
+	statsService := service.NewStatsService(workoutRepo, setRepo, recordRepo, userRepo)
+	analyticsService := service.NewAnalyticsService(workoutRepo, setRepo, userRepo)
+	exportService := service.NewExportService(exportRepo)
+	importService := service.NewImportService(importRepo, setRepo, recordRepo, userRepo)
+	reportService := service.NewReportService(workoutRepo, setRepo, exerciseRepo, userRepo)
 
 	// Setup router
-	r := router.SetupRouter(cfg, userService, exerciseService, workoutService, setService)
+	r := router.SetupRouter(cfg, keys, userService, sessionService, accountService, securityService, exerciseService, workoutService, setService, recordService, templateService, programService, statsService, analyticsService, exportService, importService, reportService)
+
+	// Drop expired refresh tokens, denylist entries, account tokens and login throttles every hour
+	go func() {
+		for range time.Tick(time.Hour) {
+			if err := sessionService.DeleteExpired(); err != nil {
+				log.Printf("Failed to delete expired tokens: %v", err)
+			}
+			if err := accountService.DeleteExpired(); err != nil {
+				log.Printf("Failed to delete expired account tokens: %v", err)
+			}
+			if err := securityService.DeleteExpired(); err != nil {
+				log.Printf("Failed to delete expired login throttles and security events: %v", err)
+			}
+		}
+	}()
+
+	// Purge the accounts whose deletion grace period has ended every hour
+	go func() {
+		for range time.Tick(time.Hour) {
+			deleted, err := userService.PurgeDeleted()
+			if err != nil {
+				log.Printf("Failed to purge deleted accounts: %v", err)
+			} else if deleted > 0 {
+				log.Printf("Purged %d deleted accounts", deleted)
+			}
+		}
+	}()
 
 	// Create HTTP server
 	addr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
@@ -84,6 +150,9 @@ func main() {
 
 		log.Println("Server stopped")
 
+		// Send the password reset emails still queued
+		accountService.Close()
+
 		// Close database connection
 		log.Println("Closing database connection...")
 		database.Close()
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 var (
@@ -19,38 +20,34 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
-// GenerateToken generates a JWT token for a user
-func GenerateToken(userID int64, email, secretKey string, expiryHours int) (string, error) {
-	expirationTime := time.Now().Add(time.Duration(expiryHours) * time.Hour)
+// GenerateToken generates a JWT access token for a user. Each token gets a unique ID (jti)
+// so that it can be revoked before it expires; the claims are returned along with the token.
+func GenerateToken(userID int64, email string, keys *KeySet, expiry time.Duration) (string, *Claims, error) {
+	now := time.Now()
 	claims := &Claims{
 		UserID: userID,
 		Email:  email,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(secretKey))
+	tokenString, err := keys.sign(claims)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
-	return tokenString, nil
+	return tokenString, claims, nil
 }
 
-// ValidateToken validates a JWT token and returns the claims
-func ValidateToken(tokenString, secretKey string) (*Claims, error) {
+// ValidateToken validates a JWT token against the keys of a key set and returns the claims
+func ValidateToken(tokenString string, keys *KeySet) (*Claims, error) {
 	claims := &Claims{}
 
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
-		}
-		return []byte(secretKey), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, claims, keys.verificationKey)
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -59,7 +56,8 @@ func ValidateToken(tokenString, secretKey string) (*Claims, error) {
 		return nil, ErrInvalidToken
 	}
 
-	if !token.Valid {
+	// Tokens without an ID cannot be revoked
+	if !token.Valid || claims.ID == "" {
 		return nil, ErrInvalidToken
 	}
 
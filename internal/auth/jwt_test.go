@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGenerateToken(t *testing.T) {
+	keys, _ := NewKeySet(nil, nil, "secret")
+	token, claims, err := GenerateToken(7, "user@example.com", keys, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateToken failed: %v", err)
+	}
+	if claims.ID == "" {
+		t.Error("expected the token to have an ID")
+	}
+
+	validated, err := ValidateToken(token, keys)
+	if err != nil {
+		t.Fatalf("ValidateToken failed: %v", err)
+	}
+	if validated.UserID != 7 || validated.ID != claims.ID {
+		t.Errorf("unexpected claims %+v", validated)
+	}
+
+	other, _ := NewKeySet(nil, nil, "other-secret")
+	if _, err := ValidateToken(token, other); err != ErrInvalidToken {
+		t.Errorf("expected an invalid token for another secret, got %v", err)
+	}
+
+	expired, _, _ := GenerateToken(7, "user@example.com", keys, -time.Minute)
+	if _, err := ValidateToken(expired, keys); err != ErrExpiredToken {
+		t.Errorf("expected an expired token, got %v", err)
+	}
+}
+
+func TestOpaqueToken(t *testing.T) {
+	first, err := GenerateOpaqueToken()
+	if err != nil {
+		t.Fatalf("GenerateOpaqueToken failed: %v", err)
+	}
+	second, _ := GenerateOpaqueToken()
+	if first == second || len(first) != 43 {
+		t.Errorf("expected distinct 43 character tokens, got %q and %q", first, second)
+	}
+
+	hash := HashOpaqueToken(first)
+	if len(hash) != 64 || hash != HashOpaqueToken(first) || hash == HashOpaqueToken(second) {
+		t.Errorf("unexpected hash %q", hash)
+	}
+}
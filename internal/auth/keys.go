@@ -0,0 +1,242 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"phoenix-alliance-be/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSABits is the smallest RSA key accepted for signing tokens
+const minRSABits = 2048
+
+// Key is an asymmetric key tokens are signed or verified with
+type Key struct {
+	ID      string // kid header: the RFC 7638 thumbprint of the public key
+	Method  jwt.SigningMethod
+	Public  crypto.PublicKey
+	Private crypto.Signer // Nil for keys that only verify
+}
+
+// KeySet holds the key new tokens are signed with and every key tokens are accepted from.
+// Keys are identified by the kid header of tokens, so that a new key can take over signing
+// while tokens signed by the previous one are still accepted.
+type KeySet struct {
+	signing *Key            // Nil when tokens are signed with the HMAC secret
+	keys    map[string]*Key // Verification keys by ID, including the signing key
+	secret  []byte          // HS256 secret, for tokens without a kid; empty when not accepted
+}
+
+// NewKeySet creates a key set that signs with signing, or with HS256 and secret when signing is
+// nil, and also accepts tokens of the verification keys
+func NewKeySet(signing *Key, verification []*Key, secret string) (*KeySet, error) {
+	if signing == nil && secret == "" {
+		return nil, errors.New("a signing key or an HMAC secret is required")
+	}
+	if signing != nil && signing.Private == nil {
+		return nil, errors.New("the signing key must be a private key")
+	}
+
+	set := &KeySet{signing: signing, keys: make(map[string]*Key), secret: []byte(secret)}
+	for _, key := range append(verification, signing) {
+		if key != nil {
+			set.keys[key.ID] = key
+		}
+	}
+	return set, nil
+}
+
+// LoadKeySet builds the key set of the JWT configuration, reading its PEM files
+func LoadKeySet(cfg *config.JWTConfig) (*KeySet, error) {
+	var signing *Key
+	signingPEM := []byte(cfg.SigningKey)
+	if cfg.SigningKeyFile != "" {
+		data, err := os.ReadFile(cfg.SigningKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading signing key: %w", err)
+		}
+		signingPEM = data
+	}
+	if len(signingPEM) > 0 {
+		key, err := ParseKeyPEM(signingPEM)
+		if err != nil {
+			return nil, fmt.Errorf("parsing signing key: %w", err)
+		}
+		signing = key
+	}
+
+	var verification []*Key
+	for _, path := range cfg.VerificationKeyFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading verification key: %w", err)
+		}
+		key, err := ParseKeyPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing verification key %s: %w", path, err)
+		}
+		verification = append(verification, key)
+	}
+
+	return NewKeySet(signing, verification, cfg.SecretKey)
+}
+
+// ParseKeyPEM parses an RSA or Ed25519 key from PEM: a private key (PKCS #1 or PKCS #8), which
+// can sign, or a public key (PKIX), which only verifies. RSA keys sign with RS256 and Ed25519
+// keys with EdDSA.
+func ParseKeyPEM(data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.Public, key.Private = jwt.SigningMethodRS256, &k.PublicKey, k
+	case *rsa.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.Public, key.Private = jwt.SigningMethodEdDSA, k.Public(), k
+	case ed25519.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, errors.New("unsupported key type, use RSA or Ed25519")
+	}
+
+	if rsaKey, ok := key.Public.(*rsa.PublicKey); ok && rsaKey.N.BitLen() < minRSABits {
+		return nil, fmt.Errorf("RSA keys must have at least %d bits", minRSABits)
+	}
+	key.ID = Thumbprint(key.Public)
+	return key, nil
+}
+
+// sign signs the claims with the signing key, or the HMAC secret
+func (s *KeySet) sign(claims *Claims) (string, error) {
+	if s.signing == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	}
+
+	token := jwt.NewWithClaims(s.signing.Method, claims)
+	token.Header["kid"] = s.signing.ID
+	return token.SignedString(s.signing.Private)
+}
+
+// verificationKey returns the key a token must be verified with. Tokens name their key with
+// kid; tokens without one are HS256 tokens of the secret. The algorithm must be the key's,
+// so that a public key can never be used as an HMAC secret.
+func (s *KeySet) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok || len(s.secret) == 0 {
+			return nil, ErrInvalidToken
+		}
+		return s.secret, nil
+	}
+
+	key, ok := s.keys[kid]
+	if !ok || token.Method.Alg() != key.Method.Alg() {
+		return nil, ErrInvalidToken
+	}
+	return key.Public, nil
+}
+
+// JWK represents a public key in JSON Web Key format (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`   // RSA modulus
+	E         string `json:"e,omitempty"`   // RSA exponent
+	Curve     string `json:"crv,omitempty"` // Ed25519
+	X         string `json:"x,omitempty"`   // Ed25519 public key
+}
+
+// JWKS represents a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public verification keys, for services that verify tokens themselves.
+// The HMAC secret is never published, so HS256 tokens can only be verified here.
+func (s *KeySet) JWKS() *JWKS {
+	set := &JWKS{Keys: []JWK{}}
+	for _, key := range s.keys {
+		jwk := publicJWK(key.Public)
+		jwk.KeyID = key.ID
+		jwk.Use = "sig"
+		jwk.Algorithm = key.Method.Alg()
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+	return set
+}
+
+// publicJWK returns the key type and public members of the JWK of a key
+func publicJWK(public crypto.PublicKey) JWK {
+	switch k := public.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			KeyType: "RSA",
+			N:       base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+			E:       base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		}
+	case ed25519.PublicKey:
+		return JWK{KeyType: "OKP", Curve: "Ed25519", X: base64.RawURLEncoding.EncodeToString(k)}
+	}
+	return JWK{}
+}
+
+// Thumbprint returns the RFC 7638 thumbprint of a public key: the SHA-256 of its required
+// JWK members in lexicographic order, base64url encoded
+func Thumbprint(public crypto.PublicKey) string {
+	jwk := publicJWK(public)
+	var members interface{}
+	if jwk.KeyType == "RSA" {
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.KeyType, jwk.N}
+	} else {
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Curve, jwk.KeyType, jwk.X}
+	}
+
+	data, _ := json.Marshal(members)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// generateKeyPEM generates a private key and returns it PEM encoded with its public key
+func generateKeyPEM(t *testing.T, private interface{}) (privatePEM, publicPEM []byte) {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("marshaling private key: %v", err)
+	}
+	var public interface{}
+	switch k := private.(type) {
+	case *rsa.PrivateKey:
+		public = &k.PublicKey
+	case ed25519.PrivateKey:
+		public = k.Public()
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatalf("marshaling public key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+}
+
+func TestKeySetRotation(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	oldPEM, oldPublicPEM := generateKeyPEM(t, rsaKey)
+	newPEM, _ := generateKeyPEM(t, edKey)
+
+	oldKey, err := ParseKeyPEM(oldPEM)
+	if err != nil {
+		t.Fatalf("ParseKeyPEM failed: %v", err)
+	}
+	oldKeys, _ := NewKeySet(oldKey, nil, "")
+	oldToken, _, err := GenerateToken(7, "user@example.com", oldKeys, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateToken failed: %v", err)
+	}
+	parsed, _, _ := jwt.NewParser().ParseUnverified(oldToken, &Claims{})
+	if parsed.Method.Alg() != "RS256" || parsed.Header["kid"] != oldKey.ID {
+		t.Errorf("unexpected header %v", parsed.Header)
+	}
+
+	// The new key signs while tokens of the old one, now verification only, are still accepted
+	newKey, _ := ParseKeyPEM(newPEM)
+	verifyOnly, _ := ParseKeyPEM(oldPublicPEM)
+	if verifyOnly.ID != oldKey.ID || verifyOnly.Private != nil {
+		t.Fatalf("expected the public key to share the ID of its private key, got %+v", verifyOnly)
+	}
+	keys, err := NewKeySet(newKey, []*Key{verifyOnly}, "")
+	if err != nil {
+		t.Fatalf("NewKeySet failed: %v", err)
+	}
+
+	newToken, _, _ := GenerateToken(7, "user@example.com", keys, 15*time.Minute)
+	for _, token := range []string{oldToken, newToken} {
+		if claims, err := ValidateToken(token, keys); err != nil || claims.UserID != 7 {
+			t.Errorf("expected the token to be accepted, got %v", err)
+		}
+	}
+	if _, err := ValidateToken(newToken, oldKeys); err != ErrInvalidToken {
+		t.Errorf("expected a token of an unknown key to be rejected, got %v", err)
+	}
+
+	if _, err := NewKeySet(verifyOnly, nil, ""); err == nil {
+		t.Error("expected a public key to be refused for signing")
+	}
+
+	jwks := keys.JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("expected 2 published keys, got %+v", jwks.Keys)
+	}
+	for _, jwk := range jwks.Keys {
+		switch jwk.KeyID {
+		case oldKey.ID:
+			if jwk.KeyType != "RSA" || jwk.Algorithm != "RS256" || jwk.E != "AQAB" || jwk.N == "" {
+				t.Errorf("unexpected RSA key %+v", jwk)
+			}
+		case newKey.ID:
+			if jwk.KeyType != "OKP" || jwk.Algorithm != "EdDSA" || jwk.Curve != "Ed25519" || jwk.X == "" {
+				t.Errorf("unexpected Ed25519 key %+v", jwk)
+			}
+		default:
+			t.Errorf("unexpected key %+v", jwk)
+		}
+	}
+}
+
+func TestKeySetLegacySecret(t *testing.T) {
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	keyPEM, _ := generateKeyPEM(t, edKey)
+	key, _ := ParseKeyPEM(keyPEM)
+
+	legacyKeys, _ := NewKeySet(nil, nil, "secret")
+	legacyToken, _, _ := GenerateToken(7, "user@example.com", legacyKeys, 15*time.Minute)
+
+	// HS256 tokens without a kid are accepted only while the secret is configured
+	keys, _ := NewKeySet(key, nil, "secret")
+	if _, err := ValidateToken(legacyToken, keys); err != nil {
+		t.Errorf("expected the HS256 token to be accepted, got %v", err)
+	}
+	withoutSecret, _ := NewKeySet(key, nil, "")
+	if _, err := ValidateToken(legacyToken, withoutSecret); err != ErrInvalidToken {
+		t.Errorf("expected the HS256 token to be rejected without the secret, got %v", err)
+	}
+	if len(keys.JWKS().Keys) != 1 {
+		t.Error("expected the HMAC secret not to be published")
+	}
+
+	// An HS256 token naming a public key, signed with the key as its secret, is rejected
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{
+		ID:        "forged",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}})
+	forged.Header["kid"] = key.ID
+	signed, _ := forged.SignedString([]byte(key.Public.(ed25519.PublicKey)))
+	if _, err := ValidateToken(signed, keys); err != ErrInvalidToken {
+		t.Errorf("expected an algorithm mismatch to be rejected, got %v", err)
+	}
+}
+
+func TestParseKeyPEMRejectsSmallRSAKeys(t *testing.T) {
+	small, _ := rsa.GenerateKey(rand.Reader, 1024)
+	keyPEM, _ := generateKeyPEM(t, small)
+	if _, err := ParseKeyPEM(keyPEM); err == nil {
+		t.Error("expected a 1024 bit RSA key to be rejected")
+	}
+}
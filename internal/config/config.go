@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"strconv"
 	"strings"
@@ -15,12 +16,15 @@ type Config struct {
 	Database DatabaseConfig
 	JWT      JWTConfig
 	CORS     CORSConfig
+	Mail     MailConfig
+	Account  AccountConfig
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Port string
-	Host string
+	Port              string
+	Host              string
+	TrustProxyHeaders bool // Read the client IP from X-Forwarded-For, set by a reverse proxy
 }
 
 // DatabaseConfig holds database configuration
@@ -35,8 +39,29 @@ type DatabaseConfig struct {
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
-	SecretKey string
-	Expiry    int // in hours
+	SecretKey            string   // HS256 secret; signs tokens when no signing key is set
+	SigningKey           string   // PEM of the RSA or Ed25519 private key that signs tokens
+	SigningKeyFile       string   // Path of the signing key PEM, used instead of SigningKey
+	VerificationKeyFiles []string // PEM files of previous keys whose tokens are still accepted
+	Expiry               int      // Access token lifetime, in minutes
+	RefreshExpiry        int      // Refresh token lifetime, in days
+}
+
+// MailConfig holds email delivery configuration
+type MailConfig struct {
+	Driver       string // smtp, or log to write messages to LogFile for local development
+	From         string
+	AppURL       string // Base URL of the app, for the links sent by email
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string // Empty when the server needs no authentication
+	SMTPPassword string
+	LogFile      string // Empty for the server log
+}
+
+// AccountConfig holds account lifecycle configuration
+type AccountConfig struct {
+	DeletionGraceDays int // Days a deleted account can be restored before its data is purged
 }
 
 // CORSConfig holds CORS configuration
@@ -56,8 +81,9 @@ func Load() (*Config, error) {
 
 	config := &Config{
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "8080"),
-			Host: getEnv("SERVER_HOST", "localhost"),
+			Port:              getEnv("SERVER_PORT", "8080"),
+			Host:              getEnv("SERVER_HOST", "localhost"),
+			TrustProxyHeaders: getEnvAsBool("TRUST_PROXY_HEADERS", false),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -68,20 +94,55 @@ func Load() (*Config, error) {
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
 		JWT: JWTConfig{
-			SecretKey: getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
-			Expiry:    getEnvAsInt("JWT_EXPIRY_HOURS", 24),
+			SecretKey:            getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
+			SigningKey:           getEnv("JWT_SIGNING_KEY", ""),
+			SigningKeyFile:       getEnv("JWT_SIGNING_KEY_FILE", ""),
+			VerificationKeyFiles: splitCSV(getEnv("JWT_VERIFICATION_KEY_FILES", "")),
+			Expiry:               loadJWTExpiry(),
+			RefreshExpiry:        getEnvAsInt("JWT_REFRESH_EXPIRY_DAYS", 30),
 		},
 		CORS: loadCORSConfig(),
+		Mail: MailConfig{
+			Driver:       getEnv("MAIL_DRIVER", "log"),
+			From:         getEnv("MAIL_FROM", "Phoenix Alliance <no-reply@localhost>"),
+			AppURL:       strings.TrimRight(getEnv("APP_URL", "http://localhost:3000"), "/"),
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			LogFile:      getEnv("MAIL_LOG_FILE", ""),
+		},
+		Account: AccountConfig{
+			DeletionGraceDays: getEnvAsInt("ACCOUNT_DELETION_GRACE_DAYS", 30),
+		},
 	}
 
-	// Validate required fields
+	// Validate required fields: tokens are signed with a key or, without one, with the secret
 	if config.JWT.SecretKey == "your-secret-key-change-in-production" {
-		return nil, fmt.Errorf("JWT_SECRET must be set in environment variables")
+		if config.JWT.SigningKey == "" && config.JWT.SigningKeyFile == "" {
+			return nil, fmt.Errorf("JWT_SECRET, JWT_SIGNING_KEY or JWT_SIGNING_KEY_FILE must be set in environment variables")
+		}
+		// The placeholder must never verify tokens
+		config.JWT.SecretKey = ""
+	}
+
+	if config.Mail.Driver == "smtp" && config.Mail.SMTPHost == "" {
+		return nil, fmt.Errorf("SMTP_HOST must be set when MAIL_DRIVER is smtp")
 	}
 
 	return config, nil
 }
 
+// loadJWTExpiry returns the access token lifetime in minutes. JWT_EXPIRY_HOURS, from before
+// lifetimes were set in minutes, is ignored so that deployments still setting it get short-lived
+// access tokens rather than day-long ones.
+func loadJWTExpiry() int {
+	if os.Getenv("JWT_EXPIRY_HOURS") != "" {
+		log.Printf("JWT_EXPIRY_HOURS is no longer read, access tokens expire after JWT_EXPIRY_MINUTES (default 15)")
+	}
+	return getEnvAsInt("JWT_EXPIRY_MINUTES", 15)
+}
+
 func loadCORSConfig() CORSConfig {
 	originsRaw := getEnv("CORS_ALLOWED_ORIGINS", "*")
 	origins := splitCSV(originsRaw)
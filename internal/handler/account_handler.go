@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"phoenix-alliance-be/internal/middleware"
+	"phoenix-alliance-be/internal/models"
+	"phoenix-alliance-be/internal/service"
+)
+
+// AccountHandler handles password reset and email verification requests
+type AccountHandler struct {
+	accountService service.AccountService
+}
+
+// NewAccountHandler creates a new account handler
+func NewAccountHandler(accountService service.AccountService) *AccountHandler {
+	return &AccountHandler{accountService: accountService}
+}
+
+// ForgotPassword handles POST /password/forgot, emailing a reset link when the address has an account
+func (h *AccountHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.PasswordForgotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Email == "" {
+		respondWithError(w, http.StatusBadRequest, "Email is required")
+		return
+	}
+
+	if err := h.accountService.ForgotPassword(req.Email); err != nil {
+		if err.Error() == "too many password reset requests" {
+			respondWithError(w, http.StatusTooManyRequests, "Too many password reset requests, try again later")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Accepted whether or not the address has an account
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ResetPassword handles POST /password/reset, setting a new password with a reset token
+func (h *AccountHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.PasswordResetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Token == "" || req.Password == "" {
+		respondWithError(w, http.StatusBadRequest, "Token and password are required")
+		return
+	}
+
+	if len(req.Password) < 8 {
+		respondWithError(w, http.StatusBadRequest, "Password must be at least 8 characters")
+		return
+	}
+
+	if err := h.accountService.ResetPassword(&req); err != nil {
+		if err.Error() == "invalid or expired token" {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// VerifyEmail handles POST /email/verify, verifying the address a token was sent to
+func (h *AccountHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req models.EmailVerifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Token == "" {
+		respondWithError(w, http.StatusBadRequest, "Token is required")
+		return
+	}
+
+	user, err := h.accountService.VerifyEmail(req.Token)
+	if err != nil {
+		if err.Error() == "invalid or expired token" {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, user)
+}
+
+// ResendVerification handles POST /email/verify/resend, emailing the user a new verification link
+func (h *AccountHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	if err := h.accountService.SendVerification(userID); err != nil {
+		switch err.Error() {
+		case "email already verified":
+			respondWithError(w, http.StatusConflict, err.Error())
+		case "user not found":
+			respondWithError(w, http.StatusNotFound, err.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+
+	"phoenix-alliance-be/internal/middleware"
+	"phoenix-alliance-be/internal/service"
+)
+
+// AnalyticsHandler handles training volume and load analytics requests
+type AnalyticsHandler struct {
+	analyticsService service.AnalyticsService
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(analyticsService service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{analyticsService: analyticsService}
+}
+
+// GetVolume handles GET /analytics/volume?weeks=
+func (h *AnalyticsHandler) GetVolume(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	query, msg := parseAnalyticsQuery(r)
+	if msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	volume, err := h.analyticsService.GetMuscleVolume(userID, query)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, volume)
+}
+
+// GetLoad handles GET /analytics/load?weeks=&metric=srpe|tonnage
+func (h *AnalyticsHandler) GetLoad(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	query, msg := parseAnalyticsQuery(r)
+	if msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	load, err := h.analyticsService.GetLoad(userID, query)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, load)
+}
@@ -2,32 +2,39 @@ package handler
 
 import (
 	"encoding/json"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 
+	"phoenix-alliance-be/internal/middleware"
 	"phoenix-alliance-be/internal/models"
 	"phoenix-alliance-be/internal/service"
 )
 
+// maxEmailLength is the longest email address accepted, the size of the users.email column
+const maxEmailLength = 255
+
 // AuthHandler handles authentication-related requests
 type AuthHandler struct {
-	userService service.UserService
-	config      interface {
-		GetJWTSecret() string
-		GetJWTExpiry() int
-	}
-}
-
-// AuthConfig interface for JWT configuration
-type AuthConfig interface {
-	GetJWTSecret() string
-	GetJWTExpiry() int
+	userService     service.UserService
+	sessionService  service.SessionService
+	accountService  service.AccountService
+	securityService service.SecurityService
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(userService service.UserService, cfg AuthConfig) *AuthHandler {
+func NewAuthHandler(
+	userService service.UserService,
+	sessionService service.SessionService,
+	accountService service.AccountService,
+	securityService service.SecurityService,
+) *AuthHandler {
 	return &AuthHandler{
-		userService: userService,
-		config:      cfg,
+		userService:     userService,
+		sessionService:  sessionService,
+		accountService:  accountService,
+		securityService: securityService,
 	}
 }
 
@@ -50,6 +57,13 @@ func (h *AuthHandler) Signup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Timezone != "" {
+		if msg := models.ValidateTimezone(req.Timezone); msg != "" {
+			respondWithError(w, http.StatusBadRequest, msg)
+			return
+		}
+	}
+
 	user, err := h.userService.CreateUser(&req)
 	if err != nil {
 		if err.Error() == "user with this email already exists" {
@@ -60,6 +74,11 @@ func (h *AuthHandler) Signup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The account works before it is verified; a failed email can be sent again
+	if err := h.accountService.SendVerification(user.ID); err != nil {
+		log.Printf("Failed to send the verification email of user %d: %v", user.ID, err)
+	}
+
 	respondWithJSON(w, http.StatusCreated, user)
 }
 
@@ -77,14 +96,97 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, user, err := h.userService.LoginUser(&req, h.config.GetJWTSecret(), h.config.GetJWTExpiry())
+	if len(req.Email) > maxEmailLength {
+		respondWithError(w, http.StatusBadRequest, "Invalid email")
+		return
+	}
+
+	// Repeated failures lock the account and the client's IP out for longer and longer
+	client := &models.ClientInfo{IPAddress: middleware.ClientIP(r), UserAgent: r.UserAgent()}
+	attempt, wait, err := h.securityService.BeginLogin(req.Email, client)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if wait > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		respondWithError(w, http.StatusTooManyRequests, "Too many failed login attempts, try again later")
+		return
+	}
+
+	user, err := h.userService.LoginUser(&req)
 	if err != nil {
+		h.securityService.RecordLoginFailure(attempt)
 		respondWithError(w, http.StatusUnauthorized, err.Error())
 		return
 	}
+	if err := h.securityService.RecordLoginSuccess(attempt, user); err != nil {
+		log.Printf("Failed to record the login of user %d: %v", user.ID, err)
+	}
+
+	tokens, err := h.sessionService.Create(user.ID, user.Email)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, &models.LoginResponse{TokenPair: tokens, User: user})
+}
+
+// Refresh handles POST /token/refresh, exchanging a refresh token for new tokens
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.RefreshToken == "" {
+		respondWithError(w, http.StatusBadRequest, "Refresh token is required")
+		return
+	}
+
+	tokens, err := h.sessionService.Refresh(req.RefreshToken)
+	if err != nil {
+		if err.Error() == "invalid refresh token" {
+			respondWithError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, tokens)
+}
+
+// Logout handles POST /logout, ending the session of the request's access token
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetTokenClaims(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	if err := h.sessionService.Logout(claims); err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LogoutAll handles POST /logout-all, ending every session of the user
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	if err := h.sessionService.LogoutAll(userID); err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
 
-	respondWithJSON(w, http.StatusOK, map[string]interface{}{
-		"token": token,
-		"user":  user,
-	})
+	w.WriteHeader(http.StatusNoContent)
 }
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -34,10 +35,6 @@ func (h *ExerciseHandler) CreateExercise(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Agregar mas campos al ejercicio:
-	// Musculo principal
-	// Musculo secundario
-	// Tipo de ejercicio (peso, cardio, flexibilidad, etc.)
 	var req models.ExerciseCreateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondWithError(w, http.StatusBadRequest, "Invalid request body")
@@ -48,6 +45,10 @@ func (h *ExerciseHandler) CreateExercise(w http.ResponseWriter, r *http.Request)
 		respondWithError(w, http.StatusBadRequest, "Exercise name is required")
 		return
 	}
+	if msg := validateExerciseMetadata(req.Type, &req.ExerciseMetadata); msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
 
 	exercise, err := h.exerciseService.CreateExercise(userID, &req)
 	if err != nil {
@@ -58,7 +59,7 @@ func (h *ExerciseHandler) CreateExercise(w http.ResponseWriter, r *http.Request)
 	respondWithJSON(w, http.StatusCreated, exercise)
 }
 
-// GetExercises handles GET /exercises
+// GetExercises handles GET /exercises?limit=&cursor=&sort=&from=&to=&q=&type=&muscle=&equipment=&movement_pattern=
 func (h *ExerciseHandler) GetExercises(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
@@ -66,7 +67,13 @@ func (h *ExerciseHandler) GetExercises(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	exercises, err := h.exerciseService.GetExercises(userID)
+	filter, msg := parseExerciseFilter(r, "-created_at")
+	if msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	exercises, err := h.exerciseService.GetExercises(userID, filter)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -75,7 +82,7 @@ func (h *ExerciseHandler) GetExercises(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, exercises)
 }
 
-// GetExerciseHistory handles GET /exercises/{id}/history
+// GetExerciseHistory handles GET /exercises/{id}/history?formula=epley|brzycki|lombardi|rpe&include=sets,metrics plus the set list filters
 func (h *ExerciseHandler) GetExerciseHistory(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
@@ -90,7 +97,25 @@ func (h *ExerciseHandler) GetExerciseHistory(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	history, err := h.setService.GetExerciseHistory(userID, exerciseID)
+	formula, ok := parseE1RMFormula(r)
+	if !ok {
+		respondWithError(w, http.StatusBadRequest, "Invalid formula. Must be 'epley', 'brzycki', 'lombardi', or 'rpe'")
+		return
+	}
+
+	filter, msg := parseSetFilter(r, "-performed_at")
+	if msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	include, msg := parseHistoryInclude(r)
+	if msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	history, err := h.setService.GetExerciseHistory(userID, exerciseID, formula, filter, include)
 	if err != nil {
 		if err.Error() == "exercise not found" {
 			respondWithError(w, http.StatusNotFound, err.Error())
@@ -103,7 +128,7 @@ func (h *ExerciseHandler) GetExerciseHistory(w http.ResponseWriter, r *http.Requ
 	respondWithJSON(w, http.StatusOK, history)
 }
 
-// GetExerciseProgress handles GET /exercises/{id}/progress?range=week|month|year
+// GetExerciseProgress handles GET /exercises/{id}/progress?range=week|month|year&from=&to=&bucket=day|week|month&window=&formula=epley|brzycki|lombardi|rpe&include_warmups=&fill_empty=
 func (h *ExerciseHandler) GetExerciseProgress(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
@@ -118,19 +143,13 @@ func (h *ExerciseHandler) GetExerciseProgress(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// @TODO: Agregar rangos de tiempo en un enum
-	rangeParam := r.URL.Query().Get("range")
-	if rangeParam == "" {
-		rangeParam = "month" // Default to month
-	}
-
-	rangeType := models.ProgressRange(rangeParam)
-	if rangeType != models.ProgressRangeWeek && rangeType != models.ProgressRangeMonth && rangeType != models.ProgressRangeYear {
-		respondWithError(w, http.StatusBadRequest, "Invalid range. Must be 'week', 'month', or 'year'")
+	query, msg := parseProgressQuery(r)
+	if msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
 		return
 	}
 
-	progress, err := h.setService.GetExerciseProgress(userID, exerciseID, rangeType)
+	progress, err := h.setService.GetExerciseProgress(userID, exerciseID, query)
 	if err != nil {
 		if err.Error() == "exercise not found" {
 			respondWithError(w, http.StatusNotFound, err.Error())
@@ -168,6 +187,10 @@ func (h *ExerciseHandler) UpdateExercise(w http.ResponseWriter, r *http.Request)
 		respondWithError(w, http.StatusBadRequest, "Exercise name is required")
 		return
 	}
+	if msg := validateExerciseMetadata(req.Type, &req.ExerciseMetadata); msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
 
 	exercise, err := h.exerciseService.UpdateExercise(userID, exerciseID, &req)
 	if err != nil {
@@ -175,6 +198,10 @@ func (h *ExerciseHandler) UpdateExercise(w http.ResponseWriter, r *http.Request)
 			respondWithError(w, http.StatusNotFound, err.Error())
 			return
 		}
+		if err.Error() == "catalog exercises cannot be modified" {
+			respondWithError(w, http.StatusBadRequest, "Only the name of a catalog exercise can be changed")
+			return
+		}
 		respondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -209,3 +236,73 @@ func (h *ExerciseHandler) DeleteExercise(w http.ResponseWriter, r *http.Request)
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// GetCatalog handles GET /catalog with the same query parameters as GET /exercises
+func (h *ExerciseHandler) GetCatalog(w http.ResponseWriter, r *http.Request) {
+	filter, msg := parseExerciseFilter(r, "name")
+	if msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	catalog, err := h.exerciseService.GetCatalog(filter)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, catalog)
+}
+
+// AdoptExercise handles POST /catalog/{id}/adopt; the body with an alias is optional
+func (h *ExerciseHandler) AdoptExercise(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	vars := mux.Vars(r)
+	exerciseID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid exercise ID")
+		return
+	}
+
+	var req models.CatalogAdoptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	exercise, err := h.exerciseService.AdoptExercise(userID, exerciseID, &req)
+	if err != nil {
+		if err.Error() == "exercise not found" {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, exercise)
+}
+
+// parseE1RMFormula reads the e1RM formula from the query string (default: epley)
+func parseE1RMFormula(r *http.Request) (models.E1RMFormula, bool) {
+	formulaParam := r.URL.Query().Get("formula")
+	if formulaParam == "" {
+		return models.E1RMFormulaEpley, true
+	}
+
+	formula := models.E1RMFormula(formulaParam)
+	return formula, formula.IsValid()
+}
+
+// validateExerciseMetadata validates the type and catalog fields of an exercise request and returns an error message
+func validateExerciseMetadata(exerciseType models.ExerciseType, metadata *models.ExerciseMetadata) string {
+	if exerciseType != "" && !exerciseType.IsValid() {
+		return "Invalid type. Must be 'weight', 'bodyweight', 'cardio', 'flexibility', or 'timed'"
+	}
+	return metadata.Validate()
+}
@@ -20,6 +20,7 @@ type mockExerciseService struct {
 	createFunc func(userID int64, req *models.ExerciseCreateRequest) (*models.ExerciseResponse, error)
 	updateFunc func(userID, exerciseID int64, req *models.ExerciseUpdateRequest) (*models.ExerciseResponse, error)
 	deleteFunc func(userID, exerciseID int64) error
+	adoptFunc  func(userID, exerciseID int64, req *models.CatalogAdoptRequest) (*models.ExerciseResponse, error)
 }
 
 func (m *mockExerciseService) CreateExercise(userID int64, req *models.ExerciseCreateRequest) (*models.ExerciseResponse, error) {
@@ -29,7 +30,7 @@ func (m *mockExerciseService) CreateExercise(userID int64, req *models.ExerciseC
 	return nil, nil
 }
 
-func (m *mockExerciseService) GetExercises(userID int64) ([]*models.ExerciseResponse, error) {
+func (m *mockExerciseService) GetExercises(userID int64, filter *models.ExerciseFilter) (*models.ListResponse[*models.ExerciseResponse], error) {
 	return nil, nil
 }
 
@@ -51,6 +52,17 @@ func (m *mockExerciseService) DeleteExercise(userID, exerciseID int64) error {
 	return nil
 }
 
+func (m *mockExerciseService) GetCatalog(filter *models.ExerciseFilter) (*models.ListResponse[*models.ExerciseResponse], error) {
+	return nil, nil
+}
+
+func (m *mockExerciseService) AdoptExercise(userID, exerciseID int64, req *models.CatalogAdoptRequest) (*models.ExerciseResponse, error) {
+	if m.adoptFunc != nil {
+		return m.adoptFunc(userID, exerciseID, req)
+	}
+	return nil, nil
+}
+
 // mockSetService is a mock implementation of SetService
 type mockSetService struct{}
 
@@ -58,15 +70,31 @@ func (m *mockSetService) CreateSet(userID, workoutID int64, req *models.SetCreat
 	return nil, nil
 }
 
-func (m *mockSetService) GetWorkoutSets(workoutID int64) ([]*models.SetResponse, error) {
+func (m *mockSetService) CreateSets(userID, workoutID int64, reqs []*models.SetCreateRequest) ([]*models.SetResponse, error) {
 	return nil, nil
 }
 
-func (m *mockSetService) GetExerciseHistory(userID, exerciseID int64) (*models.ExerciseHistoryResponse, error) {
+func (m *mockSetService) UpdateSet(userID, workoutID, setID int64, req *models.SetUpdateRequest) (*models.SetResponse, error) {
+	return nil, nil
+}
+
+func (m *mockSetService) DeleteSet(userID, workoutID, setID int64) error {
+	return nil
+}
+
+func (m *mockSetService) ReorderSets(userID, workoutID int64, req *models.SetOrderRequest) ([]*models.SetResponse, error) {
 	return nil, nil
 }
 
-func (m *mockSetService) GetExerciseProgress(userID, exerciseID int64, rangeType models.ProgressRange) (*models.ExerciseProgressResponse, error) {
+func (m *mockSetService) GetWorkoutSets(workoutID int64, filter *models.SetFilter) (*models.ListResponse[*models.SetResponse], error) {
+	return nil, nil
+}
+
+func (m *mockSetService) GetExerciseHistory(userID, exerciseID int64, formula models.E1RMFormula, filter *models.SetFilter, include models.HistoryInclude) (*models.ExerciseHistoryResponse, error) {
+	return nil, nil
+}
+
+func (m *mockSetService) GetExerciseProgress(userID, exerciseID int64, query *models.ProgressQuery) (*models.ExerciseProgressResponse, error) {
 	return nil, nil
 }
 
@@ -309,6 +337,24 @@ func TestDeleteExercise(t *testing.T) {
 	})
 }
 
+// TestGetExerciseProgressPeriodTooLong tests that the GetExerciseProgress handler refuses
+// periods spanning more buckets than can be generated
+func TestGetExerciseProgressPeriodTooLong(t *testing.T) {
+	handler := NewExerciseHandler(&mockExerciseService{}, &mockSetService{})
+
+	request := httptest.NewRequest("GET", "/exercises/1/progress?bucket=day&from=0001-01-01", nil)
+	ctx := context.WithValue(request.Context(), middleware.UserIDKey, int64(1))
+	request = request.WithContext(ctx)
+	request = mux.SetURLVars(request, map[string]string{"id": "1"})
+	recorder := httptest.NewRecorder()
+
+	handler.GetExerciseProgress(recorder, request)
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, recorder.Code)
+	}
+}
+
 // serviceError is a simple error type for testing
 type serviceError struct {
 	message string
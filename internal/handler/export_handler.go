@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"phoenix-alliance-be/internal/middleware"
+	"phoenix-alliance-be/internal/models"
+	"phoenix-alliance-be/internal/service"
+)
+
+// ExportHandler handles training log export requests
+type ExportHandler struct {
+	exportService service.ExportService
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(exportService service.ExportService) *ExportHandler {
+	return &ExportHandler{exportService: exportService}
+}
+
+// exportContentTypes are the response content types of export formats
+var exportContentTypes = map[models.ExportFormat]string{
+	models.ExportFormatCSV:  "text/csv; charset=utf-8",
+	models.ExportFormatJSON: "application/json",
+}
+
+// Export handles GET /export?format=csv|json
+func (h *ExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	format := models.ExportFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = models.ExportFormatJSON
+	}
+	if !format.IsValid() {
+		respondWithError(w, http.StatusBadRequest, "Invalid format. Must be 'csv' or 'json'")
+		return
+	}
+
+	w.Header().Set("Content-Type", exportContentTypes[format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="training-log-%s.%s"`, time.Now().UTC().Format("2006-01-02"), format))
+
+	out := &trackingWriter{ResponseWriter: w}
+	if err := h.exportService.Export(userID, format, out); err != nil {
+		// Once the body has started the status is sent, and the client gets a truncated file
+		if !out.written {
+			w.Header().Del("Content-Disposition")
+			respondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		log.Printf("Export of user %d interrupted: %v", userID, err)
+	}
+}
+
+// trackingWriter records whether any of the response body has been written
+type trackingWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (t *trackingWriter) Write(p []byte) (int, error) {
+	t.written = true
+	return t.ResponseWriter.Write(p)
+}
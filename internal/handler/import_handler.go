@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"phoenix-alliance-be/internal/middleware"
+	"phoenix-alliance-be/internal/models"
+	"phoenix-alliance-be/internal/service"
+)
+
+// ImportHandler handles imports of training history from other apps
+type ImportHandler struct {
+	importService service.ImportService
+}
+
+// NewImportHandler creates a new import handler
+func NewImportHandler(importService service.ImportService) *ImportHandler {
+	return &ImportHandler{importService: importService}
+}
+
+// Import handles POST /import?format=&dry_run= with the CSV file in the multipart field "file"
+func (h *ImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	opts := models.ImportOptions{Format: models.ImportFormat(r.URL.Query().Get("format"))}
+	var msg string
+	if opts.DryRun, msg = parseBoolParam(r, "dry_run"); msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	// Leave room for the multipart headers around the file
+	r.Body = http.MaxBytesReader(w, r.Body, models.MaxImportSize+1<<20)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid upload. Send the CSV file in the multipart field 'file', up to 32 MB")
+		return
+	}
+	defer file.Close()
+
+	result, err := h.importService.Import(userID, file, opts)
+	if err != nil {
+		if msg, ok := strings.CutPrefix(err.Error(), "invalid import: "); ok {
+			respondWithError(w, http.StatusBadRequest, msg)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	status := http.StatusCreated
+	if opts.DryRun {
+		status = http.StatusOK
+	}
+	respondWithJSON(w, status, result)
+}
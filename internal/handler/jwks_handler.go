@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"net/http"
+
+	"phoenix-alliance-be/internal/auth"
+)
+
+// JWKSHandler publishes the public keys access tokens are verified with
+type JWKSHandler struct {
+	keys *auth.KeySet
+}
+
+// NewJWKSHandler creates a new JWKS handler
+func NewJWKSHandler(keys *auth.KeySet) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// GetJWKS handles GET /.well-known/jwks.json
+func (h *JWKSHandler) GetJWKS(w http.ResponseWriter, r *http.Request) {
+	// Verifiers cache the set briefly, so a new key must be published before it signs
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	respondWithJSON(w, http.StatusOK, h.keys.JWKS())
+}
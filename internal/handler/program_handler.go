@@ -0,0 +1,308 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"phoenix-alliance-be/internal/middleware"
+	"phoenix-alliance-be/internal/models"
+	"phoenix-alliance-be/internal/service"
+
+	"github.com/gorilla/mux"
+)
+
+// maxProgramWeeks caps the length of a program
+const maxProgramWeeks = 104
+
+// ProgramHandler handles training program requests
+type ProgramHandler struct {
+	programService service.ProgramService
+}
+
+// NewProgramHandler creates a new program handler
+func NewProgramHandler(programService service.ProgramService) *ProgramHandler {
+	return &ProgramHandler{programService: programService}
+}
+
+// CreateProgram handles POST /programs
+func (h *ProgramHandler) CreateProgram(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req models.ProgramCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if msg := validateProgram(req.Name, req.Weeks, req.Days); msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	program, err := h.programService.CreateProgram(userID, &req)
+	if err != nil {
+		if err.Error() == "exercise not found" {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, program)
+}
+
+// GetPrograms handles GET /programs
+func (h *ProgramHandler) GetPrograms(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	programs, err := h.programService.GetPrograms(userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, programs)
+}
+
+// GetProgram handles GET /programs/{id}
+func (h *ProgramHandler) GetProgram(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	vars := mux.Vars(r)
+	programID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid program ID")
+		return
+	}
+
+	program, err := h.programService.GetProgramByID(userID, programID)
+	if err != nil {
+		if err.Error() == "program not found" {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, program)
+}
+
+// UpdateProgram handles PUT /programs/{id}
+func (h *ProgramHandler) UpdateProgram(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	vars := mux.Vars(r)
+	programID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid program ID")
+		return
+	}
+
+	var req models.ProgramUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if msg := validateProgram(req.Name, req.Weeks, req.Days); msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	program, err := h.programService.UpdateProgram(userID, programID, &req)
+	if err != nil {
+		if err.Error() == "program not found" || err.Error() == "exercise not found" {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, program)
+}
+
+// DeleteProgram handles DELETE /programs/{id}
+func (h *ProgramHandler) DeleteProgram(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	vars := mux.Vars(r)
+	programID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid program ID")
+		return
+	}
+
+	if err := h.programService.DeleteProgram(userID, programID); err != nil {
+		if err.Error() == "program not found" {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Enroll handles POST /programs/{id}/enroll
+func (h *ProgramHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	vars := mux.Vars(r)
+	programID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid program ID")
+		return
+	}
+
+	enrollment, err := h.programService.Enroll(userID, programID)
+	if err != nil {
+		if err.Error() == "program not found" {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, enrollment)
+}
+
+// GetNextSession handles GET /programs/{id}/next-session
+func (h *ProgramHandler) GetNextSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	vars := mux.Vars(r)
+	programID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid program ID")
+		return
+	}
+
+	session, err := h.programService.GetNextSession(userID, programID)
+	if err != nil {
+		respondWithProgramError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, session)
+}
+
+// StartNextSession handles POST /programs/{id}/next-session/start
+func (h *ProgramHandler) StartNextSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	vars := mux.Vars(r)
+	programID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid program ID")
+		return
+	}
+
+	started, err := h.programService.StartNextSession(userID, programID)
+	if err != nil {
+		respondWithProgramError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, started)
+}
+
+// respondWithProgramError maps next-session errors to status codes
+func respondWithProgramError(w http.ResponseWriter, err error) {
+	switch err.Error() {
+	case "program not found", "not enrolled in program":
+		respondWithError(w, http.StatusNotFound, err.Error())
+	case "program completed", "program has no sessions":
+		respondWithError(w, http.StatusConflict, err.Error())
+	default:
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// validateProgram performs basic validation of a program request and returns an error message
+func validateProgram(name string, weeks int, days []models.ProgramDayRequest) string {
+	if name == "" {
+		return "Program name is required"
+	}
+	if weeks < 1 || weeks > maxProgramWeeks {
+		return "Weeks must be between 1 and 104"
+	}
+	if len(days) == 0 || len(days) > 7 {
+		return "Program must have between 1 and 7 days per week"
+	}
+
+	for _, day := range days {
+		if day.Name == "" {
+			return "Day name is required"
+		}
+		if len(day.Exercises) == 0 {
+			return "Each day must have at least one exercise"
+		}
+
+		for _, exercise := range day.Exercises {
+			if exercise.ExerciseID == 0 {
+				return "Exercise ID is required"
+			}
+			if exercise.Sets < 1 {
+				return "Sets must be at least 1"
+			}
+			if exercise.Reps < 1 {
+				return "Reps must be at least 1"
+			}
+			if !exercise.Progression.IsValid() {
+				return "Invalid progression. Must be 'linear', 'double', or 'wave'"
+			}
+			if exercise.Progression == models.ProgressionDouble && (exercise.RepMax == nil || *exercise.RepMax <= exercise.Reps) {
+				return "Double progression requires rep_max greater than reps"
+			}
+			if exercise.Increment != nil && *exercise.Increment < 0 {
+				return "Increment must be non-negative"
+			}
+			if exercise.StartWeight != nil && *exercise.StartWeight < 0 {
+				return "Start weight must be non-negative"
+			}
+			if exercise.TrainingMaxPercent != nil && (*exercise.TrainingMaxPercent <= 0 || *exercise.TrainingMaxPercent > 100) {
+				return "Training max percent must be between 0 and 100"
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,356 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+// parseListParams reads limit, sort and cursor from the query string and returns an error message.
+// Sort is a whitelisted field, prefixed with "-" for descending order.
+func parseListParams(r *http.Request, sortFields []string, defaultSort string) (models.ListParams, string) {
+	query := r.URL.Query()
+	params := models.ListParams{Limit: models.DefaultPageLimit}
+
+	if limitParam := query.Get("limit"); limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil || limit < 1 || limit > models.MaxPageLimit {
+			return params, fmt.Sprintf("Limit must be between 1 and %d", models.MaxPageLimit)
+		}
+		params.Limit = limit
+	}
+
+	sort := query.Get("sort")
+	if sort == "" {
+		sort = defaultSort
+	}
+	params.Desc = strings.HasPrefix(sort, "-")
+	params.Sort = strings.TrimPrefix(sort, "-")
+	if !slices.Contains(sortFields, params.Sort) {
+		return params, fmt.Sprintf("Invalid sort. Must be one of: %s (prefix with '-' for descending)", strings.Join(sortFields, ", "))
+	}
+
+	if cursorParam := query.Get("cursor"); cursorParam != "" {
+		cursor, err := models.DecodeCursor(cursorParam)
+		// A cursor only makes sense with the sort it was issued for
+		if err != nil || cursor.Sort != params.SortKey() {
+			return params, "Invalid cursor"
+		}
+		params.Cursor = cursor
+	}
+
+	return params, ""
+}
+
+// parseDateRange reads the from and to query parameters as RFC 3339 timestamps or
+// YYYY-MM-DD dates. The range is [from, to); a date-only to covers that whole day.
+func parseDateRange(r *http.Request) (*time.Time, *time.Time, string) {
+	query := r.URL.Query()
+
+	var from, to *time.Time
+	if fromParam := query.Get("from"); fromParam != "" {
+		t, _, ok := parseTimeParam(fromParam)
+		if !ok {
+			return nil, nil, "Invalid from date. Use YYYY-MM-DD or RFC 3339"
+		}
+		from = &t
+	}
+
+	if toParam := query.Get("to"); toParam != "" {
+		t, dateOnly, ok := parseTimeParam(toParam)
+		if !ok {
+			return nil, nil, "Invalid to date. Use YYYY-MM-DD or RFC 3339"
+		}
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		to = &t
+	}
+
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, nil, "from must be before to"
+	}
+
+	return from, to, ""
+}
+
+// parseTimeParam parses an RFC 3339 timestamp or a YYYY-MM-DD date (UTC)
+func parseTimeParam(value string) (time.Time, bool, bool) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true, true
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, true
+	}
+	return time.Time{}, false, false
+}
+
+// parseFloatParam reads an optional non-negative number from the query string
+func parseFloatParam(r *http.Request, name string) (*float64, string) {
+	param := r.URL.Query().Get(name)
+	if param == "" {
+		return nil, ""
+	}
+
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil || value < 0 {
+		return nil, fmt.Sprintf("Invalid %s. Must be a non-negative number", name)
+	}
+	return &value, ""
+}
+
+// parseIntParam reads an optional non-negative integer from the query string
+func parseIntParam(r *http.Request, name string) (*int, string) {
+	param := r.URL.Query().Get(name)
+	if param == "" {
+		return nil, ""
+	}
+
+	value, err := strconv.Atoi(param)
+	if err != nil || value < 0 {
+		return nil, fmt.Sprintf("Invalid %s. Must be a non-negative integer", name)
+	}
+	return &value, ""
+}
+
+// parseBoolParam reads an optional boolean from the query string (default: false)
+func parseBoolParam(r *http.Request, name string) (bool, string) {
+	param := r.URL.Query().Get(name)
+	if param == "" {
+		return false, ""
+	}
+
+	value, err := strconv.ParseBool(param)
+	if err != nil {
+		return false, fmt.Sprintf("Invalid %s. Must be true or false", name)
+	}
+	return value, ""
+}
+
+// parseHistoryInclude reads the comma-separated parts of a history response to return,
+// sets and/or metrics (default: both)
+func parseHistoryInclude(r *http.Request) (models.HistoryInclude, string) {
+	param := r.URL.Query().Get("include")
+	if param == "" {
+		return models.HistoryInclude{Sets: true, Metrics: true}, ""
+	}
+
+	var include models.HistoryInclude
+	for _, part := range strings.Split(param, ",") {
+		switch strings.TrimSpace(part) {
+		case "sets":
+			include.Sets = true
+		case "metrics":
+			include.Metrics = true
+		default:
+			return include, "Invalid include. Must list 'sets' and/or 'metrics'"
+		}
+	}
+	return include, ""
+}
+
+// parseExerciseFilter reads pagination, date range, name search and catalog filters of exercise lists
+func parseExerciseFilter(r *http.Request, defaultSort string) (*models.ExerciseFilter, string) {
+	params, msg := parseListParams(r, models.ExerciseSortFields, defaultSort)
+	if msg != "" {
+		return nil, msg
+	}
+
+	query := r.URL.Query()
+	filter := &models.ExerciseFilter{
+		ListParams:      params,
+		Search:          query.Get("q"),
+		Type:            models.ExerciseType(query.Get("type")),
+		Muscle:          query.Get("muscle"),
+		Equipment:       query.Get("equipment"),
+		MovementPattern: query.Get("movement_pattern"),
+	}
+	if filter.From, filter.To, msg = parseDateRange(r); msg != "" {
+		return nil, msg
+	}
+	if filter.Type != "" && !filter.Type.IsValid() {
+		return nil, "Invalid type. Must be 'weight', 'bodyweight', 'cardio', 'flexibility', or 'timed'"
+	}
+
+	return filter, ""
+}
+
+// parseSetFilter reads pagination, date range, set type, weight/reps/RPE bounds and the
+// include_warmups metrics flag of set lists
+func parseSetFilter(r *http.Request, defaultSort string) (*models.SetFilter, string) {
+	params, msg := parseListParams(r, models.SetSortFields, defaultSort)
+	if msg != "" {
+		return nil, msg
+	}
+
+	filter := &models.SetFilter{ListParams: params}
+	if filter.From, filter.To, msg = parseDateRange(r); msg != "" {
+		return nil, msg
+	}
+
+	floats := []struct {
+		name  string
+		value **float64
+	}{
+		{"min_weight", &filter.MinWeight},
+		{"max_weight", &filter.MaxWeight},
+	}
+	for _, f := range floats {
+		if *f.value, msg = parseFloatParam(r, f.name); msg != "" {
+			return nil, msg
+		}
+	}
+
+	ints := []struct {
+		name  string
+		value **int
+	}{
+		{"min_reps", &filter.MinReps},
+		{"max_reps", &filter.MaxReps},
+		{"min_rpe", &filter.MinRPE},
+		{"max_rpe", &filter.MaxRPE},
+	}
+	for _, i := range ints {
+		if *i.value, msg = parseIntParam(r, i.name); msg != "" {
+			return nil, msg
+		}
+	}
+
+	filter.Type = models.SetType(r.URL.Query().Get("type"))
+	if filter.Type != "" && !filter.Type.IsValid() {
+		return nil, "Invalid type. Must be 'warmup', 'working', 'drop', 'amrap', or 'failure'"
+	}
+	if filter.IncludeWarmups, msg = parseBoolParam(r, "include_warmups"); msg != "" {
+		return nil, msg
+	}
+
+	return filter, ""
+}
+
+// parseProgressQuery reads the range or from/to bounds, bucket, rolling window and flags of
+// progress requests. The range is ignored when from is set.
+func parseProgressQuery(r *http.Request) (*models.ProgressQuery, string) {
+	query := r.URL.Query()
+	progress := &models.ProgressQuery{
+		Range:  models.ProgressRange(query.Get("range")),
+		Bucket: models.ProgressBucket(query.Get("bucket")),
+	}
+
+	if progress.Range == "" {
+		progress.Range = models.ProgressRangeMonth
+	}
+	if progress.Range != models.ProgressRangeWeek && progress.Range != models.ProgressRangeMonth && progress.Range != models.ProgressRangeYear {
+		return nil, "Invalid range. Must be 'week', 'month', or 'year'"
+	}
+
+	var msg string
+	if progress.From, progress.To, msg = parseDateRange(r); msg != "" {
+		return nil, msg
+	}
+	// Calendar dates are read in the user's time zone, so both bounds must be dates or neither
+	_, fromDate, _ := parseTimeParam(query.Get("from"))
+	_, toDate, _ := parseTimeParam(query.Get("to"))
+	if progress.From != nil && progress.To != nil && fromDate != toDate {
+		return nil, "from and to must both be dates or both be RFC 3339 timestamps"
+	}
+	progress.DateOnly = fromDate || toDate
+
+	if progress.Bucket == "" {
+		progress.Bucket = models.ProgressBucketDay
+	}
+	if !progress.Bucket.IsValid() {
+		return nil, "Invalid bucket. Must be 'day', 'week', or 'month'"
+	}
+	// Every bucket of the period is generated, so long periods need larger buckets
+	if progress.From != nil {
+		to := time.Now()
+		if progress.To != nil {
+			to = *progress.To
+		}
+		if maxBuckets := progress.Bucket.MaxBuckets(); to.After(progress.Bucket.Add(*progress.From, maxBuckets)) {
+			return nil, fmt.Sprintf("The period is too long. It can span at most %d buckets of a %s", maxBuckets, progress.Bucket)
+		}
+	}
+
+	window, msg := parseIntParam(r, "window")
+	if msg != "" {
+		return nil, msg
+	}
+	if window != nil {
+		if *window < 1 || *window > models.MaxProgressWindow {
+			return nil, fmt.Sprintf("Invalid window. Must be between 1 and %d", models.MaxProgressWindow)
+		}
+		progress.Window = *window
+	}
+
+	var ok bool
+	if progress.Formula, ok = parseE1RMFormula(r); !ok {
+		return nil, "Invalid formula. Must be 'epley', 'brzycki', 'lombardi', or 'rpe'"
+	}
+	if progress.IncludeWarmups, msg = parseBoolParam(r, "include_warmups"); msg != "" {
+		return nil, msg
+	}
+	if progress.FillEmpty, msg = parseBoolParam(r, "fill_empty"); msg != "" {
+		return nil, msg
+	}
+
+	return progress, ""
+}
+
+// parseAnalyticsQuery reads the number of weeks and load metric of analytics requests
+func parseAnalyticsQuery(r *http.Request) (*models.AnalyticsQuery, string) {
+	analytics := &models.AnalyticsQuery{
+		Weeks:  models.DefaultAnalyticsWeeks,
+		Metric: models.LoadMetric(r.URL.Query().Get("metric")),
+	}
+
+	weeks, msg := parseIntParam(r, "weeks")
+	if msg != "" {
+		return nil, msg
+	}
+	if weeks != nil {
+		if *weeks < 1 || *weeks > models.MaxAnalyticsWeeks {
+			return nil, fmt.Sprintf("Invalid weeks. Must be between 1 and %d", models.MaxAnalyticsWeeks)
+		}
+		analytics.Weeks = *weeks
+	}
+
+	if analytics.Metric == "" {
+		analytics.Metric = models.LoadMetricSRPE
+	}
+	if !analytics.Metric.IsValid() {
+		return nil, "Invalid metric. Must be 'srpe' or 'tonnage'"
+	}
+
+	return analytics, ""
+}
+
+// parseReportQuery reads the period and e1RM formula of a progress report request
+func parseReportQuery(r *http.Request) (*models.ReportQuery, string) {
+	query := r.URL.Query()
+	report := &models.ReportQuery{}
+
+	var msg string
+	if report.From, report.To, msg = parseDateRange(r); msg != "" {
+		return nil, msg
+	}
+	// Calendar dates are read in the user's time zone, so both bounds must be dates or neither
+	_, fromDate, _ := parseTimeParam(query.Get("from"))
+	_, toDate, _ := parseTimeParam(query.Get("to"))
+	if report.From != nil && report.To != nil && fromDate != toDate {
+		return nil, "from and to must both be dates or both be RFC 3339 timestamps"
+	}
+	report.DateOnly = fromDate || toDate
+
+	var ok bool
+	if report.Formula, ok = parseE1RMFormula(r); !ok {
+		return nil, "Invalid formula. Must be 'epley', 'brzycki', 'lombardi', or 'rpe'"
+	}
+
+	return report, ""
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+func TestParseListParams(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/workouts", nil)
+		params, msg := parseListParams(req, models.WorkoutSortFields, "-created_at")
+		if msg != "" {
+			t.Fatalf("expected no error, got %q", msg)
+		}
+		if params.Limit != models.DefaultPageLimit || params.Sort != "created_at" || !params.Desc {
+			t.Errorf("unexpected params %+v", params)
+		}
+	})
+
+	t.Run("cursor round trip", func(t *testing.T) {
+		cursor := &models.Cursor{Sort: "name", Value: "Bench", ID: 7}
+		req := httptest.NewRequest("GET", "/workouts?sort=name&limit=10&cursor="+cursor.Encode(), nil)
+		params, msg := parseListParams(req, models.WorkoutSortFields, "-created_at")
+		if msg != "" {
+			t.Fatalf("expected no error, got %q", msg)
+		}
+		if params.Cursor == nil || *params.Cursor != *cursor {
+			t.Errorf("expected cursor %+v, got %+v", cursor, params.Cursor)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"sort not whitelisted", "?sort=user_id"},
+		{"limit too large", "?limit=1000"},
+		{"limit not a number", "?limit=abc"},
+		{"malformed cursor", "?cursor=not-a-cursor"},
+		{"cursor from another sort", "?sort=name&cursor=" + (&models.Cursor{Sort: "-created_at", Value: "x", ID: 1}).Encode()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/workouts", nil)
+			req.URL.RawQuery = tt.query[1:]
+			if _, msg := parseListParams(req, models.WorkoutSortFields, "-created_at"); msg == "" {
+				t.Errorf("expected validation error for %s", tt.query)
+			}
+		})
+	}
+}
+
+func TestParseDateRange(t *testing.T) {
+	req := httptest.NewRequest("GET", "/workouts?from=2024-01-01&to=2024-01-31", nil)
+	from, to, msg := parseDateRange(req)
+	if msg != "" {
+		t.Fatalf("expected no error, got %q", msg)
+	}
+	if from.Format("2006-01-02") != "2024-01-01" || to.Format("2006-01-02") != "2024-02-01" {
+		t.Errorf("expected [2024-01-01, 2024-02-01), got [%s, %s)", from, to)
+	}
+
+	req = httptest.NewRequest("GET", "/workouts?from=2024-02-01&to=2024-01-01", nil)
+	if _, _, msg := parseDateRange(req); msg == "" {
+		t.Error("expected error for inverted range")
+	}
+}
+
+func TestParseProgressQuery(t *testing.T) {
+	req := httptest.NewRequest("GET", "/exercises/1/progress?from=2024-01-01&to=2024-04-01&bucket=week", nil)
+	query, msg := parseProgressQuery(req)
+	if msg != "" {
+		t.Fatalf("expected no error, got %q", msg)
+	}
+	if query.From == nil || query.To == nil || !query.DateOnly || query.Bucket != models.ProgressBucketWeek {
+		t.Errorf("unexpected query %+v", query)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"unknown bucket", "?bucket=hour"},
+		{"window too small", "?window=0"},
+		{"window too large", "?window=53"},
+		{"mixed bounds", "?from=2024-01-01&to=2024-04-01T00:00:00Z"},
+		{"too many days", "?from=2023-01-01&to=2024-04-01&bucket=day"},
+		{"too many weeks", "?from=2018-01-01&to=2024-04-01&bucket=week"},
+		{"too many months", "?from=2010-01-01&to=2024-04-01&bucket=month"},
+		{"too many days up to now", "?from=0001-01-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/exercises/1/progress", nil)
+			req.URL.RawQuery = tt.query[1:]
+			if _, msg := parseProgressQuery(req); msg == "" {
+				t.Errorf("expected validation error for %s", tt.query)
+			}
+		})
+	}
+}
+
+func TestParseProgressQueryLongestPeriod(t *testing.T) {
+	// A whole leap year of days is still accepted
+	req := httptest.NewRequest("GET", "/exercises/1/progress?from=2024-01-01&to=2024-12-31&bucket=day", nil)
+	if _, msg := parseProgressQuery(req); msg != "" {
+		t.Errorf("expected a year of days to be accepted, got %q", msg)
+	}
+	req = httptest.NewRequest("GET", "/exercises/1/progress?from=2015-01-01&to=2024-04-01&bucket=month", nil)
+	if _, msg := parseProgressQuery(req); msg != "" {
+		t.Errorf("expected ten years of months to be accepted, got %q", msg)
+	}
+}
+
+func TestParseHistoryInclude(t *testing.T) {
+	req := httptest.NewRequest("GET", "/exercises/1/history", nil)
+	if include, msg := parseHistoryInclude(req); msg != "" || !include.Sets || !include.Metrics {
+		t.Errorf("expected sets and metrics by default, got %+v (%q)", include, msg)
+	}
+
+	req = httptest.NewRequest("GET", "/exercises/1/history?include=metrics", nil)
+	if include, msg := parseHistoryInclude(req); msg != "" || include.Sets || !include.Metrics {
+		t.Errorf("expected metrics only, got %+v (%q)", include, msg)
+	}
+
+	req = httptest.NewRequest("GET", "/exercises/1/history?include=metrics,charts", nil)
+	if _, msg := parseHistoryInclude(req); msg == "" {
+		t.Error("expected validation error for an unknown part")
+	}
+}
+
+func TestParseAnalyticsQuery(t *testing.T) {
+	req := httptest.NewRequest("GET", "/analytics/load", nil)
+	query, msg := parseAnalyticsQuery(req)
+	if msg != "" || query.Weeks != models.DefaultAnalyticsWeeks || query.Metric != models.LoadMetricSRPE {
+		t.Errorf("expected default weeks and sRPE, got %+v (%q)", query, msg)
+	}
+
+	req = httptest.NewRequest("GET", "/analytics/load?weeks=12&metric=tonnage", nil)
+	if query, msg = parseAnalyticsQuery(req); msg != "" || query.Weeks != 12 || query.Metric != models.LoadMetricTonnage {
+		t.Errorf("expected 12 weeks of tonnage, got %+v (%q)", query, msg)
+	}
+
+	for _, params := range []string{"weeks=0", "weeks=53", "metric=trimp"} {
+		req = httptest.NewRequest("GET", "/analytics/load?"+params, nil)
+		if _, msg := parseAnalyticsQuery(req); msg == "" {
+			t.Errorf("expected validation error for %s", params)
+		}
+	}
+}
+
+func TestParseReportQuery(t *testing.T) {
+	req := httptest.NewRequest("GET", "/reports/progress.pdf?from=2024-01-01&to=2024-03-31&formula=brzycki", nil)
+	query, msg := parseReportQuery(req)
+	if msg != "" || !query.DateOnly || query.Formula != models.E1RMFormulaBrzycki {
+		t.Fatalf("expected a date range with Brzycki, got %+v (%q)", query, msg)
+	}
+	if want := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC); !query.To.Equal(want) {
+		t.Errorf("expected to to include the last day, got %s", query.To)
+	}
+
+	for _, params := range []string{"from=2024-03-01&to=2024-01-01", "from=2024-01-01&to=2024-03-01T00:00:00Z", "formula=wathan"} {
+		req = httptest.NewRequest("GET", "/reports/progress.pdf?"+params, nil)
+		if _, msg := parseReportQuery(req); msg == "" {
+			t.Errorf("expected validation error for %s", params)
+		}
+	}
+}
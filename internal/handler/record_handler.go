@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"phoenix-alliance-be/internal/middleware"
+	"phoenix-alliance-be/internal/service"
+
+	"github.com/gorilla/mux"
+)
+
+// RecordHandler handles personal record requests
+type RecordHandler struct {
+	recordService service.PersonalRecordService
+}
+
+// NewRecordHandler creates a new record handler
+func NewRecordHandler(recordService service.PersonalRecordService) *RecordHandler {
+	return &RecordHandler{recordService: recordService}
+}
+
+// GetRecords handles GET /records?current=true|false
+func (h *RecordHandler) GetRecords(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	currentOnly, ok := parseCurrentOnly(r)
+	if !ok {
+		respondWithError(w, http.StatusBadRequest, "Invalid current parameter. Must be 'true' or 'false'")
+		return
+	}
+
+	records, err := h.recordService.GetRecords(userID, currentOnly)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, records)
+}
+
+// GetExerciseRecords handles GET /exercises/{id}/records?current=true|false
+func (h *RecordHandler) GetExerciseRecords(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	vars := mux.Vars(r)
+	exerciseID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid exercise ID")
+		return
+	}
+
+	currentOnly, ok := parseCurrentOnly(r)
+	if !ok {
+		respondWithError(w, http.StatusBadRequest, "Invalid current parameter. Must be 'true' or 'false'")
+		return
+	}
+
+	records, err := h.recordService.GetExerciseRecords(userID, exerciseID, currentOnly)
+	if err != nil {
+		if err.Error() == "exercise not found" {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, records)
+}
+
+// parseCurrentOnly reads the current filter from the query string (default: false, i.e. full history)
+func parseCurrentOnly(r *http.Request) (bool, bool) {
+	currentParam := r.URL.Query().Get("current")
+	if currentParam == "" {
+		return false, true
+	}
+
+	currentOnly, err := strconv.ParseBool(currentParam)
+	if err != nil {
+		return false, false
+	}
+	return currentOnly, true
+}
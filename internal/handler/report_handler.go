@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"phoenix-alliance-be/internal/middleware"
+	"phoenix-alliance-be/internal/service"
+
+	"github.com/gorilla/mux"
+)
+
+// ReportHandler handles printable PDF report requests
+type ReportHandler struct {
+	reportService service.ReportService
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(reportService service.ReportService) *ReportHandler {
+	return &ReportHandler{reportService: reportService}
+}
+
+// GetWorkoutReport handles GET /reports/workouts/{id}.pdf
+func (h *ReportHandler) GetWorkoutReport(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	vars := mux.Vars(r)
+	workoutID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid workout ID")
+		return
+	}
+
+	var pdf bytes.Buffer
+	if err := h.reportService.WorkoutReport(userID, workoutID, &pdf); err != nil {
+		if err.Error() == "workout not found" {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithPDF(w, fmt.Sprintf("workout-%d.pdf", workoutID), pdf.Bytes())
+}
+
+// GetProgressReport handles GET /reports/progress.pdf?from=&to=&formula=
+func (h *ReportHandler) GetProgressReport(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	query, msg := parseReportQuery(r)
+	if msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	var pdf bytes.Buffer
+	if err := h.reportService.ProgressReport(userID, query, &pdf); err != nil {
+		if msg, ok := strings.CutPrefix(err.Error(), "invalid report: "); ok {
+			respondWithError(w, http.StatusBadRequest, msg)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithPDF(w, fmt.Sprintf("progress-%s.pdf", time.Now().UTC().Format("2006-01-02")), pdf.Bytes())
+}
+
+// respondWithPDF sends a rendered PDF as a download named filename
+func respondWithPDF(w http.ResponseWriter, filename string, pdf []byte) {
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(pdf)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(pdf)
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+
+	"phoenix-alliance-be/internal/middleware"
+	"phoenix-alliance-be/internal/models"
+	"phoenix-alliance-be/internal/service"
+)
+
+// SecurityHandler handles requests about the security events of the authenticated user
+type SecurityHandler struct {
+	securityService service.SecurityService
+}
+
+// NewSecurityHandler creates a new security handler
+func NewSecurityHandler(securityService service.SecurityService) *SecurityHandler {
+	return &SecurityHandler{securityService: securityService}
+}
+
+// GetEvents handles GET /me/security-events?limit=&cursor=&sort=-created_at
+func (h *SecurityHandler) GetEvents(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	params, msg := parseListParams(r, models.SecurityEventSortFields, "-created_at")
+	if msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	events, err := h.securityService.GetEvents(userID, &params)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, events)
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+
+	"phoenix-alliance-be/internal/middleware"
+	"phoenix-alliance-be/internal/service"
+)
+
+// StatsHandler handles user-level statistics requests
+type StatsHandler struct {
+	statsService service.StatsService
+}
+
+// NewStatsHandler creates a new stats handler
+func NewStatsHandler(statsService service.StatsService) *StatsHandler {
+	return &StatsHandler{statsService: statsService}
+}
+
+// GetSummary handles GET /me/summary
+func (h *StatsHandler) GetSummary(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	summary, err := h.statsService.GetSummary(userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, summary)
+}
@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"phoenix-alliance-be/internal/middleware"
+	"phoenix-alliance-be/internal/models"
+	"phoenix-alliance-be/internal/service"
+
+	"github.com/gorilla/mux"
+)
+
+// TemplateHandler handles workout template requests
+type TemplateHandler struct {
+	templateService service.WorkoutTemplateService
+}
+
+// NewTemplateHandler creates a new template handler
+func NewTemplateHandler(templateService service.WorkoutTemplateService) *TemplateHandler {
+	return &TemplateHandler{templateService: templateService}
+}
+
+// CreateTemplate handles POST /templates
+func (h *TemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req models.WorkoutTemplateCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if msg := validateTemplate(req.Name, req.Exercises); msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	template, err := h.templateService.CreateTemplate(userID, &req)
+	if err != nil {
+		if err.Error() == "exercise not found" {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, template)
+}
+
+// GetTemplates handles GET /templates
+func (h *TemplateHandler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	templates, err := h.templateService.GetTemplates(userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, templates)
+}
+
+// GetTemplate handles GET /templates/{id}
+func (h *TemplateHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	vars := mux.Vars(r)
+	templateID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	template, err := h.templateService.GetTemplateByID(userID, templateID)
+	if err != nil {
+		if err.Error() == "template not found" {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, template)
+}
+
+// UpdateTemplate handles PUT /templates/{id}
+func (h *TemplateHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	vars := mux.Vars(r)
+	templateID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	var req models.WorkoutTemplateUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if msg := validateTemplate(req.Name, req.Exercises); msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	template, err := h.templateService.UpdateTemplate(userID, templateID, &req)
+	if err != nil {
+		if err.Error() == "template not found" || err.Error() == "exercise not found" {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, template)
+}
+
+// DeleteTemplate handles DELETE /templates/{id}
+func (h *TemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	vars := mux.Vars(r)
+	templateID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(userID, templateID); err != nil {
+		if err.Error() == "template not found" {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// StartTemplate handles POST /templates/{id}/start
+func (h *TemplateHandler) StartTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	vars := mux.Vars(r)
+	templateID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	// The body is optional
+	var req models.TemplateStartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	started, err := h.templateService.StartTemplate(userID, templateID, &req)
+	if err != nil {
+		if err.Error() == "template not found" {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, started)
+}
+
+// GetPlannedSets handles GET /workouts/{id}/planned-sets
+func (h *TemplateHandler) GetPlannedSets(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	vars := mux.Vars(r)
+	workoutID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid workout ID")
+		return
+	}
+
+	plannedSets, err := h.templateService.GetPlannedSets(userID, workoutID)
+	if err != nil {
+		if err.Error() == "workout not found" {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, plannedSets)
+}
+
+// validateTemplate performs basic validation of a template request and returns an error message
+func validateTemplate(name string, exercises []models.TemplateExerciseRequest) string {
+	if name == "" {
+		return "Template name is required"
+	}
+	if len(exercises) == 0 {
+		return "Template must have at least one exercise"
+	}
+
+	for _, exercise := range exercises {
+		if exercise.ExerciseID == 0 {
+			return "Exercise ID is required"
+		}
+		if exercise.TargetSets < 1 {
+			return "Target sets must be at least 1"
+		}
+		if exercise.TargetReps < 1 {
+			return "Target reps must be at least 1"
+		}
+		if exercise.TargetWeight != nil && exercise.TargetPercentE1RM != nil {
+			return "Use either target weight or target percent of e1RM, not both"
+		}
+		if exercise.TargetWeight != nil && *exercise.TargetWeight < 0 {
+			return "Target weight must be non-negative"
+		}
+		if exercise.TargetPercentE1RM != nil && (*exercise.TargetPercentE1RM <= 0 || *exercise.TargetPercentE1RM > 120) {
+			return "Target percent of e1RM must be between 0 and 120"
+		}
+		if exercise.RestSeconds != nil && *exercise.RestSeconds < 0 {
+			return "Rest seconds must be non-negative"
+		}
+	}
+
+	return ""
+}
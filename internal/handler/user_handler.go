@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"phoenix-alliance-be/internal/middleware"
+	"phoenix-alliance-be/internal/models"
+	"phoenix-alliance-be/internal/service"
+)
+
+// UserHandler handles requests about the authenticated user
+type UserHandler struct {
+	userService    service.UserService
+	sessionService service.SessionService
+	accountService service.AccountService
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(
+	userService service.UserService,
+	sessionService service.SessionService,
+	accountService service.AccountService,
+) *UserHandler {
+	return &UserHandler{
+		userService:    userService,
+		sessionService: sessionService,
+		accountService: accountService,
+	}
+}
+
+// GetProfile handles GET /me
+func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	user, err := h.userService.GetProfile(userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, user)
+}
+
+// UpdateProfile handles PATCH /me
+func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req models.UserProfileUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Timezone != nil {
+		if msg := models.ValidateTimezone(*req.Timezone); msg != "" {
+			respondWithError(w, http.StatusBadRequest, msg)
+			return
+		}
+	}
+
+	user, err := h.userService.UpdateProfile(userID, &req)
+	if err != nil {
+		if err.Error() == "user not found" {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, user)
+}
+
+// ChangePassword handles PUT /me/password. Every session is signed out and a new one is
+// returned, in the format of the /token/refresh response.
+func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req models.PasswordChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		respondWithError(w, http.StatusBadRequest, "Current and new password are required")
+		return
+	}
+
+	if len(req.NewPassword) < 8 {
+		respondWithError(w, http.StatusBadRequest, "Password must be at least 8 characters")
+		return
+	}
+
+	if err := h.userService.ChangePassword(userID, &req); err != nil {
+		respondWithAccountError(w, err)
+		return
+	}
+
+	// Whoever knew the old password must not stay signed in; this device gets a new session
+	user, err := h.userService.GetProfile(userID)
+	if err != nil {
+		respondWithAccountError(w, err)
+		return
+	}
+	if err := h.sessionService.LogoutAll(userID); err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	tokens, err := h.sessionService.Create(user.ID, user.Email)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, tokens)
+}
+
+// ChangeEmail handles PUT /me/email, sending a verification link to the new address and a notice
+// to the previous one. Every other session is signed out.
+func (h *UserHandler) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.GetTokenClaims(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	userID := claims.UserID
+
+	var req models.EmailChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Email == "" || req.Password == "" {
+		respondWithError(w, http.StatusBadRequest, "Email and password are required")
+		return
+	}
+
+	previous, err := h.userService.GetProfile(userID)
+	if err != nil {
+		respondWithAccountError(w, err)
+		return
+	}
+	user, err := h.userService.ChangeEmail(userID, &req)
+	if err != nil {
+		respondWithAccountError(w, err)
+		return
+	}
+
+	// Whoever holds another session must not keep the account it was moved away from
+	if user.Email != previous.Email {
+		if err := h.sessionService.LogoutOthers(claims); err != nil {
+			respondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if err := h.accountService.NotifyEmailChanged(previous.Email, user.Email); err != nil {
+			log.Printf("Failed to notify user %d of the email change: %v", userID, err)
+		}
+	}
+
+	if user.VerifiedAt == nil {
+		if err := h.accountService.SendVerification(userID); err != nil {
+			log.Printf("Failed to send the verification email of user %d: %v", userID, err)
+		}
+	}
+
+	respondWithJSON(w, http.StatusOK, user)
+}
+
+// DeleteAccount handles DELETE /me. The account is purged with all its data once the grace
+// period ends; every session is signed out, and logging in again allows restoring it.
+func (h *UserHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req models.AccountDeleteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Password == "" {
+		respondWithError(w, http.StatusBadRequest, "Password is required")
+		return
+	}
+
+	user, err := h.userService.ScheduleDeletion(userID, &req)
+	if err != nil {
+		respondWithAccountError(w, err)
+		return
+	}
+
+	if err := h.sessionService.LogoutAll(userID); err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, user)
+}
+
+// RestoreAccount handles POST /me/restore, cancelling the deletion of the account
+func (h *UserHandler) RestoreAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	user, err := h.userService.CancelDeletion(userID)
+	if err != nil {
+		respondWithAccountError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, user)
+}
+
+// respondWithAccountError maps the errors of account changes to status codes
+func respondWithAccountError(w http.ResponseWriter, err error) {
+	switch err.Error() {
+	case "user not found":
+		respondWithError(w, http.StatusNotFound, err.Error())
+	case "current password is incorrect":
+		respondWithError(w, http.StatusForbidden, err.Error())
+	case "user with this email already exists", "account deletion not scheduled":
+		respondWithError(w, http.StatusConflict, err.Error())
+	default:
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+	}
+}
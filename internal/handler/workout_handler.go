@@ -2,8 +2,11 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"phoenix-alliance-be/internal/middleware"
 	"phoenix-alliance-be/internal/models"
@@ -40,6 +43,11 @@ func (h *WorkoutHandler) CreateWorkout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if msg := models.ValidatePerformedAt(req.PerformedAt); msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
 	workout, err := h.workoutService.CreateWorkout(userID, &req)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, err.Error())
@@ -71,25 +79,26 @@ func (h *WorkoutHandler) CreateSet(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Basic validation
-	if req.Weight < 0 {
-		respondWithError(w, http.StatusBadRequest, "Weight must be non-negative")
-		return
-	}
-	if req.Reps < 1 {
-		respondWithError(w, http.StatusBadRequest, "Reps must be at least 1")
+	if msg := req.Validate(); msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
 		return
 	}
-	if req.RPE != nil && (*req.RPE < 1 || *req.RPE > 10) {
-		respondWithError(w, http.StatusBadRequest, "RPE must be between 1 and 10")
+	if req.Position != nil && *req.Position < 1 {
+		respondWithError(w, http.StatusBadRequest, "Position must be at least 1")
 		return
 	}
 
+	// Requirements that depend on the exercise type are checked by the service
 	set, err := h.setService.CreateSet(userID, workoutID, &req)
 	if err != nil {
 		if err.Error() == "workout not found" || err.Error() == "exercise not found" {
 			respondWithError(w, http.StatusNotFound, err.Error())
 			return
 		}
+		if msg, ok := strings.CutPrefix(err.Error(), "invalid set: "); ok {
+			respondWithError(w, http.StatusBadRequest, msg)
+			return
+		}
 		respondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -97,7 +106,202 @@ func (h *WorkoutHandler) CreateSet(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusCreated, set)
 }
 
-// GetWorkouts handles GET /workouts
+// CreateSets handles POST /workouts/{id}/sets/batch
+func (h *WorkoutHandler) CreateSets(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	vars := mux.Vars(r)
+	workoutID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid workout ID")
+		return
+	}
+
+	var reqs []*models.SetCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Basic validation
+	if len(reqs) == 0 {
+		respondWithError(w, http.StatusBadRequest, "At least one set is required")
+		return
+	}
+	if len(reqs) > models.MaxSetBatchSize {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("At most %d sets can be created at once", models.MaxSetBatchSize))
+		return
+	}
+	for i, req := range reqs {
+		if req == nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Set %d: Invalid set", i+1))
+			return
+		}
+		if msg := req.Validate(); msg != "" {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Set %d: %s", i+1, msg))
+			return
+		}
+		if req.Position != nil && *req.Position < 1 {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Set %d: Position must be at least 1", i+1))
+			return
+		}
+	}
+
+	// Requirements that depend on the exercise type are checked by the service
+	sets, err := h.setService.CreateSets(userID, workoutID, reqs)
+	if err != nil {
+		if err.Error() == "workout not found" {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if msg, ok := strings.CutPrefix(err.Error(), "invalid set: "); ok {
+			respondWithError(w, http.StatusBadRequest, msg)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, sets)
+}
+
+// UpdateSet handles PUT /workouts/{id}/sets/{setId}
+func (h *WorkoutHandler) UpdateSet(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	vars := mux.Vars(r)
+	workoutID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid workout ID")
+		return
+	}
+	setID, err := strconv.ParseInt(vars["setId"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid set ID")
+		return
+	}
+
+	var req models.SetUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if msg := req.Validate(); msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	set, err := h.setService.UpdateSet(userID, workoutID, setID, &req)
+	if err != nil {
+		if err.Error() == "workout not found" || err.Error() == "set not found" || err.Error() == "exercise not found" {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if msg, ok := strings.CutPrefix(err.Error(), "invalid set: "); ok {
+			respondWithError(w, http.StatusBadRequest, msg)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, set)
+}
+
+// DeleteSet handles DELETE /workouts/{id}/sets/{setId}
+func (h *WorkoutHandler) DeleteSet(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	vars := mux.Vars(r)
+	workoutID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid workout ID")
+		return
+	}
+	setID, err := strconv.ParseInt(vars["setId"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid set ID")
+		return
+	}
+
+	err = h.setService.DeleteSet(userID, workoutID, setID)
+	if err != nil {
+		if err.Error() == "workout not found" || err.Error() == "set not found" {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ReorderSets handles PATCH /workouts/{id}/sets/order
+func (h *WorkoutHandler) ReorderSets(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	vars := mux.Vars(r)
+	workoutID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid workout ID")
+		return
+	}
+
+	var req models.SetOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if len(req.SetIDs) == 0 {
+		respondWithError(w, http.StatusBadRequest, "set_ids is required")
+		return
+	}
+	seen := make(map[int64]bool, len(req.SetIDs))
+	for _, id := range req.SetIDs {
+		if seen[id] {
+			respondWithError(w, http.StatusBadRequest, "set_ids must not contain duplicates")
+			return
+		}
+		seen[id] = true
+	}
+
+	sets, err := h.setService.ReorderSets(userID, workoutID, &req)
+	if err != nil {
+		if err.Error() == "workout not found" {
+			respondWithError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err.Error() == "invalid set order" {
+			respondWithError(w, http.StatusBadRequest, "set_ids must list every set of the workout exactly once")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, sets)
+}
+
+// GetWorkouts handles GET /workouts?limit=&cursor=&sort=&from=&to=&q=
 func (h *WorkoutHandler) GetWorkouts(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
@@ -105,7 +309,19 @@ func (h *WorkoutHandler) GetWorkouts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	workouts, err := h.workoutService.GetWorkouts(userID)
+	params, msg := parseListParams(r, models.WorkoutSortFields, "-performed_at")
+	if msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	filter := &models.WorkoutFilter{ListParams: params, Search: r.URL.Query().Get("q")}
+	if filter.From, filter.To, msg = parseDateRange(r); msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	workouts, err := h.workoutService.GetWorkouts(userID, filter)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -139,6 +355,10 @@ func (h *WorkoutHandler) UpdateWorkout(w http.ResponseWriter, r *http.Request) {
 		respondWithError(w, http.StatusBadRequest, "Workout name is required")
 		return
 	}
+	if msg := models.ValidatePerformedAt(req.PerformedAt); msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
 
 	workout, err := h.workoutService.UpdateWorkout(userID, workoutID, &req)
 	if err != nil {
@@ -181,7 +401,99 @@ func (h *WorkoutHandler) GetWorkout(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, workout)
 }
 
-// GetWorkoutSets handles GET /workouts/{id}/sets
+// StartWorkout handles POST /workouts/{id}/start
+func (h *WorkoutHandler) StartWorkout(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	vars := mux.Vars(r)
+	workoutID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid workout ID")
+		return
+	}
+
+	var req models.WorkoutStartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Bodyweight != nil && *req.Bodyweight <= 0 {
+		respondWithError(w, http.StatusBadRequest, "Bodyweight must be positive")
+		return
+	}
+
+	workout, err := h.workoutService.StartWorkout(userID, workoutID, &req)
+	if err != nil {
+		h.respondWithSessionError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, workout)
+}
+
+// FinishWorkout handles POST /workouts/{id}/finish
+func (h *WorkoutHandler) FinishWorkout(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	vars := mux.Vars(r)
+	workoutID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid workout ID")
+		return
+	}
+
+	var req models.WorkoutFinishRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Basic validation
+	if req.Status != "" && req.Status != models.WorkoutStatusCompleted && req.Status != models.WorkoutStatusAbandoned {
+		respondWithError(w, http.StatusBadRequest, "Invalid status. Must be 'completed' or 'abandoned'")
+		return
+	}
+	if req.Bodyweight != nil && *req.Bodyweight <= 0 {
+		respondWithError(w, http.StatusBadRequest, "Bodyweight must be positive")
+		return
+	}
+	if req.SessionRPE != nil && (*req.SessionRPE < 1 || *req.SessionRPE > 10) {
+		respondWithError(w, http.StatusBadRequest, "Session RPE must be between 1 and 10")
+		return
+	}
+
+	workout, err := h.workoutService.FinishWorkout(userID, workoutID, &req)
+	if err != nil {
+		h.respondWithSessionError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, workout)
+}
+
+// respondWithSessionError maps the errors of the session endpoints to responses
+func (h *WorkoutHandler) respondWithSessionError(w http.ResponseWriter, err error) {
+	if err.Error() == "workout not found" {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if msg, ok := strings.CutPrefix(err.Error(), "invalid transition: "); ok {
+		respondWithError(w, http.StatusConflict, msg)
+		return
+	}
+	respondWithError(w, http.StatusInternalServerError, err.Error())
+}
+
+// GetWorkoutSets handles GET /workouts/{id}/sets?limit=&cursor=&sort=&min_weight=&max_weight=&min_reps=&max_reps=&min_rpe=&max_rpe=&type=
 func (h *WorkoutHandler) GetWorkoutSets(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middleware.GetUserID(r)
 	if !ok {
@@ -196,6 +508,12 @@ func (h *WorkoutHandler) GetWorkoutSets(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	filter, msg := parseSetFilter(r, "position")
+	if msg != "" {
+		respondWithError(w, http.StatusBadRequest, msg)
+		return
+	}
+
 	// Verify workout belongs to user
 	_, err = h.workoutService.GetWorkoutByID(userID, workoutID)
 	if err != nil {
@@ -208,7 +526,7 @@ func (h *WorkoutHandler) GetWorkoutSets(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Get sets for workout
-	sets, err := h.setService.GetWorkoutSets(workoutID)
+	sets, err := h.setService.GetWorkoutSets(workoutID, filter)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, err.Error())
 		return
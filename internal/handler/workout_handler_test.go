@@ -33,7 +33,7 @@ func (m *mockWorkoutService) GetWorkoutByID(userID, workoutID int64) (*models.Wo
 	return nil, nil
 }
 
-func (m *mockWorkoutService) GetWorkouts(userID int64) ([]*models.WorkoutResponse, error) {
+func (m *mockWorkoutService) GetWorkouts(userID int64, filter *models.WorkoutFilter) (*models.ListResponse[*models.WorkoutResponse], error) {
 	return nil, nil
 }
 
@@ -51,6 +51,14 @@ func (m *mockWorkoutService) DeleteWorkout(userID, workoutID int64) error {
 	return nil
 }
 
+func (m *mockWorkoutService) StartWorkout(userID, workoutID int64, req *models.WorkoutStartRequest) (*models.WorkoutResponse, error) {
+	return nil, nil
+}
+
+func (m *mockWorkoutService) FinishWorkout(userID, workoutID int64, req *models.WorkoutFinishRequest) (*models.WorkoutResponse, error) {
+	return nil, nil
+}
+
 // mockSetServiceWorkout is a stub for SetService used in workout handler tests
 type mockSetServiceWorkout struct{}
 
@@ -58,15 +66,31 @@ func (m *mockSetServiceWorkout) CreateSet(userID, workoutID int64, req *models.S
 	return nil, nil
 }
 
-func (m *mockSetServiceWorkout) GetWorkoutSets(workoutID int64) ([]*models.SetResponse, error) {
+func (m *mockSetServiceWorkout) CreateSets(userID, workoutID int64, reqs []*models.SetCreateRequest) ([]*models.SetResponse, error) {
+	return nil, nil
+}
+
+func (m *mockSetServiceWorkout) UpdateSet(userID, workoutID, setID int64, req *models.SetUpdateRequest) (*models.SetResponse, error) {
+	return nil, nil
+}
+
+func (m *mockSetServiceWorkout) DeleteSet(userID, workoutID, setID int64) error {
+	return nil
+}
+
+func (m *mockSetServiceWorkout) ReorderSets(userID, workoutID int64, req *models.SetOrderRequest) ([]*models.SetResponse, error) {
+	return nil, nil
+}
+
+func (m *mockSetServiceWorkout) GetWorkoutSets(workoutID int64, filter *models.SetFilter) (*models.ListResponse[*models.SetResponse], error) {
 	return nil, nil
 }
 
-func (m *mockSetServiceWorkout) GetExerciseHistory(userID, exerciseID int64) (*models.ExerciseHistoryResponse, error) {
+func (m *mockSetServiceWorkout) GetExerciseHistory(userID, exerciseID int64, formula models.E1RMFormula, filter *models.SetFilter, include models.HistoryInclude) (*models.ExerciseHistoryResponse, error) {
 	return nil, nil
 }
 
-func (m *mockSetServiceWorkout) GetExerciseProgress(userID, exerciseID int64, rangeType models.ProgressRange) (*models.ExerciseProgressResponse, error) {
+func (m *mockSetServiceWorkout) GetExerciseProgress(userID, exerciseID int64, query *models.ProgressQuery) (*models.ExerciseProgressResponse, error) {
 	return nil, nil
 }
 
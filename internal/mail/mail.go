@@ -0,0 +1,53 @@
+// Package mail delivers the emails of account flows, such as password reset links
+package mail
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"phoenix-alliance-be/internal/config"
+)
+
+// Message represents a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer defines the interface for sending emails
+type Mailer interface {
+	Send(msg *Message) error
+}
+
+// New creates the mailer of the mail configuration: SMTP delivery, or a log of the messages
+// for local development
+func New(cfg *config.MailConfig) (Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return NewSMTPMailer(cfg)
+	case "log":
+		if cfg.LogFile == "" {
+			return NewLogMailer(log.Writer()), nil
+		}
+		file, err := os.OpenFile(cfg.LogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("opening mail log: %w", err)
+		}
+		return NewLogMailer(file), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q, use smtp or log", cfg.Driver)
+	}
+}
+
+// validateHeaders rejects line breaks in header values, which would let them add headers
+func validateHeaders(values ...string) error {
+	for _, value := range values {
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("invalid header value %q", value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"time"
+
+	"phoenix-alliance-be/internal/config"
+)
+
+// SMTPMailer sends messages through an SMTP server, upgrading the connection with STARTTLS
+// when the server offers it
+type SMTPMailer struct {
+	addr     string
+	from     string    // From header, which may include a display name
+	envelope string    // Bare address of From, for the SMTP envelope
+	auth     smtp.Auth // Nil when the server needs no authentication
+}
+
+// NewSMTPMailer creates a mailer for the SMTP server of the mail configuration
+func NewSMTPMailer(cfg *config.MailConfig) (*SMTPMailer, error) {
+	from, err := netmail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender %q: %w", cfg.From, err)
+	}
+
+	m := &SMTPMailer{
+		addr:     net.JoinHostPort(cfg.SMTPHost, cfg.SMTPPort),
+		from:     from.String(),
+		envelope: from.Address,
+	}
+	if cfg.SMTPUsername != "" {
+		m.auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+	return m, nil
+}
+
+// Send sends a message
+func (m *SMTPMailer) Send(msg *Message) error {
+	if err := validateHeaders(msg.To, msg.Subject); err != nil {
+		return err
+	}
+
+	var data bytes.Buffer
+	fmt.Fprintf(&data, "From: %s\r\n", m.from)
+	fmt.Fprintf(&data, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&data, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&data, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	data.WriteString("MIME-Version: 1.0\r\n")
+	data.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	data.WriteString(msg.Body)
+
+	return smtp.SendMail(m.addr, m.auth, m.envelope, []string{msg.To}, data.Bytes())
+}
@@ -3,20 +3,26 @@ package middleware
 import (
 	"context"
 	"encoding/json"
+	"log"
 	"net/http"
 	"strings"
 
 	"phoenix-alliance-be/internal/auth"
-	"phoenix-alliance-be/internal/config"
 )
 
 type contextKey string
 
 const UserIDKey contextKey = "user_id"
 const UserEmailKey contextKey = "user_email"
+const TokenClaimsKey contextKey = "token_claims"
 
-// AuthMiddleware validates JWT tokens and adds user info to request context
-func AuthMiddleware(cfg *config.Config) func(http.Handler) http.Handler {
+// TokenDenylist reports whether an access token was revoked before it expired
+type TokenDenylist interface {
+	IsRevoked(jti string) (bool, error)
+}
+
+// AuthMiddleware validates JWT tokens, rejects revoked ones and adds user info to request context
+func AuthMiddleware(keys *auth.KeySet, denylist TokenDenylist) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
@@ -32,15 +38,27 @@ func AuthMiddleware(cfg *config.Config) func(http.Handler) http.Handler {
 			}
 
 			tokenString := parts[1]
-			claims, err := auth.ValidateToken(tokenString, cfg.JWT.SecretKey)
+			claims, err := auth.ValidateToken(tokenString, keys)
 			if err != nil {
 				respondWithError(w, http.StatusUnauthorized, "Invalid or expired token")
 				return
 			}
 
+			revoked, err := denylist.IsRevoked(claims.ID)
+			if err != nil {
+				log.Printf("Failed to check revocation of token %s: %v", claims.ID, err)
+				respondWithError(w, http.StatusInternalServerError, "Failed to verify token")
+				return
+			}
+			if revoked {
+				respondWithError(w, http.StatusUnauthorized, "Invalid or expired token")
+				return
+			}
+
 			// Add user info to context
 			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
 			ctx = context.WithValue(ctx, UserEmailKey, claims.Email)
+			ctx = context.WithValue(ctx, TokenClaimsKey, claims)
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
@@ -53,6 +71,12 @@ func GetUserID(r *http.Request) (int64, bool) {
 	return userID, ok
 }
 
+// GetTokenClaims extracts the claims of the request's access token from request context
+func GetTokenClaims(r *http.Request) (*auth.Claims, bool) {
+	claims, ok := r.Context().Value(TokenClaimsKey).(*auth.Claims)
+	return claims, ok
+}
+
 // respondWithError sends a JSON error response
 func respondWithError(w http.ResponseWriter, code int, message string) {
 	respondWithJSON(w, code, map[string]string{"error": message})
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// AccountTokenPurpose is what an account token can be used for
+type AccountTokenPurpose string
+
+// Account token purposes
+const (
+	PurposePasswordReset     AccountTokenPurpose = "password_reset"
+	PurposeEmailVerification AccountTokenPurpose = "email_verification"
+)
+
+// Lifetimes of account tokens
+const (
+	PasswordResetTokenExpiry     = time.Hour
+	EmailVerificationTokenExpiry = 48 * time.Hour
+)
+
+// PasswordResetCooldown is how long after a reset link is sent no other is sent to the same
+// address, so that the endpoint cannot flood an inbox or keep replacing the link a user received
+const PasswordResetCooldown = 5 * time.Minute
+
+// AccountToken represents a single-use token sent by email to reset a password or verify an
+// email address
+type AccountToken struct {
+	ID        int64               `db:"id_account_token"`
+	UserID    int64               `db:"user_id"`
+	Purpose   AccountTokenPurpose `db:"purpose"`
+	TokenHash string              `db:"token_hash"`
+	Email     string              `db:"email"` // Address the token was sent to
+	ExpiresAt time.Time           `db:"expires_at"`
+	UsedAt    *time.Time          `db:"used_at"`
+	CreatedAt time.Time           `db:"created_at"`
+}
+
+// PasswordForgotRequest represents the request body for requesting a password reset email
+type PasswordForgotRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// PasswordResetRequest represents the request body for setting a new password with a reset token
+type PasswordResetRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8"`
+}
+
+// EmailVerifyRequest represents the request body for verifying an email address
+type EmailVerifyRequest struct {
+	Token string `json:"token" validate:"required"`
+}
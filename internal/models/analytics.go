@@ -0,0 +1,88 @@
+package models
+
+import "time"
+
+// Limits of analytics requests
+const (
+	DefaultAnalyticsWeeks = 8
+	MaxAnalyticsWeeks     = 52
+)
+
+// HardSetMinRPE is the lowest RPE at which a working set counts as a hard set;
+// working sets without an RPE are assumed to be hard
+const HardSetMinRPE = 7
+
+// SecondaryMuscleShare is the fraction of a set credited to each secondary muscle of its exercise
+const SecondaryMuscleShare = 0.5
+
+// LoadMetric is the measure of training load used by load analytics
+type LoadMetric string
+
+const (
+	LoadMetricSRPE    LoadMetric = "srpe"    // Session RPE x duration in minutes (Foster)
+	LoadMetricTonnage LoadMetric = "tonnage" // Weight x reps of working sets
+)
+
+// IsValid reports whether the metric is one of the supported metrics
+func (m LoadMetric) IsValid() bool {
+	return m == LoadMetricSRPE || m == LoadMetricTonnage
+}
+
+// AnalyticsQuery holds the options of an analytics request
+type AnalyticsQuery struct {
+	Weeks  int        // Weeks covered, ending with the current one
+	Metric LoadMetric // Load analytics only
+}
+
+// MuscleVolume represents the work credited to a muscle group
+type MuscleVolume struct {
+	Muscle   string  `json:"muscle"`
+	HardSets float64 `json:"hard_sets"`
+	Tonnage  float64 `json:"tonnage"` // Weight x reps
+}
+
+// MuscleVolumeWeek represents the work per muscle group in a week
+type MuscleVolumeWeek struct {
+	WeekStart time.Time      `json:"week_start"` // Monday, midnight in the user's time zone
+	Muscles   []MuscleVolume `json:"muscles"`    // Trained muscle groups only, by tonnage
+}
+
+// MuscleVolumeResponse represents weekly volume per muscle group
+type MuscleVolumeResponse struct {
+	Timezone string             `json:"timezone"`
+	Weeks    []MuscleVolumeWeek `json:"weeks"` // Oldest first
+}
+
+// DailyLoad represents the training load of a calendar day in the user's time zone
+type DailyLoad struct {
+	Date    time.Time // Midnight in the user's time zone
+	Load    float64
+	Unrated int // Sessions without a session RPE or duration, which add no sRPE load
+}
+
+// LoadSnapshot represents the training load over the 7 days ending today
+type LoadSnapshot struct {
+	AcuteLoad   float64  `json:"acute_load"`   // Last 7 days
+	ChronicLoad float64  `json:"chronic_load"` // Weekly average over the last 28 days
+	ACWR        *float64 `json:"acwr"`         // Acute:chronic workload ratio, null without chronic load
+	Monotony    *float64 `json:"monotony"`     // Mean / standard deviation of daily load, null when constant
+	Strain      *float64 `json:"strain"`       // Weekly load x monotony
+}
+
+// LoadWeek represents the training load of a week
+type LoadWeek struct {
+	WeekStart time.Time `json:"week_start"` // Monday, midnight in the user's time zone
+	Load      float64   `json:"load"`
+	ACWR      *float64  `json:"acwr"` // Week load over the average of the 4 weeks ending with it
+	Monotony  *float64  `json:"monotony"`
+	Strain    *float64  `json:"strain"`
+}
+
+// LoadResponse represents training-load analytics
+type LoadResponse struct {
+	Timezone        string       `json:"timezone"`
+	Metric          LoadMetric   `json:"metric"`
+	Current         LoadSnapshot `json:"current"`
+	Weeks           []LoadWeek   `json:"weeks"`            // Oldest first; the current week is in progress
+	UnratedSessions int          `json:"unrated_sessions"` // sRPE only: sessions in range that add no load
+}
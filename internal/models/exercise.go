@@ -1,46 +1,189 @@
 package models
 
 import (
+	"slices"
 	"time"
 )
 
-// Exercise represents an exercise created by a user
+// Exercise represents an exercise created by a user or a catalog entry shared by all users
 type Exercise struct {
-	ID        int64      `json:"id" db:"id_exercise"`
-	UserID    int64      `json:"user_id" db:"user_id"`
-	Name      string     `json:"name" db:"name"`
-	CreatedAt time.Time  `json:"created_at" db:"created_at"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	ID               int64        `json:"id" db:"id_exercise"`
+	UserID           int64        `json:"user_id" db:"user_id"` // Zero for catalog entries
+	Name             string       `json:"name" db:"name"`       // The user's alias for adopted catalog entries
+	CatalogName      *string      `json:"catalog_name,omitempty" db:"-"`
+	Type             ExerciseType `json:"type" db:"exercise_type"`
+	PrimaryMuscles   []string     `json:"primary_muscles" db:"primary_muscles"`
+	SecondaryMuscles []string     `json:"secondary_muscles" db:"secondary_muscles"`
+	Equipment        *string      `json:"equipment,omitempty" db:"equipment"`
+	MovementPattern  *string      `json:"movement_pattern,omitempty" db:"movement_pattern"`
+	CreatedAt        time.Time    `json:"created_at" db:"created_at"`
+	DeletedAt        *time.Time   `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// IsCatalog reports whether the exercise is a system-owned catalog entry
+func (e *Exercise) IsCatalog() bool {
+	return e.UserID == 0
+}
+
+// ExerciseType determines what a set of the exercise measures
+type ExerciseType string
+
+const (
+	ExerciseTypeWeight      ExerciseType = "weight"      // Load x reps
+	ExerciseTypeBodyweight  ExerciseType = "bodyweight"  // Reps, weight is added load
+	ExerciseTypeCardio      ExerciseType = "cardio"      // Distance and/or duration
+	ExerciseTypeFlexibility ExerciseType = "flexibility" // Duration
+	ExerciseTypeTimed       ExerciseType = "timed"       // Duration, optionally loaded (e.g. planks)
+)
+
+// IsValid reports whether the exercise type is supported
+func (t ExerciseType) IsValid() bool {
+	switch t {
+	case ExerciseTypeWeight, ExerciseTypeBodyweight, ExerciseTypeCardio, ExerciseTypeFlexibility, ExerciseTypeTimed:
+		return true
+	}
+	return false
+}
+
+// CountsReps reports whether sets of the type are measured in reps, which is
+// what strength metrics and personal records are computed from
+func (t ExerciseType) CountsReps() bool {
+	return t == ExerciseTypeWeight || t == ExerciseTypeBodyweight
+}
+
+// ValidateSet checks that a set carries the measurements of the exercise type
+// and returns an error message
+func (t ExerciseType) ValidateSet(req *SetValues) string {
+	switch t {
+	case ExerciseTypeCardio:
+		if req.DurationSeconds == nil && req.DistanceMeters == nil {
+			return "Cardio sets require distance_meters or duration_seconds"
+		}
+		if req.Weight != 0 || req.Reps != 0 {
+			return "Cardio sets cannot have weight or reps"
+		}
+	case ExerciseTypeFlexibility, ExerciseTypeTimed:
+		if req.DurationSeconds == nil || *req.DurationSeconds < 1 {
+			return "Timed sets require duration_seconds"
+		}
+		if req.Reps != 0 {
+			return "Timed sets cannot have reps"
+		}
+		if req.DistanceMeters != nil {
+			return "Distance is only allowed for cardio sets"
+		}
+		if t == ExerciseTypeFlexibility && req.Weight != 0 {
+			return "Flexibility sets cannot have weight"
+		}
+	default:
+		if req.Reps < 1 {
+			return "Reps must be at least 1"
+		}
+		if req.DistanceMeters != nil || req.DurationSeconds != nil {
+			return "Distance and duration are only allowed for cardio and timed sets"
+		}
+	}
+	return ""
+}
+
+// Muscle groups, equipment and movement patterns accepted in exercise metadata
+var (
+	MuscleGroups = []string{
+		"chest", "upper_back", "lats", "traps", "lower_back", "front_delts", "side_delts", "rear_delts",
+		"biceps", "triceps", "forearms", "abs", "obliques", "glutes", "quads", "hamstrings",
+		"adductors", "abductors", "calves", "neck", "full_body",
+	}
+	Equipment = []string{
+		"barbell", "dumbbell", "kettlebell", "machine", "cable", "bodyweight", "band",
+		"smith_machine", "ez_bar", "trap_bar", "other",
+	}
+	MovementPatterns = []string{
+		"horizontal_push", "vertical_push", "horizontal_pull", "vertical_pull",
+		"squat", "hinge", "lunge", "carry", "rotation", "isolation", "locomotion",
+	}
+)
+
+// ExerciseMetadata holds the catalog fields shared by exercise requests
+type ExerciseMetadata struct {
+	PrimaryMuscles   []string `json:"primary_muscles,omitempty"`
+	SecondaryMuscles []string `json:"secondary_muscles,omitempty"`
+	Equipment        *string  `json:"equipment,omitempty"`
+	MovementPattern  *string  `json:"movement_pattern,omitempty"`
+}
+
+// Validate checks the metadata against the accepted values and returns an error message
+func (m *ExerciseMetadata) Validate() string {
+	for _, muscle := range append(slices.Clone(m.PrimaryMuscles), m.SecondaryMuscles...) {
+		if !slices.Contains(MuscleGroups, muscle) {
+			return "Invalid muscle group: " + muscle
+		}
+	}
+	if m.Equipment != nil && *m.Equipment != "" && !slices.Contains(Equipment, *m.Equipment) {
+		return "Invalid equipment: " + *m.Equipment
+	}
+	if m.MovementPattern != nil && *m.MovementPattern != "" && !slices.Contains(MovementPatterns, *m.MovementPattern) {
+		return "Invalid movement pattern: " + *m.MovementPattern
+	}
+	return ""
 }
 
 // ExerciseCreateRequest represents the request body for creating an exercise
 type ExerciseCreateRequest struct {
-	Name string `json:"name" validate:"required,min=1"`
+	Name string       `json:"name" validate:"required,min=1"`
+	Type ExerciseType `json:"type,omitempty"` // Defaults to weight
+	ExerciseMetadata
 }
 
-// ExerciseUpdateRequest represents the request body for updating an exercise
+// ExerciseUpdateRequest represents the request body for updating an exercise.
+// Omitted metadata fields are left unchanged; an empty list or string clears them.
 type ExerciseUpdateRequest struct {
-	Name string `json:"name" validate:"required,min=1"`
+	Name string       `json:"name" validate:"required,min=1"`
+	Type ExerciseType `json:"type,omitempty"`
+	ExerciseMetadata
+}
+
+// CatalogAdoptRequest represents the request body for adopting a catalog exercise
+type CatalogAdoptRequest struct {
+	Alias *string `json:"alias,omitempty"` // Name shown instead of the catalog name
 }
 
 // ExerciseResponse represents the exercise data returned in responses
-// @TODO: Agregar mas campos al ejercicio:
-// Musculo principal
-// Musculo secundario
-// Tipo de ejercicio (peso, cardio, flexibilidad, etc.)
 type ExerciseResponse struct {
-	ID        int64     `json:"id"`
-	UserID    int64     `json:"user_id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
+	ID               int64        `json:"id"`
+	UserID           int64        `json:"user_id,omitempty"`
+	Name             string       `json:"name"`
+	IsCatalog        bool         `json:"is_catalog"`
+	CatalogName      *string      `json:"catalog_name,omitempty"`
+	Type             ExerciseType `json:"type"`
+	PrimaryMuscles   []string     `json:"primary_muscles"`
+	SecondaryMuscles []string     `json:"secondary_muscles"`
+	Equipment        *string      `json:"equipment,omitempty"`
+	MovementPattern  *string      `json:"movement_pattern,omitempty"`
+	CreatedAt        time.Time    `json:"created_at"`
 }
 
 // ToResponse converts an Exercise to ExerciseResponse
 func (e *Exercise) ToResponse() *ExerciseResponse {
+	primary := e.PrimaryMuscles
+	if primary == nil {
+		primary = []string{}
+	}
+	secondary := e.SecondaryMuscles
+	if secondary == nil {
+		secondary = []string{}
+	}
+
 	return &ExerciseResponse{
-		ID:        e.ID,
-		UserID:    e.UserID,
-		Name:      e.Name,
-		CreatedAt: e.CreatedAt,
+		ID:               e.ID,
+		UserID:           e.UserID,
+		Name:             e.Name,
+		IsCatalog:        e.IsCatalog(),
+		CatalogName:      e.CatalogName,
+		Type:             e.Type,
+		PrimaryMuscles:   primary,
+		SecondaryMuscles: secondary,
+		Equipment:        e.Equipment,
+		MovementPattern:  e.MovementPattern,
+		CreatedAt:        e.CreatedAt,
 	}
 }
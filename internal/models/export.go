@@ -0,0 +1,27 @@
+package models
+
+// ExportFormat is the file format of a training log export
+type ExportFormat string
+
+const (
+	ExportFormatCSV  ExportFormat = "csv"  // One row per set
+	ExportFormatJSON ExportFormat = "json" // Exercises and workouts with their sets
+)
+
+// IsValid reports whether the format is one of the supported formats
+func (f ExportFormat) IsValid() bool {
+	return f == ExportFormatCSV || f == ExportFormatJSON
+}
+
+// ExportRow represents a workout of an export with one of its sets and the set's exercise
+type ExportRow struct {
+	Workout      *Workout
+	Set          *Set // Nil for a workout without sets
+	ExerciseName string
+}
+
+// ExportWorkout represents a workout with its sets in a JSON export
+type ExportWorkout struct {
+	*WorkoutResponse
+	Sets []*SetResponse `json:"sets"` // In workout order
+}
@@ -0,0 +1,65 @@
+package models
+
+// ImportFormat is the CSV export format of another training app
+type ImportFormat string
+
+const (
+	ImportFormatStrong   ImportFormat = "strong"
+	ImportFormatHevy     ImportFormat = "hevy"
+	ImportFormatFitNotes ImportFormat = "fitnotes"
+)
+
+// IsValid reports whether the format is one of the supported formats
+func (f ImportFormat) IsValid() bool {
+	return f == ImportFormatStrong || f == ImportFormatHevy || f == ImportFormatFitNotes
+}
+
+// MaxImportSize is the largest file accepted by POST /import, in bytes
+const MaxImportSize = 32 << 20
+
+// ImportOptions holds the options of an import
+type ImportOptions struct {
+	Format ImportFormat // Detected from the CSV header when empty
+	DryRun bool         // Report what would be created without storing anything
+}
+
+// ImportWorkout represents a workout read from an import file, with its sets in order
+type ImportWorkout struct {
+	Workout *Workout
+	Sets    []*ImportSet
+}
+
+// ImportSet represents a set read from an import file. Exercise is set once the exercise
+// name is matched to an existing exercise or one to create.
+type ImportSet struct {
+	Line         int // Line of the file the set was read from
+	ExerciseName string
+	Exercise     *Exercise
+	Set          *Set
+}
+
+// ImportBatch holds what an import stores in a single transaction. Exercises are created
+// first, and sets refer to them through ImportSet.Exercise.
+type ImportBatch struct {
+	Exercises []*Exercise
+	Workouts  []*ImportWorkout
+}
+
+// ImportExerciseMatch reports the exercise an imported exercise name was mapped to
+type ImportExerciseMatch struct {
+	Name         string `json:"name"` // As written in the file
+	ExerciseID   int64  `json:"exercise_id,omitempty"`
+	ExerciseName string `json:"exercise_name"`
+	Created      bool   `json:"created"` // The exercise is new, created with the name from the file
+}
+
+// ImportResult reports what an import created, or would create on a dry run
+type ImportResult struct {
+	Format            ImportFormat          `json:"format"`
+	DryRun            bool                  `json:"dry_run"`
+	Workouts          int                   `json:"workouts"`
+	Sets              int                   `json:"sets"`
+	Exercises         []ImportExerciseMatch `json:"exercises"`
+	DuplicateWorkouts int                   `json:"duplicate_workouts"` // Already logged, left out
+	Skipped           []string              `json:"skipped"`            // Rows that could not be imported, with the reason
+}
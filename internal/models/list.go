@@ -0,0 +1,115 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Page size limits for list endpoints
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// Sort fields accepted by each list endpoint
+var (
+	WorkoutSortFields  = []string{"performed_at", "created_at", "name"}
+	ExerciseSortFields = []string{"created_at", "name"}
+	SetSortFields      = []string{"performed_at", "created_at", "weight", "reps", "position"}
+)
+
+// ListParams holds keyset pagination and sorting options of a list request
+type ListParams struct {
+	Limit  int // Zero means no limit
+	Sort   string
+	Desc   bool
+	Cursor *Cursor
+}
+
+// SortKey returns the sort as written in query strings, e.g. "-created_at"
+func (p ListParams) SortKey() string {
+	if p.Desc {
+		return "-" + p.Sort
+	}
+	return p.Sort
+}
+
+// Cursor is the position of the last row of a page: its sort value and ID
+type Cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    int64  `json:"id"`
+}
+
+// Encode returns the opaque representation of the cursor sent to clients
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor produced by Encode
+func DecodeCursor(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	cursor := &Cursor{}
+	if err := json.Unmarshal(data, cursor); err != nil || cursor.Sort == "" {
+		return nil, errors.New("invalid cursor")
+	}
+	return cursor, nil
+}
+
+// WorkoutFilter represents the filters of a workout list request
+type WorkoutFilter struct {
+	ListParams
+	From   *time.Time // Inclusive
+	To     *time.Time // Exclusive
+	Search string     // Case-insensitive name match
+}
+
+// ExerciseFilter represents the filters of an exercise list request
+type ExerciseFilter struct {
+	ListParams
+	From            *time.Time // Inclusive
+	To              *time.Time // Exclusive
+	Search          string     // Case-insensitive name match
+	Type            ExerciseType
+	Muscle          string // Matches primary or secondary muscles
+	Equipment       string
+	MovementPattern string
+}
+
+// SetFilter represents the filters of a set list request; bounds are inclusive
+type SetFilter struct {
+	ListParams
+	From      *time.Time // Inclusive
+	To        *time.Time // Exclusive
+	MinWeight *float64
+	MaxWeight *float64
+	MinReps   *int
+	MaxReps   *int
+	MinRPE    *int
+	MaxRPE    *int
+	Type      SetType
+	// IncludeWarmups counts warm-up sets in metrics; listed sets are not affected
+	IncludeWarmups bool
+}
+
+// ListResponse is the standard envelope of paginated list endpoints
+type ListResponse[T any] struct {
+	Data       []T     `json:"data"`
+	NextCursor *string `json:"next_cursor"` // Null on the last page
+}
+
+// NewListResponse builds a list envelope; an empty cursor marks the last page
+func NewListResponse[T any](data []T, nextCursor string) *ListResponse[T] {
+	response := &ListResponse[T]{Data: data}
+	if nextCursor != "" {
+		response.NextCursor = &nextCursor
+	}
+	return response
+}
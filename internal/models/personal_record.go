@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+)
+
+// PersonalRecordType represents the kind of personal record
+type PersonalRecordType string
+
+const (
+	PersonalRecordMaxWeight PersonalRecordType = "max_weight" // Heaviest weight lifted
+	PersonalRecordMaxReps   PersonalRecordType = "max_reps"   // Most reps at a given weight
+	PersonalRecordMaxE1RM   PersonalRecordType = "max_e1rm"   // Best estimated one-rep max
+	PersonalRecordMaxVolume PersonalRecordType = "max_volume" // Best single-session volume
+)
+
+// PersonalRecord represents a personal record achieved by a set
+type PersonalRecord struct {
+	ID            int64              `json:"id" db:"id_personal_record"`
+	UserID        int64              `json:"user_id" db:"user_id"`
+	ExerciseID    int64              `json:"exercise_id" db:"exercise_id"`
+	ExerciseName  string             `json:"exercise_name" db:"-"`
+	WorkoutID     int64              `json:"workout_id" db:"workout_id"`
+	SetID         int64              `json:"set_id" db:"set_id"`
+	RecordType    PersonalRecordType `json:"record_type" db:"record_type"`
+	Value         float64            `json:"value" db:"value"`
+	Weight        float64            `json:"weight" db:"weight"`
+	Reps          int                `json:"reps" db:"reps"`
+	PreviousValue *float64           `json:"previous_value,omitempty" db:"previous_value"`
+	IsCurrent     bool               `json:"is_current" db:"is_current"`
+	AchievedAt    time.Time          `json:"achieved_at" db:"achieved_at"`
+	CreatedAt     time.Time          `json:"created_at" db:"created_at"`
+}
+
+// PersonalRecordResponse represents the personal record data returned in responses
+type PersonalRecordResponse struct {
+	ID            int64              `json:"id"`
+	ExerciseID    int64              `json:"exercise_id"`
+	ExerciseName  string             `json:"exercise_name,omitempty"`
+	WorkoutID     int64              `json:"workout_id"`
+	SetID         int64              `json:"set_id"`
+	RecordType    PersonalRecordType `json:"record_type"`
+	Value         float64            `json:"value"`
+	Weight        float64            `json:"weight"`
+	Reps          int                `json:"reps"`
+	PreviousValue *float64           `json:"previous_value,omitempty"`
+	IsCurrent     bool               `json:"is_current"`
+	AchievedAt    time.Time          `json:"achieved_at"`
+}
+
+// ToResponse converts a PersonalRecord to PersonalRecordResponse
+func (p *PersonalRecord) ToResponse() *PersonalRecordResponse {
+	return &PersonalRecordResponse{
+		ID:            p.ID,
+		ExerciseID:    p.ExerciseID,
+		ExerciseName:  p.ExerciseName,
+		WorkoutID:     p.WorkoutID,
+		SetID:         p.SetID,
+		RecordType:    p.RecordType,
+		Value:         p.Value,
+		Weight:        p.Weight,
+		Reps:          p.Reps,
+		PreviousValue: p.PreviousValue,
+		IsCurrent:     p.IsCurrent,
+		AchievedAt:    p.AchievedAt,
+	}
+}
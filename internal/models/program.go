@@ -0,0 +1,169 @@
+package models
+
+import (
+	"time"
+)
+
+// ProgressionType represents how a program exercise's load progresses over time
+type ProgressionType string
+
+const (
+	ProgressionLinear ProgressionType = "linear" // Add load every session the target is hit
+	ProgressionDouble ProgressionType = "double" // Add reps up to rep_max, then add load
+	ProgressionWave   ProgressionType = "wave"   // 5/3/1-style percentage waves of a training max
+)
+
+// IsValid reports whether the progression is one of the supported progressions
+func (p ProgressionType) IsValid() bool {
+	switch p {
+	case ProgressionLinear, ProgressionDouble, ProgressionWave:
+		return true
+	}
+	return false
+}
+
+// Program represents a multi-week training program. Its days repeat every week.
+type Program struct {
+	ID          int64         `json:"id" db:"id_program"`
+	UserID      int64         `json:"user_id" db:"user_id"`
+	Name        string        `json:"name" db:"name"`
+	Description *string       `json:"description,omitempty" db:"description"`
+	Weeks       int           `json:"weeks" db:"weeks"`
+	DaysPerWeek int           `json:"days_per_week" db:"days_per_week"`
+	Days        []*ProgramDay `json:"days" db:"-"`
+	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
+	DeletedAt   *time.Time    `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// ProgramDay represents one session of the program's weekly cycle
+type ProgramDay struct {
+	ID        int64              `json:"id" db:"id_program_day"`
+	ProgramID int64              `json:"program_id" db:"program_id"`
+	DayNumber int                `json:"day_number" db:"day_number"` // 1-based
+	Name      string             `json:"name" db:"name"`
+	Exercises []*ProgramExercise `json:"exercises" db:"-"`
+}
+
+// ProgramExercise represents an exercise prescription and its progression rule
+type ProgramExercise struct {
+	ID                 int64           `json:"id" db:"id_program_exercise"`
+	ProgramDayID       int64           `json:"program_day_id" db:"program_day_id"`
+	ExerciseID         int64           `json:"exercise_id" db:"exercise_id"`
+	Position           int             `json:"position" db:"position"`
+	Sets               int             `json:"sets" db:"sets"`
+	Reps               int             `json:"reps" db:"reps"`
+	RepMax             *int            `json:"rep_max,omitempty" db:"rep_max"` // Top of the rep range for double progression
+	Progression        ProgressionType `json:"progression" db:"progression"`
+	Increment          float64         `json:"increment" db:"increment"`
+	StartWeight        *float64        `json:"start_weight,omitempty" db:"start_weight"`                 // Used when there is no history (training max for waves)
+	TrainingMaxPercent *float64        `json:"training_max_percent,omitempty" db:"training_max_percent"` // Percent of e1RM used as training max for waves
+}
+
+// ProgramExerciseRequest represents an exercise prescription in a program request
+type ProgramExerciseRequest struct {
+	ExerciseID         int64           `json:"exercise_id" validate:"required"`
+	Sets               int             `json:"sets" validate:"required,min=1"`
+	Reps               int             `json:"reps" validate:"required,min=1"`
+	RepMax             *int            `json:"rep_max,omitempty" validate:"omitempty,min=1"`
+	Progression        ProgressionType `json:"progression" validate:"required"`
+	Increment          *float64        `json:"increment,omitempty" validate:"omitempty,min=0"`
+	StartWeight        *float64        `json:"start_weight,omitempty" validate:"omitempty,min=0"`
+	TrainingMaxPercent *float64        `json:"training_max_percent,omitempty" validate:"omitempty,gt=0"`
+}
+
+// ProgramDayRequest represents a session in a program request
+type ProgramDayRequest struct {
+	Name      string                   `json:"name" validate:"required,min=1"`
+	Exercises []ProgramExerciseRequest `json:"exercises" validate:"required,min=1"`
+}
+
+// ProgramCreateRequest represents the request body for creating a program.
+// Days are numbered in the order they are sent.
+type ProgramCreateRequest struct {
+	Name        string              `json:"name" validate:"required,min=1"`
+	Description *string             `json:"description,omitempty"`
+	Weeks       int                 `json:"weeks" validate:"required,min=1"`
+	Days        []ProgramDayRequest `json:"days" validate:"required,min=1"`
+}
+
+// ProgramUpdateRequest represents the request body for updating a program.
+// The day list replaces the existing one.
+type ProgramUpdateRequest struct {
+	Name        string              `json:"name" validate:"required,min=1"`
+	Description *string             `json:"description,omitempty"`
+	Weeks       int                 `json:"weeks" validate:"required,min=1"`
+	Days        []ProgramDayRequest `json:"days" validate:"required,min=1"`
+}
+
+// ProgramResponse represents the program data returned in responses
+type ProgramResponse struct {
+	ID          int64         `json:"id"`
+	UserID      int64         `json:"user_id"`
+	Name        string        `json:"name"`
+	Description *string       `json:"description,omitempty"`
+	Weeks       int           `json:"weeks"`
+	DaysPerWeek int           `json:"days_per_week"`
+	Days        []*ProgramDay `json:"days"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// ToResponse converts a Program to ProgramResponse
+func (p *Program) ToResponse() *ProgramResponse {
+	days := p.Days
+	if days == nil {
+		days = []*ProgramDay{}
+	}
+	return &ProgramResponse{
+		ID:          p.ID,
+		UserID:      p.UserID,
+		Name:        p.Name,
+		Description: p.Description,
+		Weeks:       p.Weeks,
+		DaysPerWeek: p.DaysPerWeek,
+		Days:        days,
+		CreatedAt:   p.CreatedAt,
+	}
+}
+
+// ProgramEnrollment represents a user following a program
+type ProgramEnrollment struct {
+	ID                int64     `json:"id" db:"id_enrollment"`
+	ProgramID         int64     `json:"program_id" db:"program_id"`
+	UserID            int64     `json:"user_id" db:"user_id"`
+	CompletedSessions int       `json:"completed_sessions" db:"completed_sessions"`
+	Active            bool      `json:"active" db:"active"`
+	StartedAt         time.Time `json:"started_at" db:"started_at"`
+}
+
+// PrescribedSet represents a single set target within a session prescription
+type PrescribedSet struct {
+	SetNumber     int      `json:"set_number"`
+	TargetReps    int      `json:"target_reps"`
+	TargetRepsMax *int     `json:"target_reps_max,omitempty"` // Upper bound for double progression
+	TargetWeight  *float64 `json:"target_weight,omitempty"`
+	AMRAP         bool     `json:"amrap"` // As many reps as possible
+}
+
+// ExercisePrescription represents the targets of an exercise for the next session
+type ExercisePrescription struct {
+	ExerciseID  int64           `json:"exercise_id"`
+	Progression ProgressionType `json:"progression"`
+	Sets        []PrescribedSet `json:"sets"`
+}
+
+// SessionPrescription represents the next workout to perform in a program
+type SessionPrescription struct {
+	ProgramID    int64                   `json:"program_id"`
+	EnrollmentID int64                   `json:"enrollment_id"`
+	Week         int                     `json:"week"`
+	Day          int                     `json:"day"`
+	Name         string                  `json:"name"`
+	Exercises    []*ExercisePrescription `json:"exercises"`
+}
+
+// ProgramSessionStartResponse represents a workout created from a program session
+type ProgramSessionStartResponse struct {
+	Workout     *WorkoutResponse     `json:"workout"`
+	Session     *SessionPrescription `json:"session"`
+	PlannedSets []*PlannedSet        `json:"planned_sets"`
+}
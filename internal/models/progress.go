@@ -4,26 +4,67 @@ import "time"
 
 // ExerciseHistoryResponse represents the history of sets for an exercise
 type ExerciseHistoryResponse struct {
-	ExerciseID   int64          `json:"exercise_id"`
-	ExerciseName string         `json:"exercise_name"`
-	Sets         []*SetResponse `json:"sets"`
-	Metrics      *ExerciseMetrics `json:"metrics,omitempty"`
+	ExerciseID   int64            `json:"exercise_id"`
+	ExerciseName string           `json:"exercise_name"`
+	Sets         []*SetResponse   `json:"sets"`
+	NextCursor   *string          `json:"next_cursor"`       // Null on the last page of sets
+	Metrics      *ExerciseMetrics `json:"metrics,omitempty"` // Covers every set matching the filters
+}
+
+// HistoryInclude selects the parts of an exercise history response
+type HistoryInclude struct {
+	Sets    bool // The requested page of sets
+	Metrics bool // Metrics over every matching set
 }
 
 // ExerciseMetrics represents aggregated metrics for an exercise
 type ExerciseMetrics struct {
-	TotalSets        int     `json:"total_sets"`
-	TotalVolume      float64 `json:"total_volume"`      // Sum of (weight * reps)
-	MaxWeight        float64 `json:"max_weight"`
-	MaxReps          int     `json:"max_reps"`
-	AverageWeight    float64 `json:"average_weight"`
-	AverageReps      float64 `json:"average_reps"`
-	AverageRest      *float64 `json:"average_rest,omitempty"`
-	AverageRPE       *float64 `json:"average_rpe,omitempty"`
-	FirstRecordedAt  *time.Time `json:"first_recorded_at,omitempty"`
-	LastRecordedAt   *time.Time `json:"last_recorded_at,omitempty"`
+	TotalSets          int         `json:"total_sets"`
+	TotalVolume        float64     `json:"total_volume"` // Sum of (weight * reps)
+	MaxWeight          float64     `json:"max_weight"`
+	MaxReps            int         `json:"max_reps"`
+	AverageWeight      float64     `json:"average_weight"`
+	AverageReps        float64     `json:"average_reps"`
+	AverageRest        *float64    `json:"average_rest,omitempty"`
+	AverageRPE         *float64    `json:"average_rpe,omitempty"`
+	FirstRecordedAt    *time.Time  `json:"first_recorded_at,omitempty"`
+	LastRecordedAt     *time.Time  `json:"last_recorded_at,omitempty"`
+	EstimatedOneRepMax float64     `json:"estimated_1rm"` // Best e1RM across all sets
+	E1RMFormula        E1RMFormula `json:"e1rm_formula"`
+	TotalDistance      float64     `json:"total_distance_meters,omitempty"`  // Cardio exercises
+	TotalDuration      int         `json:"total_duration_seconds,omitempty"` // Cardio and timed exercises
+}
+
+// E1RMFormula represents the formula used to estimate a one-rep max
+type E1RMFormula string
+
+const (
+	E1RMFormulaEpley    E1RMFormula = "epley"
+	E1RMFormulaBrzycki  E1RMFormula = "brzycki"
+	E1RMFormulaLombardi E1RMFormula = "lombardi"
+	E1RMFormulaRPE      E1RMFormula = "rpe" // RTS-style RPE percentage table
+)
+
+// IsValid reports whether the formula is one of the supported formulas
+func (f E1RMFormula) IsValid() bool {
+	switch f {
+	case E1RMFormulaEpley, E1RMFormulaBrzycki, E1RMFormulaLombardi, E1RMFormulaRPE:
+		return true
+	}
+	return false
 }
 
+// RPEPercentages holds RTS-style percentages of 1RM indexed by effective reps - 1,
+// where effective reps = reps + reps in reserve (10 - RPE).
+// e.g. 5 reps @ RPE 8 -> 7 effective reps -> 81.1% of 1RM.
+var RPEPercentages = []float64{
+	1.000, 0.955, 0.922, 0.892, 0.863, 0.837, 0.811, 0.786,
+	0.762, 0.739, 0.707, 0.680, 0.653, 0.626, 0.599,
+}
+
+// MaxBrzyckiReps is the rep count above which Brzycki is no longer meaningful
+const MaxBrzyckiReps = 36
+
 // ProgressRange represents the time range for progress queries
 type ProgressRange string
 
@@ -33,23 +74,126 @@ const (
 	ProgressRangeYear  ProgressRange = "year"
 )
 
+// ProgressRangeCustom is reported when a progress request sets from or to
+const ProgressRangeCustom ProgressRange = "custom"
+
+// ProgressBucket is the period each progress data point covers
+type ProgressBucket string
+
+const (
+	ProgressBucketDay   ProgressBucket = "day"
+	ProgressBucketWeek  ProgressBucket = "week" // ISO weeks, starting on Monday
+	ProgressBucketMonth ProgressBucket = "month"
+)
+
+// MaxProgressWindow is the largest number of buckets a rolling average can span
+const MaxProgressWindow = 52
+
+// IsValid reports whether the bucket is one of the supported buckets
+func (b ProgressBucket) IsValid() bool {
+	switch b {
+	case ProgressBucketDay, ProgressBucketWeek, ProgressBucketMonth:
+		return true
+	}
+	return false
+}
+
+// DefaultWindow returns the number of buckets rolling averages span by default:
+// a week of days, four weeks, or three months
+func (b ProgressBucket) DefaultWindow() int {
+	switch b {
+	case ProgressBucketWeek:
+		return 4
+	case ProgressBucketMonth:
+		return 3
+	}
+	return 7
+}
+
+// MaxBuckets returns the largest number of buckets a progress request can span: a leap year
+// of days, five years of weeks, or ten years of months
+func (b ProgressBucket) MaxBuckets() int {
+	switch b {
+	case ProgressBucketWeek:
+		return 260
+	case ProgressBucketMonth:
+		return 120
+	}
+	return 366
+}
+
+// Start returns the start of the bucket t falls in, in loc
+func (b ProgressBucket) Start(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.In(loc).Date()
+	switch b {
+	case ProgressBucketWeek:
+		start := time.Date(year, month, day, 0, 0, 0, 0, loc)
+		return start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+	case ProgressBucketMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// Add moves a bucket start n buckets forward (or backward when n is negative)
+func (b ProgressBucket) Add(start time.Time, n int) time.Time {
+	switch b {
+	case ProgressBucketWeek:
+		return start.AddDate(0, 0, 7*n)
+	case ProgressBucketMonth:
+		return start.AddDate(0, n, 0)
+	}
+	return start.AddDate(0, 0, n)
+}
+
+// ProgressQuery holds the options of a progress request
+type ProgressQuery struct {
+	Range          ProgressRange // Span ending at To (or now) used when From is not set
+	From           *time.Time    // Inclusive
+	To             *time.Time    // Exclusive
+	DateOnly       bool          // From and To are calendar dates, read in the user's time zone
+	Bucket         ProgressBucket
+	Window         int // Buckets spanned by the rolling averages
+	Formula        E1RMFormula
+	IncludeWarmups bool
+	FillEmpty      bool // Add zero data points for buckets without sets
+}
+
+// ProgressRollup describes the per-bucket aggregation of a user's sets of an exercise
+type ProgressRollup struct {
+	From           time.Time // Inclusive; the first bucket is the one From falls in
+	To             time.Time // Exclusive
+	Bucket         ProgressBucket
+	Window         int // Buckets spanned by the rolling averages
+	Formula        E1RMFormula
+	IncludeWarmups bool
+	Location       *time.Location // Buckets start at midnight in this time zone
+}
+
 // ExerciseProgressResponse represents progress data for a specific time range
 type ExerciseProgressResponse struct {
-	ExerciseID   int64          `json:"exercise_id"`
-	ExerciseName string         `json:"exercise_name"`
-	Range        string         `json:"range"`
-	StartDate    time.Time      `json:"start_date"`
-	EndDate      time.Time      `json:"end_date"`
+	ExerciseID   int64               `json:"exercise_id"`
+	ExerciseName string              `json:"exercise_name"`
+	Range        string              `json:"range"`
+	Bucket       ProgressBucket      `json:"bucket"`
+	Window       int                 `json:"window"`   // Buckets spanned by the rolling averages
+	Timezone     string              `json:"timezone"` // Buckets start at midnight in the user's time zone
+	StartDate    time.Time           `json:"start_date"`
+	EndDate      time.Time           `json:"end_date"`
 	DataPoints   []ProgressDataPoint `json:"data_points"`
 	Summary      *ExerciseMetrics    `json:"summary,omitempty"`
 }
 
 // ProgressDataPoint represents a single data point in progress tracking
 type ProgressDataPoint struct {
-	Date         time.Time `json:"date"`
-	TotalVolume  float64   `json:"total_volume"`
-	MaxWeight    float64   `json:"max_weight"`
-	TotalSets    int       `json:"total_sets"`
-	AverageRPE   *float64  `json:"average_rpe,omitempty"`
+	Date               time.Time `json:"date"` // Start of the bucket in the user's time zone
+	TotalVolume        float64   `json:"total_volume"`
+	MaxWeight          float64   `json:"max_weight"`
+	TotalSets          int       `json:"total_sets"`
+	AverageRPE         *float64  `json:"average_rpe,omitempty"`
+	EstimatedOneRepMax float64   `json:"estimated_1rm"` // Best e1RM of the bucket
+	// RollingVolume is the average volume per bucket over the window ending with this one,
+	// RollingE1RM the average e1RM of the buckets with sets in that window
+	RollingVolume float64  `json:"rolling_volume"`
+	RollingE1RM   *float64 `json:"rolling_e1rm,omitempty"`
 }
-
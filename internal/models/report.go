@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Limits of progress reports
+const (
+	DefaultReportWeeks     = 12 // Weeks covered when from is not set
+	ReportTopExercises     = 10 // Exercises listed by volume
+	ReportChartedExercises = 6  // Top exercises with e1RM and volume charts
+	MaxReportDays          = 5 * 366
+)
+
+// ReportQuery holds the options of a progress report request
+type ReportQuery struct {
+	From     *time.Time // Inclusive
+	To       *time.Time // Exclusive
+	DateOnly bool       // From and To are calendar dates, read in the user's time zone
+	Formula  E1RMFormula
+}
+
+// WorkoutReport holds the contents of a printable workout sheet
+type WorkoutReport struct {
+	Workout   *Workout
+	Location  *time.Location // Times are printed in the user's time zone
+	Volume    float64
+	Exercises []WorkoutReportExercise // In order of first set
+}
+
+// WorkoutReportExercise represents an exercise of a workout sheet with its sets
+type WorkoutReportExercise struct {
+	Exercise *Exercise
+	Sets     []*Set // In workout order
+}
+
+// ProgressReport holds the contents of a printable progress report
+type ProgressReport struct {
+	Location     *time.Location
+	From         time.Time // Inclusive
+	To           time.Time // Exclusive
+	Bucket       ProgressBucket
+	Formula      E1RMFormula
+	Volume       []VolumeDataPoint // Every bucket of the range, including those without sets
+	TopExercises []ExerciseVolume
+	Exercises    []ExerciseReport // Charted exercises, highest volume first
+}
+
+// ExerciseReport represents the progress of one exercise in a progress report
+type ExerciseReport struct {
+	ExerciseID   int64
+	ExerciseName string
+	Summary      *ExerciseMetrics    // Nil when the range has no working sets
+	DataPoints   []ProgressDataPoint // Every bucket of the range
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// Login throttling policy. Failures are counted per account email and per client IP; once a
+// key reaches its free attempts, each further failure locks it out for twice as long as the last.
+const (
+	AccountFreeLoginAttempts = 5  // Failures of an account before it is locked out
+	IPFreeLoginAttempts      = 20 // Failures from an IP, across accounts, before it is locked out
+	LoginLockoutBase         = 30 * time.Second
+	LoginLockoutMax          = time.Hour
+	LoginFailureWindow       = 24 * time.Hour // Failures are forgotten after a day without any
+	SecurityEventRetention   = 90 * 24 * time.Hour
+	MaxUserAgentLength       = 512
+)
+
+// LoginLockout returns how long a key is locked out after a number of consecutive failures
+func LoginLockout(failures, freeAttempts int) time.Duration {
+	if failures < freeAttempts {
+		return 0
+	}
+	lockout := LoginLockoutBase
+	for i := freeAttempts; i < failures && lockout < LoginLockoutMax; i++ {
+		lockout *= 2
+	}
+	return min(lockout, LoginLockoutMax)
+}
+
+// LoginThrottle represents the failed logins of an account or IP
+type LoginThrottle struct {
+	Key           string     `db:"throttle_key"`
+	Failures      int        `db:"failures"`
+	LastFailureAt time.Time  `db:"last_failure_at"`
+	LockedUntil   *time.Time `db:"locked_until"`
+}
+
+// LoginAttempt is a login attempt counted against the throttles of its account and IP before
+// its password is checked
+type LoginAttempt struct {
+	Email    string
+	Client   *ClientInfo
+	Reserved []*LoginThrottle // Throttles the attempt was counted against, as they were after it
+}
+
+// SecurityEventType is the kind of a security event
+type SecurityEventType string
+
+// Security event types
+const (
+	EventLoginSucceeded SecurityEventType = "login_succeeded"
+	EventLoginFailed    SecurityEventType = "login_failed"
+	EventLoginBlocked   SecurityEventType = "login_blocked" // Attempted while locked out; the password was not checked
+)
+
+// SecurityEventSortFields are the sort fields accepted by the security event list
+var SecurityEventSortFields = []string{"created_at"}
+
+// SecurityEvent represents an entry of the audit trail of an account
+type SecurityEvent struct {
+	ID        int64             `json:"id" db:"id_security_event"`
+	UserID    *int64            `json:"-" db:"user_id"` // Nil for attempts on unknown emails
+	Type      SecurityEventType `json:"type" db:"event_type"`
+	Email     string            `json:"email" db:"email"` // Email the attempt was made with
+	IPAddress string            `json:"ip_address" db:"ip_address"`
+	UserAgent string            `json:"user_agent" db:"user_agent"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+}
+
+// ClientInfo identifies the client of a request in security events
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// RefreshToken represents a stored refresh token. Each use rotates it into a new token of
+// the same family, along with a new access token.
+type RefreshToken struct {
+	ID              int64      `db:"id_refresh_token"`
+	UserID          int64      `db:"user_id"`
+	TokenHash       string     `db:"token_hash"`
+	FamilyID        string     `db:"family_id"`  // Shared by the tokens rotated from one login
+	AccessJTI       string     `db:"access_jti"` // ID of the access token issued with this token
+	AccessExpiresAt time.Time  `db:"access_expires_at"`
+	ExpiresAt       time.Time  `db:"expires_at"`
+	UsedAt          *time.Time `db:"used_at"` // Set once rotated; a used token must not come back
+	RevokedAt       *time.Time `db:"revoked_at"`
+	CreatedAt       time.Time  `db:"created_at"`
+}
+
+// IsActive reports whether the token can still be exchanged at the given time
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return t.UsedAt == nil && t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// TokenPair represents the tokens of a session
+type TokenPair struct {
+	Token            string    `json:"token"` // Access token, sent as "Authorization: Bearer <token>"
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// LoginResponse represents the response of a successful login
+type LoginResponse struct {
+	*TokenPair
+	User *UserResponse `json:"user"`
+}
+
+// RefreshRequest represents the request body for exchanging a refresh token
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
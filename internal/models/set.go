@@ -6,51 +6,150 @@ import (
 
 // Set represents a training set
 type Set struct {
-	ID          int64     `json:"id" db:"id_set"`
-	WorkoutID   int64     `json:"workout_id" db:"workout_id"`
-	ExerciseID  int64     `json:"exercise_id" db:"exercise_id"`
-	Weight      float64   `json:"weight" db:"weight"`
-	Reps        int       `json:"reps" db:"reps"`
-	RestSeconds *int      `json:"rest_seconds,omitempty" db:"rest_seconds"`
-	Notes       *string   `json:"notes,omitempty" db:"notes"`
-	RPE         *int      `json:"rpe,omitempty" db:"rpe"` // Rate of Perceived Exertion (1-10)
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	ID          int64   `json:"id" db:"id_set"`
+	WorkoutID   int64   `json:"workout_id" db:"workout_id"`
+	ExerciseID  int64   `json:"exercise_id" db:"exercise_id"`
+	Weight      float64 `json:"weight" db:"weight"`
+	Reps        int     `json:"reps" db:"reps"`
+	RestSeconds *int    `json:"rest_seconds,omitempty" db:"rest_seconds"`
+	Notes       *string `json:"notes,omitempty" db:"notes"`
+	RPE         *int    `json:"rpe,omitempty" db:"rpe"` // Rate of Perceived Exertion (1-10)
+	// DistanceMeters and DurationSeconds are set for cardio and timed exercises
+	DistanceMeters  *float64   `json:"distance_meters,omitempty" db:"distance_meters"`
+	DurationSeconds *int       `json:"duration_seconds,omitempty" db:"duration_seconds"`
+	Type            SetType    `json:"type" db:"set_type"`
+	GroupID         *int       `json:"group_id,omitempty" db:"group_id"` // Sets sharing a group form a superset or circuit
+	Position        int        `json:"position" db:"position"`           // 1-based order within the workout
+	PerformedAt     time.Time  `json:"performed_at" db:"performed_at"`   // When the set was done, used for history and progress
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// IsWarmup reports whether the set is a warm-up, which metrics and records skip by default
+func (s *Set) IsWarmup() bool {
+	return s.Type == SetTypeWarmup
+}
+
+// SetType describes the role of a set within a workout
+type SetType string
+
+const (
+	SetTypeWarmup  SetType = "warmup"
+	SetTypeWorking SetType = "working"
+	SetTypeDrop    SetType = "drop"
+	SetTypeAMRAP   SetType = "amrap" // As many reps as possible
+	SetTypeFailure SetType = "failure"
+)
+
+// IsValid reports whether the set type is supported
+func (t SetType) IsValid() bool {
+	switch t {
+	case SetTypeWarmup, SetTypeWorking, SetTypeDrop, SetTypeAMRAP, SetTypeFailure:
+		return true
+	}
+	return false
+}
+
+// SetValues holds the measured fields shared by set requests
+type SetValues struct {
+	ExerciseID      int64      `json:"exercise_id" validate:"required"`
+	Weight          float64    `json:"weight" validate:"required,min=0"`
+	Reps            int        `json:"reps" validate:"min=0"` // Required by the exercise type, see ExerciseType.ValidateSet
+	RestSeconds     *int       `json:"rest_seconds,omitempty" validate:"omitempty,min=0"`
+	Notes           *string    `json:"notes,omitempty"`
+	RPE             *int       `json:"rpe,omitempty" validate:"omitempty,min=1,max=10"`
+	DistanceMeters  *float64   `json:"distance_meters,omitempty" validate:"omitempty,min=0"`
+	DurationSeconds *int       `json:"duration_seconds,omitempty" validate:"omitempty,min=0"`
+	Type            SetType    `json:"type,omitempty"` // Defaults to working
+	GroupID         *int       `json:"group_id,omitempty" validate:"omitempty,min=1"`
+	PerformedAt     *time.Time `json:"performed_at,omitempty"` // Defaults to now, or to the workout's performed_at if it was backdated
+}
+
+// Validate checks the ranges of the values and returns an error message. Requirements
+// that depend on the exercise type are checked by ExerciseType.ValidateSet.
+func (v *SetValues) Validate() string {
+	if v.Weight < 0 {
+		return "Weight must be non-negative"
+	}
+	if v.Reps < 0 {
+		return "Reps must be non-negative"
+	}
+	if v.RPE != nil && (*v.RPE < 1 || *v.RPE > 10) {
+		return "RPE must be between 1 and 10"
+	}
+	if v.DistanceMeters != nil && *v.DistanceMeters < 0 {
+		return "Distance must be non-negative"
+	}
+	if v.DurationSeconds != nil && *v.DurationSeconds < 0 {
+		return "Duration must be non-negative"
+	}
+	if v.Type != "" && !v.Type.IsValid() {
+		return "Invalid type. Must be 'warmup', 'working', 'drop', 'amrap', or 'failure'"
+	}
+	if v.GroupID != nil && *v.GroupID < 1 {
+		return "Group ID must be at least 1"
+	}
+	return ValidatePerformedAt(v.PerformedAt)
 }
 
 // SetCreateRequest represents the request body for creating a set
 type SetCreateRequest struct {
-	ExerciseID  int64   `json:"exercise_id" validate:"required"`
-	Weight      float64 `json:"weight" validate:"required,min=0"`
-	Reps        int     `json:"reps" validate:"required,min=1"`
-	RestSeconds *int    `json:"rest_seconds,omitempty" validate:"omitempty,min=0"`
-	Notes       *string `json:"notes,omitempty"`
-	RPE         *int    `json:"rpe,omitempty" validate:"omitempty,min=1,max=10"`
+	SetValues
+	Position *int `json:"position,omitempty" validate:"omitempty,min=1"` // Defaults to, and is capped at, after the last set
+}
+
+// MaxSetBatchSize is the largest number of sets accepted by a single batch request
+const MaxSetBatchSize = 100
+
+// SetUpdateRequest represents the request body for updating a set; every value is
+// replaced. The position is changed through the reorder endpoint.
+type SetUpdateRequest struct {
+	SetValues
+}
+
+// SetOrderRequest represents the request body for reordering the sets of a workout
+type SetOrderRequest struct {
+	SetIDs []int64 `json:"set_ids" validate:"required,min=1"` // Every set of the workout, in the new order
 }
 
 // SetResponse represents the set data returned in responses
 type SetResponse struct {
-	ID          int64     `json:"id"`
-	WorkoutID   int64     `json:"workout_id"`
-	ExerciseID  int64     `json:"exercise_id"`
-	Weight      float64   `json:"weight"`
-	Reps        int       `json:"reps"`
-	RestSeconds *int      `json:"rest_seconds,omitempty"`
-	Notes       *string   `json:"notes,omitempty"`
-	RPE         *int      `json:"rpe,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID              int64     `json:"id"`
+	WorkoutID       int64     `json:"workout_id"`
+	ExerciseID      int64     `json:"exercise_id"`
+	Weight          float64   `json:"weight"`
+	Reps            int       `json:"reps"`
+	RestSeconds     *int      `json:"rest_seconds,omitempty"`
+	Notes           *string   `json:"notes,omitempty"`
+	RPE             *int      `json:"rpe,omitempty"`
+	DistanceMeters  *float64  `json:"distance_meters,omitempty"`
+	DurationSeconds *int      `json:"duration_seconds,omitempty"`
+	Type            SetType   `json:"type"`
+	GroupID         *int      `json:"group_id,omitempty"`
+	Position        int       `json:"position"`
+	PerformedAt     time.Time `json:"performed_at"`
+	CreatedAt       time.Time `json:"created_at"`
+	// PersonalRecords lists the records this set broke (only set on creation)
+	PersonalRecords []PersonalRecordType `json:"personal_records,omitempty"`
 }
 
 // ToResponse converts a Set to SetResponse
 func (s *Set) ToResponse() *SetResponse {
 	return &SetResponse{
-		ID:          s.ID,
-		WorkoutID:   s.WorkoutID,
-		ExerciseID:  s.ExerciseID,
-		Weight:      s.Weight,
-		Reps:        s.Reps,
-		RestSeconds: s.RestSeconds,
-		Notes:       s.Notes,
-		RPE:         s.RPE,
-		CreatedAt:   s.CreatedAt,
+		ID:              s.ID,
+		WorkoutID:       s.WorkoutID,
+		ExerciseID:      s.ExerciseID,
+		Weight:          s.Weight,
+		Reps:            s.Reps,
+		RestSeconds:     s.RestSeconds,
+		Notes:           s.Notes,
+		RPE:             s.RPE,
+		DistanceMeters:  s.DistanceMeters,
+		DurationSeconds: s.DurationSeconds,
+		Type:            s.Type,
+		GroupID:         s.GroupID,
+		Position:        s.Position,
+		PerformedAt:     s.PerformedAt,
+		CreatedAt:       s.CreatedAt,
 	}
 }
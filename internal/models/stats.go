@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Limits of the dashboard summary
+const (
+	SummaryTrendWeeks    = 8 // Weeks covered by the volume trend and the top exercises
+	SummaryTopExercises  = 5
+	SummaryRecentRecords = 5
+)
+
+// TrainingDay is a calendar day in the user's time zone with at least one trained workout
+type TrainingDay struct {
+	Date     time.Time // Midnight in the user's time zone
+	Workouts int
+}
+
+// VolumeDataPoint represents the volume lifted across all exercises in a bucket
+type VolumeDataPoint struct {
+	Date        time.Time `json:"date"` // Start of the bucket in the user's time zone
+	TotalVolume float64   `json:"total_volume"`
+	TotalSets   int       `json:"total_sets"`
+}
+
+// ExerciseVolume represents the volume lifted in an exercise over a period
+type ExerciseVolume struct {
+	ExerciseID   int64   `json:"exercise_id"`
+	ExerciseName string  `json:"exercise_name"`
+	TotalVolume  float64 `json:"total_volume"`
+	TotalSets    int     `json:"total_sets"`
+}
+
+// MuscleGroupRecency reports when a muscle group was last trained as a primary muscle
+type MuscleGroupRecency struct {
+	Muscle        string     `json:"muscle"`
+	LastTrainedAt *time.Time `json:"last_trained_at"` // Null if never trained
+	DaysSince     *int       `json:"days_since"`      // Calendar days in the user's time zone
+}
+
+// UserSummaryResponse represents the training overview shown on the dashboard
+type UserSummaryResponse struct {
+	Timezone          string                    `json:"timezone"`
+	WorkoutsThisWeek  int                       `json:"workouts_this_week"` // ISO week, starting on Monday
+	WorkoutsThisMonth int                       `json:"workouts_this_month"`
+	TotalWorkouts     int                       `json:"total_workouts"`
+	CurrentStreak     int                       `json:"current_streak_weeks"` // Consecutive weeks with a workout
+	VolumeTrend       []VolumeDataPoint         `json:"volume_trend"`         // Weekly, oldest first
+	TopExercises      []ExerciseVolume          `json:"top_exercises"`
+	RecentRecords     []*PersonalRecordResponse `json:"recent_records"`
+	MuscleGroups      []MuscleGroupRecency      `json:"muscle_groups"`
+}
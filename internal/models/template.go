@@ -0,0 +1,105 @@
+package models
+
+import (
+	"time"
+)
+
+// WorkoutTemplate represents a reusable workout plan created by a user
+type WorkoutTemplate struct {
+	ID        int64               `json:"id" db:"id_template"`
+	UserID    int64               `json:"user_id" db:"user_id"`
+	Name      string              `json:"name" db:"name"`
+	Notes     *string             `json:"notes,omitempty" db:"notes"`
+	Exercises []*TemplateExercise `json:"exercises" db:"-"`
+	CreatedAt time.Time           `json:"created_at" db:"created_at"`
+	DeletedAt *time.Time          `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// TemplateExercise represents an exercise prescription within a template
+type TemplateExercise struct {
+	ID                int64    `json:"id" db:"id_template_exercise"`
+	TemplateID        int64    `json:"template_id" db:"template_id"`
+	ExerciseID        int64    `json:"exercise_id" db:"exercise_id"`
+	Position          int      `json:"position" db:"position"`
+	TargetSets        int      `json:"target_sets" db:"target_sets"`
+	TargetReps        int      `json:"target_reps" db:"target_reps"`
+	TargetWeight      *float64 `json:"target_weight,omitempty" db:"target_weight"`
+	TargetPercentE1RM *float64 `json:"target_percent_e1rm,omitempty" db:"target_percent_e1rm"` // e.g. 75 for 75% of e1RM
+	RestSeconds       *int     `json:"rest_seconds,omitempty" db:"rest_seconds"`
+}
+
+// TemplateExerciseRequest represents an exercise prescription in a template request
+type TemplateExerciseRequest struct {
+	ExerciseID        int64    `json:"exercise_id" validate:"required"`
+	TargetSets        int      `json:"target_sets" validate:"required,min=1"`
+	TargetReps        int      `json:"target_reps" validate:"required,min=1"`
+	TargetWeight      *float64 `json:"target_weight,omitempty" validate:"omitempty,min=0"`
+	TargetPercentE1RM *float64 `json:"target_percent_e1rm,omitempty" validate:"omitempty,gt=0"`
+	RestSeconds       *int     `json:"rest_seconds,omitempty" validate:"omitempty,min=0"`
+}
+
+// WorkoutTemplateCreateRequest represents the request body for creating a template.
+// Exercises are stored in the order they are sent.
+type WorkoutTemplateCreateRequest struct {
+	Name      string                    `json:"name" validate:"required,min=1"`
+	Notes     *string                   `json:"notes,omitempty"`
+	Exercises []TemplateExerciseRequest `json:"exercises" validate:"required,min=1"`
+}
+
+// WorkoutTemplateUpdateRequest represents the request body for updating a template.
+// The exercise list replaces the existing one.
+type WorkoutTemplateUpdateRequest struct {
+	Name      string                    `json:"name" validate:"required,min=1"`
+	Notes     *string                   `json:"notes,omitempty"`
+	Exercises []TemplateExerciseRequest `json:"exercises" validate:"required,min=1"`
+}
+
+// TemplateStartRequest represents the optional request body for starting a template
+type TemplateStartRequest struct {
+	Name *string `json:"name,omitempty"` // Defaults to the template name
+}
+
+// WorkoutTemplateResponse represents the template data returned in responses
+type WorkoutTemplateResponse struct {
+	ID        int64               `json:"id"`
+	UserID    int64               `json:"user_id"`
+	Name      string              `json:"name"`
+	Notes     *string             `json:"notes,omitempty"`
+	Exercises []*TemplateExercise `json:"exercises"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// ToResponse converts a WorkoutTemplate to WorkoutTemplateResponse
+func (t *WorkoutTemplate) ToResponse() *WorkoutTemplateResponse {
+	exercises := t.Exercises
+	if exercises == nil {
+		exercises = []*TemplateExercise{}
+	}
+	return &WorkoutTemplateResponse{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		Name:      t.Name,
+		Notes:     t.Notes,
+		Exercises: exercises,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+// PlannedSet represents a prescribed set slot in a workout that has not been performed yet
+type PlannedSet struct {
+	ID           int64     `json:"id" db:"id_planned_set"`
+	WorkoutID    int64     `json:"workout_id" db:"workout_id"`
+	ExerciseID   int64     `json:"exercise_id" db:"exercise_id"`
+	Position     int       `json:"position" db:"position"`
+	TargetReps   int       `json:"target_reps" db:"target_reps"`
+	TargetWeight *float64  `json:"target_weight,omitempty" db:"target_weight"`
+	RestSeconds  *int      `json:"rest_seconds,omitempty" db:"rest_seconds"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// StartedWorkoutResponse represents a workout created from a template with its planned sets
+type StartedWorkoutResponse struct {
+	Workout     *WorkoutResponse `json:"workout"`
+	TemplateID  int64            `json:"template_id"`
+	PlannedSets []*PlannedSet    `json:"planned_sets"`
+}
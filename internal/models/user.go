@@ -6,16 +6,31 @@ import (
 
 // User represents a user in the system
 type User struct {
-	ID        int64     `json:"id" db:"id_user"`
-	Email     string    `json:"email" db:"email"`
-	Password  string    `json:"-" db:"password"` // Never return password in JSON
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID                  int64      `json:"id" db:"id_user"`
+	Email               string     `json:"email" db:"email"`
+	Password            string     `json:"-" db:"password"`              // Never return password in JSON
+	Timezone            string     `json:"timezone" db:"timezone"`       // IANA name, e.g. America/Bogota
+	VerifiedAt          *time.Time `json:"verified_at" db:"verified_at"` // Nil until the email address is verified
+	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at" db:"deletion_scheduled_at"` // Purge date of an account being deleted
+}
+
+// DefaultTimezone is the time zone of users who have not set one
+const DefaultTimezone = "UTC"
+
+// ValidateTimezone checks that a time zone is a known IANA name and returns an error message
+func ValidateTimezone(name string) string {
+	if _, err := time.LoadLocation(name); err != nil || name == "" || name == "Local" {
+		return "Invalid timezone. Must be an IANA name such as 'America/New_York'"
+	}
+	return ""
 }
 
 // UserCreateRequest represents the request body for creating a user
 type UserCreateRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=8"`
+	Timezone string `json:"timezone,omitempty"` // Defaults to UTC
 }
 
 // UserLoginRequest represents the request body for login
@@ -24,19 +39,46 @@ type UserLoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// UserProfileUpdateRequest represents the request body for updating the profile; omitted fields are left unchanged
+type UserProfileUpdateRequest struct {
+	Timezone *string `json:"timezone,omitempty"`
+}
+
+// PasswordChangeRequest represents the request body for changing the password
+type PasswordChangeRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8"`
+}
+
+// EmailChangeRequest represents the request body for changing the email address
+type EmailChangeRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"` // Current password
+}
+
+// AccountDeleteRequest represents the request body for deleting the account
+type AccountDeleteRequest struct {
+	Password string `json:"password" validate:"required"` // Current password
+}
+
 // UserResponse represents the user data returned in responses
 type UserResponse struct {
-	ID        int64     `json:"id"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
+	ID                  int64      `json:"id"`
+	Email               string     `json:"email"`
+	Timezone            string     `json:"timezone"`
+	VerifiedAt          *time.Time `json:"verified_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"` // Set while the account is being deleted
 }
 
 // ToResponse converts a User to UserResponse
 func (u *User) ToResponse() *UserResponse {
 	return &UserResponse{
-		ID:        u.ID,
-		Email:     u.Email,
-		CreatedAt: u.CreatedAt,
+		ID:                  u.ID,
+		Email:               u.Email,
+		Timezone:            u.Timezone,
+		VerifiedAt:          u.VerifiedAt,
+		CreatedAt:           u.CreatedAt,
+		DeletionScheduledAt: u.DeletionScheduledAt,
 	}
 }
-
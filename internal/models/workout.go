@@ -6,38 +6,124 @@ import (
 
 // Workout represents a workout session
 type Workout struct {
-	ID        int64     `json:"id" db:"id_workout"`
-	UserID    int64     `json:"user_id" db:"user_id"`
-	Name      string    `json:"name" db:"name"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	ID         int64         `json:"id" db:"id_workout"`
+	UserID     int64         `json:"user_id" db:"user_id"`
+	Name       string        `json:"name" db:"name"`
+	Status     WorkoutStatus `json:"status" db:"status"`
+	StartedAt  *time.Time    `json:"started_at,omitempty" db:"started_at"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty" db:"finished_at"`
+	Bodyweight *float64      `json:"bodyweight,omitempty" db:"bodyweight"`
+	SessionRPE *int          `json:"session_rpe,omitempty" db:"session_rpe"` // How hard the whole session felt (1-10)
+	Notes      *string       `json:"notes,omitempty" db:"notes"`
+	// PerformedAt is when the session took place, which differs from CreatedAt for backdated workouts
+	PerformedAt time.Time  `json:"performed_at" db:"performed_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// DurationSeconds returns the length of a finished session, or nil if it was never
+// started or has not finished
+func (w *Workout) DurationSeconds() *int {
+	if w.StartedAt == nil || w.FinishedAt == nil {
+		return nil
+	}
+	seconds := int(w.FinishedAt.Sub(*w.StartedAt).Seconds())
+	return &seconds
+}
+
+// WorkoutStatus is the state of a workout session
+type WorkoutStatus string
+
+const (
+	WorkoutStatusPlanned    WorkoutStatus = "planned"
+	WorkoutStatusInProgress WorkoutStatus = "in_progress"
+	WorkoutStatusCompleted  WorkoutStatus = "completed"
+	WorkoutStatusAbandoned  WorkoutStatus = "abandoned"
+)
+
+// CanTransitionTo reports whether a session in this state can move to the next one.
+// Planned sessions start, started sessions complete, and either can be abandoned.
+func (s WorkoutStatus) CanTransitionTo(next WorkoutStatus) bool {
+	switch next {
+	case WorkoutStatusInProgress:
+		return s == WorkoutStatusPlanned
+	case WorkoutStatusCompleted:
+		return s == WorkoutStatusInProgress
+	case WorkoutStatusAbandoned:
+		return s == WorkoutStatusPlanned || s == WorkoutStatusInProgress
+	}
+	return false
+}
+
+// MaxClockSkew is how far in the future a client-supplied performed_at may be
+const MaxClockSkew = 5 * time.Minute
+
+// ValidatePerformedAt checks a client-supplied performed_at and returns an error message
+func ValidatePerformedAt(performedAt *time.Time) string {
+	if performedAt != nil && performedAt.After(time.Now().Add(MaxClockSkew)) {
+		return "Performed at cannot be in the future"
+	}
+	return ""
 }
 
 // WorkoutCreateRequest represents the request body for creating a workout
 type WorkoutCreateRequest struct {
-	Name string `json:"name" validate:"required,min=1"`
+	Name        string     `json:"name" validate:"required,min=1"`
+	PerformedAt *time.Time `json:"performed_at,omitempty"` // Defaults to now; set it to log a past session
 }
 
 // WorkoutUpdateRequest represents the request body for updating a workout
 type WorkoutUpdateRequest struct {
-	Name string `json:"name" validate:"required,min=1"`
+	Name        string     `json:"name" validate:"required,min=1"`
+	PerformedAt *time.Time `json:"performed_at,omitempty"` // Left unchanged when omitted
+}
+
+// WorkoutStartRequest represents the optional request body for starting a workout
+type WorkoutStartRequest struct {
+	Bodyweight *float64 `json:"bodyweight,omitempty" validate:"omitempty,gt=0"`
+}
+
+// WorkoutFinishRequest represents the optional request body for finishing a workout
+type WorkoutFinishRequest struct {
+	Status     WorkoutStatus `json:"status,omitempty"` // completed (default) or abandoned
+	Bodyweight *float64      `json:"bodyweight,omitempty" validate:"omitempty,gt=0"`
+	SessionRPE *int          `json:"session_rpe,omitempty" validate:"omitempty,min=1,max=10"`
+	Notes      *string       `json:"notes,omitempty"`
 }
 
 // WorkoutResponse represents the workout data returned in responses
 type WorkoutResponse struct {
-	ID        int64     `json:"id"`
-	UserID    int64     `json:"user_id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
+	ID              int64         `json:"id"`
+	UserID          int64         `json:"user_id"`
+	Name            string        `json:"name"`
+	Status          WorkoutStatus `json:"status"`
+	StartedAt       *time.Time    `json:"started_at,omitempty"`
+	FinishedAt      *time.Time    `json:"finished_at,omitempty"`
+	DurationSeconds *int          `json:"duration_seconds,omitempty"`
+	Bodyweight      *float64      `json:"bodyweight,omitempty"`
+	SessionRPE      *int          `json:"session_rpe,omitempty"`
+	Notes           *string       `json:"notes,omitempty"`
+	// Volume and Density (volume per minute) are only set on single workout responses
+	Volume      *float64  `json:"volume,omitempty"`
+	Density     *float64  `json:"density,omitempty"`
+	PerformedAt time.Time `json:"performed_at"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 // ToResponse converts a Workout to WorkoutResponse
 func (w *Workout) ToResponse() *WorkoutResponse {
 	return &WorkoutResponse{
-		ID:        w.ID,
-		UserID:    w.UserID,
-		Name:      w.Name,
-		CreatedAt: w.CreatedAt,
+		ID:              w.ID,
+		UserID:          w.UserID,
+		Name:            w.Name,
+		Status:          w.Status,
+		StartedAt:       w.StartedAt,
+		FinishedAt:      w.FinishedAt,
+		DurationSeconds: w.DurationSeconds(),
+		Bodyweight:      w.Bodyweight,
+		SessionRPE:      w.SessionRPE,
+		Notes:           w.Notes,
+		PerformedAt:     w.PerformedAt,
+		CreatedAt:       w.CreatedAt,
 	}
 }
-
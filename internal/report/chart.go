@@ -0,0 +1,118 @@
+package report
+
+import (
+	"math"
+)
+
+// Chart layout, in millimeters
+const (
+	chartHeight    = 42.0 // Plot area
+	chartAxisWidth = 14.0 // Room for the value labels left of the plot
+	chartMaxLabels = 8    // Date labels under the plot
+)
+
+// chart is a bar or line chart with one value per period
+type chart struct {
+	title  string
+	labels []string // One per value
+	values []float64
+	line   bool // Connect the periods with values instead of drawing bars
+}
+
+// drawChart renders the chart with its top left corner at x, y and returns its total height
+func (d *document) drawChart(c chart, x, y, width float64) float64 {
+	pdf := d.pdf
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetXY(x, y)
+	pdf.CellFormat(width, 5, d.tr(c.title), "", 0, "L", false, 0, "")
+
+	plotX, plotY := x+chartAxisWidth, y+7
+	plotWidth := width - chartAxisWidth
+
+	top := niceCeiling(maxValue(c.values))
+	if top == 0 {
+		pdf.SetXY(plotX, plotY+chartHeight/2-3)
+		pdf.SetFont("Helvetica", "I", 9)
+		d.setColor(muted)
+		pdf.CellFormat(plotWidth, 6, "No data in this period", "", 0, "C", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+		return chartHeight + 14
+	}
+
+	// Grid lines with their values at zero, half and the top of the scale
+	pdf.SetFont("Helvetica", "", 7)
+	pdf.SetLineWidth(0.1)
+	for i := 0; i <= 2; i++ {
+		value := top * float64(i) / 2
+		lineY := plotY + chartHeight - chartHeight*float64(i)/2
+		pdf.SetDrawColor(gridColor[0], gridColor[1], gridColor[2])
+		pdf.Line(plotX, lineY, plotX+plotWidth, lineY)
+		pdf.SetXY(x, lineY-2)
+		pdf.CellFormat(chartAxisWidth-1.5, 4, formatCompact(value), "", 0, "R", false, 0, "")
+	}
+
+	slot := plotWidth / float64(len(c.values))
+	pointX := func(i int) float64 { return plotX + slot*(float64(i)+0.5) }
+	pointY := func(v float64) float64 { return plotY + chartHeight - chartHeight*v/top }
+
+	pdf.SetFillColor(accent[0], accent[1], accent[2])
+	pdf.SetDrawColor(accent[0], accent[1], accent[2])
+	if c.line {
+		// Periods without sets have no value and are skipped rather than drawn as zero
+		pdf.SetLineWidth(0.5)
+		previous := -1
+		for i, v := range c.values {
+			if v <= 0 {
+				continue
+			}
+			if previous >= 0 {
+				pdf.Line(pointX(previous), pointY(c.values[previous]), pointX(i), pointY(v))
+			}
+			pdf.Circle(pointX(i), pointY(v), 0.7, "F")
+			previous = i
+		}
+	} else {
+		barWidth := math.Min(slot*0.7, 12)
+		for i, v := range c.values {
+			if v > 0 {
+				pdf.Rect(pointX(i)-barWidth/2, pointY(v), barWidth, plotY+chartHeight-pointY(v), "F")
+			}
+		}
+	}
+	pdf.SetLineWidth(0.2)
+	pdf.SetDrawColor(0, 0, 0)
+
+	// Date labels under evenly spaced periods
+	pdf.SetFont("Helvetica", "", 7)
+	step := (len(c.labels) + chartMaxLabels - 1) / chartMaxLabels
+	labelWidth := slot * float64(step)
+	for i := 0; i < len(c.labels); i += step {
+		pdf.SetXY(pointX(i)-labelWidth/2, plotY+chartHeight+1)
+		pdf.CellFormat(labelWidth, 4, d.tr(c.labels[i]), "", 0, "C", false, 0, "")
+	}
+
+	return chartHeight + 14
+}
+
+// maxValue returns the largest of values, or 0
+func maxValue(values []float64) float64 {
+	top := 0.0
+	for _, v := range values {
+		top = math.Max(top, v)
+	}
+	return top
+}
+
+// niceCeiling rounds v up to 1, 2, 2.5 or 5 times a power of ten, for a readable scale
+func niceCeiling(v float64) float64 {
+	if v <= 0 {
+		return 0
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, step := range []float64{1, 2, 2.5, 5, 10} {
+		if v <= step*magnitude {
+			return step * magnitude
+		}
+	}
+	return 10 * magnitude
+}
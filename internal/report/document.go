@@ -0,0 +1,241 @@
+// Package report renders workout sheets and progress reports as PDF documents.
+// Documents are drawn with the PDF core fonts, so no font files or external tools are needed.
+package report
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Page layout, in millimeters
+const (
+	pageMargin = 15.0
+	lineHeight = 6.0
+	rowHeight  = 6.5
+)
+
+// Colors of the documents
+var (
+	headerFill = [3]int{230, 233, 238}
+	accent     = [3]int{214, 84, 38}
+	muted      = [3]int{120, 120, 120}
+	gridColor  = [3]int{210, 210, 210}
+)
+
+// document wraps an A4 PDF with the building blocks shared by the reports
+type document struct {
+	pdf    *fpdf.Fpdf
+	tr     func(string) string // Translates UTF-8 into the code page of the core fonts
+	width  float64             // Width between the margins
+	bottom float64             // Lowest y content may reach
+}
+
+// newDocument starts a document with a title and numbered pages
+func newDocument(title string) *document {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(true, pageMargin)
+	pdf.SetTitle(title, true)
+	pdf.SetCreator("Phoenix Alliance", true)
+	pdf.AliasNbPages("")
+
+	pageWidth, pageHeight := pdf.GetPageSize()
+	d := &document{
+		pdf:    pdf,
+		tr:     pdf.UnicodeTranslatorFromDescriptor(""),
+		width:  pageWidth - 2*pageMargin,
+		bottom: pageHeight - pageMargin,
+	}
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pageMargin + 4)
+		d.setColor(muted)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(0, 5, d.tr(fmt.Sprintf("%s - page %d of {nb}", title, pdf.PageNo())), "", 0, "C", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+	pdf.AddPage()
+	return d
+}
+
+// write renders the document to w
+func (d *document) write(w io.Writer) error {
+	if err := d.pdf.Error(); err != nil {
+		return err
+	}
+	return d.pdf.Output(w)
+}
+
+// ensureSpace starts a new page unless h millimeters fit below the current position
+func (d *document) ensureSpace(h float64) {
+	if d.pdf.GetY()+h > d.bottom {
+		d.pdf.AddPage()
+	}
+}
+
+func (d *document) setColor(c [3]int) {
+	d.pdf.SetTextColor(c[0], c[1], c[2])
+}
+
+// title writes the document heading
+func (d *document) title(text string) {
+	d.pdf.SetFont("Helvetica", "B", 18)
+	d.pdf.MultiCell(0, 9, d.tr(text), "", "L", false)
+	d.pdf.Ln(2)
+}
+
+// section writes a section heading, kept on the same page as the next minHeight millimeters
+func (d *document) section(text string, minHeight float64) {
+	d.ensureSpace(10 + minHeight)
+	d.pdf.Ln(3)
+	d.pdf.SetFont("Helvetica", "B", 13)
+	d.pdf.CellFormat(0, 7, d.tr(text), "B", 1, "L", false, 0, "")
+	d.pdf.Ln(2)
+}
+
+// paragraph writes wrapped text
+func (d *document) paragraph(text string) {
+	d.pdf.SetFont("Helvetica", "", 10)
+	d.pdf.MultiCell(0, 5, d.tr(text), "", "L", false)
+	d.pdf.Ln(1)
+}
+
+// note writes a line of small gray text
+func (d *document) note(text string) {
+	d.pdf.SetFont("Helvetica", "I", 9)
+	d.setColor(muted)
+	d.pdf.MultiCell(0, 5, d.tr(text), "", "L", false)
+	d.pdf.SetTextColor(0, 0, 0)
+}
+
+// field is a labeled value of a summary block
+type field struct {
+	label string
+	value string
+}
+
+// fields writes labeled values in two columns, leaving out empty values
+func (d *document) fields(fields []field) {
+	var shown []field
+	for _, f := range fields {
+		if f.value != "" {
+			shown = append(shown, f)
+		}
+	}
+
+	columnWidth := d.width / 2
+	for i, f := range shown {
+		d.pdf.SetFont("Helvetica", "B", 10)
+		d.pdf.CellFormat(32, lineHeight, d.tr(f.label), "", 0, "L", false, 0, "")
+		d.pdf.SetFont("Helvetica", "", 10)
+		ln := 0
+		if i%2 == 1 || i == len(shown)-1 {
+			ln = 1
+		}
+		d.pdf.CellFormat(columnWidth-32, lineHeight, d.tr(d.fit(f.value, columnWidth-33)), "", ln, "L", false, 0, "")
+	}
+}
+
+// column describes a table column; widths are fractions of the page width
+type column struct {
+	title string
+	width float64
+	align string
+}
+
+// table writes rows under a header that is repeated on every page the table spans
+func (d *document) table(columns []column, rows [][]string) {
+	header := func() {
+		d.pdf.SetFont("Helvetica", "B", 9)
+		d.pdf.SetFillColor(headerFill[0], headerFill[1], headerFill[2])
+		for _, c := range columns {
+			d.pdf.CellFormat(c.width*d.width, rowHeight, d.tr(c.title), "B", 0, c.align, true, 0, "")
+		}
+		d.pdf.Ln(-1)
+	}
+
+	d.ensureSpace(2 * rowHeight)
+	header()
+	d.pdf.SetFont("Helvetica", "", 9)
+	for _, row := range rows {
+		if d.pdf.GetY()+rowHeight > d.bottom {
+			d.pdf.AddPage()
+			header()
+			d.pdf.SetFont("Helvetica", "", 9)
+		}
+		for i, c := range columns {
+			width := c.width * d.width
+			d.pdf.CellFormat(width, rowHeight, d.tr(d.fit(row[i], width-2)), "B", 0, c.align, false, 0, "")
+		}
+		d.pdf.Ln(-1)
+	}
+	d.pdf.Ln(2)
+}
+
+// fit shortens text with an ellipsis until it is at most width millimeters wide in the current font
+func (d *document) fit(text string, width float64) string {
+	if d.pdf.GetStringWidth(d.tr(text)) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && d.pdf.GetStringWidth(d.tr(string(runes)+"…")) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// formatNumber formats a number with at most two decimals and thousands separators
+func formatNumber(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+
+	whole, decimals, _ := strings.Cut(s, ".")
+	sign := ""
+	if strings.HasPrefix(whole, "-") {
+		sign, whole = "-", whole[1:]
+	}
+	for i := len(whole) - 3; i > 0; i -= 3 {
+		whole = whole[:i] + "," + whole[i:]
+	}
+	if decimals != "" {
+		return sign + whole + "." + decimals
+	}
+	return sign + whole
+}
+
+// formatCompact formats an axis value, abbreviating thousands and millions
+func formatCompact(v float64) string {
+	switch {
+	case v >= 1e6:
+		return formatNumber(v/1e6) + "M"
+	case v >= 1e4:
+		return formatNumber(v/1e3) + "k"
+	}
+	return formatNumber(v)
+}
+
+// formatDuration formats seconds as h:mm:ss, or m:ss under an hour
+func formatDuration(seconds int) string {
+	h, m, s := seconds/3600, seconds/60%60, seconds%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
+
+// formatDistance formats meters, switching to kilometers from 1 km
+func formatDistance(meters float64) string {
+	if meters >= 1000 {
+		return formatNumber(meters/1000) + " km"
+	}
+	return formatNumber(meters) + " m"
+}
+
+// formatDate formats the calendar date of t
+func formatDate(t time.Time) string {
+	return t.Format("2 Jan 2006")
+}
@@ -0,0 +1,110 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+// topExerciseColumns are the columns of the table of exercises by volume
+var topExerciseColumns = []column{
+	{"Exercise", 0.52, "L"}, {"Sets", 0.14, "R"}, {"Volume", 0.2, "R"}, {"Share", 0.14, "R"},
+}
+
+// chartGap separates charts drawn side by side, in millimeters
+const chartGap = 8.0
+
+// WriteProgress renders a progress report as a PDF to w: the volume of every period, the
+// exercises by volume, and the e1RM and volume charts of the top exercises
+func WriteProgress(w io.Writer, report *models.ProgressReport) error {
+	d := newDocument("Progress report")
+	d.title("Progress report")
+
+	totalVolume, totalSets := 0.0, 0
+	for _, point := range report.Volume {
+		totalVolume += point.TotalVolume
+		totalSets += point.TotalSets
+	}
+	// To is exclusive, so the period ends on the day before
+	last := report.To.Add(-time.Nanosecond)
+	d.fields([]field{
+		{"Period", formatDate(report.From) + " – " + formatDate(last)},
+		{"Time zone", report.Location.String()},
+		{"Grouped by", string(report.Bucket)},
+		{"e1RM formula", string(report.Formula)},
+		{"Working sets", strconv.Itoa(totalSets)},
+		{"Volume", formatNumber(totalVolume)},
+	})
+
+	labels := make([]string, len(report.Volume))
+	values := make([]float64, len(report.Volume))
+	for i, point := range report.Volume {
+		labels[i] = bucketLabel(point.Date, report.Bucket)
+		values[i] = point.TotalVolume
+	}
+	d.section("Volume", chartHeight+14)
+	height := d.drawChart(chart{title: fmt.Sprintf("Volume per %s", report.Bucket), labels: labels, values: values}, pageMargin, d.pdf.GetY(), d.width)
+	d.pdf.SetXY(pageMargin, d.pdf.GetY()+height)
+
+	d.section("Exercises by volume", 2*rowHeight)
+	if len(report.TopExercises) == 0 {
+		d.note("No working sets with weight and reps were logged in this period.")
+	}
+	rows := make([][]string, len(report.TopExercises))
+	for i, exercise := range report.TopExercises {
+		share := ""
+		if totalVolume > 0 {
+			share = fmt.Sprintf("%.0f%%", 100*exercise.TotalVolume/totalVolume)
+		}
+		rows[i] = []string{exercise.ExerciseName, strconv.Itoa(exercise.TotalSets), formatNumber(exercise.TotalVolume), share}
+	}
+	if len(rows) > 0 {
+		d.table(topExerciseColumns, rows)
+	}
+
+	for _, exercise := range report.Exercises {
+		writeExerciseProgress(d, &exercise, report.Bucket)
+	}
+
+	return d.write(w)
+}
+
+// writeExerciseProgress writes the summary of an exercise with its e1RM and volume charts side by side
+func writeExerciseProgress(d *document, exercise *models.ExerciseReport, bucket models.ProgressBucket) {
+	d.section(exercise.ExerciseName, lineHeight+chartHeight+14)
+	if summary := exercise.Summary; summary != nil {
+		d.fields([]field{
+			{"Best e1RM", formatNumber(summary.EstimatedOneRepMax)},
+			{"Max weight", formatNumber(summary.MaxWeight)},
+			{"Working sets", strconv.Itoa(summary.TotalSets)},
+			{"Volume", formatNumber(summary.TotalVolume)},
+		})
+		d.pdf.Ln(2)
+	}
+
+	labels := make([]string, len(exercise.DataPoints))
+	e1rm := make([]float64, len(exercise.DataPoints))
+	volume := make([]float64, len(exercise.DataPoints))
+	for i, point := range exercise.DataPoints {
+		labels[i] = bucketLabel(point.Date, bucket)
+		e1rm[i] = point.EstimatedOneRepMax
+		volume[i] = point.TotalVolume
+	}
+
+	width := (d.width - chartGap) / 2
+	y := d.pdf.GetY()
+	height := d.drawChart(chart{title: "Estimated 1RM", labels: labels, values: e1rm, line: true}, pageMargin, y, width)
+	d.drawChart(chart{title: "Volume", labels: labels, values: volume}, pageMargin+width+chartGap, y, width)
+	d.pdf.SetXY(pageMargin, y+height)
+}
+
+// bucketLabel names the period starting at t on a chart axis
+func bucketLabel(t time.Time, bucket models.ProgressBucket) string {
+	if bucket == models.ProgressBucketMonth {
+		return t.Format("Jan 06")
+	}
+	return t.Format("2 Jan")
+}
@@ -0,0 +1,98 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+func TestWriteWorkout(t *testing.T) {
+	started := time.Date(2024, 5, 13, 18, 0, 0, 0, time.UTC)
+	finished := started.Add(65 * time.Minute)
+	rpe, rest, distance, duration := 8, 180, 5200.0, 1800
+	notes := "Paused reps with a very long note that has to be cut to fit in its column of the table"
+
+	var sets []*models.Set
+	for i := 0; i < 60; i++ {
+		sets = append(sets, &models.Set{Weight: 100, Reps: 5, RPE: &rpe, RestSeconds: &rest, Notes: &notes, Type: models.SetTypeWorking})
+	}
+	report := &models.WorkoutReport{
+		Workout: &models.Workout{
+			Name: "Push – heavy", Status: models.WorkoutStatusCompleted,
+			StartedAt: &started, FinishedAt: &finished, PerformedAt: started,
+		},
+		Location: time.UTC,
+		Volume:   30000,
+		Exercises: []models.WorkoutReportExercise{
+			{Exercise: &models.Exercise{Name: "Bench Press", Type: models.ExerciseTypeWeight}, Sets: sets},
+			{Exercise: &models.Exercise{Name: "Running", Type: models.ExerciseTypeCardio}, Sets: []*models.Set{
+				{DistanceMeters: &distance, DurationSeconds: &duration, Type: models.SetTypeWorking},
+			}},
+		},
+	}
+
+	var out bytes.Buffer
+	if err := WriteWorkout(&out, report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(out.Bytes(), []byte("%PDF-")) {
+		t.Fatal("expected a PDF document")
+	}
+	// 60 sets do not fit on one page
+	if pages := bytes.Count(out.Bytes(), []byte("/Type /Page\n")); pages < 2 {
+		t.Errorf("expected the sets to span pages, got %d", pages)
+	}
+}
+
+func TestWriteProgress(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	report := &models.ProgressReport{
+		Location: time.UTC,
+		From:     from,
+		To:       from.AddDate(0, 0, 84),
+		Bucket:   models.ProgressBucketWeek,
+		Formula:  models.E1RMFormulaEpley,
+		TopExercises: []models.ExerciseVolume{
+			{ExerciseID: 1, ExerciseName: "Squat", TotalVolume: 36000, TotalSets: 24},
+		},
+	}
+	exercise := models.ExerciseReport{ExerciseID: 1, ExerciseName: "Squat", Summary: &models.ExerciseMetrics{TotalSets: 24, EstimatedOneRepMax: 160}}
+	for i := 0; i < 12; i++ {
+		week := from.AddDate(0, 0, 7*i)
+		volume := 0.0
+		if i%3 != 1 {
+			volume = 3000 + 100*float64(i)
+		}
+		report.Volume = append(report.Volume, models.VolumeDataPoint{Date: week, TotalVolume: volume, TotalSets: 2})
+		exercise.DataPoints = append(exercise.DataPoints, models.ProgressDataPoint{Date: week, TotalVolume: volume, EstimatedOneRepMax: volume / 20})
+	}
+	report.Exercises = []models.ExerciseReport{exercise, {ExerciseID: 2, ExerciseName: "Empty"}}
+
+	var out bytes.Buffer
+	if err := WriteProgress(&out, report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(out.Bytes(), []byte("%PDF-")) {
+		t.Fatal("expected a PDF document")
+	}
+}
+
+func TestNiceCeiling(t *testing.T) {
+	tests := map[float64]float64{0: 0, 0.8: 1, 1: 1, 1.2: 2, 2.2: 2.5, 4100: 5000, 7300: 10000, 101: 200}
+	for v, want := range tests {
+		if got := niceCeiling(v); got != want {
+			t.Errorf("niceCeiling(%v) = %v; want %v", v, got, want)
+		}
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := map[float64]string{0: "0", 102.5: "102.5", 1234567.891: "1,234,567.89", -4500: "-4,500", 999: "999"}
+	for v, want := range tests {
+		if got := formatNumber(v); got != want {
+			t.Errorf("formatNumber(%v) = %q; want %q", v, got, want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+// Columns of the set tables of a workout sheet, for exercises that count reps and for the others
+var (
+	repSetColumns = []column{
+		{"#", 0.06, "C"}, {"Type", 0.12, "L"}, {"Weight", 0.12, "R"}, {"Reps", 0.09, "R"},
+		{"RPE", 0.08, "R"}, {"Rest", 0.1, "R"}, {"Notes", 0.43, "L"},
+	}
+	durationSetColumns = []column{
+		{"#", 0.06, "C"}, {"Type", 0.12, "L"}, {"Weight", 0.12, "R"}, {"Distance", 0.13, "R"},
+		{"Time", 0.11, "R"}, {"RPE", 0.08, "R"}, {"Notes", 0.38, "L"},
+	}
+)
+
+// WriteWorkout renders the sheet of a workout, with its sets grouped by exercise, as a PDF to w
+func WriteWorkout(w io.Writer, report *models.WorkoutReport) error {
+	workout := report.Workout
+	d := newDocument(workout.Name)
+	d.title(workout.Name)
+
+	totalSets := 0
+	for _, exercise := range report.Exercises {
+		totalSets += len(exercise.Sets)
+	}
+	summary := []field{
+		{"Date", workout.PerformedAt.In(report.Location).Format("Monday 2 January 2006, 15:04 MST")},
+		{"Status", string(workout.Status)},
+		{"Exercises", strconv.Itoa(len(report.Exercises))},
+		{"Sets", strconv.Itoa(totalSets)},
+		{"Volume", formatNumber(report.Volume)},
+	}
+	if duration := workout.DurationSeconds(); duration != nil {
+		summary = append(summary, field{"Duration", formatDuration(*duration)})
+	}
+	if workout.Bodyweight != nil {
+		summary = append(summary, field{"Bodyweight", formatNumber(*workout.Bodyweight)})
+	}
+	if workout.SessionRPE != nil {
+		summary = append(summary, field{"Session RPE", strconv.Itoa(*workout.SessionRPE)})
+	}
+	d.fields(summary)
+	if workout.Notes != nil && *workout.Notes != "" {
+		d.pdf.Ln(2)
+		d.paragraph(*workout.Notes)
+	}
+
+	if len(report.Exercises) == 0 {
+		d.pdf.Ln(4)
+		d.note("No sets were logged in this workout.")
+	}
+	for _, exercise := range report.Exercises {
+		d.section(exercise.Exercise.Name, 2*rowHeight)
+		if exercise.Exercise.Type.CountsReps() {
+			d.table(repSetColumns, repSetRows(exercise.Sets))
+		} else {
+			d.table(durationSetColumns, durationSetRows(exercise.Sets))
+		}
+	}
+
+	return d.write(w)
+}
+
+// repSetRows returns the table rows of sets measured in weight and reps
+func repSetRows(sets []*models.Set) [][]string {
+	rows := make([][]string, len(sets))
+	for i, set := range sets {
+		rest := ""
+		if set.RestSeconds != nil {
+			rest = formatDuration(*set.RestSeconds)
+		}
+		rows[i] = []string{
+			strconv.Itoa(i + 1),
+			string(set.Type),
+			formatNumber(set.Weight),
+			strconv.Itoa(set.Reps),
+			optionalInt(set.RPE),
+			rest,
+			optionalString(set.Notes),
+		}
+	}
+	return rows
+}
+
+// durationSetRows returns the table rows of sets measured in distance and time
+func durationSetRows(sets []*models.Set) [][]string {
+	rows := make([][]string, len(sets))
+	for i, set := range sets {
+		weight, distance, duration := "", "", ""
+		if set.Weight > 0 {
+			weight = formatNumber(set.Weight)
+		}
+		if set.DistanceMeters != nil {
+			distance = formatDistance(*set.DistanceMeters)
+		}
+		if set.DurationSeconds != nil {
+			duration = formatDuration(*set.DurationSeconds)
+		}
+		rows[i] = []string{
+			strconv.Itoa(i + 1),
+			string(set.Type),
+			weight,
+			distance,
+			duration,
+			optionalInt(set.RPE),
+			optionalString(set.Notes),
+		}
+	}
+	return rows
+}
+
+// optionalInt formats an optional integer, empty when nil
+func optionalInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(*v)
+}
+
+// optionalString returns an optional string, empty when nil
+func optionalString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
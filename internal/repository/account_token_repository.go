@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+// AccountTokenRepository defines the interface for password reset and email verification token data operations
+type AccountTokenRepository interface {
+	Create(token *models.AccountToken) error
+	Consume(purpose models.AccountTokenPurpose, tokenHash string, now time.Time) (*models.AccountToken, error)
+	ResetPassword(tokenHash, passwordHash string, now time.Time) (*models.AccountToken, error)
+	InvalidateForUser(userID int64, purpose models.AccountTokenPurpose, now time.Time) error
+	IssuedSince(userID int64, purpose models.AccountTokenPurpose, since, now time.Time) (bool, error)
+	DeleteExpired(before time.Time) error
+}
+
+type accountTokenRepository struct {
+	db *sql.DB
+}
+
+// NewAccountTokenRepository creates a new account token repository
+func NewAccountTokenRepository(db *sql.DB) AccountTokenRepository {
+	return &accountTokenRepository{db: db}
+}
+
+// Create stores a new account token
+func (r *accountTokenRepository) Create(token *models.AccountToken) error {
+	query := `
+		INSERT INTO account_tokens (user_id, purpose, token_hash, email, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id_account_token
+	`
+
+	return r.db.QueryRow(
+		query,
+		token.UserID,
+		token.Purpose,
+		token.TokenHash,
+		token.Email,
+		token.ExpiresAt,
+		token.CreatedAt,
+	).Scan(&token.ID)
+}
+
+// Consume marks a token as used and returns it, provided it has the purpose, is unused and has
+// not expired. Of two concurrent uses of the same token only one succeeds.
+func (r *accountTokenRepository) Consume(purpose models.AccountTokenPurpose, tokenHash string, now time.Time) (*models.AccountToken, error) {
+	return consumeAccountToken(r.db, purpose, tokenHash, now)
+}
+
+// ResetPassword uses up a password reset token and sets the new password of its user in one
+// transaction, so that the token is only used up once the password is changed. Receiving the
+// link proves the user owns the address, which is marked as verified. A token sent to an
+// address the user has changed since is not found.
+func (r *accountTokenRepository) ResetPassword(tokenHash, passwordHash string, now time.Time) (*models.AccountToken, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	token, err := consumeAccountToken(tx, models.PurposePasswordReset, tokenHash, now)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE users
+		SET password = $1, verified_at = COALESCE(verified_at, $2)
+		WHERE id_user = $3 AND email = $4
+	`
+	result, err := tx.Exec(query, passwordHash, now, token.UserID, token.Email)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rows == 0 {
+		return nil, errors.New("account token not found")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// consumeAccountToken marks a token as used with the database or a transaction
+func consumeAccountToken(q rowQuerier, purpose models.AccountTokenPurpose, tokenHash string, now time.Time) (*models.AccountToken, error) {
+	query := `
+		UPDATE account_tokens
+		SET used_at = $1
+		WHERE token_hash = $2 AND purpose = $3 AND used_at IS NULL AND expires_at > $1
+		RETURNING id_account_token, user_id, purpose, token_hash, email, expires_at, used_at, created_at
+	`
+
+	token := &models.AccountToken{}
+	err := q.QueryRow(query, now, tokenHash, purpose).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Purpose,
+		&token.TokenHash,
+		&token.Email,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("account token not found")
+		}
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// InvalidateForUser marks the unused tokens of a user for a purpose as used, so that only the
+// latest link sent works
+func (r *accountTokenRepository) InvalidateForUser(userID int64, purpose models.AccountTokenPurpose, now time.Time) error {
+	query := `
+		UPDATE account_tokens
+		SET used_at = $1
+		WHERE user_id = $2 AND purpose = $3 AND used_at IS NULL
+	`
+
+	_, err := r.db.Exec(query, now, userID, purpose)
+	return err
+}
+
+// IssuedSince reports whether a user was issued a token for a purpose since a time that is
+// still unused and unexpired
+func (r *accountTokenRepository) IssuedSince(userID int64, purpose models.AccountTokenPurpose, since, now time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM account_tokens
+			WHERE user_id = $1 AND purpose = $2 AND created_at > $3 AND used_at IS NULL AND expires_at > $4
+		)
+	`
+
+	var issued bool
+	err := r.db.QueryRow(query, userID, purpose, since, now).Scan(&issued)
+	return issued, err
+}
+
+// DeleteExpired drops the tokens that expired before a time
+func (r *accountTokenRepository) DeleteExpired(before time.Time) error {
+	_, err := r.db.Exec(`DELETE FROM account_tokens WHERE expires_at < $1`, before)
+	return err
+}
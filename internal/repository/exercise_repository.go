@@ -3,8 +3,11 @@ package repository
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 
 	"phoenix-alliance-be/internal/models"
+
+	"github.com/lib/pq"
 )
 
 // ExerciseRepository defines the interface for exercise data operations
@@ -13,8 +16,18 @@ type ExerciseRepository interface {
 	GetByID(id int64) (*models.Exercise, error)
 	GetByUserID(userID int64) ([]*models.Exercise, error)
 	GetByIDAndUserID(id, userID int64) (*models.Exercise, error)
+	List(userID int64, filter *models.ExerciseFilter) ([]*models.Exercise, string, error)
 	Update(exercise *models.Exercise) error
 	Delete(id, userID int64) error
+	ListCatalog(filter *models.ExerciseFilter) ([]*models.Exercise, string, error)
+	Adopt(userID, exerciseID int64, alias *string) error
+	Unadopt(userID, exerciseID int64) error
+}
+
+// exerciseSortColumns are the columns exercises can be sorted by
+var exerciseSortColumns = map[string]sortColumn{
+	"created_at": {column: "created_at", sqlType: "timestamptz"},
+	"name":       {column: "name", sqlType: "text"},
 }
 
 type exerciseRepository struct {
@@ -26,46 +39,86 @@ func NewExerciseRepository(db *sql.DB) ExerciseRepository {
 	return &exerciseRepository{db: db}
 }
 
+// exerciseColumns is the column list read by scanExercise when selecting from the exercises table
+const exerciseColumns = `id_exercise, COALESCE(user_id, 0), name, CASE WHEN user_id IS NULL THEN name END, exercise_type, primary_muscles, secondary_muscles, equipment, movement_pattern, created_at, deleted_at`
+
+// libraryColumns is the column list read by scanExercise when selecting from exerciseLibrary
+const libraryColumns = `id_exercise, user_id, name, catalog_name, exercise_type, primary_muscles, secondary_muscles, equipment, movement_pattern, created_at, deleted_at`
+
+// exerciseLibrary returns a subquery of the exercises a user can reference: their own and
+// every catalog entry. Adopted catalog entries take the user's alias and adoption time and,
+// together with the user's own exercises, are flagged in_library.
+func exerciseLibrary(userParam string) string {
+	return fmt.Sprintf(`(
+		SELECT e.id_exercise, COALESCE(e.user_id, 0) AS user_id, COALESCE(a.alias, e.name) AS name,
+			CASE WHEN e.user_id IS NULL THEN e.name END AS catalog_name,
+			e.exercise_type, e.primary_muscles, e.secondary_muscles, e.equipment, e.movement_pattern,
+			COALESCE(a.created_at, e.created_at) AS created_at, e.deleted_at,
+			(e.user_id IS NOT NULL OR a.user_id IS NOT NULL) AS in_library
+		FROM exercises e
+		LEFT JOIN user_catalog_exercises a ON a.exercise_id = e.id_exercise AND a.user_id = %[1]s
+		WHERE (e.user_id = %[1]s OR e.user_id IS NULL) AND e.deleted_at IS NULL
+	) AS library`, userParam)
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanExercise scans the exerciseColumns of a row, followed by any extra columns
+func scanExercise(row rowScanner, extra ...interface{}) (*models.Exercise, error) {
+	exercise := &models.Exercise{}
+	dest := []interface{}{
+		&exercise.ID,
+		&exercise.UserID,
+		&exercise.Name,
+		&exercise.CatalogName,
+		&exercise.Type,
+		pq.Array(&exercise.PrimaryMuscles),
+		pq.Array(&exercise.SecondaryMuscles),
+		&exercise.Equipment,
+		&exercise.MovementPattern,
+		&exercise.CreatedAt,
+		&exercise.DeletedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return exercise, nil
+}
+
 // Create creates a new exercise
 func (r *exerciseRepository) Create(exercise *models.Exercise) error {
 	query := `
-		INSERT INTO exercises (user_id, name, created_at)
-		VALUES ($1, $2, $3)
-		RETURNING id_exercise, user_id, name, created_at
-	`
+		INSERT INTO exercises (user_id, name, exercise_type, primary_muscles, secondary_muscles, equipment, movement_pattern, created_at)
+		VALUES ($1, $2, $3, COALESCE($4::text[], '{}'), COALESCE($5::text[], '{}'), $6, $7, $8)
+		RETURNING ` + exerciseColumns
 
-	err := r.db.QueryRow(
+	created, err := scanExercise(r.db.QueryRow(
 		query,
 		exercise.UserID,
 		exercise.Name,
+		exercise.Type,
+		pq.Array(exercise.PrimaryMuscles),
+		pq.Array(exercise.SecondaryMuscles),
+		exercise.Equipment,
+		exercise.MovementPattern,
 		exercise.CreatedAt,
-	).Scan(
-		&exercise.ID,
-		&exercise.UserID,
-		&exercise.Name,
-		&exercise.CreatedAt,
-	)
-
+	))
 	if err != nil {
 		return err
 	}
 
+	*exercise = *created
 	return nil
 }
 
 // GetByID retrieves an exercise by ID (only non-deleted)
 func (r *exerciseRepository) GetByID(id int64) (*models.Exercise, error) {
-	exercise := &models.Exercise{}
-	query := `SELECT id_exercise, user_id, name, created_at, deleted_at FROM exercises WHERE id_exercise = $1 AND deleted_at IS NULL`
-
-	err := r.db.QueryRow(query, id).Scan(
-		&exercise.ID,
-		&exercise.UserID,
-		&exercise.Name,
-		&exercise.CreatedAt,
-		&exercise.DeletedAt,
-	)
+	query := `SELECT ` + exerciseColumns + ` FROM exercises WHERE id_exercise = $1 AND deleted_at IS NULL`
 
+	exercise, err := scanExercise(r.db.QueryRow(query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors.New("exercise not found")
@@ -76,12 +129,13 @@ func (r *exerciseRepository) GetByID(id int64) (*models.Exercise, error) {
 	return exercise, nil
 }
 
-// GetByUserID retrieves all exercises for a user (only non-deleted)
+// GetByUserID retrieves all exercises for a user, merging the ones they created with the
+// catalog entries they adopted (only non-deleted)
 func (r *exerciseRepository) GetByUserID(userID int64) ([]*models.Exercise, error) {
 	query := `
-		SELECT id_exercise, user_id, name, created_at, deleted_at
-		FROM exercises 
-		WHERE user_id = $1 AND deleted_at IS NULL
+		SELECT ` + libraryColumns + `
+		FROM ` + exerciseLibrary("$1") + `
+		WHERE in_library
 		ORDER BY created_at DESC
 	`
 
@@ -93,14 +147,7 @@ func (r *exerciseRepository) GetByUserID(userID int64) ([]*models.Exercise, erro
 
 	var exercises []*models.Exercise
 	for rows.Next() {
-		exercise := &models.Exercise{}
-		err := rows.Scan(
-			&exercise.ID,
-			&exercise.UserID,
-			&exercise.Name,
-			&exercise.CreatedAt,
-			&exercise.DeletedAt,
-		)
+		exercise, err := scanExercise(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -110,19 +157,97 @@ func (r *exerciseRepository) GetByUserID(userID int64) ([]*models.Exercise, erro
 	return exercises, rows.Err()
 }
 
-// GetByIDAndUserID retrieves an exercise by ID and ensures it belongs to the user (only non-deleted)
-func (r *exerciseRepository) GetByIDAndUserID(id, userID int64) (*models.Exercise, error) {
-	exercise := &models.Exercise{}
-	query := `SELECT id_exercise, user_id, name, created_at, deleted_at FROM exercises WHERE id_exercise = $1 AND user_id = $2 AND deleted_at IS NULL`
+// List retrieves a page of a user's exercises, including adopted catalog entries, matching the
+// filter and the cursor of the next page
+func (r *exerciseRepository) List(userID int64, filter *models.ExerciseFilter) ([]*models.Exercise, string, error) {
+	b := &queryBuilder{}
+	from := exerciseLibrary(b.arg(userID))
+	b.conditions = append(b.conditions, "in_library")
+	return r.list(libraryColumns, from, b, filter)
+}
 
-	err := r.db.QueryRow(query, id, userID).Scan(
-		&exercise.ID,
-		&exercise.UserID,
-		&exercise.Name,
-		&exercise.CreatedAt,
-		&exercise.DeletedAt,
-	)
+// ListCatalog retrieves a page of catalog exercises matching the filter and the cursor of the next page
+func (r *exerciseRepository) ListCatalog(filter *models.ExerciseFilter) ([]*models.Exercise, string, error) {
+	b := &queryBuilder{}
+	b.conditions = append(b.conditions, "user_id IS NULL AND deleted_at IS NULL")
+	return r.list(exerciseColumns, "exercises", b, filter)
+}
+
+// list applies the exercise filter and keyset pagination on top of the base conditions
+func (r *exerciseRepository) list(columns, from string, b *queryBuilder, filter *models.ExerciseFilter) ([]*models.Exercise, string, error) {
+	page, err := newKeyset(&filter.ListParams, exerciseSortColumns, "id_exercise")
+	if err != nil {
+		return nil, "", err
+	}
+
+	if filter.From != nil {
+		b.where("created_at >= %s", *filter.From)
+	}
+	if filter.To != nil {
+		b.where("created_at < %s", *filter.To)
+	}
+	if filter.Search != "" {
+		b.where("name ILIKE %s", likePattern(filter.Search))
+	}
+	if filter.Type != "" {
+		b.where("exercise_type = %s", filter.Type)
+	}
+	if filter.Muscle != "" {
+		muscle := pq.Array([]string{filter.Muscle})
+		b.where("(primary_muscles && %[1]s OR secondary_muscles && %[1]s)", muscle)
+	}
+	if filter.Equipment != "" {
+		b.where("equipment = %s", filter.Equipment)
+	}
+	if filter.MovementPattern != "" {
+		b.where("movement_pattern = %s", filter.MovementPattern)
+	}
+	page.apply(b)
+
+	query := fmt.Sprintf(`
+		SELECT %s, %s
+		FROM %s
+		%s
+		%s
+	`, columns, page.selectValue(), from, b.whereClause(), page.orderBy(b))
+
+	rows, err := r.db.Query(query, b.args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var exercises []*models.Exercise
+	var sortValues []string
+	for rows.Next() {
+		var sortValue string
+		exercise, err := scanExercise(rows, &sortValue)
+		if err != nil {
+			return nil, "", err
+		}
+		exercises = append(exercises, exercise)
+		sortValues = append(sortValues, sortValue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if page.hasMore(len(exercises)) {
+		exercises = exercises[:filter.Limit]
+		last := len(exercises) - 1
+		next = page.nextCursor(sortValues[last], exercises[last].ID)
+	}
+
+	return exercises, next, nil
+}
+
+// GetByIDAndUserID retrieves an exercise by ID and ensures the user can reference it: either
+// they created it or it is a catalog entry, under their alias if adopted (only non-deleted)
+func (r *exerciseRepository) GetByIDAndUserID(id, userID int64) (*models.Exercise, error) {
+	query := `SELECT ` + libraryColumns + ` FROM ` + exerciseLibrary("$2") + ` WHERE id_exercise = $1`
 
+	exercise, err := scanExercise(r.db.QueryRow(query, id, userID))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors.New("exercise not found")
@@ -137,24 +262,22 @@ func (r *exerciseRepository) GetByIDAndUserID(id, userID int64) (*models.Exercis
 func (r *exerciseRepository) Update(exercise *models.Exercise) error {
 	query := `
 		UPDATE exercises 
-		SET name = $1
-		WHERE id_exercise = $2 AND user_id = $3 AND deleted_at IS NULL
-		RETURNING id_exercise, user_id, name, created_at, deleted_at
-	`
+		SET name = $1, exercise_type = $2, primary_muscles = COALESCE($3::text[], '{}'), secondary_muscles = COALESCE($4::text[], '{}'),
+			equipment = $5, movement_pattern = $6
+		WHERE id_exercise = $7 AND user_id = $8 AND deleted_at IS NULL
+		RETURNING ` + exerciseColumns
 
-	err := r.db.QueryRow(
+	updated, err := scanExercise(r.db.QueryRow(
 		query,
 		exercise.Name,
+		exercise.Type,
+		pq.Array(exercise.PrimaryMuscles),
+		pq.Array(exercise.SecondaryMuscles),
+		exercise.Equipment,
+		exercise.MovementPattern,
 		exercise.ID,
 		exercise.UserID,
-	).Scan(
-		&exercise.ID,
-		&exercise.UserID,
-		&exercise.Name,
-		&exercise.CreatedAt,
-		&exercise.DeletedAt,
-	)
-
+	))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return errors.New("exercise not found")
@@ -162,6 +285,7 @@ func (r *exerciseRepository) Update(exercise *models.Exercise) error {
 		return err
 	}
 
+	*exercise = *updated
 	return nil
 }
 
@@ -189,3 +313,51 @@ func (r *exerciseRepository) Delete(id, userID int64) error {
 
 	return nil
 }
+
+// Adopt adds a catalog exercise to a user's library, or updates the alias if already adopted
+func (r *exerciseRepository) Adopt(userID, exerciseID int64, alias *string) error {
+	query := `
+		INSERT INTO user_catalog_exercises (user_id, exercise_id, alias)
+		SELECT $1::bigint, id_exercise, $3::varchar
+		FROM exercises
+		WHERE id_exercise = $2 AND user_id IS NULL AND deleted_at IS NULL
+		ON CONFLICT (user_id, exercise_id) DO UPDATE SET alias = EXCLUDED.alias
+	`
+
+	result, err := r.db.Exec(query, userID, exerciseID, alias)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("exercise not found")
+	}
+
+	return nil
+}
+
+// Unadopt removes a catalog exercise from a user's library; logged sets are kept
+func (r *exerciseRepository) Unadopt(userID, exerciseID int64) error {
+	query := `DELETE FROM user_catalog_exercises WHERE user_id = $1 AND exercise_id = $2`
+
+	result, err := r.db.Exec(query, userID, exerciseID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("exercise not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"database/sql"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+// ExportRepository defines the interface for reading a user's full training log. Rows are
+// passed to fn one at a time as they are read, so that exports do not hold the log in memory;
+// an error returned by fn stops the export.
+type ExportRepository interface {
+	StreamExercises(userID int64, fn func(exercise *models.Exercise) error) error
+	StreamWorkouts(userID int64, fn func(row *models.ExportRow) error) error
+}
+
+type exportRepository struct {
+	db *sql.DB
+}
+
+// NewExportRepository creates a new export repository
+func NewExportRepository(db *sql.DB) ExportRepository {
+	return &exportRepository{db: db}
+}
+
+// exportSetColumns are the set columns of an export row, read from an outer join on sets.
+// Columns that are not nullable default to zero values, with an id_set of 0, when a workout
+// has no sets.
+const exportSetColumns = `COALESCE(s.id_set, 0), w.id_workout, COALESCE(s.exercise_id, 0), COALESCE(s.weight, 0), COALESCE(s.reps, 0), s.rest_seconds, s.notes, s.rpe, s.distance_meters, s.duration_seconds, COALESCE(s.set_type, 'working'), s.group_id, COALESCE(s.position, 0), COALESCE(s.performed_at, w.performed_at), COALESCE(s.created_at, w.created_at), s.deleted_at`
+
+// StreamExercises passes the user's exercises to fn, along with the catalog and deleted
+// exercises their sets refer to, by ID. Adopted catalog entries take the user's alias, as
+// they do in the API.
+func (r *exportRepository) StreamExercises(userID int64, fn func(exercise *models.Exercise) error) error {
+	query := `
+		SELECT e.id_exercise, COALESCE(e.user_id, 0), COALESCE(a.alias, e.name), CASE WHEN e.user_id IS NULL THEN e.name END,
+		       e.exercise_type, e.primary_muscles, e.secondary_muscles, e.equipment, e.movement_pattern,
+		       COALESCE(a.created_at, e.created_at), e.deleted_at
+		FROM exercises e
+		LEFT JOIN user_catalog_exercises a ON a.exercise_id = e.id_exercise AND a.user_id = $1
+		WHERE (e.user_id = $1 AND e.deleted_at IS NULL) OR e.id_exercise IN (
+			SELECT s.exercise_id
+			FROM sets s
+			INNER JOIN workouts w ON s.workout_id = w.id_workout
+			WHERE w.user_id = $1 AND s.deleted_at IS NULL AND w.deleted_at IS NULL
+		)
+		ORDER BY e.id_exercise
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		exercise, err := scanExercise(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(exercise); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// StreamWorkouts passes the user's workouts to fn with each of their sets in turn, oldest
+// workout first and sets in workout order, named as in the API. A workout without sets is
+// passed once with a nil set.
+func (r *exportRepository) StreamWorkouts(userID int64, fn func(row *models.ExportRow) error) error {
+	query := `
+		SELECT
+			w.id_workout, w.user_id, w.name, w.status, w.started_at, w.finished_at, w.bodyweight,
+			w.session_rpe, w.notes, w.performed_at, w.created_at, w.deleted_at,
+			` + exportSetColumns + `,
+			COALESCE(a.alias, e.name, '')
+		FROM workouts w
+		LEFT JOIN sets s ON s.workout_id = w.id_workout AND s.deleted_at IS NULL
+		LEFT JOIN exercises e ON s.exercise_id = e.id_exercise
+		LEFT JOIN user_catalog_exercises a ON a.exercise_id = s.exercise_id AND a.user_id = w.user_id
+		WHERE w.user_id = $1 AND w.deleted_at IS NULL
+		ORDER BY w.performed_at, w.id_workout, s.position
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := &models.ExportRow{}
+		workout := &models.Workout{}
+		set := &models.Set{}
+		err := rows.Scan(
+			&workout.ID,
+			&workout.UserID,
+			&workout.Name,
+			&workout.Status,
+			&workout.StartedAt,
+			&workout.FinishedAt,
+			&workout.Bodyweight,
+			&workout.SessionRPE,
+			&workout.Notes,
+			&workout.PerformedAt,
+			&workout.CreatedAt,
+			&workout.DeletedAt,
+			&set.ID,
+			&set.WorkoutID,
+			&set.ExerciseID,
+			&set.Weight,
+			&set.Reps,
+			&set.RestSeconds,
+			&set.Notes,
+			&set.RPE,
+			&set.DistanceMeters,
+			&set.DurationSeconds,
+			&set.Type,
+			&set.GroupID,
+			&set.Position,
+			&set.PerformedAt,
+			&set.CreatedAt,
+			&set.DeletedAt,
+			&row.ExerciseName,
+		)
+		if err != nil {
+			return err
+		}
+
+		row.Workout = workout
+		if set.ID != 0 {
+			row.Set = set
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
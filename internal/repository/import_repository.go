@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// ImportRepository defines the interface for storing training history imported from other apps
+type ImportRepository interface {
+	FindExercises(userID int64, names []string) ([]*models.Exercise, error)
+	FindWorkouts(userID int64, performedAt []time.Time) ([]*models.Workout, error)
+	Create(userID int64, batch *models.ImportBatch) error
+}
+
+type importRepository struct {
+	db *sql.DB
+}
+
+// NewImportRepository creates a new import repository
+func NewImportRepository(db *sql.DB) ImportRepository {
+	return &importRepository{db: db}
+}
+
+// FindExercises retrieves the exercises a user can reference whose name or catalog name matches
+// one of the lowercase names, those in the user's library first
+func (r *importRepository) FindExercises(userID int64, names []string) ([]*models.Exercise, error) {
+	query := `
+		SELECT ` + libraryColumns + `
+		FROM ` + exerciseLibrary("$1") + `
+		WHERE LOWER(name) = ANY($2) OR LOWER(catalog_name) = ANY($2)
+		ORDER BY in_library DESC, id_exercise
+	`
+
+	rows, err := r.db.Query(query, userID, pq.Array(names))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exercises []*models.Exercise
+	for rows.Next() {
+		exercise, err := scanExercise(rows)
+		if err != nil {
+			return nil, err
+		}
+		exercises = append(exercises, exercise)
+	}
+
+	return exercises, rows.Err()
+}
+
+// FindWorkouts retrieves a user's workouts performed at any of the given times (only non-deleted)
+func (r *importRepository) FindWorkouts(userID int64, performedAt []time.Time) ([]*models.Workout, error) {
+	query := `SELECT ` + workoutColumns + ` FROM workouts WHERE user_id = $1 AND performed_at = ANY($2::timestamptz[]) AND deleted_at IS NULL`
+
+	times := make([]string, len(performedAt))
+	for i, t := range performedAt {
+		times[i] = t.Format(time.RFC3339Nano)
+	}
+
+	rows, err := r.db.Query(query, userID, pq.Array(times))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var workouts []*models.Workout
+	for rows.Next() {
+		workout, err := scanWorkout(rows)
+		if err != nil {
+			return nil, err
+		}
+		workouts = append(workouts, workout)
+	}
+
+	return workouts, rows.Err()
+}
+
+// Create stores an import in a single transaction: it creates the new exercises, adds the
+// catalog exercises used to the user's library, and creates the workouts with their sets.
+// Either everything is stored or nothing is.
+func (r *importRepository) Create(userID int64, batch *models.ImportBatch) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, exercise := range batch.Exercises {
+		err := tx.QueryRow(`
+			INSERT INTO exercises (user_id, name, exercise_type, primary_muscles, secondary_muscles, created_at)
+			VALUES ($1, $2, $3, '{}', '{}', $4)
+			RETURNING id_exercise
+		`, userID, exercise.Name, exercise.Type, exercise.CreatedAt).Scan(&exercise.ID)
+		if err != nil {
+			return err
+		}
+		exercise.UserID = userID
+	}
+
+	adopted := make(map[int64]bool)
+	insertSet, err := tx.Prepare(`
+		INSERT INTO sets (workout_id, exercise_id, weight, reps, rest_seconds, notes, rpe, distance_meters, duration_seconds, set_type, group_id, position, performed_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`)
+	if err != nil {
+		return err
+	}
+	defer insertSet.Close()
+
+	for _, imported := range batch.Workouts {
+		workout := imported.Workout
+		err := tx.QueryRow(`
+			INSERT INTO workouts (user_id, name, status, started_at, finished_at, notes, performed_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id_workout
+		`, userID, workout.Name, workout.Status, workout.StartedAt, workout.FinishedAt, workout.Notes, workout.PerformedAt, workout.CreatedAt).Scan(&workout.ID)
+		if err != nil {
+			return err
+		}
+		workout.UserID = userID
+
+		for _, importedSet := range imported.Sets {
+			set := importedSet.Set
+			set.WorkoutID = workout.ID
+			set.ExerciseID = importedSet.Exercise.ID
+
+			if importedSet.Exercise.IsCatalog() && !adopted[set.ExerciseID] {
+				_, err := tx.Exec(`
+					INSERT INTO user_catalog_exercises (user_id, exercise_id)
+					VALUES ($1, $2)
+					ON CONFLICT (user_id, exercise_id) DO NOTHING
+				`, userID, set.ExerciseID)
+				if err != nil {
+					return err
+				}
+				adopted[set.ExerciseID] = true
+			}
+
+			_, err := insertSet.Exec(
+				set.WorkoutID,
+				set.ExerciseID,
+				set.Weight,
+				set.Reps,
+				set.RestSeconds,
+				set.Notes,
+				set.RPE,
+				set.DistanceMeters,
+				set.DurationSeconds,
+				set.Type,
+				set.GroupID,
+				set.Position,
+				set.PerformedAt,
+				set.CreatedAt,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
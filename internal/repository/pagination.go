@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+// sortColumn maps a whitelisted sort field to its column and SQL type
+type sortColumn struct {
+	column  string
+	sqlType string
+}
+
+// queryBuilder accumulates WHERE conditions and their positional arguments
+type queryBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+// arg registers an argument and returns its placeholder
+func (b *queryBuilder) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// where adds a condition; %s in the condition is replaced by the argument placeholder
+func (b *queryBuilder) where(condition string, value interface{}) {
+	b.conditions = append(b.conditions, fmt.Sprintf(condition, b.arg(value)))
+}
+
+// whereClause joins the conditions into a WHERE clause
+func (b *queryBuilder) whereClause() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(b.conditions, " AND ")
+}
+
+// keyset implements cursor pagination over a sort column with the row ID as tie-breaker
+type keyset struct {
+	params   *models.ListParams
+	sort     sortColumn
+	idColumn string
+}
+
+// newKeyset resolves the sort field of params against the whitelisted columns
+func newKeyset(params *models.ListParams, columns map[string]sortColumn, idColumn string) (*keyset, error) {
+	sort, ok := columns[params.Sort]
+	if !ok {
+		return nil, errors.New("invalid sort field")
+	}
+	if params.Cursor != nil && params.Cursor.Sort != params.SortKey() {
+		return nil, errors.New("invalid cursor")
+	}
+	return &keyset{params: params, sort: sort, idColumn: idColumn}, nil
+}
+
+// selectValue is the select expression of the sort value stored in cursors
+func (k *keyset) selectValue() string {
+	return k.sort.column + "::text"
+}
+
+// apply adds the condition that skips every row up to the cursor
+func (k *keyset) apply(b *queryBuilder) {
+	cursor := k.params.Cursor
+	if cursor == nil {
+		return
+	}
+
+	op := ">"
+	if k.params.Desc {
+		op = "<"
+	}
+	value := b.arg(cursor.Value)
+	id := b.arg(cursor.ID)
+	b.conditions = append(b.conditions, fmt.Sprintf("(%s, %s) %s (%s::%s, %s)", k.sort.column, k.idColumn, op, value, k.sort.sqlType, id))
+}
+
+// orderBy returns the ORDER BY and LIMIT clauses; one extra row is fetched to detect a next page
+func (k *keyset) orderBy(b *queryBuilder) string {
+	direction := "ASC"
+	if k.params.Desc {
+		direction = "DESC"
+	}
+
+	clause := fmt.Sprintf("ORDER BY %s %s, %s %s", k.sort.column, direction, k.idColumn, direction)
+	if k.params.Limit > 0 {
+		clause += " LIMIT " + b.arg(k.params.Limit+1)
+	}
+	return clause
+}
+
+// hasMore reports whether the fetched rows extend past the requested page
+func (k *keyset) hasMore(count int) bool {
+	return k.params.Limit > 0 && count > k.params.Limit
+}
+
+// nextCursor returns the cursor pointing after the row with the given sort value and ID
+func (k *keyset) nextCursor(value string, id int64) string {
+	cursor := &models.Cursor{Sort: k.params.SortKey(), Value: value, ID: id}
+	return cursor.Encode()
+}
+
+// likePattern builds a substring ILIKE pattern, escaping wildcards in the search term
+func likePattern(search string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(search) + "%"
+}
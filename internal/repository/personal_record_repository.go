@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"database/sql"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+// PersonalRecordRepository defines the interface for personal record data operations
+type PersonalRecordRepository interface {
+	Create(record *models.PersonalRecord) error
+	GetByUserID(userID int64, currentOnly bool) ([]*models.PersonalRecord, error)
+	GetByExerciseIDAndUserID(exerciseID, userID int64, currentOnly bool) ([]*models.PersonalRecord, error)
+	DeleteBySetID(setID int64) error
+	ReplaceForExercise(userID, exerciseID int64, records []*models.PersonalRecord) error
+}
+
+// recomputeCurrent marks as current, among the records of a user's exercise, the latest of
+// each kind, and for rep records the latest of each weight
+const recomputeCurrent = `
+	UPDATE personal_records p
+	SET is_current = NOT EXISTS (
+		SELECT 1 FROM personal_records q
+		WHERE q.user_id = p.user_id AND q.exercise_id = p.exercise_id AND q.record_type = p.record_type
+		  AND (q.achieved_at, q.id_personal_record) > (p.achieved_at, p.id_personal_record)
+		  AND (p.record_type <> 'max_reps' OR q.weight = p.weight)
+	)
+	WHERE p.user_id = $1 AND p.exercise_id = $2
+`
+
+type personalRecordRepository struct {
+	db *sql.DB
+}
+
+// NewPersonalRecordRepository creates a new personal record repository
+func NewPersonalRecordRepository(db *sql.DB) PersonalRecordRepository {
+	return &personalRecordRepository{db: db}
+}
+
+// Create stores a new personal record and marks the previous record of the same
+// kind as no longer current. Rep records are kept per weight and only supersede the
+// record of the same weight. A volume record replaces the one of its workout, so that
+// a workout keeps a single volume record however many of its sets raised it.
+func (r *personalRecordRepository) Create(record *models.PersonalRecord) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if record.RecordType == models.PersonalRecordMaxVolume {
+		_, err := tx.Exec(
+			`DELETE FROM personal_records WHERE user_id = $1 AND exercise_id = $2 AND record_type = $3 AND workout_id = $4`,
+			record.UserID, record.ExerciseID, record.RecordType, record.WorkoutID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	supersede := `
+		UPDATE personal_records
+		SET is_current = FALSE
+		WHERE user_id = $1 AND exercise_id = $2 AND record_type = $3 AND is_current
+	`
+	args := []interface{}{record.UserID, record.ExerciseID, record.RecordType}
+	if record.RecordType == models.PersonalRecordMaxReps {
+		supersede += ` AND weight = $4`
+		args = append(args, record.Weight)
+	}
+
+	if _, err := tx.Exec(supersede, args...); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO personal_records (user_id, exercise_id, workout_id, set_id, record_type, value, weight, reps, previous_value, is_current, achieved_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, TRUE, $10, $11)
+		RETURNING id_personal_record, is_current, created_at
+	`
+
+	err = tx.QueryRow(
+		query,
+		record.UserID,
+		record.ExerciseID,
+		record.WorkoutID,
+		record.SetID,
+		record.RecordType,
+		record.Value,
+		record.Weight,
+		record.Reps,
+		record.PreviousValue,
+		record.AchievedAt,
+		record.CreatedAt,
+	).Scan(
+		&record.ID,
+		&record.IsCurrent,
+		&record.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteBySetID removes the records set by a set that was changed or deleted and
+// recomputes which of the remaining records of the exercise are current: the latest
+// of each kind, and for rep records the latest of each weight.
+func (r *personalRecordRepository) DeleteBySetID(setID int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		WITH deleted AS (DELETE FROM personal_records WHERE set_id = $1 RETURNING user_id, exercise_id)
+		SELECT DISTINCT user_id, exercise_id FROM deleted
+	`, setID)
+	if err != nil {
+		return err
+	}
+
+	type key struct{ userID, exerciseID int64 }
+	var affected []key
+	for rows.Next() {
+		var k key
+		if err := rows.Scan(&k.userID, &k.exerciseID); err != nil {
+			rows.Close()
+			return err
+		}
+		affected = append(affected, k)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, k := range affected {
+		if _, err := tx.Exec(recomputeCurrent, k.userID, k.exerciseID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ReplaceForExercise replaces every record of a user's exercise, for when sets were added
+// before the latest ones and the records have to be replayed from the whole history
+func (r *personalRecordRepository) ReplaceForExercise(userID, exerciseID int64, records []*models.PersonalRecord) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM personal_records WHERE user_id = $1 AND exercise_id = $2`, userID, exerciseID); err != nil {
+		return err
+	}
+
+	insert, err := tx.Prepare(`
+		INSERT INTO personal_records (user_id, exercise_id, workout_id, set_id, record_type, value, weight, reps, previous_value, is_current, achieved_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, FALSE, $10, $11)
+		RETURNING id_personal_record
+	`)
+	if err != nil {
+		return err
+	}
+	defer insert.Close()
+
+	for _, record := range records {
+		err := insert.QueryRow(
+			userID,
+			exerciseID,
+			record.WorkoutID,
+			record.SetID,
+			record.RecordType,
+			record.Value,
+			record.Weight,
+			record.Reps,
+			record.PreviousValue,
+			record.AchievedAt,
+			record.CreatedAt,
+		).Scan(&record.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(recomputeCurrent, userID, exerciseID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetByUserID retrieves the personal records of a user across all exercises
+func (r *personalRecordRepository) GetByUserID(userID int64, currentOnly bool) ([]*models.PersonalRecord, error) {
+	query := `
+		SELECT p.id_personal_record, p.user_id, p.exercise_id, COALESCE(a.alias, e.name), p.workout_id, p.set_id, p.record_type,
+		       p.value, p.weight, p.reps, p.previous_value, p.is_current, p.achieved_at, p.created_at
+		FROM personal_records p
+		INNER JOIN exercises e ON p.exercise_id = e.id_exercise
+		LEFT JOIN user_catalog_exercises a ON a.exercise_id = p.exercise_id AND a.user_id = p.user_id
+		WHERE p.user_id = $1 AND e.deleted_at IS NULL AND ($2 = FALSE OR p.is_current)
+		ORDER BY p.achieved_at DESC, p.id_personal_record DESC
+	`
+
+	return r.query(query, userID, currentOnly)
+}
+
+// GetByExerciseIDAndUserID retrieves the personal records of a user for an exercise
+func (r *personalRecordRepository) GetByExerciseIDAndUserID(exerciseID, userID int64, currentOnly bool) ([]*models.PersonalRecord, error) {
+	query := `
+		SELECT p.id_personal_record, p.user_id, p.exercise_id, COALESCE(a.alias, e.name), p.workout_id, p.set_id, p.record_type,
+		       p.value, p.weight, p.reps, p.previous_value, p.is_current, p.achieved_at, p.created_at
+		FROM personal_records p
+		INNER JOIN exercises e ON p.exercise_id = e.id_exercise
+		LEFT JOIN user_catalog_exercises a ON a.exercise_id = p.exercise_id AND a.user_id = p.user_id
+		WHERE p.exercise_id = $1 AND p.user_id = $2 AND ($3 = FALSE OR p.is_current)
+		ORDER BY p.achieved_at DESC, p.id_personal_record DESC
+	`
+
+	return r.query(query, exerciseID, userID, currentOnly)
+}
+
+// query runs a personal record select and scans the rows
+func (r *personalRecordRepository) query(query string, args ...interface{}) ([]*models.PersonalRecord, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*models.PersonalRecord
+	for rows.Next() {
+		record := &models.PersonalRecord{}
+		err := rows.Scan(
+			&record.ID,
+			&record.UserID,
+			&record.ExerciseID,
+			&record.ExerciseName,
+			&record.WorkoutID,
+			&record.SetID,
+			&record.RecordType,
+			&record.Value,
+			&record.Weight,
+			&record.Reps,
+			&record.PreviousValue,
+			&record.IsCurrent,
+			&record.AchievedAt,
+			&record.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"database/sql"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+// PlannedSetRepository defines the interface for planned set data operations
+type PlannedSetRepository interface {
+	CreateBatch(plannedSets []*models.PlannedSet) error
+	GetByWorkoutID(workoutID int64) ([]*models.PlannedSet, error)
+}
+
+type plannedSetRepository struct {
+	db *sql.DB
+}
+
+// NewPlannedSetRepository creates a new planned set repository
+func NewPlannedSetRepository(db *sql.DB) PlannedSetRepository {
+	return &plannedSetRepository{db: db}
+}
+
+// CreateBatch creates several planned sets in a single transaction
+func (r *plannedSetRepository) CreateBatch(plannedSets []*models.PlannedSet) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, plannedSet := range plannedSets {
+		if err := insertPlannedSet(tx, plannedSet); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// insertPlannedSet inserts a planned set with the database or a transaction
+func insertPlannedSet(q rowQuerier, plannedSet *models.PlannedSet) error {
+	query := `
+		INSERT INTO planned_sets (workout_id, exercise_id, position, target_reps, target_weight, rest_seconds, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id_planned_set, created_at
+	`
+
+	return q.QueryRow(
+		query,
+		plannedSet.WorkoutID,
+		plannedSet.ExerciseID,
+		plannedSet.Position,
+		plannedSet.TargetReps,
+		plannedSet.TargetWeight,
+		plannedSet.RestSeconds,
+		plannedSet.CreatedAt,
+	).Scan(&plannedSet.ID, &plannedSet.CreatedAt)
+}
+
+// GetByWorkoutID retrieves the planned sets of a workout in order
+func (r *plannedSetRepository) GetByWorkoutID(workoutID int64) ([]*models.PlannedSet, error) {
+	query := `
+		SELECT id_planned_set, workout_id, exercise_id, position, target_reps, target_weight, rest_seconds, created_at
+		FROM planned_sets
+		WHERE workout_id = $1
+		ORDER BY position ASC
+	`
+
+	rows, err := r.db.Query(query, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var plannedSets []*models.PlannedSet
+	for rows.Next() {
+		plannedSet := &models.PlannedSet{}
+		err := rows.Scan(
+			&plannedSet.ID,
+			&plannedSet.WorkoutID,
+			&plannedSet.ExerciseID,
+			&plannedSet.Position,
+			&plannedSet.TargetReps,
+			&plannedSet.TargetWeight,
+			&plannedSet.RestSeconds,
+			&plannedSet.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		plannedSets = append(plannedSets, plannedSet)
+	}
+
+	return plannedSets, rows.Err()
+}
@@ -0,0 +1,474 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"phoenix-alliance-be/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// ProgramRepository defines the interface for program and enrollment data operations
+type ProgramRepository interface {
+	Create(program *models.Program) error
+	GetByIDAndUserID(id, userID int64) (*models.Program, error)
+	GetByUserID(userID int64) ([]*models.Program, error)
+	Update(program *models.Program) error
+	Delete(id, userID int64) error
+	Enroll(enrollment *models.ProgramEnrollment) error
+	GetActiveEnrollment(programID, userID int64) (*models.ProgramEnrollment, error)
+	AdvanceEnrollment(id int64, completedSessions int) error
+}
+
+type programRepository struct {
+	db *sql.DB
+}
+
+// NewProgramRepository creates a new program repository
+func NewProgramRepository(db *sql.DB) ProgramRepository {
+	return &programRepository{db: db}
+}
+
+// Create creates a new program together with its days and exercises
+func (r *programRepository) Create(program *models.Program) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO programs (user_id, name, description, weeks, days_per_week, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id_program, user_id, name, description, weeks, days_per_week, created_at, deleted_at
+	`
+
+	err = tx.QueryRow(
+		query,
+		program.UserID,
+		program.Name,
+		program.Description,
+		program.Weeks,
+		program.DaysPerWeek,
+		program.CreatedAt,
+	).Scan(
+		&program.ID,
+		&program.UserID,
+		&program.Name,
+		&program.Description,
+		&program.Weeks,
+		&program.DaysPerWeek,
+		&program.CreatedAt,
+		&program.DeletedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := insertProgramDays(tx, program); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetByIDAndUserID retrieves a program by ID and ensures it belongs to the user (only non-deleted)
+func (r *programRepository) GetByIDAndUserID(id, userID int64) (*models.Program, error) {
+	program := &models.Program{}
+	query := `
+		SELECT id_program, user_id, name, description, weeks, days_per_week, created_at, deleted_at
+		FROM programs
+		WHERE id_program = $1 AND user_id = $2 AND deleted_at IS NULL
+	`
+
+	err := r.db.QueryRow(query, id, userID).Scan(
+		&program.ID,
+		&program.UserID,
+		&program.Name,
+		&program.Description,
+		&program.Weeks,
+		&program.DaysPerWeek,
+		&program.CreatedAt,
+		&program.DeletedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("program not found")
+		}
+		return nil, err
+	}
+
+	if err := r.loadDays([]*models.Program{program}); err != nil {
+		return nil, err
+	}
+
+	return program, nil
+}
+
+// GetByUserID retrieves all programs for a user (only non-deleted)
+func (r *programRepository) GetByUserID(userID int64) ([]*models.Program, error) {
+	query := `
+		SELECT id_program, user_id, name, description, weeks, days_per_week, created_at, deleted_at
+		FROM programs
+		WHERE user_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var programs []*models.Program
+	for rows.Next() {
+		program := &models.Program{}
+		err := rows.Scan(
+			&program.ID,
+			&program.UserID,
+			&program.Name,
+			&program.Description,
+			&program.Weeks,
+			&program.DaysPerWeek,
+			&program.CreatedAt,
+			&program.DeletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		programs = append(programs, program)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadDays(programs); err != nil {
+		return nil, err
+	}
+
+	return programs, nil
+}
+
+// Update updates an existing program and replaces its days (only non-deleted)
+func (r *programRepository) Update(program *models.Program) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE programs
+		SET name = $1, description = $2, weeks = $3, days_per_week = $4
+		WHERE id_program = $5 AND user_id = $6 AND deleted_at IS NULL
+		RETURNING id_program, user_id, name, description, weeks, days_per_week, created_at, deleted_at
+	`
+
+	err = tx.QueryRow(
+		query,
+		program.Name,
+		program.Description,
+		program.Weeks,
+		program.DaysPerWeek,
+		program.ID,
+		program.UserID,
+	).Scan(
+		&program.ID,
+		&program.UserID,
+		&program.Name,
+		&program.Description,
+		&program.Weeks,
+		&program.DaysPerWeek,
+		&program.CreatedAt,
+		&program.DeletedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("program not found")
+		}
+		return err
+	}
+
+	// Exercises are removed by ON DELETE CASCADE
+	if _, err := tx.Exec(`DELETE FROM program_days WHERE program_id = $1`, program.ID); err != nil {
+		return err
+	}
+
+	if err := insertProgramDays(tx, program); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete performs a soft delete on a program for a user and ends its enrollments
+func (r *programRepository) Delete(id, userID int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE programs
+		SET deleted_at = CURRENT_TIMESTAMP
+		WHERE id_program = $1 AND user_id = $2 AND deleted_at IS NULL
+	`
+
+	result, err := tx.Exec(query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("program not found")
+	}
+
+	if _, err := tx.Exec(`UPDATE program_enrollments SET active = FALSE WHERE program_id = $1 AND active`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Enroll creates a new active enrollment, ending any previous active enrollment of the user in the program
+func (r *programRepository) Enroll(enrollment *models.ProgramEnrollment) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`UPDATE program_enrollments SET active = FALSE WHERE program_id = $1 AND user_id = $2 AND active`,
+		enrollment.ProgramID,
+		enrollment.UserID,
+	)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO program_enrollments (program_id, user_id, completed_sessions, active, started_at)
+		VALUES ($1, $2, 0, TRUE, $3)
+		RETURNING id_enrollment, program_id, user_id, completed_sessions, active, started_at
+	`
+
+	err = tx.QueryRow(
+		query,
+		enrollment.ProgramID,
+		enrollment.UserID,
+		enrollment.StartedAt,
+	).Scan(
+		&enrollment.ID,
+		&enrollment.ProgramID,
+		&enrollment.UserID,
+		&enrollment.CompletedSessions,
+		&enrollment.Active,
+		&enrollment.StartedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetActiveEnrollment retrieves the active enrollment of a user in a program
+func (r *programRepository) GetActiveEnrollment(programID, userID int64) (*models.ProgramEnrollment, error) {
+	enrollment := &models.ProgramEnrollment{}
+	query := `
+		SELECT id_enrollment, program_id, user_id, completed_sessions, active, started_at
+		FROM program_enrollments
+		WHERE program_id = $1 AND user_id = $2 AND active
+	`
+
+	err := r.db.QueryRow(query, programID, userID).Scan(
+		&enrollment.ID,
+		&enrollment.ProgramID,
+		&enrollment.UserID,
+		&enrollment.CompletedSessions,
+		&enrollment.Active,
+		&enrollment.StartedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("enrollment not found")
+		}
+		return nil, err
+	}
+
+	return enrollment, nil
+}
+
+// AdvanceEnrollment moves an enrollment forward to the given number of completed sessions.
+// It only succeeds when the enrollment has not advanced in the meantime.
+func (r *programRepository) AdvanceEnrollment(id int64, completedSessions int) error {
+	query := `
+		UPDATE program_enrollments
+		SET completed_sessions = $2
+		WHERE id_enrollment = $1 AND active AND completed_sessions = $2 - 1
+	`
+
+	result, err := r.db.Exec(query, id, completedSessions)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("enrollment not found")
+	}
+
+	return nil
+}
+
+// insertProgramDays inserts the days of a program and their exercises in order
+func insertProgramDays(tx *sql.Tx, program *models.Program) error {
+	dayQuery := `
+		INSERT INTO program_days (program_id, day_number, name)
+		VALUES ($1, $2, $3)
+		RETURNING id_program_day
+	`
+	exerciseQuery := `
+		INSERT INTO program_exercises (program_day_id, exercise_id, position, sets, reps, rep_max, progression, increment, start_weight, training_max_percent)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id_program_exercise
+	`
+
+	for i, day := range program.Days {
+		day.ProgramID = program.ID
+		day.DayNumber = i + 1
+		if err := tx.QueryRow(dayQuery, day.ProgramID, day.DayNumber, day.Name).Scan(&day.ID); err != nil {
+			return err
+		}
+
+		for j, exercise := range day.Exercises {
+			exercise.ProgramDayID = day.ID
+			exercise.Position = j
+			err := tx.QueryRow(
+				exerciseQuery,
+				exercise.ProgramDayID,
+				exercise.ExerciseID,
+				exercise.Position,
+				exercise.Sets,
+				exercise.Reps,
+				exercise.RepMax,
+				exercise.Progression,
+				exercise.Increment,
+				exercise.StartWeight,
+				exercise.TrainingMaxPercent,
+			).Scan(&exercise.ID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadDays attaches the ordered days and exercises to each program
+func (r *programRepository) loadDays(programs []*models.Program) error {
+	if len(programs) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*models.Program, len(programs))
+	ids := make([]int64, len(programs))
+	for i, program := range programs {
+		program.Days = []*models.ProgramDay{}
+		byID[program.ID] = program
+		ids[i] = program.ID
+	}
+
+	query := `
+		SELECT d.id_program_day, d.program_id, d.day_number, d.name,
+		       e.id_program_exercise, e.exercise_id, e.position, e.sets, e.reps, e.rep_max,
+		       e.progression, e.increment, e.start_weight, e.training_max_percent
+		FROM program_days d
+		LEFT JOIN program_exercises e ON e.program_day_id = d.id_program_day
+		WHERE d.program_id = ANY($1)
+		ORDER BY d.program_id, d.day_number, e.position
+	`
+
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	days := make(map[int64]*models.ProgramDay)
+	for rows.Next() {
+		day := &models.ProgramDay{}
+		var (
+			exerciseRowID      sql.NullInt64
+			exerciseID         sql.NullInt64
+			position           sql.NullInt32
+			sets               sql.NullInt32
+			reps               sql.NullInt32
+			repMax             *int
+			progression        sql.NullString
+			increment          sql.NullFloat64
+			startWeight        *float64
+			trainingMaxPercent *float64
+		)
+		err := rows.Scan(
+			&day.ID,
+			&day.ProgramID,
+			&day.DayNumber,
+			&day.Name,
+			&exerciseRowID,
+			&exerciseID,
+			&position,
+			&sets,
+			&reps,
+			&repMax,
+			&progression,
+			&increment,
+			&startWeight,
+			&trainingMaxPercent,
+		)
+		if err != nil {
+			return err
+		}
+
+		if existing, ok := days[day.ID]; ok {
+			day = existing
+		} else {
+			day.Exercises = []*models.ProgramExercise{}
+			days[day.ID] = day
+			if program, ok := byID[day.ProgramID]; ok {
+				program.Days = append(program.Days, day)
+			}
+		}
+
+		if exerciseRowID.Valid {
+			day.Exercises = append(day.Exercises, &models.ProgramExercise{
+				ID:                 exerciseRowID.Int64,
+				ProgramDayID:       day.ID,
+				ExerciseID:         exerciseID.Int64,
+				Position:           int(position.Int32),
+				Sets:               int(sets.Int32),
+				Reps:               int(reps.Int32),
+				RepMax:             repMax,
+				Progression:        models.ProgressionType(progression.String),
+				Increment:          increment.Float64,
+				StartWeight:        startWeight,
+				TrainingMaxPercent: trainingMaxPercent,
+			})
+		}
+	}
+
+	return rows.Err()
+}
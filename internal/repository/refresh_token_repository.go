@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+// RefreshTokenRepository defines the interface for session token data operations
+type RefreshTokenRepository interface {
+	Create(token *models.RefreshToken) error
+	GetByHash(tokenHash string) (*models.RefreshToken, error)
+	GetByAccessJTI(jti string) (*models.RefreshToken, error)
+	Rotate(used, next *models.RefreshToken) error
+	RevokeFamily(familyID string) error
+	RevokeAllForUser(userID int64) error
+	RevokeOthersForUser(userID int64, familyID string) error
+	RevokeAccessToken(jti string, userID int64, expiresAt time.Time) error
+	IsAccessTokenRevoked(jti string) (bool, error)
+	DeleteExpired(before time.Time) error
+}
+
+type refreshTokenRepository struct {
+	db *sql.DB
+}
+
+// NewRefreshTokenRepository creates a new refresh token repository
+func NewRefreshTokenRepository(db *sql.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+// refreshTokenColumns lists the columns scanned by scanRefreshToken
+const refreshTokenColumns = `id_refresh_token, user_id, token_hash, family_id, access_jti, access_expires_at, expires_at, used_at, revoked_at, created_at`
+
+// scanRefreshToken scans a row of refreshTokenColumns
+func scanRefreshToken(row rowScanner) (*models.RefreshToken, error) {
+	token := &models.RefreshToken{}
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.FamilyID,
+		&token.AccessJTI,
+		&token.AccessExpiresAt,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("refresh token not found")
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// rowQuerier is implemented by *sql.DB and *sql.Tx
+type rowQuerier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// insertRefreshToken inserts a token with the database or a transaction
+func insertRefreshToken(q rowQuerier, token *models.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (user_id, token_hash, family_id, access_jti, access_expires_at, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id_refresh_token
+	`
+
+	return q.QueryRow(
+		query,
+		token.UserID,
+		token.TokenHash,
+		token.FamilyID,
+		token.AccessJTI,
+		token.AccessExpiresAt,
+		token.ExpiresAt,
+		token.CreatedAt,
+	).Scan(&token.ID)
+}
+
+// Create stores the first refresh token of a session
+func (r *refreshTokenRepository) Create(token *models.RefreshToken) error {
+	return insertRefreshToken(r.db, token)
+}
+
+// GetByHash retrieves a refresh token by the hash of its value
+func (r *refreshTokenRepository) GetByHash(tokenHash string) (*models.RefreshToken, error) {
+	query := `SELECT ` + refreshTokenColumns + ` FROM refresh_tokens WHERE token_hash = $1`
+	return scanRefreshToken(r.db.QueryRow(query, tokenHash))
+}
+
+// GetByAccessJTI retrieves the refresh token issued along with an access token
+func (r *refreshTokenRepository) GetByAccessJTI(jti string) (*models.RefreshToken, error) {
+	query := `SELECT ` + refreshTokenColumns + ` FROM refresh_tokens WHERE access_jti = $1`
+	return scanRefreshToken(r.db.QueryRow(query, jti))
+}
+
+// Rotate marks a token as used and stores the token that replaces it. Of two concurrent
+// rotations of the same token only one succeeds; the other fails as already used.
+func (r *refreshTokenRepository) Rotate(used, next *models.RefreshToken) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE refresh_tokens
+		SET used_at = $1
+		WHERE id_refresh_token = $2 AND used_at IS NULL AND revoked_at IS NULL
+	`, next.CreatedAt, used.ID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("refresh token already used")
+	}
+
+	if err := insertRefreshToken(tx, next); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RevokeFamily revokes every refresh token of a session and the access tokens issued with
+// them that have not expired yet
+func (r *refreshTokenRepository) RevokeFamily(familyID string) error {
+	return r.revoke(`family_id = $1`, familyID)
+}
+
+// RevokeAllForUser revokes every session of a user
+func (r *refreshTokenRepository) RevokeAllForUser(userID int64) error {
+	return r.revoke(`user_id = $1`, userID)
+}
+
+// RevokeOthersForUser revokes every session of a user but the one of a refresh token family
+func (r *refreshTokenRepository) RevokeOthersForUser(userID int64, familyID string) error {
+	return r.revoke(`user_id = $1 AND family_id <> $2`, userID, familyID)
+}
+
+// revoke revokes the refresh tokens matching a condition on args, and denylists their live access tokens
+func (r *refreshTokenRepository) revoke(condition string, args ...interface{}) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO revoked_tokens (jti, user_id, expires_at)
+		SELECT access_jti, user_id, access_expires_at
+		FROM refresh_tokens
+		WHERE `+condition+` AND access_expires_at > CURRENT_TIMESTAMP
+		ON CONFLICT (jti) DO NOTHING
+	`, args...)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE refresh_tokens
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE `+condition+` AND revoked_at IS NULL
+	`, args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RevokeAccessToken denylists an access token until it expires
+func (r *refreshTokenRepository) RevokeAccessToken(jti string, userID int64, expiresAt time.Time) error {
+	query := `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING
+	`
+
+	_, err := r.db.Exec(query, jti, userID, expiresAt)
+	return err
+}
+
+// IsAccessTokenRevoked reports whether an access token is on the denylist
+func (r *refreshTokenRepository) IsAccessTokenRevoked(jti string) (bool, error) {
+	var revoked bool
+	err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)`, jti).Scan(&revoked)
+	return revoked, err
+}
+
+// DeleteExpired drops the refresh tokens and denylisted access tokens that expired before a time
+func (r *refreshTokenRepository) DeleteExpired(before time.Time) error {
+	if _, err := r.db.Exec(`DELETE FROM refresh_tokens WHERE expires_at < $1`, before); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(`DELETE FROM revoked_tokens WHERE expires_at < $1`, before)
+	return err
+}
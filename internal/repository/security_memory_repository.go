@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+// memorySecurityRepository keeps login throttles and security events in memory, for tests and
+// single-process development setups
+type memorySecurityRepository struct {
+	mu        sync.Mutex
+	throttles map[string]*models.LoginThrottle
+	events    []*models.SecurityEvent
+	lastID    int64
+}
+
+// NewMemorySecurityRepository creates a security repository that keeps its state in memory
+func NewMemorySecurityRepository() SecurityRepository {
+	return &memorySecurityRepository{throttles: make(map[string]*models.LoginThrottle)}
+}
+
+// ReserveAttempt counts a login attempt against a key before its password is checked and returns
+// the key's throttle. An attempt on a locked key is refused and not counted: the returned bool is
+// false. Checking and counting happen under one lock, as they do in one statement in the database.
+func (r *memorySecurityRepository) ReserveAttempt(key string, now time.Time, window time.Duration, freeAttempts int) (*models.LoginThrottle, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	throttle, ok := r.throttles[key]
+	if !ok {
+		throttle = &models.LoginThrottle{Key: key}
+		r.throttles[key] = throttle
+	}
+	if throttle.LockedUntil != nil && throttle.LockedUntil.After(now) {
+		copied := *throttle
+		return &copied, false, nil
+	}
+
+	if throttle.LastFailureAt.Before(now.Add(-window)) {
+		throttle.Failures = 0
+	}
+	throttle.Failures++
+	throttle.LastFailureAt = now
+	throttle.LockedUntil = nil
+	if lockout := models.LoginLockout(throttle.Failures, freeAttempts); lockout > 0 {
+		until := now.Add(lockout)
+		throttle.LockedUntil = &until
+	}
+
+	copied := *throttle
+	return &copied, true, nil
+}
+
+// ReleaseAttempt takes back an attempt reserved on a throttle that turned out not to be a failure,
+// along with the lockout it started
+func (r *memorySecurityRepository) ReleaseAttempt(reserved *models.LoginThrottle) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	throttle, ok := r.throttles[reserved.Key]
+	if !ok {
+		return nil
+	}
+	throttle.Failures = max(throttle.Failures-1, 0)
+	if throttle.LockedUntil != nil && reserved.LockedUntil != nil && throttle.LockedUntil.Equal(*reserved.LockedUntil) {
+		throttle.LockedUntil = nil
+	}
+	return nil
+}
+
+// ClearThrottle forgets the failed logins of a key
+func (r *memorySecurityRepository) ClearThrottle(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.throttles, key)
+	return nil
+}
+
+// CreateEvent adds an event to the audit trail
+func (r *memorySecurityRepository) CreateEvent(event *models.SecurityEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	event.ID = r.lastID
+	copied := *event
+	r.events = append(r.events, &copied)
+	return nil
+}
+
+// ListEvents retrieves a page of the security events of a user and the cursor of the next page
+func (r *memorySecurityRepository) ListEvents(userID int64, params *models.ListParams) ([]*models.SecurityEvent, string, error) {
+	page, err := newKeyset(params, securityEventSortColumns, "id_security_event")
+	if err != nil {
+		return nil, "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// before reports whether event a comes before b in the requested order
+	before := func(a, b *models.SecurityEvent) bool {
+		if params.Desc {
+			a, b = b, a
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	}
+
+	var cursor *models.SecurityEvent
+	if params.Cursor != nil {
+		at, err := time.Parse(time.RFC3339Nano, params.Cursor.Value)
+		if err != nil {
+			return nil, "", err
+		}
+		cursor = &models.SecurityEvent{ID: params.Cursor.ID, CreatedAt: at}
+	}
+
+	events := []*models.SecurityEvent{}
+	for _, event := range r.events {
+		if event.UserID != nil && *event.UserID == userID && (cursor == nil || before(cursor, event)) {
+			copied := *event
+			events = append(events, &copied)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return before(events[i], events[j]) })
+
+	next := ""
+	if page.hasMore(len(events)) {
+		events = events[:params.Limit]
+		last := events[len(events)-1]
+		next = page.nextCursor(last.CreatedAt.Format(time.RFC3339Nano), last.ID)
+	}
+
+	return events, next, nil
+}
+
+// DeleteExpired drops the throttles whose failures are forgotten and lockouts are over, and
+// the events older than the retention period
+func (r *memorySecurityRepository) DeleteExpired(now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, throttle := range r.throttles {
+		if throttle.LastFailureAt.Before(now.Add(-models.LoginFailureWindow)) &&
+			(throttle.LockedUntil == nil || throttle.LockedUntil.Before(now)) {
+			delete(r.throttles, key)
+		}
+	}
+
+	kept := r.events[:0]
+	for _, event := range r.events {
+		if !event.CreatedAt.Before(now.Add(-models.SecurityEventRetention)) {
+			kept = append(kept, event)
+		}
+	}
+	r.events = kept
+	return nil
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+// SecurityRepository defines the interface for login throttling and security event data operations
+type SecurityRepository interface {
+	ReserveAttempt(key string, now time.Time, window time.Duration, freeAttempts int) (*models.LoginThrottle, bool, error)
+	ReleaseAttempt(throttle *models.LoginThrottle) error
+	ClearThrottle(key string) error
+	CreateEvent(event *models.SecurityEvent) error
+	ListEvents(userID int64, params *models.ListParams) ([]*models.SecurityEvent, string, error)
+	DeleteExpired(now time.Time) error
+}
+
+type securityRepository struct {
+	db *sql.DB
+}
+
+// NewSecurityRepository creates a new security repository
+func NewSecurityRepository(db *sql.DB) SecurityRepository {
+	return &securityRepository{db: db}
+}
+
+// securityEventSortColumns whitelists the sort fields of security event lists
+var securityEventSortColumns = map[string]sortColumn{
+	"created_at": {column: "created_at", sqlType: "timestamptz"},
+}
+
+// lockedUntil returns the SQL of models.LoginLockout after a number of failures, as the end of
+// the lockout starting at $2, or NULL while free attempts are left ($4)
+func lockedUntil(failures string) string {
+	return fmt.Sprintf(
+		`CASE WHEN %[1]s >= $4::int THEN $2::timestamptz + LEAST($5::float8 * POWER(2, LEAST(%[1]s - $4::int, 20)), $6::float8) * INTERVAL '1 second' END`,
+		failures,
+	)
+}
+
+// ReserveAttempt counts a login attempt against a key before its password is checked and returns
+// the key's throttle. The count starts over when the last failure is older than the window, and
+// once the free attempts are used up the key is locked out. An attempt on a locked key is refused
+// and not counted: the returned bool is false. Checking and counting is a single statement, so
+// concurrent attempts cannot get past the limit.
+func (r *securityRepository) ReserveAttempt(key string, now time.Time, window time.Duration, freeAttempts int) (*models.LoginThrottle, bool, error) {
+	failures := `CASE WHEN t.last_failure_at < $3 THEN 1 ELSE t.failures + 1 END`
+	query := `
+		INSERT INTO login_throttles AS t (throttle_key, failures, last_failure_at, locked_until)
+		VALUES ($1, 1, $2, ` + lockedUntil("1") + `)
+		ON CONFLICT (throttle_key) DO UPDATE SET
+			failures = ` + failures + `,
+			last_failure_at = $2,
+			locked_until = ` + lockedUntil(failures) + `
+		WHERE t.locked_until IS NULL OR t.locked_until <= $2
+		RETURNING throttle_key, failures, last_failure_at, locked_until
+	`
+
+	throttle := &models.LoginThrottle{}
+	err := r.db.QueryRow(
+		query,
+		key,
+		now,
+		now.Add(-window),
+		freeAttempts,
+		models.LoginLockoutBase.Seconds(),
+		models.LoginLockoutMax.Seconds(),
+	).Scan(
+		&throttle.Key,
+		&throttle.Failures,
+		&throttle.LastFailureAt,
+		&throttle.LockedUntil,
+	)
+	if err == nil {
+		return throttle, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, false, err
+	}
+
+	// The key is locked out
+	query = `SELECT throttle_key, failures, last_failure_at, locked_until FROM login_throttles WHERE throttle_key = $1`
+	err = r.db.QueryRow(query, key).Scan(&throttle.Key, &throttle.Failures, &throttle.LastFailureAt, &throttle.LockedUntil)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, false, err
+	}
+	throttle.Key = key
+	return throttle, false, nil
+}
+
+// ReleaseAttempt takes back an attempt reserved on a throttle that turned out not to be a failure,
+// along with the lockout it started
+func (r *securityRepository) ReleaseAttempt(throttle *models.LoginThrottle) error {
+	query := `
+		UPDATE login_throttles
+		SET failures = GREATEST(failures - 1, 0),
+		    locked_until = CASE WHEN locked_until = $2 THEN NULL ELSE locked_until END
+		WHERE throttle_key = $1
+	`
+	_, err := r.db.Exec(query, throttle.Key, throttle.LockedUntil)
+	return err
+}
+
+// ClearThrottle forgets the failed logins of a key
+func (r *securityRepository) ClearThrottle(key string) error {
+	_, err := r.db.Exec(`DELETE FROM login_throttles WHERE throttle_key = $1`, key)
+	return err
+}
+
+// CreateEvent adds an event to the audit trail
+func (r *securityRepository) CreateEvent(event *models.SecurityEvent) error {
+	query := `
+		INSERT INTO security_events (user_id, event_type, email, ip_address, user_agent, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id_security_event
+	`
+
+	return r.db.QueryRow(
+		query,
+		event.UserID,
+		event.Type,
+		event.Email,
+		event.IPAddress,
+		event.UserAgent,
+		event.CreatedAt,
+	).Scan(&event.ID)
+}
+
+// ListEvents retrieves a page of the security events of a user and the cursor of the next page
+func (r *securityRepository) ListEvents(userID int64, params *models.ListParams) ([]*models.SecurityEvent, string, error) {
+	page, err := newKeyset(params, securityEventSortColumns, "id_security_event")
+	if err != nil {
+		return nil, "", err
+	}
+
+	b := &queryBuilder{}
+	b.where("user_id = %s", userID)
+	page.apply(b)
+
+	query := fmt.Sprintf(`
+		SELECT id_security_event, user_id, event_type, email, ip_address, user_agent, created_at, %s
+		FROM security_events
+		%s
+		%s
+	`, page.selectValue(), b.whereClause(), page.orderBy(b))
+
+	rows, err := r.db.Query(query, b.args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	events := []*models.SecurityEvent{}
+	var sortValues []string
+	for rows.Next() {
+		event := &models.SecurityEvent{}
+		var sortValue string
+		err := rows.Scan(
+			&event.ID,
+			&event.UserID,
+			&event.Type,
+			&event.Email,
+			&event.IPAddress,
+			&event.UserAgent,
+			&event.CreatedAt,
+			&sortValue,
+		)
+		if err != nil {
+			return nil, "", err
+		}
+		events = append(events, event)
+		sortValues = append(sortValues, sortValue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if page.hasMore(len(events)) {
+		events = events[:params.Limit]
+		last := len(events) - 1
+		next = page.nextCursor(sortValues[last], events[last].ID)
+	}
+
+	return events, next, nil
+}
+
+// DeleteExpired drops the throttles whose failures are forgotten and lockouts are over, and
+// the events older than the retention period
+func (r *securityRepository) DeleteExpired(now time.Time) error {
+	query := `
+		DELETE FROM login_throttles
+		WHERE last_failure_at < $1 AND (locked_until IS NULL OR locked_until < $2)
+	`
+	if _, err := r.db.Exec(query, now.Add(-models.LoginFailureWindow), now); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec(`DELETE FROM security_events WHERE created_at < $1`, now.Add(-models.SecurityEventRetention))
+	return err
+}
@@ -0,0 +1,413 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+// effectiveRepsSQL adds the reps in reserve derived from the RPE to the performed reps
+const effectiveRepsSQL = `(s.reps + CASE WHEN s.rpe BETWEEN 1 AND 10 THEN 10 - s.rpe ELSE 0 END)`
+
+// oneRepMaxSQL returns the e1RM of a set row as a SQL expression, computed as the
+// service estimates it in Go so that both agree to the cent once rounded
+func oneRepMaxSQL(formula models.E1RMFormula) string {
+	reps := effectiveRepsSQL
+	epley := fmt.Sprintf(`CASE WHEN %[1]s = 1 THEN s.weight ELSE s.weight * (1 + %[1]s / 30.0) END`, reps)
+
+	var estimate string
+	switch formula {
+	case models.E1RMFormulaBrzycki:
+		estimate = fmt.Sprintf(`s.weight * 36 / (37 - LEAST(%s, %d))`, reps, models.MaxBrzyckiReps)
+	case models.E1RMFormulaLombardi:
+		estimate = fmt.Sprintf(`s.weight * POWER(%s, 0.10)`, reps)
+	case models.E1RMFormulaRPE:
+		percentages := make([]string, len(models.RPEPercentages))
+		for i, p := range models.RPEPercentages {
+			percentages[i] = fmt.Sprintf("%.3f", p)
+		}
+		// Outside the RPE table, fall back to Epley
+		estimate = fmt.Sprintf(`CASE WHEN %[1]s <= %[2]d THEN s.weight / (ARRAY[%[3]s])[%[1]s] ELSE %[4]s END`,
+			reps, len(percentages), strings.Join(percentages, ", "), epley)
+	default:
+		estimate = epley
+	}
+
+	return fmt.Sprintf(`CASE WHEN s.weight > 0 AND s.reps >= 1 THEN %s ELSE 0 END`, estimate)
+}
+
+// GetMetrics aggregates a user's sets of an exercise matching the filter in the database.
+// Sets of deleted workouts are always left out, and warm-ups unless the filter includes them.
+// It returns nil when no set matches.
+func (r *setRepository) GetMetrics(exerciseID, userID int64, filter *models.SetFilter, formula models.E1RMFormula) (*models.ExerciseMetrics, error) {
+	if !formula.IsValid() {
+		formula = models.E1RMFormulaEpley
+	}
+
+	b := &queryBuilder{}
+	b.where("s.exercise_id = %s", exerciseID)
+	b.where("w.user_id = %s", userID)
+	b.conditions = append(b.conditions, "w.deleted_at IS NULL")
+	applySetFilter(b, filter)
+	if !filter.IncludeWarmups {
+		b.where("s.set_type <> %s", models.SetTypeWarmup)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			COUNT(*),
+			COALESCE(SUM(s.weight * s.reps), 0),
+			COALESCE(MAX(s.weight), 0),
+			COALESCE(MAX(s.reps), 0),
+			COALESCE(AVG(s.weight), 0),
+			COALESCE(AVG(s.reps), 0),
+			AVG(s.rest_seconds),
+			AVG(s.rpe),
+			MIN(s.performed_at),
+			MAX(s.performed_at),
+			COALESCE(ROUND(MAX(%s), 2), 0),
+			COALESCE(SUM(s.distance_meters), 0),
+			COALESCE(SUM(s.duration_seconds), 0)
+		FROM sets s
+		INNER JOIN workouts w ON s.workout_id = w.id_workout
+		%s
+	`, oneRepMaxSQL(formula), b.whereClause())
+
+	metrics := &models.ExerciseMetrics{E1RMFormula: formula}
+	err := r.db.QueryRow(query, b.args...).Scan(
+		&metrics.TotalSets,
+		&metrics.TotalVolume,
+		&metrics.MaxWeight,
+		&metrics.MaxReps,
+		&metrics.AverageWeight,
+		&metrics.AverageReps,
+		&metrics.AverageRest,
+		&metrics.AverageRPE,
+		&metrics.FirstRecordedAt,
+		&metrics.LastRecordedAt,
+		&metrics.EstimatedOneRepMax,
+		&metrics.TotalDistance,
+		&metrics.TotalDuration,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if metrics.TotalSets == 0 {
+		return nil, nil
+	}
+
+	return metrics, nil
+}
+
+// GetProgressRollup aggregates a user's sets of an exercise into consecutive buckets in the
+// database, one data point per bucket from the one rollup.From falls in up to rollup.To,
+// including buckets without sets. Each point carries the rolling averages over the window
+// of buckets ending with it: empty buckets count as no volume but are left out of the e1RM trend.
+func (r *setRepository) GetProgressRollup(exerciseID, userID int64, rollup *models.ProgressRollup) ([]models.ProgressDataPoint, error) {
+	formula := rollup.Formula
+	if !formula.IsValid() {
+		formula = models.E1RMFormulaEpley
+	}
+	bucket := rollup.Bucket
+	if !bucket.IsValid() {
+		bucket = models.ProgressBucketDay
+	}
+	window := rollup.Window
+	if window < 1 {
+		window = bucket.DefaultWindow()
+	}
+	loc := rollup.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	b := &queryBuilder{}
+	b.where("s.exercise_id = %s", exerciseID)
+	b.where("w.user_id = %s", userID)
+	b.conditions = append(b.conditions, "s.deleted_at IS NULL", "w.deleted_at IS NULL")
+	from := b.arg(rollup.From) + "::timestamptz"
+	to := b.arg(rollup.To) + "::timestamptz"
+	b.conditions = append(b.conditions, "s.performed_at >= "+from, "s.performed_at < "+to)
+	if !rollup.IncludeWarmups {
+		b.where("s.set_type <> %s", models.SetTypeWarmup)
+	}
+	field := b.arg(string(bucket)) + "::text"
+	zone := b.arg(loc.String()) + "::text"
+
+	// Buckets are local timestamps so that days, ISO weeks and months follow the user's calendar
+	query := fmt.Sprintf(`
+		WITH totals AS (
+			SELECT
+				date_trunc(%[1]s, s.performed_at AT TIME ZONE %[2]s) AS bucket,
+				SUM(s.weight * s.reps) AS total_volume,
+				MAX(s.weight) AS max_weight,
+				COUNT(*) AS total_sets,
+				AVG(s.rpe) AS average_rpe,
+				ROUND(MAX(%[3]s), 2) AS estimated_1rm
+			FROM sets s
+			INNER JOIN workouts w ON s.workout_id = w.id_workout
+			%[4]s
+			GROUP BY 1
+		), buckets AS (
+			SELECT generate_series(
+				date_trunc(%[1]s, %[5]s AT TIME ZONE %[2]s),
+				%[6]s AT TIME ZONE %[2]s,
+				('1 ' || %[1]s)::interval
+			) AS bucket
+		)
+		SELECT
+			b.bucket AT TIME ZONE %[2]s,
+			COALESCE(t.total_volume, 0),
+			COALESCE(t.max_weight, 0),
+			COALESCE(t.total_sets, 0),
+			t.average_rpe,
+			COALESCE(t.estimated_1rm, 0),
+			COALESCE(SUM(t.total_volume) OVER rolling, 0) / %[7]d,
+			AVG(NULLIF(t.estimated_1rm, 0)) OVER rolling
+		FROM buckets b
+		LEFT JOIN totals t ON t.bucket = b.bucket
+		WHERE b.bucket < %[6]s AT TIME ZONE %[2]s
+		WINDOW rolling AS (ORDER BY b.bucket ROWS BETWEEN %[8]d PRECEDING AND CURRENT ROW)
+		ORDER BY b.bucket
+	`, field, zone, oneRepMaxSQL(formula), b.whereClause(), from, to, window, window-1)
+
+	rows, err := r.db.Query(query, b.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dataPoints := []models.ProgressDataPoint{}
+	for rows.Next() {
+		var dp models.ProgressDataPoint
+		err := rows.Scan(
+			&dp.Date,
+			&dp.TotalVolume,
+			&dp.MaxWeight,
+			&dp.TotalSets,
+			&dp.AverageRPE,
+			&dp.EstimatedOneRepMax,
+			&dp.RollingVolume,
+			&dp.RollingE1RM,
+		)
+		if err != nil {
+			return nil, err
+		}
+		dp.Date = dp.Date.In(loc)
+		dataPoints = append(dataPoints, dp)
+	}
+
+	return dataPoints, rows.Err()
+}
+
+// GetVolumeRollup returns the volume a user lifted across all exercises in consecutive
+// buckets in loc, from the one from falls in up to to, including buckets without sets.
+// Warm-ups are left out.
+func (r *setRepository) GetVolumeRollup(userID int64, from, to time.Time, bucket models.ProgressBucket, loc *time.Location) ([]models.VolumeDataPoint, error) {
+	query := `
+		WITH totals AS (
+			SELECT
+				date_trunc($4::text, s.performed_at AT TIME ZONE $5::text) AS bucket,
+				SUM(s.weight * s.reps) AS total_volume,
+				COUNT(*) AS total_sets
+			FROM sets s
+			INNER JOIN workouts w ON s.workout_id = w.id_workout
+			WHERE w.user_id = $1 AND s.deleted_at IS NULL AND w.deleted_at IS NULL AND s.set_type <> 'warmup'
+				AND s.performed_at >= $2::timestamptz AND s.performed_at < $3::timestamptz
+			GROUP BY 1
+		), buckets AS (
+			SELECT generate_series(
+				date_trunc($4::text, $2::timestamptz AT TIME ZONE $5::text),
+				$3::timestamptz AT TIME ZONE $5::text,
+				('1 ' || $4::text)::interval
+			) AS bucket
+		)
+		SELECT b.bucket AT TIME ZONE $5::text, COALESCE(t.total_volume, 0), COALESCE(t.total_sets, 0)
+		FROM buckets b
+		LEFT JOIN totals t ON t.bucket = b.bucket
+		WHERE b.bucket < $3::timestamptz AT TIME ZONE $5::text
+		ORDER BY b.bucket
+	`
+
+	rows, err := r.db.Query(query, userID, from, to, string(bucket), loc.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dataPoints := []models.VolumeDataPoint{}
+	for rows.Next() {
+		var dp models.VolumeDataPoint
+		if err := rows.Scan(&dp.Date, &dp.TotalVolume, &dp.TotalSets); err != nil {
+			return nil, err
+		}
+		dp.Date = dp.Date.In(loc)
+		dataPoints = append(dataPoints, dp)
+	}
+
+	return dataPoints, rows.Err()
+}
+
+// GetTopExercisesByVolume returns the exercises a user lifted the most volume in within a
+// period, highest first, under the user's alias for adopted catalog entries. Warm-ups are left out.
+func (r *setRepository) GetTopExercisesByVolume(userID int64, from, to time.Time, limit int) ([]models.ExerciseVolume, error) {
+	query := `
+		SELECT e.id_exercise, COALESCE(a.alias, e.name), SUM(s.weight * s.reps) AS total_volume, COUNT(*)
+		FROM sets s
+		INNER JOIN workouts w ON s.workout_id = w.id_workout
+		INNER JOIN exercises e ON s.exercise_id = e.id_exercise
+		LEFT JOIN user_catalog_exercises a ON a.exercise_id = e.id_exercise AND a.user_id = w.user_id
+		WHERE w.user_id = $1 AND s.deleted_at IS NULL AND w.deleted_at IS NULL AND s.set_type <> 'warmup'
+			AND s.performed_at >= $2 AND s.performed_at < $3
+		GROUP BY e.id_exercise, COALESCE(a.alias, e.name)
+		HAVING SUM(s.weight * s.reps) > 0
+		ORDER BY total_volume DESC, e.id_exercise
+		LIMIT $4
+	`
+
+	rows, err := r.db.Query(query, userID, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exercises := []models.ExerciseVolume{}
+	for rows.Next() {
+		var exercise models.ExerciseVolume
+		if err := rows.Scan(&exercise.ExerciseID, &exercise.ExerciseName, &exercise.TotalVolume, &exercise.TotalSets); err != nil {
+			return nil, err
+		}
+		exercises = append(exercises, exercise)
+	}
+
+	return exercises, rows.Err()
+}
+
+// GetMuscleGroupsLastTrained returns when a user last trained each muscle group, counting
+// the primary muscles of the exercises of their working sets
+func (r *setRepository) GetMuscleGroupsLastTrained(userID int64) (map[string]time.Time, error) {
+	query := `
+		SELECT m.muscle, MAX(s.performed_at)
+		FROM sets s
+		INNER JOIN workouts w ON s.workout_id = w.id_workout
+		INNER JOIN exercises e ON s.exercise_id = e.id_exercise
+		CROSS JOIN LATERAL unnest(e.primary_muscles) AS m(muscle)
+		WHERE w.user_id = $1 AND s.deleted_at IS NULL AND w.deleted_at IS NULL AND s.set_type <> 'warmup'
+		GROUP BY m.muscle
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lastTrained := make(map[string]time.Time)
+	for rows.Next() {
+		var muscle string
+		var at time.Time
+		if err := rows.Scan(&muscle, &at); err != nil {
+			return nil, err
+		}
+		lastTrained[muscle] = at
+	}
+
+	return lastTrained, rows.Err()
+}
+
+// GetMuscleVolume returns the hard sets and tonnage credited to each muscle group per week in
+// loc for a user's working sets performed in a period, oldest week first. A set counts fully
+// for the primary muscles of its exercise and partly for the secondary ones; cardio and
+// flexibility sets are left out. Weeks without sets are omitted.
+func (r *setRepository) GetMuscleVolume(userID int64, from, to time.Time, loc *time.Location) ([]models.MuscleVolumeWeek, error) {
+	query := `
+		SELECT
+			date_trunc('week', s.performed_at AT TIME ZONE $4::text) AS week,
+			m.muscle,
+			COALESCE(SUM(m.share) FILTER (WHERE s.rpe IS NULL OR s.rpe >= $5), 0),
+			SUM(m.share * s.weight * s.reps) AS tonnage
+		FROM sets s
+		INNER JOIN workouts w ON s.workout_id = w.id_workout
+		INNER JOIN exercises e ON s.exercise_id = e.id_exercise
+		CROSS JOIN LATERAL (
+			SELECT unnest(e.primary_muscles) AS muscle, 1.0 AS share
+			UNION ALL
+			SELECT unnest(e.secondary_muscles), $6::numeric
+		) m
+		WHERE w.user_id = $1 AND s.deleted_at IS NULL AND w.deleted_at IS NULL AND s.set_type <> 'warmup'
+			AND e.exercise_type NOT IN ('cardio', 'flexibility')
+			AND s.performed_at >= $2 AND s.performed_at < $3
+		GROUP BY week, m.muscle
+		ORDER BY week, tonnage DESC, m.muscle
+	`
+
+	rows, err := r.db.Query(query, userID, from, to, loc.String(), models.HardSetMinRPE, models.SecondaryMuscleShare)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	weeks := []models.MuscleVolumeWeek{}
+	for rows.Next() {
+		var week time.Time
+		var volume models.MuscleVolume
+		if err := rows.Scan(&week, &volume.Muscle, &volume.HardSets, &volume.Tonnage); err != nil {
+			return nil, err
+		}
+		week = localDate(week, loc)
+		if len(weeks) == 0 || !weeks[len(weeks)-1].WeekStart.Equal(week) {
+			weeks = append(weeks, models.MuscleVolumeWeek{WeekStart: week})
+		}
+		last := &weeks[len(weeks)-1]
+		last.Muscles = append(last.Muscles, volume)
+	}
+
+	return weeks, rows.Err()
+}
+
+// GetDailyTonnage returns the weight x reps of a user's working sets performed in a period,
+// per day in loc, oldest first. Days without sets are omitted.
+func (r *setRepository) GetDailyTonnage(userID int64, from, to time.Time, loc *time.Location) ([]models.DailyLoad, error) {
+	query := `
+		SELECT (s.performed_at AT TIME ZONE $4::text)::date AS day, SUM(s.weight * s.reps), 0
+		FROM sets s
+		INNER JOIN workouts w ON s.workout_id = w.id_workout
+		WHERE w.user_id = $1 AND s.deleted_at IS NULL AND w.deleted_at IS NULL AND s.set_type <> 'warmup'
+			AND s.performed_at >= $2 AND s.performed_at < $3
+		GROUP BY day
+		ORDER BY day
+	`
+
+	return queryDailyLoads(r.db, loc, query, userID, from, to, loc.String())
+}
+
+// queryDailyLoads runs a query selecting a date, a load and an unrated session count per day
+func queryDailyLoads(db *sql.DB, loc *time.Location, query string, args ...interface{}) ([]models.DailyLoad, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []models.DailyLoad
+	for rows.Next() {
+		var date time.Time
+		var day models.DailyLoad
+		if err := rows.Scan(&date, &day.Load, &day.Unrated); err != nil {
+			return nil, err
+		}
+		day.Date = localDate(date, loc)
+		days = append(days, day)
+	}
+
+	return days, rows.Err()
+}
+
+// localDate places a calendar date, or a local timestamp, read from the database as UTC at
+// midnight of the same date in loc
+func localDate(date time.Time, loc *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+}
@@ -3,19 +3,45 @@ package repository
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"time"
 
 	"phoenix-alliance-be/internal/models"
+
+	"github.com/lib/pq"
 )
 
 // SetRepository defines the interface for set data operations
 type SetRepository interface {
 	Create(set *models.Set) error
+	CreateBatch(sets []*models.Set) error
 	GetByID(id int64) (*models.Set, error)
 	GetByWorkoutID(workoutID int64) ([]*models.Set, error)
 	GetByExerciseID(exerciseID int64) ([]*models.Set, error)
 	GetByExerciseIDAndUserID(exerciseID, userID int64) ([]*models.Set, error)
-	GetByExerciseIDAndDateRange(exerciseID int64, startDate, endDate time.Time) ([]*models.Set, error)
+	GetByExerciseIDAndDateRange(exerciseID, userID int64, startDate, endDate time.Time) ([]*models.Set, error)
+	ListByWorkoutID(workoutID int64, filter *models.SetFilter) ([]*models.Set, string, error)
+	ListByExerciseIDAndUserID(exerciseID, userID int64, filter *models.SetFilter) ([]*models.Set, string, error)
+	GetByIDAndWorkoutID(id, workoutID int64) (*models.Set, error)
+	Update(set *models.Set) error
+	Delete(id, workoutID int64) error
+	Reorder(workoutID int64, setIDs []int64) error
+	GetMetrics(exerciseID, userID int64, filter *models.SetFilter, formula models.E1RMFormula) (*models.ExerciseMetrics, error)
+	GetProgressRollup(exerciseID, userID int64, rollup *models.ProgressRollup) ([]models.ProgressDataPoint, error)
+	GetVolumeRollup(userID int64, from, to time.Time, bucket models.ProgressBucket, loc *time.Location) ([]models.VolumeDataPoint, error)
+	GetTopExercisesByVolume(userID int64, from, to time.Time, limit int) ([]models.ExerciseVolume, error)
+	GetMuscleGroupsLastTrained(userID int64) (map[string]time.Time, error)
+	GetMuscleVolume(userID int64, from, to time.Time, loc *time.Location) ([]models.MuscleVolumeWeek, error)
+	GetDailyTonnage(userID int64, from, to time.Time, loc *time.Location) ([]models.DailyLoad, error)
+}
+
+// setSortColumns are the columns sets can be sorted by
+var setSortColumns = map[string]sortColumn{
+	"performed_at": {column: "s.performed_at", sqlType: "timestamptz"},
+	"created_at":   {column: "s.created_at", sqlType: "timestamptz"},
+	"weight":       {column: "s.weight", sqlType: "numeric"},
+	"reps":         {column: "s.reps", sqlType: "integer"},
+	"position":     {column: "s.position", sqlType: "integer"},
 }
 
 type setRepository struct {
@@ -27,25 +53,13 @@ func NewSetRepository(db *sql.DB) SetRepository {
 	return &setRepository{db: db}
 }
 
-// Create creates a new set
-func (r *setRepository) Create(set *models.Set) error {
-	query := `
-		INSERT INTO sets (workout_id, exercise_id, weight, reps, rest_seconds, notes, rpe, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id_set, workout_id, exercise_id, weight, reps, rest_seconds, notes, rpe, created_at
-	`
+// setColumns is the column list read by scanSet; queries alias sets as s
+const setColumns = `s.id_set, s.workout_id, s.exercise_id, s.weight, s.reps, s.rest_seconds, s.notes, s.rpe, s.distance_meters, s.duration_seconds, s.set_type, s.group_id, s.position, s.performed_at, s.created_at, s.deleted_at`
 
-	err := r.db.QueryRow(
-		query,
-		set.WorkoutID,
-		set.ExerciseID,
-		set.Weight,
-		set.Reps,
-		set.RestSeconds,
-		set.Notes,
-		set.RPE,
-		set.CreatedAt,
-	).Scan(
+// scanSet scans the setColumns of a row, followed by any extra columns
+func scanSet(row rowScanner, extra ...interface{}) (*models.Set, error) {
+	set := &models.Set{}
+	dest := []interface{}{
 		&set.ID,
 		&set.WorkoutID,
 		&set.ExerciseID,
@@ -54,37 +68,114 @@ func (r *setRepository) Create(set *models.Set) error {
 		&set.RestSeconds,
 		&set.Notes,
 		&set.RPE,
+		&set.DistanceMeters,
+		&set.DurationSeconds,
+		&set.Type,
+		&set.GroupID,
+		&set.Position,
+		&set.PerformedAt,
 		&set.CreatedAt,
-	)
+		&set.DeletedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// Create creates a new set. A set without a position is appended to its workout;
+// otherwise the sets from that position on are shifted down to make room.
+func (r *setRepository) Create(set *models.Set) error {
+	return r.CreateBatch([]*models.Set{set})
+}
 
+// CreateBatch creates several sets in a single transaction, in order, placing each
+// one as Create does. Either every set is stored or none is.
+func (r *setRepository) CreateBatch(sets []*models.Set) error {
+	tx, err := r.db.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
+	created := make([]*models.Set, len(sets))
+	for i, set := range sets {
+		if created[i], err = insertSet(tx, set); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for i, set := range sets {
+		*set = *created[i]
+	}
 	return nil
 }
 
-// GetByID retrieves a set by ID
+// insertSet positions and inserts a set within a transaction and returns the stored row.
+// Positions past the end of the workout are clamped to right after its last set, so that
+// positions stay contiguous.
+func insertSet(tx *sql.Tx, set *models.Set) (*models.Set, error) {
+	if err := lockWorkoutSets(tx, set.WorkoutID); err != nil {
+		return nil, err
+	}
+
+	var position int
+	err := tx.QueryRow(`SELECT COALESCE(MAX(position), 0) + 1 FROM sets WHERE workout_id = $1 AND deleted_at IS NULL`, set.WorkoutID).Scan(&position)
+	if err != nil {
+		return nil, err
+	}
+	if set.Position > 0 && set.Position < position {
+		position = set.Position
+		_, err = tx.Exec(`UPDATE sets SET position = position + 1 WHERE workout_id = $1 AND position >= $2 AND deleted_at IS NULL`, set.WorkoutID, position)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+		INSERT INTO sets AS s (workout_id, exercise_id, weight, reps, rest_seconds, notes, rpe, distance_meters, duration_seconds, set_type, group_id, position, performed_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING ` + setColumns
+
+	return scanSet(tx.QueryRow(
+		query,
+		set.WorkoutID,
+		set.ExerciseID,
+		set.Weight,
+		set.Reps,
+		set.RestSeconds,
+		set.Notes,
+		set.RPE,
+		set.DistanceMeters,
+		set.DurationSeconds,
+		set.Type,
+		set.GroupID,
+		position,
+		set.PerformedAt,
+		set.CreatedAt,
+	))
+}
+
+// lockWorkoutSets locks a workout until the end of the transaction, so that concurrent
+// changes to the positions of its sets run one after the other
+func lockWorkoutSets(tx *sql.Tx, workoutID int64) error {
+	_, err := tx.Exec(`SELECT 1 FROM workouts WHERE id_workout = $1 FOR UPDATE`, workoutID)
+	return err
+}
+
+// GetByID retrieves a set by ID (only non-deleted)
 func (r *setRepository) GetByID(id int64) (*models.Set, error) {
-	set := &models.Set{}
 	query := `
-		SELECT id_set, workout_id, exercise_id, weight, reps, rest_seconds, notes, rpe, created_at 
-		FROM sets 
-		WHERE id_set = $1
+		SELECT ` + setColumns + ` 
+		FROM sets s 
+		WHERE s.id_set = $1 AND s.deleted_at IS NULL
 	`
 
-	err := r.db.QueryRow(query, id).Scan(
-		&set.ID,
-		&set.WorkoutID,
-		&set.ExerciseID,
-		&set.Weight,
-		&set.Reps,
-		&set.RestSeconds,
-		&set.Notes,
-		&set.RPE,
-		&set.CreatedAt,
-	)
-
+	set, err := scanSet(r.db.QueryRow(query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors.New("set not found")
@@ -98,13 +189,57 @@ func (r *setRepository) GetByID(id int64) (*models.Set, error) {
 // GetByWorkoutID retrieves all sets for a workout
 func (r *setRepository) GetByWorkoutID(workoutID int64) ([]*models.Set, error) {
 	query := `
-		SELECT id_set, workout_id, exercise_id, weight, reps, rest_seconds, notes, rpe, created_at 
-		FROM sets 
-		WHERE workout_id = $1 
-		ORDER BY created_at ASC
+		SELECT ` + setColumns + ` 
+		FROM sets s 
+		WHERE s.workout_id = $1 AND s.deleted_at IS NULL
+		ORDER BY s.position ASC, s.id_set ASC
+	`
+
+	return r.query(query, workoutID)
+}
+
+// GetByExerciseID retrieves all sets for an exercise
+func (r *setRepository) GetByExerciseID(exerciseID int64) ([]*models.Set, error) {
+	query := `
+		SELECT ` + setColumns + ` 
+		FROM sets s 
+		WHERE s.exercise_id = $1 AND s.deleted_at IS NULL
+		ORDER BY s.performed_at DESC
+	`
+
+	return r.query(query, exerciseID)
+}
+
+// GetByExerciseIDAndUserID retrieves all sets for an exercise logged in a user's workouts.
+// Ownership goes through the workout since catalog exercises are shared by all users.
+func (r *setRepository) GetByExerciseIDAndUserID(exerciseID, userID int64) ([]*models.Set, error) {
+	query := `
+		SELECT ` + setColumns + ` 
+		FROM sets s
+		INNER JOIN workouts w ON s.workout_id = w.id_workout
+		WHERE s.exercise_id = $1 AND w.user_id = $2 AND s.deleted_at IS NULL AND w.deleted_at IS NULL
+		ORDER BY s.performed_at DESC
+	`
+
+	return r.query(query, exerciseID, userID)
+}
+
+// GetByExerciseIDAndDateRange retrieves a user's sets for an exercise performed within a date range
+func (r *setRepository) GetByExerciseIDAndDateRange(exerciseID, userID int64, startDate, endDate time.Time) ([]*models.Set, error) {
+	query := `
+		SELECT ` + setColumns + ` 
+		FROM sets s
+		INNER JOIN workouts w ON s.workout_id = w.id_workout
+		WHERE s.exercise_id = $1 AND w.user_id = $2 AND s.deleted_at IS NULL AND w.deleted_at IS NULL AND s.performed_at >= $3 AND s.performed_at <= $4
+		ORDER BY s.performed_at ASC
 	`
 
-	rows, err := r.db.Query(query, workoutID)
+	return r.query(query, exerciseID, userID, startDate, endDate)
+}
+
+// query runs a select of setColumns and scans every row
+func (r *setRepository) query(query string, args ...interface{}) ([]*models.Set, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -112,18 +247,7 @@ func (r *setRepository) GetByWorkoutID(workoutID int64) ([]*models.Set, error) {
 
 	var sets []*models.Set
 	for rows.Next() {
-		set := &models.Set{}
-		err := rows.Scan(
-			&set.ID,
-			&set.WorkoutID,
-			&set.ExerciseID,
-			&set.Weight,
-			&set.Reps,
-			&set.RestSeconds,
-			&set.Notes,
-			&set.RPE,
-			&set.CreatedAt,
-		)
+		set, err := scanSet(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -133,118 +257,229 @@ func (r *setRepository) GetByWorkoutID(workoutID int64) ([]*models.Set, error) {
 	return sets, rows.Err()
 }
 
-// GetByExerciseID retrieves all sets for an exercise
-func (r *setRepository) GetByExerciseID(exerciseID int64) ([]*models.Set, error) {
-	query := `
-		SELECT id_set, workout_id, exercise_id, weight, reps, rest_seconds, notes, rpe, created_at 
-		FROM sets 
-		WHERE exercise_id = $1 
-		ORDER BY created_at DESC
-	`
+// ListByWorkoutID retrieves a page of a workout's sets matching the filter and the cursor of the next page
+func (r *setRepository) ListByWorkoutID(workoutID int64, filter *models.SetFilter) ([]*models.Set, string, error) {
+	b := &queryBuilder{}
+	b.where("s.workout_id = %s", workoutID)
+	return r.list("FROM sets s", b, filter)
+}
+
+// ListByExerciseIDAndUserID retrieves a page of a user's sets of an exercise matching the filter and the cursor of the next page
+func (r *setRepository) ListByExerciseIDAndUserID(exerciseID, userID int64, filter *models.SetFilter) ([]*models.Set, string, error) {
+	b := &queryBuilder{}
+	b.where("s.exercise_id = %s", exerciseID)
+	b.where("w.user_id = %s", userID)
+	b.conditions = append(b.conditions, "w.deleted_at IS NULL")
+	return r.list("FROM sets s INNER JOIN workouts w ON s.workout_id = w.id_workout", b, filter)
+}
 
-	rows, err := r.db.Query(query, exerciseID)
+// list applies the set filter and keyset pagination on top of the base conditions
+func (r *setRepository) list(from string, b *queryBuilder, filter *models.SetFilter) ([]*models.Set, string, error) {
+	page, err := newKeyset(&filter.ListParams, setSortColumns, "s.id_set")
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	applySetFilter(b, filter)
+	page.apply(b)
+
+	query := fmt.Sprintf(`
+		SELECT %s, %s
+		%s
+		%s
+		%s
+	`, setColumns, page.selectValue(), from, b.whereClause(), page.orderBy(b))
+
+	rows, err := r.db.Query(query, b.args...)
+	if err != nil {
+		return nil, "", err
 	}
 	defer rows.Close()
 
 	var sets []*models.Set
+	var sortValues []string
 	for rows.Next() {
-		set := &models.Set{}
-		err := rows.Scan(
-			&set.ID,
-			&set.WorkoutID,
-			&set.ExerciseID,
-			&set.Weight,
-			&set.Reps,
-			&set.RestSeconds,
-			&set.Notes,
-			&set.RPE,
-			&set.CreatedAt,
-		)
+		var sortValue string
+		set, err := scanSet(rows, &sortValue)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		sets = append(sets, set)
+		sortValues = append(sortValues, sortValue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
 	}
 
-	return sets, rows.Err()
+	next := ""
+	if page.hasMore(len(sets)) {
+		sets = sets[:filter.Limit]
+		last := len(sets) - 1
+		next = page.nextCursor(sortValues[last], sets[last].ID)
+	}
+
+	return sets, next, nil
 }
 
-// GetByExerciseIDAndUserID retrieves all sets for an exercise that belong to a user
-func (r *setRepository) GetByExerciseIDAndUserID(exerciseID, userID int64) ([]*models.Set, error) {
+// applySetFilter adds the conditions of a set filter, leaving out deleted sets
+func applySetFilter(b *queryBuilder, filter *models.SetFilter) {
+	b.conditions = append(b.conditions, "s.deleted_at IS NULL")
+	if filter.From != nil {
+		b.where("s.performed_at >= %s", *filter.From)
+	}
+	if filter.To != nil {
+		b.where("s.performed_at < %s", *filter.To)
+	}
+	if filter.MinWeight != nil {
+		b.where("s.weight >= %s", *filter.MinWeight)
+	}
+	if filter.MaxWeight != nil {
+		b.where("s.weight <= %s", *filter.MaxWeight)
+	}
+	if filter.MinReps != nil {
+		b.where("s.reps >= %s", *filter.MinReps)
+	}
+	if filter.MaxReps != nil {
+		b.where("s.reps <= %s", *filter.MaxReps)
+	}
+	if filter.MinRPE != nil {
+		b.where("s.rpe >= %s", *filter.MinRPE)
+	}
+	if filter.MaxRPE != nil {
+		b.where("s.rpe <= %s", *filter.MaxRPE)
+	}
+	if filter.Type != "" {
+		b.where("s.set_type = %s", filter.Type)
+	}
+}
+
+// GetByIDAndWorkoutID retrieves a set by ID and ensures it belongs to the workout (only non-deleted)
+func (r *setRepository) GetByIDAndWorkoutID(id, workoutID int64) (*models.Set, error) {
 	query := `
-		SELECT s.id_set, s.workout_id, s.exercise_id, s.weight, s.reps, s.rest_seconds, s.notes, s.rpe, s.created_at 
-		FROM sets s
-		INNER JOIN exercises e ON s.exercise_id = e.id_exercise
-		WHERE s.exercise_id = $1 AND e.user_id = $2
-		ORDER BY s.created_at DESC
+		SELECT ` + setColumns + ` 
+		FROM sets s 
+		WHERE s.id_set = $1 AND s.workout_id = $2 AND s.deleted_at IS NULL
 	`
 
-	rows, err := r.db.Query(query, exerciseID, userID)
+	set, err := scanSet(r.db.QueryRow(query, id, workoutID))
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("set not found")
+		}
 		return nil, err
 	}
-	defer rows.Close()
 
-	var sets []*models.Set
-	for rows.Next() {
-		set := &models.Set{}
-		err := rows.Scan(
-			&set.ID,
-			&set.WorkoutID,
-			&set.ExerciseID,
-			&set.Weight,
-			&set.Reps,
-			&set.RestSeconds,
-			&set.Notes,
-			&set.RPE,
-			&set.CreatedAt,
-		)
-		if err != nil {
-			return nil, err
+	return set, nil
+}
+
+// Update updates the values of an existing set (only non-deleted); the position is kept
+func (r *setRepository) Update(set *models.Set) error {
+	query := `
+		UPDATE sets AS s
+		SET exercise_id = $1, weight = $2, reps = $3, rest_seconds = $4, notes = $5, rpe = $6,
+			distance_meters = $7, duration_seconds = $8, set_type = $9, group_id = $10, performed_at = $11
+		WHERE s.id_set = $12 AND s.workout_id = $13 AND s.deleted_at IS NULL
+		RETURNING ` + setColumns
+
+	updated, err := scanSet(r.db.QueryRow(
+		query,
+		set.ExerciseID,
+		set.Weight,
+		set.Reps,
+		set.RestSeconds,
+		set.Notes,
+		set.RPE,
+		set.DistanceMeters,
+		set.DurationSeconds,
+		set.Type,
+		set.GroupID,
+		set.PerformedAt,
+		set.ID,
+		set.WorkoutID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("set not found")
 		}
-		sets = append(sets, set)
+		return err
 	}
 
-	return sets, rows.Err()
+	*set = *updated
+	return nil
 }
 
-// GetByExerciseIDAndDateRange retrieves sets for an exercise within a date range
-func (r *setRepository) GetByExerciseIDAndDateRange(exerciseID int64, startDate, endDate time.Time) ([]*models.Set, error) {
-	query := `
-		SELECT s.id_set, s.workout_id, s.exercise_id, s.weight, s.reps, s.rest_seconds, s.notes, s.rpe, s.created_at 
-		FROM sets s
-		WHERE s.exercise_id = $1 AND s.created_at >= $2 AND s.created_at <= $3
-		ORDER BY s.created_at ASC
-	`
-
-	rows, err := r.db.Query(query, exerciseID, startDate, endDate)
+// Delete performs a soft delete on a set and closes the gap it leaves in the workout order
+func (r *setRepository) Delete(id, workoutID int64) error {
+	tx, err := r.db.Begin()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
+	defer tx.Rollback()
 
-	var sets []*models.Set
-	for rows.Next() {
-		set := &models.Set{}
-		err := rows.Scan(
-			&set.ID,
-			&set.WorkoutID,
-			&set.ExerciseID,
-			&set.Weight,
-			&set.Reps,
-			&set.RestSeconds,
-			&set.Notes,
-			&set.RPE,
-			&set.CreatedAt,
-		)
-		if err != nil {
-			return nil, err
+	if err := lockWorkoutSets(tx, workoutID); err != nil {
+		return err
+	}
+
+	var position int
+	err = tx.QueryRow(`
+		UPDATE sets 
+		SET deleted_at = CURRENT_TIMESTAMP
+		WHERE id_set = $1 AND workout_id = $2 AND deleted_at IS NULL
+		RETURNING position
+	`, id, workoutID).Scan(&position)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("set not found")
 		}
-		sets = append(sets, set)
+		return err
 	}
 
-	return sets, rows.Err()
+	_, err = tx.Exec(`UPDATE sets SET position = position - 1 WHERE workout_id = $1 AND position > $2 AND deleted_at IS NULL`, workoutID, position)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
+// Reorder sets the position of every set of a workout from the order of setIDs,
+// which must list each non-deleted set of the workout exactly once
+func (r *setRepository) Reorder(workoutID int64, setIDs []int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockWorkoutSets(tx, workoutID); err != nil {
+		return err
+	}
+
+	var count int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM sets WHERE workout_id = $1 AND deleted_at IS NULL`, workoutID).Scan(&count); err != nil {
+		return err
+	}
+	if count != len(setIDs) {
+		return errors.New("invalid set order")
+	}
+
+	result, err := tx.Exec(`
+		UPDATE sets s
+		SET position = o.position
+		FROM unnest($2::bigint[]) WITH ORDINALITY AS o(id_set, position)
+		WHERE s.id_set = o.id_set AND s.workout_id = $1 AND s.deleted_at IS NULL
+	`, workoutID, pq.Array(setIDs))
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected != int64(len(setIDs)) {
+		return errors.New("invalid set order")
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+// recordingDriver is a database driver that records the statements it runs; queries return no rows
+type recordingDriver struct {
+	mu         sync.Mutex
+	statements []string
+}
+
+func (d *recordingDriver) Open(name string) (driver.Conn, error) {
+	return &recordingConn{driver: d}, nil
+}
+
+func (d *recordingDriver) record(query string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.statements = append(d.statements, strings.Join(strings.Fields(query), " "))
+}
+
+type recordingConn struct {
+	driver *recordingDriver
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordingStmt{driver: c.driver, query: query}, nil
+}
+
+func (c *recordingConn) Close() error              { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error) { return c, nil }
+func (c *recordingConn) Commit() error             { return nil }
+func (c *recordingConn) Rollback() error           { return nil }
+
+type recordingStmt struct {
+	driver *recordingDriver
+	query  string
+}
+
+func (s *recordingStmt) Close() error  { return nil }
+func (s *recordingStmt) NumInput() int { return -1 }
+
+func (s *recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.driver.record(s.query)
+	return driver.RowsAffected(0), nil
+}
+
+func (s *recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.driver.record(s.query)
+	return emptyRows{}, nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string              { return nil }
+func (emptyRows) Close() error                   { return nil }
+func (emptyRows) Next(dest []driver.Value) error { return io.EOF }
+
+// recordingConnector connects to a recording driver
+type recordingConnector struct {
+	driver *recordingDriver
+}
+
+func (c recordingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.driver.Open("")
+}
+
+func (c recordingConnector) Driver() driver.Driver { return c.driver }
+
+// newRecordingDB returns a database recording the statements run against it
+func newRecordingDB(t *testing.T) (*sql.DB, *recordingDriver) {
+	t.Helper()
+	d := &recordingDriver{}
+	db := sql.OpenDB(recordingConnector{driver: d})
+	t.Cleanup(func() { db.Close() })
+	return db, d
+}
+
+func TestExerciseSetsLeaveOutDeletedWorkouts(t *testing.T) {
+	db, recorded := newRecordingDB(t)
+	repo := &setRepository{db: db}
+
+	// A soft-deleted workout keeps its sets, so every read of a user's sets of an exercise,
+	// history and metrics alike, has to leave its sets out
+	repo.GetByExerciseIDAndUserID(1, 2)
+	repo.GetByExerciseIDAndDateRange(1, 2, time.Now().AddDate(0, -1, 0), time.Now())
+	repo.ListByExerciseIDAndUserID(1, 2, &models.SetFilter{ListParams: models.ListParams{Limit: 20, Sort: "performed_at"}})
+	repo.GetMetrics(1, 2, &models.SetFilter{}, models.E1RMFormulaEpley)
+
+	if len(recorded.statements) != 4 {
+		t.Fatalf("expected 4 queries, got %d", len(recorded.statements))
+	}
+	for _, statement := range recorded.statements {
+		if !strings.Contains(statement, "w.deleted_at IS NULL") {
+			t.Errorf("expected the sets of deleted workouts to be left out of %q", statement)
+		}
+	}
+}
+
+func TestSetPositionChangesLockTheWorkout(t *testing.T) {
+	changes := map[string]func(repo *setRepository) error{
+		"create": func(repo *setRepository) error {
+			return repo.CreateBatch([]*models.Set{{WorkoutID: 3, Position: 1}})
+		},
+		"delete": func(repo *setRepository) error {
+			return repo.Delete(7, 3)
+		},
+		"reorder": func(repo *setRepository) error {
+			return repo.Reorder(3, []int64{7, 8})
+		},
+	}
+	for name, change := range changes {
+		t.Run(name, func(t *testing.T) {
+			db, recorded := newRecordingDB(t)
+			change(&setRepository{db: db})
+
+			// Positions are read and shifted only once the workout is locked
+			if len(recorded.statements) == 0 || recorded.statements[0] != "SELECT 1 FROM workouts WHERE id_workout = $1 FOR UPDATE" {
+				t.Errorf("expected the workout to be locked first, got %q", recorded.statements)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"phoenix-alliance-be/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// WorkoutTemplateRepository defines the interface for workout template data operations
+type WorkoutTemplateRepository interface {
+	Create(template *models.WorkoutTemplate) error
+	GetByIDAndUserID(id, userID int64) (*models.WorkoutTemplate, error)
+	GetByUserID(userID int64) ([]*models.WorkoutTemplate, error)
+	Update(template *models.WorkoutTemplate) error
+	Delete(id, userID int64) error
+}
+
+type workoutTemplateRepository struct {
+	db *sql.DB
+}
+
+// NewWorkoutTemplateRepository creates a new workout template repository
+func NewWorkoutTemplateRepository(db *sql.DB) WorkoutTemplateRepository {
+	return &workoutTemplateRepository{db: db}
+}
+
+// Create creates a new template together with its exercises
+func (r *workoutTemplateRepository) Create(template *models.WorkoutTemplate) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO workout_templates (user_id, name, notes, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id_template, user_id, name, notes, created_at, deleted_at
+	`
+
+	err = tx.QueryRow(
+		query,
+		template.UserID,
+		template.Name,
+		template.Notes,
+		template.CreatedAt,
+	).Scan(
+		&template.ID,
+		&template.UserID,
+		&template.Name,
+		&template.Notes,
+		&template.CreatedAt,
+		&template.DeletedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := insertTemplateExercises(tx, template); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetByIDAndUserID retrieves a template by ID and ensures it belongs to the user (only non-deleted)
+func (r *workoutTemplateRepository) GetByIDAndUserID(id, userID int64) (*models.WorkoutTemplate, error) {
+	template := &models.WorkoutTemplate{}
+	query := `SELECT id_template, user_id, name, notes, created_at, deleted_at FROM workout_templates WHERE id_template = $1 AND user_id = $2 AND deleted_at IS NULL`
+
+	err := r.db.QueryRow(query, id, userID).Scan(
+		&template.ID,
+		&template.UserID,
+		&template.Name,
+		&template.Notes,
+		&template.CreatedAt,
+		&template.DeletedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("template not found")
+		}
+		return nil, err
+	}
+
+	if err := r.loadExercises([]*models.WorkoutTemplate{template}); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// GetByUserID retrieves all templates for a user (only non-deleted)
+func (r *workoutTemplateRepository) GetByUserID(userID int64) ([]*models.WorkoutTemplate, error) {
+	query := `
+		SELECT id_template, user_id, name, notes, created_at, deleted_at
+		FROM workout_templates
+		WHERE user_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []*models.WorkoutTemplate
+	for rows.Next() {
+		template := &models.WorkoutTemplate{}
+		err := rows.Scan(
+			&template.ID,
+			&template.UserID,
+			&template.Name,
+			&template.Notes,
+			&template.CreatedAt,
+			&template.DeletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadExercises(templates); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// Update updates an existing template and replaces its exercises (only non-deleted)
+func (r *workoutTemplateRepository) Update(template *models.WorkoutTemplate) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE workout_templates
+		SET name = $1, notes = $2
+		WHERE id_template = $3 AND user_id = $4 AND deleted_at IS NULL
+		RETURNING id_template, user_id, name, notes, created_at, deleted_at
+	`
+
+	err = tx.QueryRow(
+		query,
+		template.Name,
+		template.Notes,
+		template.ID,
+		template.UserID,
+	).Scan(
+		&template.ID,
+		&template.UserID,
+		&template.Name,
+		&template.Notes,
+		&template.CreatedAt,
+		&template.DeletedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("template not found")
+		}
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM template_exercises WHERE template_id = $1`, template.ID); err != nil {
+		return err
+	}
+
+	if err := insertTemplateExercises(tx, template); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete performs a soft delete on a template for a user
+func (r *workoutTemplateRepository) Delete(id, userID int64) error {
+	query := `
+		UPDATE workout_templates
+		SET deleted_at = CURRENT_TIMESTAMP
+		WHERE id_template = $1 AND user_id = $2 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("template not found")
+	}
+
+	return nil
+}
+
+// insertTemplateExercises inserts the exercises of a template in order
+func insertTemplateExercises(tx *sql.Tx, template *models.WorkoutTemplate) error {
+	query := `
+		INSERT INTO template_exercises (template_id, exercise_id, position, target_sets, target_reps, target_weight, target_percent_e1rm, rest_seconds)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id_template_exercise
+	`
+
+	for i, exercise := range template.Exercises {
+		exercise.TemplateID = template.ID
+		exercise.Position = i
+		err := tx.QueryRow(
+			query,
+			exercise.TemplateID,
+			exercise.ExerciseID,
+			exercise.Position,
+			exercise.TargetSets,
+			exercise.TargetReps,
+			exercise.TargetWeight,
+			exercise.TargetPercentE1RM,
+			exercise.RestSeconds,
+		).Scan(&exercise.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadExercises attaches the ordered exercises to each template
+func (r *workoutTemplateRepository) loadExercises(templates []*models.WorkoutTemplate) error {
+	if len(templates) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*models.WorkoutTemplate, len(templates))
+	ids := make([]int64, len(templates))
+	for i, template := range templates {
+		template.Exercises = []*models.TemplateExercise{}
+		byID[template.ID] = template
+		ids[i] = template.ID
+	}
+
+	query := `
+		SELECT id_template_exercise, template_id, exercise_id, position, target_sets, target_reps, target_weight, target_percent_e1rm, rest_seconds
+		FROM template_exercises
+		WHERE template_id = ANY($1)
+		ORDER BY template_id, position ASC
+	`
+
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		exercise := &models.TemplateExercise{}
+		err := rows.Scan(
+			&exercise.ID,
+			&exercise.TemplateID,
+			&exercise.ExerciseID,
+			&exercise.Position,
+			&exercise.TargetSets,
+			&exercise.TargetReps,
+			&exercise.TargetWeight,
+			&exercise.TargetPercentE1RM,
+			&exercise.RestSeconds,
+		)
+		if err != nil {
+			return err
+		}
+		if template, ok := byID[exercise.TemplateID]; ok {
+			template.Exercises = append(template.Exercises, exercise)
+		}
+	}
+
+	return rows.Err()
+}
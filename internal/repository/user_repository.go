@@ -3,9 +3,12 @@ package repository
 import (
 	"database/sql"
 	"errors"
+	"time"
 
 	"phoenix-alliance-be/internal/models"
 
+	"github.com/lib/pq"
+
 )
 
 // UserRepository defines the interface for user data operations
@@ -13,6 +16,14 @@ type UserRepository interface {
 	Create(user *models.User) error
 	GetByID(id int64) (*models.User, error)
 	GetByEmail(email string) (*models.User, error)
+	UpdateProfile(user *models.User) error
+	UpdatePassword(userID int64, passwordHash string) error
+	MarkVerified(userID int64, email string, at time.Time) error
+	UpdateEmail(user *models.User) error
+	ScheduleDeletion(userID int64, at time.Time) error
+	CancelDeletion(userID int64) error
+	Delete(userID int64) error
+	DeleteScheduled(before time.Time) (int64, error)
 }
 
 type userRepository struct {
@@ -27,17 +38,18 @@ func NewUserRepository(db *sql.DB) UserRepository {
 // Create creates a new user
 func (r *userRepository) Create(user *models.User) error {
 	query := `
-		INSERT INTO users (email, password, created_at)
-		VALUES ($1, $2, $3)
-		RETURNING id_user, email, created_at
+		INSERT INTO users (email, password, timezone, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id_user, email, timezone, created_at
 	`
 
 	err := r.db.QueryRow(
 		query,
 		user.Email,
 		user.Password,
+		user.Timezone,
 		user.CreatedAt,
-	).Scan(&user.ID, &user.Email, &user.CreatedAt)
+	).Scan(&user.ID, &user.Email, &user.Timezone, &user.CreatedAt)
 
 	if err != nil {
 		return err
@@ -49,13 +61,16 @@ func (r *userRepository) Create(user *models.User) error {
 // GetByID retrieves a user by ID
 func (r *userRepository) GetByID(id int64) (*models.User, error) {
 	user := &models.User{}
-	query := `SELECT id_user, email, password, created_at FROM users WHERE id_user = $1`
+	query := `SELECT id_user, email, password, timezone, verified_at, created_at, deletion_scheduled_at FROM users WHERE id_user = $1`
 
 	err := r.db.QueryRow(query, id).Scan(
 		&user.ID,
 		&user.Email,
 		&user.Password,
+		&user.Timezone,
+		&user.VerifiedAt,
 		&user.CreatedAt,
+		&user.DeletionScheduledAt,
 	)
 
 	if err != nil {
@@ -71,13 +86,16 @@ func (r *userRepository) GetByID(id int64) (*models.User, error) {
 // GetByEmail retrieves a user by email
 func (r *userRepository) GetByEmail(email string) (*models.User, error) {
 	user := &models.User{}
-	query := `SELECT id_user, email, password, created_at FROM users WHERE email = $1`
+	query := `SELECT id_user, email, password, timezone, verified_at, created_at, deletion_scheduled_at FROM users WHERE email = $1`
 
 	err := r.db.QueryRow(query, email).Scan(
 		&user.ID,
 		&user.Email,
 		&user.Password,
+		&user.Timezone,
+		&user.VerifiedAt,
 		&user.CreatedAt,
+		&user.DeletionScheduledAt,
 	)
 
 	if err != nil {
@@ -89,3 +107,114 @@ func (r *userRepository) GetByEmail(email string) (*models.User, error) {
 
 	return user, nil
 }
+
+// UpdateProfile updates the profile settings of a user
+func (r *userRepository) UpdateProfile(user *models.User) error {
+	query := `
+		UPDATE users
+		SET timezone = $1
+		WHERE id_user = $2
+		RETURNING id_user, email, timezone, verified_at, created_at, deletion_scheduled_at
+	`
+
+	err := r.db.QueryRow(query, user.Timezone, user.ID).Scan(
+		&user.ID,
+		&user.Email,
+		&user.Timezone,
+		&user.VerifiedAt,
+		&user.CreatedAt,
+		&user.DeletionScheduledAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("user not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// UpdatePassword replaces the password hash of a user
+func (r *userRepository) UpdatePassword(userID int64, passwordHash string) error {
+	return r.execOnUser(`UPDATE users SET password = $1 WHERE id_user = $2`, passwordHash, userID)
+}
+
+// MarkVerified records that a user verified their email address. Nothing changes when the
+// address of the user is no longer the one that was verified.
+func (r *userRepository) MarkVerified(userID int64, email string, at time.Time) error {
+	query := `
+		UPDATE users
+		SET verified_at = COALESCE(verified_at, $1)
+		WHERE id_user = $2 AND email = $3
+	`
+
+	return r.execOnUser(query, at, userID, email)
+}
+
+// UpdateEmail changes the email address of a user, which is unverified until the new address is verified
+func (r *userRepository) UpdateEmail(user *models.User) error {
+	query := `
+		UPDATE users
+		SET email = $1, verified_at = NULL
+		WHERE id_user = $2
+		RETURNING verified_at
+	`
+
+	err := r.db.QueryRow(query, user.Email, user.ID).Scan(&user.VerifiedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("user not found")
+		}
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return errors.New("user with this email already exists")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ScheduleDeletion sets the date an account and all its data are purged
+func (r *userRepository) ScheduleDeletion(userID int64, at time.Time) error {
+	return r.execOnUser(`UPDATE users SET deletion_scheduled_at = $1 WHERE id_user = $2`, at, userID)
+}
+
+// CancelDeletion keeps an account whose deletion was scheduled
+func (r *userRepository) CancelDeletion(userID int64) error {
+	return r.execOnUser(`UPDATE users SET deletion_scheduled_at = NULL WHERE id_user = $1`, userID)
+}
+
+// Delete deletes a user; workouts, sets, exercises and every other row of the user cascade
+func (r *userRepository) Delete(userID int64) error {
+	return r.execOnUser(`DELETE FROM users WHERE id_user = $1`, userID)
+}
+
+// DeleteScheduled deletes the users whose deletion date is before a time and returns how many were deleted
+func (r *userRepository) DeleteScheduled(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM users WHERE deletion_scheduled_at <= $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// execOnUser runs a statement that must affect one user
+func (r *userRepository) execOnUser(query string, args ...interface{}) error {
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
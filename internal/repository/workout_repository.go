@@ -3,6 +3,8 @@ package repository
 import (
 	"database/sql"
 	"errors"
+	"fmt"
+	"time"
 
 	"phoenix-alliance-be/internal/models"
 )
@@ -10,11 +12,50 @@ import (
 // WorkoutRepository defines the interface for workout data operations
 type WorkoutRepository interface {
 	Create(workout *models.Workout) error
+	CreateWithPlannedSets(workout *models.Workout, plannedSets []*models.PlannedSet) error
 	GetByID(id int64) (*models.Workout, error)
 	GetByIDAndUserID(id, userID int64) (*models.Workout, error)
 	GetByUserID(userID int64) ([]*models.Workout, error)
+	List(userID int64, filter *models.WorkoutFilter) ([]*models.Workout, string, error)
 	Update(workout *models.Workout) error
+	UpdateSession(workout *models.Workout) error
 	Delete(id, userID int64) error
+	GetVolume(id int64) (float64, error)
+	GetTrainingDays(userID int64, loc *time.Location) ([]models.TrainingDay, error)
+	GetDailySessionLoad(userID int64, from, to time.Time, loc *time.Location) ([]models.DailyLoad, error)
+}
+
+// workoutSortColumns are the columns workouts can be sorted by
+var workoutSortColumns = map[string]sortColumn{
+	"performed_at": {column: "performed_at", sqlType: "timestamptz"},
+	"created_at":   {column: "created_at", sqlType: "timestamptz"},
+	"name":         {column: "name", sqlType: "text"},
+}
+
+// workoutColumns is the column list read by scanWorkout
+const workoutColumns = `id_workout, user_id, name, status, started_at, finished_at, bodyweight, session_rpe, notes, performed_at, created_at, deleted_at`
+
+// scanWorkout scans the workoutColumns of a row, followed by any extra columns
+func scanWorkout(row rowScanner, extra ...interface{}) (*models.Workout, error) {
+	workout := &models.Workout{}
+	dest := []interface{}{
+		&workout.ID,
+		&workout.UserID,
+		&workout.Name,
+		&workout.Status,
+		&workout.StartedAt,
+		&workout.FinishedAt,
+		&workout.Bodyweight,
+		&workout.SessionRPE,
+		&workout.Notes,
+		&workout.PerformedAt,
+		&workout.CreatedAt,
+		&workout.DeletedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return workout, nil
 }
 
 type workoutRepository struct {
@@ -28,45 +69,65 @@ func NewWorkoutRepository(db *sql.DB) WorkoutRepository {
 
 // Create creates a new workout
 func (r *workoutRepository) Create(workout *models.Workout) error {
+	return insertWorkout(r.db, workout)
+}
+
+// CreateWithPlannedSets creates a new workout with its planned set slots in a single
+// transaction, so that no workout is left behind without them
+func (r *workoutRepository) CreateWithPlannedSets(workout *models.Workout, plannedSets []*models.PlannedSet) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	created := *workout
+	if err := insertWorkout(tx, &created); err != nil {
+		return err
+	}
+	for _, plannedSet := range plannedSets {
+		plannedSet.WorkoutID = created.ID
+		if err := insertPlannedSet(tx, plannedSet); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	*workout = created
+	return nil
+}
+
+// insertWorkout inserts a workout with the database or a transaction
+func insertWorkout(q rowQuerier, workout *models.Workout) error {
 	query := `
-		INSERT INTO workouts (user_id, name, created_at)
-		VALUES ($1, $2, $3)
-		RETURNING id_workout, user_id, name, created_at, deleted_at
-	`
+		INSERT INTO workouts (user_id, name, status, performed_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + workoutColumns
 
-	err := r.db.QueryRow(
+	created, err := scanWorkout(q.QueryRow(
 		query,
 		workout.UserID,
 		workout.Name,
+		workout.Status,
+		workout.PerformedAt,
 		workout.CreatedAt,
-	).Scan(
-		&workout.ID,
-		&workout.UserID,
-		&workout.Name,
-		&workout.CreatedAt,
-		&workout.DeletedAt,
-	)
-
+	))
 	if err != nil {
 		return err
 	}
 
+	*workout = *created
 	return nil
 }
 
 // GetByID retrieves a workout by ID
 func (r *workoutRepository) GetByID(id int64) (*models.Workout, error) {
-	workout := &models.Workout{}
-	query := `SELECT id_workout, user_id, name, created_at, deleted_at FROM workouts WHERE id_workout = $1 AND deleted_at IS NULL`
-
-	err := r.db.QueryRow(query, id).Scan(
-		&workout.ID,
-		&workout.UserID,
-		&workout.Name,
-		&workout.CreatedAt,
-		&workout.DeletedAt,
-	)
+	query := `SELECT ` + workoutColumns + ` FROM workouts WHERE id_workout = $1 AND deleted_at IS NULL`
 
+	workout, err := scanWorkout(r.db.QueryRow(query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors.New("workout not found")
@@ -79,17 +140,9 @@ func (r *workoutRepository) GetByID(id int64) (*models.Workout, error) {
 
 // GetByIDAndUserID retrieves a workout by ID and ensures it belongs to the user
 func (r *workoutRepository) GetByIDAndUserID(id, userID int64) (*models.Workout, error) {
-	workout := &models.Workout{}
-	query := `SELECT id_workout, user_id, name, created_at, deleted_at FROM workouts WHERE id_workout = $1 AND user_id = $2 AND deleted_at IS NULL`
-
-	err := r.db.QueryRow(query, id, userID).Scan(
-		&workout.ID,
-		&workout.UserID,
-		&workout.Name,
-		&workout.CreatedAt,
-		&workout.DeletedAt,
-	)
+	query := `SELECT ` + workoutColumns + ` FROM workouts WHERE id_workout = $1 AND user_id = $2 AND deleted_at IS NULL`
 
+	workout, err := scanWorkout(r.db.QueryRow(query, id, userID))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors.New("workout not found")
@@ -103,10 +156,10 @@ func (r *workoutRepository) GetByIDAndUserID(id, userID int64) (*models.Workout,
 // GetByUserID retrieves all workouts for a user
 func (r *workoutRepository) GetByUserID(userID int64) ([]*models.Workout, error) {
 	query := `
-		SELECT id_workout, user_id, name, created_at, deleted_at
+		SELECT ` + workoutColumns + `
 		FROM workouts
 		WHERE user_id = $1 AND deleted_at IS NULL
-		ORDER BY created_at DESC
+		ORDER BY performed_at DESC
 	`
 
 	rows, err := r.db.Query(query, userID)
@@ -117,14 +170,7 @@ func (r *workoutRepository) GetByUserID(userID int64) ([]*models.Workout, error)
 
 	var workouts []*models.Workout
 	for rows.Next() {
-		workout := &models.Workout{}
-		err := rows.Scan(
-			&workout.ID,
-			&workout.UserID,
-			&workout.Name,
-			&workout.CreatedAt,
-			&workout.DeletedAt,
-		)
+		workout, err := scanWorkout(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -134,28 +180,99 @@ func (r *workoutRepository) GetByUserID(userID int64) ([]*models.Workout, error)
 	return workouts, rows.Err()
 }
 
+// List retrieves a page of a user's workouts matching the filter and the cursor of the next page
+func (r *workoutRepository) List(userID int64, filter *models.WorkoutFilter) ([]*models.Workout, string, error) {
+	page, err := newKeyset(&filter.ListParams, workoutSortColumns, "id_workout")
+	if err != nil {
+		return nil, "", err
+	}
+
+	b := &queryBuilder{}
+	b.where("user_id = %s AND deleted_at IS NULL", userID)
+	if filter.From != nil {
+		b.where("performed_at >= %s", *filter.From)
+	}
+	if filter.To != nil {
+		b.where("performed_at < %s", *filter.To)
+	}
+	if filter.Search != "" {
+		b.where("name ILIKE %s", likePattern(filter.Search))
+	}
+	page.apply(b)
+
+	query := fmt.Sprintf(`
+		SELECT %s, %s
+		FROM workouts
+		%s
+		%s
+	`, workoutColumns, page.selectValue(), b.whereClause(), page.orderBy(b))
+
+	rows, err := r.db.Query(query, b.args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var workouts []*models.Workout
+	var sortValues []string
+	for rows.Next() {
+		var sortValue string
+		workout, err := scanWorkout(rows, &sortValue)
+		if err != nil {
+			return nil, "", err
+		}
+		workouts = append(workouts, workout)
+		sortValues = append(sortValues, sortValue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if page.hasMore(len(workouts)) {
+		workouts = workouts[:filter.Limit]
+		last := len(workouts) - 1
+		next = page.nextCursor(sortValues[last], workouts[last].ID)
+	}
+
+	return workouts, next, nil
+}
+
 // Update updates an existing workout (only non-deleted)
 func (r *workoutRepository) Update(workout *models.Workout) error {
 	query := `
 		UPDATE workouts
-		SET name = $1
-		WHERE id_workout = $2 AND user_id = $3 AND deleted_at IS NULL
-		RETURNING id_workout, user_id, name, created_at, deleted_at
-	`
+		SET name = $1, performed_at = $2
+		WHERE id_workout = $3 AND user_id = $4 AND deleted_at IS NULL
+		RETURNING ` + workoutColumns
 
-	err := r.db.QueryRow(
-		query,
-		workout.Name,
+	return r.update(workout, query, workout.Name, workout.PerformedAt, workout.ID, workout.UserID)
+}
+
+// UpdateSession stores the session state, timing and notes of a workout (only non-deleted)
+func (r *workoutRepository) UpdateSession(workout *models.Workout) error {
+	query := `
+		UPDATE workouts
+		SET status = $1, started_at = $2, finished_at = $3, bodyweight = $4, session_rpe = $5, notes = $6, performed_at = $7
+		WHERE id_workout = $8 AND user_id = $9 AND deleted_at IS NULL
+		RETURNING ` + workoutColumns
+
+	return r.update(workout, query,
+		workout.Status,
+		workout.StartedAt,
+		workout.FinishedAt,
+		workout.Bodyweight,
+		workout.SessionRPE,
+		workout.Notes,
+		workout.PerformedAt,
 		workout.ID,
 		workout.UserID,
-	).Scan(
-		&workout.ID,
-		&workout.UserID,
-		&workout.Name,
-		&workout.CreatedAt,
-		&workout.DeletedAt,
 	)
+}
 
+// update runs an update returning the workoutColumns and stores the result in workout
+func (r *workoutRepository) update(workout *models.Workout, query string, args ...interface{}) error {
+	updated, err := scanWorkout(r.db.QueryRow(query, args...))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return errors.New("workout not found")
@@ -163,6 +280,7 @@ func (r *workoutRepository) Update(workout *models.Workout) error {
 		return err
 	}
 
+	*workout = *updated
 	return nil
 }
 
@@ -190,3 +308,72 @@ func (r *workoutRepository) Delete(id, userID int64) error {
 
 	return nil
 }
+
+// GetVolume returns the total load (weight x reps) lifted in a workout, leaving out warm-up sets
+func (r *workoutRepository) GetVolume(id int64) (float64, error) {
+	query := `
+		SELECT COALESCE(SUM(weight * reps), 0)
+		FROM sets
+		WHERE workout_id = $1 AND set_type <> 'warmup' AND deleted_at IS NULL
+	`
+
+	var volume float64
+	err := r.db.QueryRow(query, id).Scan(&volume)
+	return volume, err
+}
+
+// GetTrainingDays returns the days in loc a user trained, newest first, with the number of
+// workouts on each. Abandoned sessions and planned ones without sets are not counted.
+func (r *workoutRepository) GetTrainingDays(userID int64, loc *time.Location) ([]models.TrainingDay, error) {
+	query := `
+		SELECT (w.performed_at AT TIME ZONE $2::text)::date AS day, COUNT(*)
+		FROM workouts w
+		WHERE w.user_id = $1 AND w.deleted_at IS NULL AND w.status <> 'abandoned'
+			AND (w.status <> 'planned' OR EXISTS (
+				SELECT 1 FROM sets s WHERE s.workout_id = w.id_workout AND s.deleted_at IS NULL
+			))
+		GROUP BY day
+		ORDER BY day DESC
+	`
+
+	rows, err := r.db.Query(query, userID, loc.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []models.TrainingDay
+	for rows.Next() {
+		var date time.Time
+		var day models.TrainingDay
+		if err := rows.Scan(&date, &day.Workouts); err != nil {
+			return nil, err
+		}
+		day.Date = localDate(date, loc)
+		days = append(days, day)
+	}
+
+	return days, rows.Err()
+}
+
+// GetDailySessionLoad returns the session RPE x duration in minutes (Foster's sRPE load) of a
+// user's workouts performed in a period, per day in loc, oldest first. Trained workouts without
+// a session RPE or a finished duration add no load and are counted as unrated.
+func (r *workoutRepository) GetDailySessionLoad(userID int64, from, to time.Time, loc *time.Location) ([]models.DailyLoad, error) {
+	query := `
+		SELECT
+			(w.performed_at AT TIME ZONE $4::text)::date AS day,
+			COALESCE(SUM(w.session_rpe * EXTRACT(EPOCH FROM w.finished_at - w.started_at) / 60), 0),
+			COUNT(*) FILTER (WHERE w.session_rpe IS NULL OR w.started_at IS NULL OR w.finished_at IS NULL)
+		FROM workouts w
+		WHERE w.user_id = $1 AND w.deleted_at IS NULL AND w.status <> 'abandoned'
+			AND (w.status <> 'planned' OR EXISTS (
+				SELECT 1 FROM sets s WHERE s.workout_id = w.id_workout AND s.deleted_at IS NULL
+			))
+			AND w.performed_at >= $2 AND w.performed_at < $3
+		GROUP BY day
+		ORDER BY day
+	`
+
+	return queryDailyLoads(r.db, loc, query, userID, from, to, loc.String())
+}
@@ -3,6 +3,7 @@ package router
 import (
 	"net/http"
 
+	"phoenix-alliance-be/internal/auth"
 	"phoenix-alliance-be/internal/config"
 	"phoenix-alliance-be/internal/handler"
 	"phoenix-alliance-be/internal/middleware"
@@ -14,28 +15,89 @@ import (
 // SetupRouter configures and returns the application router
 func SetupRouter(
 	cfg *config.Config,
+	keys *auth.KeySet,
 	userService service.UserService,
+	sessionService service.SessionService,
+	accountService service.AccountService,
+	securityService service.SecurityService,
 	exerciseService service.ExerciseService,
 	workoutService service.WorkoutService,
 	setService service.SetService,
+	recordService service.PersonalRecordService,
+	templateService service.WorkoutTemplateService,
+	programService service.ProgramService,
+	statsService service.StatsService,
+	analyticsService service.AnalyticsService,
+	exportService service.ExportService,
+	importService service.ImportService,
+	reportService service.ReportService,
 ) *mux.Router {
 	router := mux.NewRouter()
 
+	// Behind a reverse proxy, the client IP of login throttling and security events is forwarded
+	if cfg.Server.TrustProxyHeaders {
+		router.Use(middleware.RealIPMiddleware)
+	}
+
 	// Apply CORS middleware to all routes
 	router.Use(middleware.CORSMiddleware(cfg))
 
 	// Create handlers
-	authHandler := handler.NewAuthHandler(userService, &jwtConfigAdapter{cfg: cfg})
+	authHandler := handler.NewAuthHandler(userService, sessionService, accountService, securityService)
+	accountHandler := handler.NewAccountHandler(accountService)
+	securityHandler := handler.NewSecurityHandler(securityService)
+	userHandler := handler.NewUserHandler(userService, sessionService, accountService)
 	exerciseHandler := handler.NewExerciseHandler(exerciseService, setService)
 	workoutHandler := handler.NewWorkoutHandler(workoutService, setService)
+	recordHandler := handler.NewRecordHandler(recordService)
+	templateHandler := handler.NewTemplateHandler(templateService)
+	programHandler := handler.NewProgramHandler(programService)
+	statsHandler := handler.NewStatsHandler(statsService)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService)
+	exportHandler := handler.NewExportHandler(exportService)
+	importHandler := handler.NewImportHandler(importService)
+	reportHandler := handler.NewReportHandler(reportService)
+	jwksHandler := handler.NewJWKSHandler(keys)
 
 	// Public routes (no authentication required)
 	router.HandleFunc("/signup", authHandler.Signup).Methods("POST", "OPTIONS")
 	router.HandleFunc("/login", authHandler.Login).Methods("POST", "OPTIONS")
+	router.HandleFunc("/token/refresh", authHandler.Refresh).Methods("POST", "OPTIONS")
+	router.HandleFunc("/password/forgot", accountHandler.ForgotPassword).Methods("POST", "OPTIONS")
+	router.HandleFunc("/password/reset", accountHandler.ResetPassword).Methods("POST", "OPTIONS")
+	router.HandleFunc("/email/verify", accountHandler.VerifyEmail).Methods("POST", "OPTIONS")
+	router.HandleFunc("/.well-known/jwks.json", jwksHandler.GetJWKS).Methods("GET", "OPTIONS")
 
 	// Protected routes (authentication required)
 	api := router.PathPrefix("/").Subrouter()
-	api.Use(middleware.AuthMiddleware(cfg))
+	api.Use(middleware.AuthMiddleware(keys, sessionService))
+
+	// Session routes
+	api.HandleFunc("/logout", authHandler.Logout).Methods("POST", "OPTIONS")
+	api.HandleFunc("/logout-all", authHandler.LogoutAll).Methods("POST", "OPTIONS")
+	api.HandleFunc("/email/verify/resend", accountHandler.ResendVerification).Methods("POST", "OPTIONS")
+
+	// Profile routes
+	api.HandleFunc("/me", userHandler.GetProfile).Methods("GET", "OPTIONS")
+	api.HandleFunc("/me", userHandler.UpdateProfile).Methods("PATCH", "OPTIONS")
+	api.HandleFunc("/me", userHandler.DeleteAccount).Methods("DELETE", "OPTIONS")
+	api.HandleFunc("/me/password", userHandler.ChangePassword).Methods("PUT", "OPTIONS")
+	api.HandleFunc("/me/email", userHandler.ChangeEmail).Methods("PUT", "OPTIONS")
+	api.HandleFunc("/me/restore", userHandler.RestoreAccount).Methods("POST", "OPTIONS")
+	api.HandleFunc("/me/summary", statsHandler.GetSummary).Methods("GET", "OPTIONS")
+	api.HandleFunc("/me/security-events", securityHandler.GetEvents).Methods("GET", "OPTIONS")
+
+	// Analytics routes
+	api.HandleFunc("/analytics/volume", analyticsHandler.GetVolume).Methods("GET", "OPTIONS")
+	api.HandleFunc("/analytics/load", analyticsHandler.GetLoad).Methods("GET", "OPTIONS")
+
+	// Export and import routes
+	api.HandleFunc("/export", exportHandler.Export).Methods("GET", "OPTIONS")
+	api.HandleFunc("/import", importHandler.Import).Methods("POST", "OPTIONS")
+
+	// PDF report routes
+	api.HandleFunc("/reports/workouts/{id:[0-9]+}.pdf", reportHandler.GetWorkoutReport).Methods("GET", "OPTIONS")
+	api.HandleFunc("/reports/progress.pdf", reportHandler.GetProgressReport).Methods("GET", "OPTIONS")
 
 	// Exercise routes
 	api.HandleFunc("/exercises", exerciseHandler.CreateExercise).Methods("POST", "OPTIONS")
@@ -44,6 +106,11 @@ func SetupRouter(
 	api.HandleFunc("/exercises/{id}", exerciseHandler.DeleteExercise).Methods("DELETE", "OPTIONS")
 	api.HandleFunc("/exercises/{id}/history", exerciseHandler.GetExerciseHistory).Methods("GET", "OPTIONS")
 	api.HandleFunc("/exercises/{id}/progress", exerciseHandler.GetExerciseProgress).Methods("GET", "OPTIONS")
+	api.HandleFunc("/exercises/{id}/records", recordHandler.GetExerciseRecords).Methods("GET", "OPTIONS")
+
+	// Exercise catalog routes
+	api.HandleFunc("/catalog", exerciseHandler.GetCatalog).Methods("GET", "OPTIONS")
+	api.HandleFunc("/catalog/{id}/adopt", exerciseHandler.AdoptExercise).Methods("POST", "OPTIONS")
 
 	// Workout routes
 	api.HandleFunc("/workouts", workoutHandler.CreateWorkout).Methods("POST", "OPTIONS")
@@ -51,8 +118,36 @@ func SetupRouter(
 	api.HandleFunc("/workouts/{id}", workoutHandler.GetWorkout).Methods("GET", "OPTIONS")
 	api.HandleFunc("/workouts/{id}", workoutHandler.UpdateWorkout).Methods("PUT", "OPTIONS")
 	api.HandleFunc("/workouts/{id}", workoutHandler.DeleteWorkout).Methods("DELETE", "OPTIONS")
+	api.HandleFunc("/workouts/{id}/start", workoutHandler.StartWorkout).Methods("POST", "OPTIONS")
+	api.HandleFunc("/workouts/{id}/finish", workoutHandler.FinishWorkout).Methods("POST", "OPTIONS")
 	api.HandleFunc("/workouts/{id}/sets", workoutHandler.CreateSet).Methods("POST", "OPTIONS")
 	api.HandleFunc("/workouts/{id}/sets", workoutHandler.GetWorkoutSets).Methods("GET", "OPTIONS")
+	api.HandleFunc("/workouts/{id}/sets/batch", workoutHandler.CreateSets).Methods("POST", "OPTIONS")
+	api.HandleFunc("/workouts/{id}/sets/order", workoutHandler.ReorderSets).Methods("PATCH", "OPTIONS")
+	api.HandleFunc("/workouts/{id}/sets/{setId}", workoutHandler.UpdateSet).Methods("PUT", "OPTIONS")
+	api.HandleFunc("/workouts/{id}/sets/{setId}", workoutHandler.DeleteSet).Methods("DELETE", "OPTIONS")
+	api.HandleFunc("/workouts/{id}/planned-sets", templateHandler.GetPlannedSets).Methods("GET", "OPTIONS")
+
+	// Template routes
+	api.HandleFunc("/templates", templateHandler.CreateTemplate).Methods("POST", "OPTIONS")
+	api.HandleFunc("/templates", templateHandler.GetTemplates).Methods("GET", "OPTIONS")
+	api.HandleFunc("/templates/{id}", templateHandler.GetTemplate).Methods("GET", "OPTIONS")
+	api.HandleFunc("/templates/{id}", templateHandler.UpdateTemplate).Methods("PUT", "OPTIONS")
+	api.HandleFunc("/templates/{id}", templateHandler.DeleteTemplate).Methods("DELETE", "OPTIONS")
+	api.HandleFunc("/templates/{id}/start", templateHandler.StartTemplate).Methods("POST", "OPTIONS")
+
+	// Program routes
+	api.HandleFunc("/programs", programHandler.CreateProgram).Methods("POST", "OPTIONS")
+	api.HandleFunc("/programs", programHandler.GetPrograms).Methods("GET", "OPTIONS")
+	api.HandleFunc("/programs/{id}", programHandler.GetProgram).Methods("GET", "OPTIONS")
+	api.HandleFunc("/programs/{id}", programHandler.UpdateProgram).Methods("PUT", "OPTIONS")
+	api.HandleFunc("/programs/{id}", programHandler.DeleteProgram).Methods("DELETE", "OPTIONS")
+	api.HandleFunc("/programs/{id}/enroll", programHandler.Enroll).Methods("POST", "OPTIONS")
+	api.HandleFunc("/programs/{id}/next-session", programHandler.GetNextSession).Methods("GET", "OPTIONS")
+	api.HandleFunc("/programs/{id}/next-session/start", programHandler.StartNextSession).Methods("POST", "OPTIONS")
+
+	// Personal record routes
+	api.HandleFunc("/records", recordHandler.GetRecords).Methods("GET", "OPTIONS")
 
 	// Health check
 	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -62,16 +157,3 @@ func SetupRouter(
 
 	return router
 }
-
-// jwtConfigAdapter adapts config.Config to handler.AuthConfig interface
-type jwtConfigAdapter struct {
-	cfg *config.Config
-}
-
-func (a *jwtConfigAdapter) GetJWTSecret() string {
-	return a.cfg.JWT.SecretKey
-}
-
-func (a *jwtConfigAdapter) GetJWTExpiry() int {
-	return a.cfg.JWT.Expiry
-}
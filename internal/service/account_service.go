@@ -0,0 +1,288 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"sync"
+	"time"
+
+	"phoenix-alliance-be/internal/auth"
+	"phoenix-alliance-be/internal/mail"
+	"phoenix-alliance-be/internal/models"
+	"phoenix-alliance-be/internal/repository"
+)
+
+// AccountService defines the interface for the password reset and email verification flows
+type AccountService interface {
+	SendVerification(userID int64) error
+	VerifyEmail(token string) (*models.UserResponse, error)
+	ForgotPassword(email string) error
+	ResetPassword(req *models.PasswordResetRequest) error
+	NotifyEmailChanged(previous, current string) error
+	DeleteExpired() error
+	Close()
+}
+
+// passwordResetQueueSize is how many password reset requests can wait for the worker sending
+// them; further requests are refused until it catches up
+const passwordResetQueueSize = 64
+
+type accountService struct {
+	tokenRepo      repository.AccountTokenRepository
+	userRepo       repository.UserRepository
+	sessionService SessionService
+	mailer         mail.Mailer
+	appURL         string
+
+	mu     sync.Mutex
+	closed bool
+	resets chan string
+	done   chan struct{}
+}
+
+// NewAccountService creates a new account service. Links sent by email point to pages of appURL.
+// Password reset links are sent by a worker that Close stops.
+func NewAccountService(
+	tokenRepo repository.AccountTokenRepository,
+	userRepo repository.UserRepository,
+	sessionService SessionService,
+	mailer mail.Mailer,
+	appURL string,
+) AccountService {
+	s := &accountService{
+		tokenRepo:      tokenRepo,
+		userRepo:       userRepo,
+		sessionService: sessionService,
+		mailer:         mailer,
+		appURL:         appURL,
+		resets:         make(chan string, passwordResetQueueSize),
+		done:           make(chan struct{}),
+	}
+	go s.sendPasswordResets()
+	return s
+}
+
+// SendVerification emails a user a link to verify their address. Links sent before stop working.
+func (s *accountService) SendVerification(userID int64) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if user.VerifiedAt != nil {
+		return errors.New("email already verified")
+	}
+
+	token, err := s.issue(user, models.PurposeEmailVerification, models.EmailVerificationTokenExpiry)
+	if err != nil {
+		return err
+	}
+
+	err = s.mailer.Send(&mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Welcome to Phoenix Alliance!\n\n"+
+			"Open this link to verify your email address:\n\n%s\n\n"+
+			"The link expires in 48 hours. If you did not sign up, you can ignore this email.\n",
+			s.link("/verify-email", token)),
+	})
+	if err != nil {
+		log.Printf("Failed to send verification email to user %d: %v", user.ID, err)
+		return errors.New("failed to send email")
+	}
+
+	return nil
+}
+
+// VerifyEmail marks the address a verification token was sent to as verified
+func (s *accountService) VerifyEmail(token string) (*models.UserResponse, error) {
+	consumed, err := s.consume(models.PurposeEmailVerification, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// The token only verifies the address it was sent to, not one the user changed to since
+	if err := s.userRepo.MarkVerified(consumed.UserID, consumed.Email, time.Now()); err != nil {
+		if err.Error() == "user not found" {
+			return nil, errors.New("invalid or expired token")
+		}
+		return nil, errors.New("failed to verify email")
+	}
+
+	user, err := s.userRepo.GetByID(consumed.UserID)
+	if err != nil {
+		return nil, errors.New("failed to verify email")
+	}
+	return user.ToResponse(), nil
+}
+
+// ForgotPassword emails a password reset link to the user of an address. Unknown addresses are
+// not reported, so that the endpoint cannot be used to find out who has an account. The link is
+// issued and sent in the background, so that the request takes as long for known addresses as
+// for unknown ones. Requests are refused while the worker sending the links is behind.
+func (s *accountService) ForgotPassword(email string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errors.New("too many password reset requests")
+	}
+
+	select {
+	case s.resets <- email:
+		return nil
+	default:
+		return errors.New("too many password reset requests")
+	}
+}
+
+// Close stops taking password reset requests and waits for the queued ones to be sent
+func (s *accountService) Close() {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.resets)
+	}
+	s.mu.Unlock()
+	<-s.done
+}
+
+// sendPasswordResets is the worker sending the queued password reset links, one at a time
+func (s *accountService) sendPasswordResets() {
+	defer close(s.done)
+	for email := range s.resets {
+		s.sendPasswordReset(email)
+	}
+}
+
+// sendPasswordReset emails a password reset link to the user of an address, if there is one and
+// no link was sent to it within the cooldown. Nobody waits for the result, so failures are logged.
+func (s *accountService) sendPasswordReset(email string) {
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		if err.Error() != "user not found" {
+			log.Printf("Failed to look up the user of a password reset request: %v", err)
+		}
+		return
+	}
+
+	// The link sent last keeps working rather than being replaced
+	now := time.Now()
+	recent, err := s.tokenRepo.IssuedSince(user.ID, models.PurposePasswordReset, now.Add(-models.PasswordResetCooldown), now)
+	if err != nil {
+		log.Printf("Failed to check the password reset tokens of user %d: %v", user.ID, err)
+		return
+	}
+	if recent {
+		return
+	}
+
+	token, err := s.issue(user, models.PurposePasswordReset, models.PasswordResetTokenExpiry)
+	if err != nil {
+		log.Printf("Failed to issue a password reset token to user %d: %v", user.ID, err)
+		return
+	}
+
+	err = s.mailer.Send(&mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Someone asked to reset the password of your Phoenix Alliance account.\n\n"+
+			"Open this link to choose a new password:\n\n%s\n\n"+
+			"The link expires in 1 hour and works once. If it was not you, you can ignore this email; "+
+			"your password has not changed.\n",
+			s.link("/reset-password", token)),
+	})
+	if err != nil {
+		log.Printf("Failed to send password reset email to user %d: %v", user.ID, err)
+	}
+}
+
+// ResetPassword sets a new password with a reset token and signs the user out everywhere. The
+// token is only used up once the password is changed.
+func (s *accountService) ResetPassword(req *models.PasswordResetRequest) error {
+	hashedPassword, err := auth.HashPassword(req.Password)
+	if err != nil {
+		return errors.New("failed to hash password")
+	}
+
+	consumed, err := s.tokenRepo.ResetPassword(auth.HashOpaqueToken(req.Token), hashedPassword, time.Now())
+	if err != nil {
+		if err.Error() == "account token not found" {
+			return errors.New("invalid or expired token")
+		}
+		return errors.New("failed to reset password")
+	}
+
+	// Whoever knew the old password must not stay signed in
+	if err := s.sessionService.LogoutAll(consumed.UserID); err != nil {
+		return errors.New("failed to revoke sessions")
+	}
+
+	return nil
+}
+
+// NotifyEmailChanged tells the previous address of an account that the address was changed,
+// so that its owner learns of a change they did not make
+func (s *accountService) NotifyEmailChanged(previous, current string) error {
+	err := s.mailer.Send(&mail.Message{
+		To:      previous,
+		Subject: "Your email address was changed",
+		Body: fmt.Sprintf("The email address of your Phoenix Alliance account was changed to %s, "+
+			"and the account was signed out on your other devices.\n\n"+
+			"If it was not you, contact us right away: emails about your account, such as password "+
+			"reset links, now go to the new address.\n", current),
+	})
+	if err != nil {
+		log.Printf("Failed to send the email change notice to %s: %v", previous, err)
+		return errors.New("failed to send email")
+	}
+	return nil
+}
+
+// DeleteExpired drops the tokens that can no longer be used
+func (s *accountService) DeleteExpired() error {
+	return s.tokenRepo.DeleteExpired(time.Now())
+}
+
+// issue creates a token for a user, replacing the unused tokens of the same purpose
+func (s *accountService) issue(user *models.User, purpose models.AccountTokenPurpose, expiry time.Duration) (string, error) {
+	token, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return "", errors.New("failed to generate token")
+	}
+
+	now := time.Now()
+	if err := s.tokenRepo.InvalidateForUser(user.ID, purpose, now); err != nil {
+		return "", errors.New("failed to create token")
+	}
+	err = s.tokenRepo.Create(&models.AccountToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: auth.HashOpaqueToken(token),
+		Email:     user.Email,
+		ExpiresAt: now.Add(expiry),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return "", errors.New("failed to create token")
+	}
+
+	return token, nil
+}
+
+// consume uses up a token, failing when it is unknown, used, expired or for another purpose
+func (s *accountService) consume(purpose models.AccountTokenPurpose, token string) (*models.AccountToken, error) {
+	consumed, err := s.tokenRepo.Consume(purpose, auth.HashOpaqueToken(token), time.Now())
+	if err != nil {
+		if err.Error() == "account token not found" {
+			return nil, errors.New("invalid or expired token")
+		}
+		return nil, errors.New("failed to check token")
+	}
+	return consumed, nil
+}
+
+// link returns the URL of an app page that receives a token
+func (s *accountService) link(path, token string) string {
+	return s.appURL + path + "?token=" + url.QueryEscape(token)
+}
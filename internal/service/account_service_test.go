@@ -0,0 +1,323 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"phoenix-alliance-be/internal/auth"
+	"phoenix-alliance-be/internal/mail"
+	"phoenix-alliance-be/internal/models"
+)
+
+// mockAccountTokenRepository is an in-memory implementation of AccountTokenRepository over the
+// users of a user repository
+type mockAccountTokenRepository struct {
+	tokens   []*models.AccountToken
+	userRepo *mockUserRepository
+}
+
+func (m *mockAccountTokenRepository) Create(token *models.AccountToken) error {
+	token.ID = int64(len(m.tokens) + 1)
+	m.tokens = append(m.tokens, token)
+	return nil
+}
+
+func (m *mockAccountTokenRepository) Consume(purpose models.AccountTokenPurpose, tokenHash string, now time.Time) (*models.AccountToken, error) {
+	for _, token := range m.tokens {
+		if token.TokenHash == tokenHash && token.Purpose == purpose && token.UsedAt == nil && now.Before(token.ExpiresAt) {
+			token.UsedAt = &now
+			return token, nil
+		}
+	}
+	return nil, errors.New("account token not found")
+}
+
+func (m *mockAccountTokenRepository) ResetPassword(tokenHash, passwordHash string, now time.Time) (*models.AccountToken, error) {
+	for _, token := range m.tokens {
+		if token.TokenHash != tokenHash || token.Purpose != models.PurposePasswordReset || token.UsedAt != nil || !now.Before(token.ExpiresAt) {
+			continue
+		}
+		user, err := m.userRepo.GetByID(token.UserID)
+		if err != nil || user.Email != token.Email {
+			return nil, errors.New("account token not found")
+		}
+		// The token stays usable when the password cannot be changed, as the transaction rolls back
+		if err := m.userRepo.UpdatePassword(user.ID, passwordHash); err != nil {
+			return nil, err
+		}
+		m.userRepo.MarkVerified(user.ID, user.Email, now)
+		token.UsedAt = &now
+		return token, nil
+	}
+	return nil, errors.New("account token not found")
+}
+
+func (m *mockAccountTokenRepository) InvalidateForUser(userID int64, purpose models.AccountTokenPurpose, now time.Time) error {
+	for _, token := range m.tokens {
+		if token.UserID == userID && token.Purpose == purpose && token.UsedAt == nil {
+			token.UsedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *mockAccountTokenRepository) IssuedSince(userID int64, purpose models.AccountTokenPurpose, since, now time.Time) (bool, error) {
+	for _, token := range m.tokens {
+		if token.UserID == userID && token.Purpose == purpose && token.CreatedAt.After(since) && token.UsedAt == nil && now.Before(token.ExpiresAt) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockAccountTokenRepository) DeleteExpired(before time.Time) error {
+	return nil
+}
+
+// linkToken matches the token of the last link written to a mail log
+var linkToken = regexp.MustCompile(`token=([A-Za-z0-9_-]+)`)
+
+// lastLinkToken returns the token of the last link sent to the mail log
+func lastLinkToken(t *testing.T, log *bytes.Buffer) string {
+	t.Helper()
+	matches := linkToken.FindAllStringSubmatch(log.String(), -1)
+	if len(matches) == 0 {
+		t.Fatalf("expected a link in the mail log, got %q", log.String())
+	}
+	return matches[len(matches)-1][1]
+}
+
+// notifyingMailer signals each message it sends, for emails sent in the background
+type notifyingMailer struct {
+	mail.Mailer
+	sent chan struct{}
+}
+
+func (m *notifyingMailer) Send(msg *mail.Message) error {
+	err := m.Mailer.Send(msg)
+	m.sent <- struct{}{}
+	return err
+}
+
+// newTestAccountService returns an account service over an in-memory user, sending emails with mailer
+func newTestAccountService(user *models.User, mailer mail.Mailer) (AccountService, *mockRefreshTokenRepository, *mockUserRepository) {
+	userRepo := &mockUserRepository{
+		getByIDFunc: func(id int64) (*models.User, error) {
+			if id != user.ID {
+				return nil, errors.New("user not found")
+			}
+			copied := *user
+			return &copied, nil
+		},
+		getByEmailFunc: func(email string) (*models.User, error) {
+			if email != user.Email {
+				return nil, errors.New("user not found")
+			}
+			copied := *user
+			return &copied, nil
+		},
+		updatePasswordFunc: func(userID int64, passwordHash string) error {
+			user.Password = passwordHash
+			return nil
+		},
+		markVerifiedFunc: func(userID int64, email string, at time.Time) error {
+			if email != user.Email {
+				return errors.New("user not found")
+			}
+			user.VerifiedAt = &at
+			return nil
+		},
+	}
+
+	sessionRepo := newMockRefreshTokenRepository()
+	sessions := NewSessionService(sessionRepo, userRepo, TokenSettings{
+		Keys:          testKeys,
+		AccessExpiry:  15 * time.Minute,
+		RefreshExpiry: 30 * 24 * time.Hour,
+	})
+	service := NewAccountService(&mockAccountTokenRepository{userRepo: userRepo}, userRepo, sessions, mailer, "https://app.example.com")
+	return service, sessionRepo, userRepo
+}
+
+func TestVerifyEmail(t *testing.T) {
+	user := &models.User{ID: 1, Email: "user@example.com"}
+	var log bytes.Buffer
+	service, _, _ := newTestAccountService(user, mail.NewLogMailer(&log))
+
+	if err := service.SendVerification(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first := lastLinkToken(t, &log)
+	if !bytes.Contains(log.Bytes(), []byte("https://app.example.com/verify-email?token=")) {
+		t.Errorf("expected a link to the app, got %q", log.String())
+	}
+
+	// Only the latest link works
+	service.SendVerification(1)
+	if _, err := service.VerifyEmail(first); err == nil || err.Error() != "invalid or expired token" {
+		t.Errorf("expected a replaced token to be rejected, got %v", err)
+	}
+
+	verified, err := service.VerifyEmail(lastLinkToken(t, &log))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if verified.VerifiedAt == nil {
+		t.Error("expected the user to be verified")
+	}
+	if _, err := service.VerifyEmail(lastLinkToken(t, &log)); err == nil {
+		t.Error("expected a used token to be rejected")
+	}
+	if err := service.SendVerification(1); err == nil || err.Error() != "email already verified" {
+		t.Errorf("expected a verified user to be refused a new link, got %v", err)
+	}
+}
+
+func TestVerifyEmailAfterAddressChange(t *testing.T) {
+	user := &models.User{ID: 1, Email: "old@example.com"}
+	var log bytes.Buffer
+	service, _, _ := newTestAccountService(user, mail.NewLogMailer(&log))
+
+	service.SendVerification(1)
+	user.Email = "new@example.com"
+	if _, err := service.VerifyEmail(lastLinkToken(t, &log)); err == nil || err.Error() != "invalid or expired token" {
+		t.Errorf("expected the token of the old address to be rejected, got %v", err)
+	}
+	if user.VerifiedAt != nil {
+		t.Error("expected the new address to stay unverified")
+	}
+}
+
+func TestNotifyEmailChanged(t *testing.T) {
+	user := &models.User{ID: 1, Email: "new@example.com"}
+	var log bytes.Buffer
+	service, _, _ := newTestAccountService(user, mail.NewLogMailer(&log))
+
+	if err := service.NotifyEmailChanged("old@example.com", "new@example.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Contains(log.Bytes(), []byte("To: old@example.com\n")) {
+		t.Errorf("expected the notice to go to the previous address, got %q", log.String())
+	}
+	if !bytes.Contains(log.Bytes(), []byte("new@example.com")) {
+		t.Errorf("expected the notice to name the new address, got %q", log.String())
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	oldHash, _ := auth.HashPassword("old-password")
+	user := &models.User{ID: 1, Email: "user@example.com", Password: oldHash}
+	var log bytes.Buffer
+	mailer := &notifyingMailer{Mailer: mail.NewLogMailer(&log), sent: make(chan struct{}, 4)}
+	service, sessionRepo, userRepo := newTestAccountService(user, mailer)
+
+	// Links are sent in the background, and only to known addresses
+	if err := service.ForgotPassword("unknown@example.com"); err != nil {
+		t.Errorf("expected unknown addresses to be accepted silently, got %v", err)
+	}
+	if err := service.ForgotPassword("user@example.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-mailer.sent:
+	case <-time.After(time.Second):
+		t.Fatal("expected a password reset email")
+	}
+	if sent := strings.Count(log.String(), "To: "); sent != 1 || !strings.Contains(log.String(), "To: user@example.com") {
+		t.Errorf("expected one email to the user, got %q", log.String())
+	}
+	token := lastLinkToken(t, &log)
+
+	if err := service.ResetPassword(&models.PasswordResetRequest{Token: "unknown", Password: "new-password"}); err == nil || err.Error() != "invalid or expired token" {
+		t.Errorf("expected an unknown token to be rejected, got %v", err)
+	}
+
+	// A token is not used up when the password cannot be changed
+	updatePassword := userRepo.updatePasswordFunc
+	userRepo.updatePasswordFunc = func(userID int64, passwordHash string) error {
+		return errors.New("connection reset")
+	}
+	if err := service.ResetPassword(&models.PasswordResetRequest{Token: token, Password: "new-password"}); err == nil || err.Error() != "failed to reset password" {
+		t.Errorf("expected the reset to fail, got %v", err)
+	}
+	userRepo.updatePasswordFunc = updatePassword
+
+	// Resetting signs out the sessions started with the old password
+	sessionRepo.Create(&models.RefreshToken{UserID: 1, AccessJTI: "session", ExpiresAt: time.Now().Add(time.Hour)})
+	if err := service.ResetPassword(&models.PasswordResetRequest{Token: token, Password: "new-password"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !auth.CheckPasswordHash("new-password", user.Password) {
+		t.Error("expected the password to be changed")
+	}
+	if sessionRepo.tokens[0].RevokedAt == nil {
+		t.Error("expected the sessions of the user to be revoked")
+	}
+	if user.VerifiedAt == nil {
+		t.Error("expected the reset to verify the address")
+	}
+
+	if err := service.ResetPassword(&models.PasswordResetRequest{Token: token, Password: "other-password"}); err == nil {
+		t.Error("expected a used token to be rejected")
+	}
+
+	// A verification token cannot reset a password
+	user.VerifiedAt = nil
+	service.SendVerification(1)
+	if err := service.ResetPassword(&models.PasswordResetRequest{Token: lastLinkToken(t, &log), Password: "other-password"}); err == nil {
+		t.Error("expected a token of another purpose to be rejected")
+	}
+}
+
+func TestForgotPasswordCooldown(t *testing.T) {
+	user := &models.User{ID: 1, Email: "user@example.com"}
+	var log bytes.Buffer
+	service, _, _ := newTestAccountService(user, mail.NewLogMailer(&log))
+
+	// Requests within the cooldown neither send another email nor replace the link
+	for i := 0; i < 3; i++ {
+		if err := service.ForgotPassword("user@example.com"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	service.Close()
+
+	if sent := strings.Count(log.String(), "To: "); sent != 1 {
+		t.Fatalf("expected one email, got %d", sent)
+	}
+	if err := service.ResetPassword(&models.PasswordResetRequest{Token: lastLinkToken(t, &log), Password: "new-password"}); err != nil {
+		t.Errorf("expected the link sent to keep working, got %v", err)
+	}
+}
+
+func TestForgotPasswordQueue(t *testing.T) {
+	user := &models.User{ID: 1, Email: "user@example.com"}
+	var log bytes.Buffer
+	// The mailer blocks until the test takes the message, holding up the worker
+	mailer := &notifyingMailer{Mailer: mail.NewLogMailer(&log), sent: make(chan struct{})}
+	service, _, _ := newTestAccountService(user, mailer)
+
+	accepted := 0
+	for ; accepted <= passwordResetQueueSize+1; accepted++ {
+		if err := service.ForgotPassword("user@example.com"); err != nil {
+			if err.Error() != "too many password reset requests" {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			break
+		}
+	}
+	if accepted < passwordResetQueueSize || accepted > passwordResetQueueSize+1 {
+		t.Errorf("expected the requests past the queue to be refused, got %d accepted", accepted)
+	}
+
+	// Closing sends what is queued and refuses new requests
+	<-mailer.sent
+	service.Close()
+	if err := service.ForgotPassword("user@example.com"); err == nil {
+		t.Error("expected requests after closing to be refused")
+	}
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+	"phoenix-alliance-be/internal/repository"
+)
+
+// AnalyticsService defines the interface for training volume and load analytics
+type AnalyticsService interface {
+	GetMuscleVolume(userID int64, query *models.AnalyticsQuery) (*models.MuscleVolumeResponse, error)
+	GetLoad(userID int64, query *models.AnalyticsQuery) (*models.LoadResponse, error)
+}
+
+// chronicWeeks is the number of weeks averaged into the chronic workload
+const chronicWeeks = 4
+
+type analyticsService struct {
+	workoutRepo repository.WorkoutRepository
+	setRepo     repository.SetRepository
+	userRepo    repository.UserRepository
+}
+
+// NewAnalyticsService creates a new analytics service
+func NewAnalyticsService(
+	workoutRepo repository.WorkoutRepository,
+	setRepo repository.SetRepository,
+	userRepo repository.UserRepository,
+) AnalyticsService {
+	return &analyticsService{
+		workoutRepo: workoutRepo,
+		setRepo:     setRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// GetMuscleVolume retrieves the weekly hard sets and tonnage per muscle group over the
+// requested weeks, ending with the current one in the user's time zone
+func (s *analyticsService) GetMuscleVolume(userID int64, query *models.AnalyticsQuery) (*models.MuscleVolumeResponse, error) {
+	loc := userLocation(s.userRepo, userID)
+	now := time.Now().In(loc)
+	first := models.ProgressBucketWeek.Add(models.ProgressBucketWeek.Start(now, loc), 1-analyticsWeeks(query))
+
+	trained, err := s.setRepo.GetMuscleVolume(userID, first, now, loc)
+	if err != nil {
+		return nil, errors.New("failed to retrieve volume")
+	}
+
+	// Every week is listed, including those without sets
+	weeks := []models.MuscleVolumeWeek{}
+	next := 0
+	for week := first; week.Before(now); week = models.ProgressBucketWeek.Add(week, 1) {
+		if next < len(trained) && trained[next].WeekStart.Equal(week) {
+			weeks = append(weeks, trained[next])
+			next++
+			continue
+		}
+		weeks = append(weeks, models.MuscleVolumeWeek{WeekStart: week, Muscles: []models.MuscleVolume{}})
+	}
+
+	return &models.MuscleVolumeResponse{Timezone: loc.String(), Weeks: weeks}, nil
+}
+
+// GetLoad retrieves the training load of the requested weeks, with the acute:chronic workload
+// ratio and Foster's monotony and strain, in the user's time zone
+func (s *analyticsService) GetLoad(userID int64, query *models.AnalyticsQuery) (*models.LoadResponse, error) {
+	loc := userLocation(s.userRepo, userID)
+	now := time.Now().In(loc)
+	metric := query.Metric
+	if !metric.IsValid() {
+		metric = models.LoadMetricSRPE
+	}
+
+	// Load the weeks before the first one so that its chronic load, and that of the current
+	// snapshot, covers full weeks
+	weeks := analyticsWeeks(query)
+	first := models.ProgressBucketWeek.Add(models.ProgressBucketWeek.Start(now, loc), 1-weeks)
+	from := models.ProgressBucketWeek.Add(first, -chronicWeeks)
+
+	var loads []models.DailyLoad
+	var err error
+	if metric == models.LoadMetricTonnage {
+		loads, err = s.setRepo.GetDailyTonnage(userID, from, now, loc)
+	} else {
+		loads, err = s.workoutRepo.GetDailySessionLoad(userID, from, now, loc)
+	}
+	if err != nil {
+		return nil, errors.New("failed to retrieve training load")
+	}
+
+	daily, unrated := dailySeries(loads, from, now, first)
+	response := &models.LoadResponse{
+		Timezone:        loc.String(),
+		Metric:          metric,
+		Current:         loadSnapshot(daily),
+		Weeks:           weeklyLoad(daily, from, weeks),
+		UnratedSessions: unrated,
+	}
+
+	return response, nil
+}
+
+// analyticsWeeks returns the number of weeks requested, within the allowed range
+func analyticsWeeks(query *models.AnalyticsQuery) int {
+	if query.Weeks < 1 {
+		return models.DefaultAnalyticsWeeks
+	}
+	return min(query.Weeks, models.MaxAnalyticsWeeks)
+}
+
+// dailySeries spreads daily loads over every day from from up to today, with zero on rest
+// days, and counts the unrated sessions from counted on
+func dailySeries(loads []models.DailyLoad, from, now, counted time.Time) ([]float64, int) {
+	byDay := make(map[int64]models.DailyLoad, len(loads))
+	for _, load := range loads {
+		byDay[load.Date.Unix()] = load
+	}
+
+	var daily []float64
+	var unrated int
+	for day := from; day.Before(now); day = models.ProgressBucketDay.Add(day, 1) {
+		load := byDay[day.Unix()]
+		daily = append(daily, load.Load)
+		if !day.Before(counted) {
+			unrated += load.Unrated
+		}
+	}
+	return daily, unrated
+}
+
+// loadSnapshot computes the acute and chronic load, their ratio, monotony and strain as of the
+// last day of a daily series
+func loadSnapshot(daily []float64) models.LoadSnapshot {
+	acute := lastDays(daily, 7)
+	chronic := lastDays(daily, 7*chronicWeeks)
+
+	snapshot := models.LoadSnapshot{
+		AcuteLoad:   round2(sum(acute)),
+		ChronicLoad: round2(sum(chronic) / chronicWeeks),
+	}
+	snapshot.ACWR = ratio(snapshot.AcuteLoad, snapshot.ChronicLoad)
+	snapshot.Monotony, snapshot.Strain = monotonyAndStrain(acute)
+	return snapshot
+}
+
+// weeklyLoad splits a daily series starting on Monday from into weeks and returns the last n,
+// each with its ACWR against the average of the chronicWeeks weeks ending with it
+func weeklyLoad(daily []float64, from time.Time, n int) []models.LoadWeek {
+	var loads []float64
+	var weekDays [][]float64
+	for start := 0; start < len(daily); start += 7 {
+		days := daily[start:min(start+7, len(daily))]
+		loads = append(loads, sum(days))
+		weekDays = append(weekDays, days)
+	}
+
+	weeks := []models.LoadWeek{}
+	for i := max(len(loads)-n, 0); i < len(loads); i++ {
+		week := models.LoadWeek{
+			WeekStart: models.ProgressBucketWeek.Add(from, i),
+			Load:      round2(loads[i]),
+		}
+		chronic := loads[max(i-chronicWeeks+1, 0) : i+1]
+		week.ACWR = ratio(loads[i], sum(chronic)/chronicWeeks)
+		week.Monotony, week.Strain = monotonyAndStrain(weekDays[i])
+		weeks = append(weeks, week)
+	}
+	return weeks
+}
+
+// monotonyAndStrain computes Foster's monotony (mean daily load over its standard deviation)
+// and strain (total load x monotony). Both are nil when the load does not vary.
+func monotonyAndStrain(daily []float64) (*float64, *float64) {
+	if len(daily) < 2 {
+		return nil, nil
+	}
+
+	mean := sum(daily) / float64(len(daily))
+	var variance float64
+	for _, load := range daily {
+		variance += (load - mean) * (load - mean)
+	}
+	sd := math.Sqrt(variance / float64(len(daily)))
+	if sd == 0 {
+		return nil, nil
+	}
+
+	monotony := round2(mean / sd)
+	strain := round2(sum(daily) * mean / sd)
+	return &monotony, &strain
+}
+
+// ratio returns a / b rounded to two decimals, or nil when b is zero
+func ratio(a, b float64) *float64 {
+	if b == 0 {
+		return nil
+	}
+	r := round2(a / b)
+	return &r
+}
+
+// lastDays returns the last n values of a daily series
+func lastDays(daily []float64, n int) []float64 {
+	return daily[max(len(daily)-n, 0):]
+}
+
+// sum adds up a series
+func sum(values []float64) float64 {
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+func TestMonotonyAndStrain(t *testing.T) {
+	// Mean 300, population standard deviation 200
+	monotony, strain := monotonyAndStrain([]float64{500, 100, 500, 100})
+	if monotony == nil || strain == nil {
+		t.Fatal("expected monotony and strain")
+	}
+	if *monotony != 1.5 || *strain != 1800 {
+		t.Errorf("expected monotony 1.5 and strain 1800, got %v and %v", *monotony, *strain)
+	}
+
+	if monotony, strain := monotonyAndStrain([]float64{400, 400, 400}); monotony != nil || strain != nil {
+		t.Error("expected no monotony for a constant load")
+	}
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	// 3 weeks of 700 followed by a week of 1400, one session a day
+	daily := make([]float64, 28)
+	for i := range daily {
+		daily[i] = 100
+		if i >= 21 {
+			daily[i] = 200
+		}
+	}
+
+	snapshot := loadSnapshot(daily)
+	if snapshot.AcuteLoad != 1400 || snapshot.ChronicLoad != 875 {
+		t.Errorf("expected acute 1400 and chronic 875, got %v and %v", snapshot.AcuteLoad, snapshot.ChronicLoad)
+	}
+	if snapshot.ACWR == nil || *snapshot.ACWR != 1.6 {
+		t.Errorf("expected an ACWR of 1.6, got %v", snapshot.ACWR)
+	}
+	if snapshot.Monotony != nil {
+		t.Errorf("expected no monotony for a constant week, got %v", *snapshot.Monotony)
+	}
+
+	if snapshot := loadSnapshot(make([]float64, 28)); snapshot.ACWR != nil {
+		t.Error("expected no ACWR without chronic load")
+	}
+}
+
+func TestWeeklyLoad(t *testing.T) {
+	from := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC) // Monday
+	// 4 full weeks of 100 a day and 3 days into the fifth
+	daily := make([]float64, 31)
+	for i := range daily {
+		daily[i] = 100
+	}
+
+	weeks := weeklyLoad(daily, from, 2)
+	if len(weeks) != 2 {
+		t.Fatalf("expected 2 weeks, got %d", len(weeks))
+	}
+	if !weeks[0].WeekStart.Equal(time.Date(2024, 4, 22, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected week start %v", weeks[0].WeekStart)
+	}
+	if weeks[0].Load != 700 || weeks[0].ACWR == nil || *weeks[0].ACWR != 1 {
+		t.Errorf("expected a steady full week, got %+v", weeks[0])
+	}
+	// The week in progress weighs 300 against (3 x 700 + 300) / 4
+	if weeks[1].Load != 300 || weeks[1].ACWR == nil || *weeks[1].ACWR != 0.5 {
+		t.Errorf("expected the week in progress at 300 with ACWR 0.5, got %+v", weeks[1])
+	}
+}
+
+func TestGetMuscleVolume(t *testing.T) {
+	thisWeek := models.ProgressBucketWeek.Start(time.Now(), time.UTC)
+	lastWeek := models.ProgressBucketWeek.Add(thisWeek, -1)
+
+	setRepo := &mockSetRepository{
+		getMuscleVolumeFunc: func(userID int64, from, to time.Time, loc *time.Location) ([]models.MuscleVolumeWeek, error) {
+			if want := models.ProgressBucketWeek.Add(thisWeek, -3); !from.Equal(want) {
+				t.Errorf("expected volume from %v, got %v", want, from)
+			}
+			return []models.MuscleVolumeWeek{
+				{WeekStart: lastWeek, Muscles: []models.MuscleVolume{{Muscle: "chest", HardSets: 4.5, Tonnage: 3000}}},
+			}, nil
+		},
+	}
+	service := NewAnalyticsService(&mockWorkoutRepository{}, setRepo, &mockUserRepository{})
+
+	volume, err := service.GetMuscleVolume(1, &models.AnalyticsQuery{Weeks: 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(volume.Weeks) != 4 {
+		t.Fatalf("expected 4 weeks, got %d", len(volume.Weeks))
+	}
+	if !volume.Weeks[2].WeekStart.Equal(lastWeek) || len(volume.Weeks[2].Muscles) != 1 {
+		t.Errorf("expected last week's volume in third place, got %+v", volume.Weeks[2])
+	}
+	if !volume.Weeks[3].WeekStart.Equal(thisWeek) || volume.Weeks[3].Muscles == nil {
+		t.Errorf("expected an empty current week, got %+v", volume.Weeks[3])
+	}
+}
+
+func TestGetLoad(t *testing.T) {
+	today := models.ProgressBucketDay.Start(time.Now(), time.UTC)
+
+	workoutRepo := &mockWorkoutRepository{
+		getDailySessionLoadFunc: func(userID int64, from, to time.Time, loc *time.Location) ([]models.DailyLoad, error) {
+			return []models.DailyLoad{{Date: today, Load: 420, Unrated: 1}}, nil
+		},
+	}
+	var tonnageRequested bool
+	setRepo := &mockSetRepository{
+		getDailyTonnageFunc: func(userID int64, from, to time.Time, loc *time.Location) ([]models.DailyLoad, error) {
+			tonnageRequested = true
+			return nil, nil
+		},
+	}
+	service := NewAnalyticsService(workoutRepo, setRepo, &mockUserRepository{})
+
+	load, err := service.GetLoad(1, &models.AnalyticsQuery{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if load.Metric != models.LoadMetricSRPE || len(load.Weeks) != models.DefaultAnalyticsWeeks {
+		t.Errorf("expected %d weeks of sRPE load, got %d of %s", models.DefaultAnalyticsWeeks, len(load.Weeks), load.Metric)
+	}
+	if load.Current.AcuteLoad != 420 || load.Current.ChronicLoad != 105 || load.UnratedSessions != 1 {
+		t.Errorf("unexpected current load %+v", load.Current)
+	}
+	if load.Current.ACWR == nil || math.Abs(*load.Current.ACWR-4) > 0.001 {
+		t.Errorf("expected an ACWR of 4, got %v", load.Current.ACWR)
+	}
+
+	if _, err := service.GetLoad(1, &models.AnalyticsQuery{Metric: models.LoadMetricTonnage}); err != nil || !tonnageRequested {
+		t.Errorf("expected tonnage load, got error %v", err)
+	}
+}
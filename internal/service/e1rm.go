@@ -0,0 +1,87 @@
+package service
+
+import (
+	"math"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+// estimateOneRepMax estimates the one-rep max of a set using the given formula.
+// When the set carries an RPE, the reps in reserve are added to the performed
+// reps so that submaximal sets are not underestimated.
+func estimateOneRepMax(set *models.Set, formula models.E1RMFormula) float64 {
+	if set.Weight <= 0 || set.Reps < 1 {
+		return 0
+	}
+
+	reps := effectiveReps(set)
+
+	switch formula {
+	case models.E1RMFormulaBrzycki:
+		return brzycki(set.Weight, reps)
+	case models.E1RMFormulaLombardi:
+		return lombardi(set.Weight, reps)
+	case models.E1RMFormulaRPE:
+		if reps <= len(models.RPEPercentages) {
+			return round2(set.Weight / models.RPEPercentages[reps-1])
+		}
+		// Outside the RPE table, fall back to Epley
+		return epley(set.Weight, reps)
+	default:
+		return epley(set.Weight, reps)
+	}
+}
+
+// effectiveReps returns the performed reps plus reps in reserve derived from RPE
+func effectiveReps(set *models.Set) int {
+	reps := set.Reps
+	if set.RPE != nil && *set.RPE >= 1 && *set.RPE <= 10 {
+		reps += 10 - *set.RPE
+	}
+	return reps
+}
+
+// epley: weight * (1 + reps / 30)
+func epley(weight float64, reps int) float64 {
+	if reps == 1 {
+		return round2(weight)
+	}
+	return round2(weight * (1 + float64(reps)/30))
+}
+
+// brzycki: weight * 36 / (37 - reps)
+func brzycki(weight float64, reps int) float64 {
+	if reps > models.MaxBrzyckiReps {
+		reps = models.MaxBrzyckiReps
+	}
+	return round2(weight * 36 / (37 - float64(reps)))
+}
+
+// lombardi: weight * reps ^ 0.10
+func lombardi(weight float64, reps int) float64 {
+	return round2(weight * math.Pow(float64(reps), 0.10))
+}
+
+// bestOneRepMax returns the highest e1RM across the given sets
+func bestOneRepMax(sets []*models.Set, formula models.E1RMFormula) float64 {
+	var best float64
+	for _, set := range sets {
+		if e1rm := estimateOneRepMax(set, formula); e1rm > best {
+			best = e1rm
+		}
+	}
+	return best
+}
+
+// round2 rounds a value to two decimal places
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// roundToIncrement rounds a load to the nearest loadable increment (e.g. 2.5kg)
+func roundToIncrement(v, increment float64) float64 {
+	if increment <= 0 {
+		return round2(v)
+	}
+	return round2(math.Round(v/increment) * increment)
+}
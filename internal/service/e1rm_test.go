@@ -0,0 +1,80 @@
+package service
+
+import (
+	"testing"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+func TestEstimateOneRepMax(t *testing.T) {
+	tests := []struct {
+		name     string
+		set      *models.Set
+		formula  models.E1RMFormula
+		expected float64
+	}{
+		{
+			name:     "epley",
+			set:      &models.Set{Weight: 100, Reps: 5},
+			formula:  models.E1RMFormulaEpley,
+			expected: 116.67,
+		},
+		{
+			name:     "epley single is the weight itself",
+			set:      &models.Set{Weight: 140, Reps: 1},
+			formula:  models.E1RMFormulaEpley,
+			expected: 140,
+		},
+		{
+			name:     "brzycki",
+			set:      &models.Set{Weight: 100, Reps: 5},
+			formula:  models.E1RMFormulaBrzycki,
+			expected: 112.5,
+		},
+		{
+			name:     "lombardi",
+			set:      &models.Set{Weight: 100, Reps: 5},
+			formula:  models.E1RMFormulaLombardi,
+			expected: 117.46,
+		},
+		{
+			name:     "rpe table uses reps in reserve",
+			set:      &models.Set{Weight: 100, Reps: 5, RPE: intPtr(8)},
+			formula:  models.E1RMFormulaRPE,
+			expected: 123.3,
+		},
+		{
+			name:     "rpe table without rpe assumes max effort",
+			set:      &models.Set{Weight: 100, Reps: 3},
+			formula:  models.E1RMFormulaRPE,
+			expected: 108.46,
+		},
+		{
+			name:     "rpe table falls back to epley for high reps",
+			set:      &models.Set{Weight: 50, Reps: 20},
+			formula:  models.E1RMFormulaRPE,
+			expected: 83.33,
+		},
+		{
+			name:     "epley is rpe aware",
+			set:      &models.Set{Weight: 100, Reps: 3, RPE: intPtr(7)},
+			formula:  models.E1RMFormulaEpley,
+			expected: 120,
+		},
+		{
+			name:     "zero weight",
+			set:      &models.Set{Weight: 0, Reps: 10},
+			formula:  models.E1RMFormulaEpley,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := estimateOneRepMax(tt.set, tt.formula)
+			if got != tt.expected {
+				t.Errorf("expected %.2f, got %.2f", tt.expected, got)
+			}
+		})
+	}
+}
@@ -11,10 +11,12 @@ import (
 // ExerciseService defines the interface for exercise business logic
 type ExerciseService interface {
 	CreateExercise(userID int64, req *models.ExerciseCreateRequest) (*models.ExerciseResponse, error)
-	GetExercises(userID int64) ([]*models.ExerciseResponse, error)
+	GetExercises(userID int64, filter *models.ExerciseFilter) (*models.ListResponse[*models.ExerciseResponse], error)
 	GetExerciseByID(userID, exerciseID int64) (*models.ExerciseResponse, error)
 	UpdateExercise(userID, exerciseID int64, req *models.ExerciseUpdateRequest) (*models.ExerciseResponse, error)
 	DeleteExercise(userID, exerciseID int64) error
+	GetCatalog(filter *models.ExerciseFilter) (*models.ListResponse[*models.ExerciseResponse], error)
+	AdoptExercise(userID, exerciseID int64, req *models.CatalogAdoptRequest) (*models.ExerciseResponse, error)
 }
 
 type exerciseService struct {
@@ -28,10 +30,20 @@ func NewExerciseService(exerciseRepo repository.ExerciseRepository) ExerciseServ
 
 // CreateExercise creates a new exercise for a user
 func (s *exerciseService) CreateExercise(userID int64, req *models.ExerciseCreateRequest) (*models.ExerciseResponse, error) {
+	exerciseType := req.Type
+	if exerciseType == "" {
+		exerciseType = models.ExerciseTypeWeight
+	}
+
 	exercise := &models.Exercise{
-		UserID:    userID,
-		Name:      req.Name,
-		CreatedAt: time.Now(),
+		UserID:           userID,
+		Name:             req.Name,
+		Type:             exerciseType,
+		PrimaryMuscles:   req.PrimaryMuscles,
+		SecondaryMuscles: req.SecondaryMuscles,
+		Equipment:        emptyToNil(req.Equipment),
+		MovementPattern:  emptyToNil(req.MovementPattern),
+		CreatedAt:        time.Now(),
 	}
 
 	if err := s.exerciseRepo.Create(exercise); err != nil {
@@ -41,9 +53,9 @@ func (s *exerciseService) CreateExercise(userID int64, req *models.ExerciseCreat
 	return exercise.ToResponse(), nil
 }
 
-// GetExercises retrieves all exercises for a user
-func (s *exerciseService) GetExercises(userID int64) ([]*models.ExerciseResponse, error) {
-	exercises, err := s.exerciseRepo.GetByUserID(userID)
+// GetExercises retrieves a page of exercises for a user
+func (s *exerciseService) GetExercises(userID int64, filter *models.ExerciseFilter) (*models.ListResponse[*models.ExerciseResponse], error) {
+	exercises, next, err := s.exerciseRepo.List(userID, filter)
 	if err != nil {
 		return nil, errors.New("failed to retrieve exercises")
 	}
@@ -53,7 +65,7 @@ func (s *exerciseService) GetExercises(userID int64) ([]*models.ExerciseResponse
 		responses[i] = exercise.ToResponse()
 	}
 
-	return responses, nil
+	return models.NewListResponse(responses, next), nil
 }
 
 // GetExerciseByID retrieves an exercise by ID for a user
@@ -66,7 +78,8 @@ func (s *exerciseService) GetExerciseByID(userID, exerciseID int64) (*models.Exe
 	return exercise.ToResponse(), nil
 }
 
-// UpdateExercise updates an existing exercise for a user
+// UpdateExercise updates an existing exercise for a user. Catalog entries are shared, so only
+// their name can be changed, which adopts them under that alias.
 func (s *exerciseService) UpdateExercise(userID, exerciseID int64, req *models.ExerciseUpdateRequest) (*models.ExerciseResponse, error) {
 	// First, verify the exercise exists and belongs to the user
 	exercise, err := s.exerciseRepo.GetByIDAndUserID(exerciseID, userID)
@@ -74,8 +87,30 @@ func (s *exerciseService) UpdateExercise(userID, exerciseID int64, req *models.E
 		return nil, errors.New("exercise not found")
 	}
 
-	// Update the exercise name
+	if exercise.IsCatalog() {
+		if req.Type != "" || req.PrimaryMuscles != nil || req.SecondaryMuscles != nil || req.Equipment != nil || req.MovementPattern != nil {
+			return nil, errors.New("catalog exercises cannot be modified")
+		}
+		return s.AdoptExercise(userID, exerciseID, &models.CatalogAdoptRequest{Alias: &req.Name})
+	}
+
+	// Update the exercise name and any metadata present in the request
 	exercise.Name = req.Name
+	if req.Type != "" {
+		exercise.Type = req.Type
+	}
+	if req.PrimaryMuscles != nil {
+		exercise.PrimaryMuscles = req.PrimaryMuscles
+	}
+	if req.SecondaryMuscles != nil {
+		exercise.SecondaryMuscles = req.SecondaryMuscles
+	}
+	if req.Equipment != nil {
+		exercise.Equipment = emptyToNil(req.Equipment)
+	}
+	if req.MovementPattern != nil {
+		exercise.MovementPattern = emptyToNil(req.MovementPattern)
+	}
 
 	// Save the updated exercise
 	if err := s.exerciseRepo.Update(exercise); err != nil {
@@ -85,13 +120,25 @@ func (s *exerciseService) UpdateExercise(userID, exerciseID int64, req *models.E
 	return exercise.ToResponse(), nil
 }
 
-// DeleteExercise performs a soft delete on an exercise for a user
+// DeleteExercise performs a soft delete on an exercise for a user; catalog entries are
+// removed from the user's library instead
 func (s *exerciseService) DeleteExercise(userID, exerciseID int64) error {
 	// First, verify the exercise exists and belongs to the user
-	_, err := s.exerciseRepo.GetByIDAndUserID(exerciseID, userID)
+	exercise, err := s.exerciseRepo.GetByIDAndUserID(exerciseID, userID)
 	if err != nil {
 		return errors.New("exercise not found")
-	}	// Perform soft delete
+	}
+	if exercise.IsCatalog() {
+		if err := s.exerciseRepo.Unadopt(userID, exerciseID); err != nil {
+			if err.Error() == "exercise not found" {
+				return errors.New("exercise not found")
+			}
+			return errors.New("failed to delete exercise")
+		}
+		return nil
+	}
+
+	// Perform soft delete
 	if err := s.exerciseRepo.Delete(exerciseID, userID); err != nil {
 		if err.Error() == "exercise not found" {
 			return errors.New("exercise not found")
@@ -99,4 +146,45 @@ func (s *exerciseService) DeleteExercise(userID, exerciseID int64) error {
 		return errors.New("failed to delete exercise")
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// GetCatalog retrieves a page of the shared exercise catalog
+func (s *exerciseService) GetCatalog(filter *models.ExerciseFilter) (*models.ListResponse[*models.ExerciseResponse], error) {
+	exercises, next, err := s.exerciseRepo.ListCatalog(filter)
+	if err != nil {
+		return nil, errors.New("failed to retrieve catalog")
+	}
+
+	responses := make([]*models.ExerciseResponse, len(exercises))
+	for i, exercise := range exercises {
+		responses[i] = exercise.ToResponse()
+	}
+
+	return models.NewListResponse(responses, next), nil
+}
+
+// AdoptExercise adds a catalog exercise to a user's library, optionally under an alias.
+// Adopting again replaces the alias; sets logged against the entry are unaffected.
+func (s *exerciseService) AdoptExercise(userID, exerciseID int64, req *models.CatalogAdoptRequest) (*models.ExerciseResponse, error) {
+	if err := s.exerciseRepo.Adopt(userID, exerciseID, emptyToNil(req.Alias)); err != nil {
+		if err.Error() == "exercise not found" {
+			return nil, errors.New("exercise not found")
+		}
+		return nil, errors.New("failed to adopt exercise")
+	}
+
+	exercise, err := s.exerciseRepo.GetByIDAndUserID(exerciseID, userID)
+	if err != nil {
+		return nil, errors.New("exercise not found")
+	}
+
+	return exercise.ToResponse(), nil
+}
+
+// emptyToNil maps an empty optional string to nil so it is stored as NULL
+func emptyToNil(value *string) *string {
+	if value == nil || *value == "" {
+		return nil
+	}
+	return value
+}
@@ -14,8 +14,12 @@ type mockExerciseRepository struct {
 	getByIDFunc          func(id int64) (*models.Exercise, error)
 	getByUserIDFunc      func(userID int64) ([]*models.Exercise, error)
 	getByIDAndUserIDFunc func(id, userID int64) (*models.Exercise, error)
+	listFunc             func(userID int64, filter *models.ExerciseFilter) ([]*models.Exercise, string, error)
 	updateFunc           func(exercise *models.Exercise) error
 	deleteFunc           func(id, userID int64) error
+	listCatalogFunc      func(filter *models.ExerciseFilter) ([]*models.Exercise, string, error)
+	adoptFunc            func(userID, exerciseID int64, alias *string) error
+	unadoptFunc          func(userID, exerciseID int64) error
 }
 
 func (m *mockExerciseRepository) Create(exercise *models.Exercise) error {
@@ -46,6 +50,13 @@ func (m *mockExerciseRepository) GetByIDAndUserID(id, userID int64) (*models.Exe
 	return nil, nil
 }
 
+func (m *mockExerciseRepository) List(userID int64, filter *models.ExerciseFilter) ([]*models.Exercise, string, error) {
+	if m.listFunc != nil {
+		return m.listFunc(userID, filter)
+	}
+	return nil, "", nil
+}
+
 func (m *mockExerciseRepository) Update(exercise *models.Exercise) error {
 	if m.updateFunc != nil {
 		return m.updateFunc(exercise)
@@ -60,6 +71,27 @@ func (m *mockExerciseRepository) Delete(id, userID int64) error {
 	return nil
 }
 
+func (m *mockExerciseRepository) ListCatalog(filter *models.ExerciseFilter) ([]*models.Exercise, string, error) {
+	if m.listCatalogFunc != nil {
+		return m.listCatalogFunc(filter)
+	}
+	return nil, "", nil
+}
+
+func (m *mockExerciseRepository) Adopt(userID, exerciseID int64, alias *string) error {
+	if m.adoptFunc != nil {
+		return m.adoptFunc(userID, exerciseID, alias)
+	}
+	return nil
+}
+
+func (m *mockExerciseRepository) Unadopt(userID, exerciseID int64) error {
+	if m.unadoptFunc != nil {
+		return m.unadoptFunc(userID, exerciseID)
+	}
+	return nil
+}
+
 // TestUpdateExercise tests the UpdateExercise service method
 func TestUpdateExercise(t *testing.T) {
 	userID := int64(1)
@@ -368,6 +400,7 @@ func TestGetExerciseByID(t *testing.T) {
 // TestGetExercises tests the GetExercises service method
 func TestGetExercises(t *testing.T) {
 	userID := int64(1)
+	filter := &models.ExerciseFilter{ListParams: models.ListParams{Limit: 50, Sort: "name"}, Search: "press"}
 
 	t.Run("Success - Returns all exercises for user", func(t *testing.T) {
 		expectedExercises := []*models.Exercise{
@@ -377,16 +410,19 @@ func TestGetExercises(t *testing.T) {
 		}
 
 		mockRepo := &mockExerciseRepository{
-			getByUserIDFunc: func(uid int64) ([]*models.Exercise, error) {
+			listFunc: func(uid int64, f *models.ExerciseFilter) ([]*models.Exercise, string, error) {
 				if uid != userID {
 					t.Errorf("Expected userID %d, got %d", userID, uid)
 				}
-				return expectedExercises, nil
+				if f.Search != "press" {
+					t.Errorf("Expected search 'press', got '%s'", f.Search)
+				}
+				return expectedExercises, "", nil
 			},
 		}
 
 		service := NewExerciseService(mockRepo)
-		result, err := service.GetExercises(userID)
+		result, err := service.GetExercises(userID, filter)
 
 		if err != nil {
 			t.Errorf("Expected no error, got %v", err)
@@ -394,10 +430,13 @@ func TestGetExercises(t *testing.T) {
 		if result == nil {
 			t.Fatal("Expected result, got nil")
 		}
-		if len(result) != len(expectedExercises) {
-			t.Errorf("Expected %d exercises, got %d", len(expectedExercises), len(result))
+		if len(result.Data) != len(expectedExercises) {
+			t.Errorf("Expected %d exercises, got %d", len(expectedExercises), len(result.Data))
+		}
+		if result.NextCursor != nil {
+			t.Errorf("Expected no next cursor, got %q", *result.NextCursor)
 		}
-		for i, ex := range result {
+		for i, ex := range result.Data {
 			if ex.ID != expectedExercises[i].ID {
 				t.Errorf("Expected ID %d, got %d", expectedExercises[i].ID, ex.ID)
 			}
@@ -409,34 +448,34 @@ func TestGetExercises(t *testing.T) {
 
 	t.Run("Success - Returns empty list when user has no exercises", func(t *testing.T) {
 		mockRepo := &mockExerciseRepository{
-			getByUserIDFunc: func(uid int64) ([]*models.Exercise, error) {
-				return []*models.Exercise{}, nil
+			listFunc: func(uid int64, f *models.ExerciseFilter) ([]*models.Exercise, string, error) {
+				return []*models.Exercise{}, "", nil
 			},
 		}
 
 		service := NewExerciseService(mockRepo)
-		result, err := service.GetExercises(userID)
+		result, err := service.GetExercises(userID, filter)
 
 		if err != nil {
 			t.Errorf("Expected no error, got %v", err)
 		}
-		if result == nil {
+		if result == nil || result.Data == nil {
 			t.Fatal("Expected empty slice, got nil")
 		}
-		if len(result) != 0 {
-			t.Errorf("Expected 0 exercises, got %d", len(result))
+		if len(result.Data) != 0 {
+			t.Errorf("Expected 0 exercises, got %d", len(result.Data))
 		}
 	})
 
 	t.Run("Error - Database query fails", func(t *testing.T) {
 		mockRepo := &mockExerciseRepository{
-			getByUserIDFunc: func(uid int64) ([]*models.Exercise, error) {
-				return nil, errors.New("database connection lost")
+			listFunc: func(uid int64, f *models.ExerciseFilter) ([]*models.Exercise, string, error) {
+				return nil, "", errors.New("database connection lost")
 			},
 		}
 
 		service := NewExerciseService(mockRepo)
-		result, err := service.GetExercises(userID)
+		result, err := service.GetExercises(userID, filter)
 
 		if err == nil {
 			t.Error("Expected error, got nil")
@@ -580,3 +619,78 @@ func TestDeleteExercise(t *testing.T) {
 		}
 	})
 }
+
+// TestCatalogExercise tests how update and delete treat shared catalog exercises
+func TestCatalogExercise(t *testing.T) {
+	userID := int64(1)
+	exerciseID := int64(7)
+	catalogName := "Barbell Bench Press"
+
+	catalogEntry := func(id, uid int64) (*models.Exercise, error) {
+		return &models.Exercise{
+			ID:          exerciseID,
+			Name:        catalogName,
+			CatalogName: &catalogName,
+			Type:        models.ExerciseTypeWeight,
+		}, nil
+	}
+
+	t.Run("Update - Renaming adopts under an alias", func(t *testing.T) {
+		var adoptedAlias *string
+		mockRepo := &mockExerciseRepository{
+			getByIDAndUserIDFunc: catalogEntry,
+			adoptFunc: func(uid, id int64, alias *string) error {
+				adoptedAlias = alias
+				return nil
+			},
+			updateFunc: func(exercise *models.Exercise) error {
+				t.Error("Catalog exercises must not be updated")
+				return nil
+			},
+		}
+
+		service := NewExerciseService(mockRepo)
+		if _, err := service.UpdateExercise(userID, exerciseID, &models.ExerciseUpdateRequest{Name: "Bench"}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if adoptedAlias == nil || *adoptedAlias != "Bench" {
+			t.Errorf("Expected alias 'Bench', got %v", adoptedAlias)
+		}
+	})
+
+	t.Run("Update - Metadata changes are rejected", func(t *testing.T) {
+		mockRepo := &mockExerciseRepository{getByIDAndUserIDFunc: catalogEntry}
+
+		service := NewExerciseService(mockRepo)
+		_, err := service.UpdateExercise(userID, exerciseID, &models.ExerciseUpdateRequest{
+			Name: catalogName,
+			Type: models.ExerciseTypeBodyweight,
+		})
+		if err == nil || err.Error() != "catalog exercises cannot be modified" {
+			t.Errorf("Expected error 'catalog exercises cannot be modified', got %v", err)
+		}
+	})
+
+	t.Run("Delete - Removes the entry from the library", func(t *testing.T) {
+		unadopted := false
+		mockRepo := &mockExerciseRepository{
+			getByIDAndUserIDFunc: catalogEntry,
+			unadoptFunc: func(uid, id int64) error {
+				unadopted = true
+				return nil
+			},
+			deleteFunc: func(id, uid int64) error {
+				t.Error("Catalog exercises must not be soft deleted")
+				return nil
+			},
+		}
+
+		service := NewExerciseService(mockRepo)
+		if err := service.DeleteExercise(userID, exerciseID); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !unadopted {
+			t.Error("Expected the catalog exercise to be unadopted")
+		}
+	})
+}
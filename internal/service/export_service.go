@@ -0,0 +1,194 @@
+package service
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+	"phoenix-alliance-be/internal/repository"
+)
+
+// ExportService defines the interface for exporting a user's training log
+type ExportService interface {
+	Export(userID int64, format models.ExportFormat, w io.Writer) error
+}
+
+// exportCSVHeader names the columns of a CSV export
+var exportCSVHeader = []string{
+	"workout_id", "workout_name", "workout_date", "exercise_name", "position", "set_type",
+	"weight", "reps", "rpe", "rest_seconds", "distance_meters", "duration_seconds", "notes", "performed_at",
+}
+
+type exportService struct {
+	exportRepo repository.ExportRepository
+}
+
+// NewExportService creates a new export service
+func NewExportService(exportRepo repository.ExportRepository) ExportService {
+	return &exportService{exportRepo: exportRepo}
+}
+
+// Export writes the user's full training log to w as it is read from the database
+func (s *exportService) Export(userID int64, format models.ExportFormat, w io.Writer) error {
+	var err error
+	switch format {
+	case models.ExportFormatCSV:
+		err = s.exportCSV(userID, w)
+	case models.ExportFormatJSON:
+		err = s.exportJSON(userID, w)
+	default:
+		return errors.New("invalid export format")
+	}
+	if err != nil {
+		return errors.New("failed to export training log")
+	}
+	return nil
+}
+
+// exportCSV writes one row per set; workouts without sets are left out. Text entered by the
+// user is escaped so that spreadsheets do not run it as a formula.
+func (s *exportService) exportCSV(userID int64, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportCSVHeader); err != nil {
+		return err
+	}
+
+	err := s.exportRepo.StreamWorkouts(userID, func(row *models.ExportRow) error {
+		if row.Set == nil {
+			return nil
+		}
+		set := row.Set
+		return cw.Write([]string{
+			strconv.FormatInt(row.Workout.ID, 10),
+			csvText(row.Workout.Name),
+			row.Workout.PerformedAt.Format(time.RFC3339),
+			csvText(row.ExerciseName),
+			strconv.Itoa(set.Position),
+			string(set.Type),
+			formatFloat(&set.Weight),
+			strconv.Itoa(set.Reps),
+			formatInt(set.RPE),
+			formatInt(set.RestSeconds),
+			formatFloat(set.DistanceMeters),
+			formatInt(set.DurationSeconds),
+			csvText(derefString(set.Notes)),
+			set.PerformedAt.Format(time.RFC3339),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// exportJSON writes an object with the exported_at time, the exercises and the workouts with
+// their sets. Only one workout is held in memory at a time.
+func (s *exportService) exportJSON(userID int64, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	exportedAt, err := json.Marshal(time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	bw.WriteString(`{"exported_at":`)
+	bw.Write(exportedAt)
+
+	bw.WriteString(`,"exercises":[`)
+	list := &jsonList{w: bw}
+	err = s.exportRepo.StreamExercises(userID, func(exercise *models.Exercise) error {
+		return list.add(exercise.ToResponse())
+	})
+	if err != nil {
+		return err
+	}
+
+	bw.WriteString(`],"workouts":[`)
+	list = &jsonList{w: bw}
+	var workout *models.ExportWorkout
+	err = s.exportRepo.StreamWorkouts(userID, func(row *models.ExportRow) error {
+		if workout != nil && workout.ID != row.Workout.ID {
+			if err := list.add(workout); err != nil {
+				return err
+			}
+			workout = nil
+		}
+		if workout == nil {
+			workout = &models.ExportWorkout{WorkoutResponse: row.Workout.ToResponse(), Sets: []*models.SetResponse{}}
+		}
+		if row.Set != nil {
+			workout.Sets = append(workout.Sets, row.Set.ToResponse())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if workout != nil {
+		if err := list.add(workout); err != nil {
+			return err
+		}
+	}
+	bw.WriteString(`]}`)
+
+	return bw.Flush()
+}
+
+// jsonList writes the elements of a JSON array, separated by commas
+type jsonList struct {
+	w     *bufio.Writer
+	count int
+}
+
+// add writes v as the next element of the array
+func (l *jsonList) add(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if l.count > 0 {
+		l.w.WriteByte(',')
+	}
+	l.count++
+	_, err = l.w.Write(data)
+	return err
+}
+
+// csvText prefixes text that a spreadsheet would read as a formula with a quote, which makes
+// the spreadsheet show it as text
+func csvText(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+// formatFloat formats an optional number for CSV, empty when nil
+func formatFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+// formatInt formats an optional integer for CSV, empty when nil
+func formatInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+// derefString returns the value of an optional string, or "" when nil
+func derefString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
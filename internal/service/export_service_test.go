@@ -0,0 +1,159 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+// mockExportRepository is a mock implementation of ExportRepository
+type mockExportRepository struct {
+	exercises []*models.Exercise
+	rows      []*models.ExportRow
+	err       error
+}
+
+func (m *mockExportRepository) StreamExercises(userID int64, fn func(exercise *models.Exercise) error) error {
+	for _, exercise := range m.exercises {
+		if err := fn(exercise); err != nil {
+			return err
+		}
+	}
+	return m.err
+}
+
+func (m *mockExportRepository) StreamWorkouts(userID int64, fn func(row *models.ExportRow) error) error {
+	for _, row := range m.rows {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return m.err
+}
+
+func newExportFixture() *mockExportRepository {
+	performedAt := time.Date(2024, 5, 13, 18, 0, 0, 0, time.UTC)
+	push := &models.Workout{ID: 1, Name: "Push", Status: models.WorkoutStatusCompleted, PerformedAt: performedAt}
+	planned := &models.Workout{ID: 2, Name: "Pull", Status: models.WorkoutStatusPlanned, PerformedAt: performedAt.AddDate(0, 0, 2)}
+	rpe := 8
+	notes := "Paused, \"slow\" reps"
+
+	return &mockExportRepository{
+		exercises: []*models.Exercise{{ID: 3, Name: "Bench Press", Type: models.ExerciseTypeWeight}},
+		rows: []*models.ExportRow{
+			{Workout: push, ExerciseName: "Bench Press", Set: &models.Set{ID: 10, WorkoutID: 1, ExerciseID: 3, Weight: 100, Reps: 5, RPE: &rpe, Type: models.SetTypeWorking, Position: 1, PerformedAt: performedAt}},
+			{Workout: push, ExerciseName: "Bench Press", Set: &models.Set{ID: 11, WorkoutID: 1, ExerciseID: 3, Weight: 102.5, Reps: 3, Notes: &notes, Type: models.SetTypeWorking, Position: 2, PerformedAt: performedAt}},
+			{Workout: planned},
+		},
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	service := NewExportService(newExportFixture())
+
+	var out bytes.Buffer
+	if err := service.Export(1, models.ExportFormatCSV, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	// The header and one row per set; the workout without sets is left out
+	if len(records) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(records))
+	}
+	if records[0][1] != "workout_name" || records[1][1] != "Push" || records[1][3] != "Bench Press" {
+		t.Errorf("unexpected rows %v", records[:2])
+	}
+	if records[1][6] != "100" || records[1][8] != "8" || records[2][6] != "102.5" || records[2][8] != "" {
+		t.Errorf("unexpected set values %v and %v", records[1], records[2])
+	}
+	if records[2][12] != "Paused, \"slow\" reps" {
+		t.Errorf("expected notes to round-trip, got %q", records[2][12])
+	}
+}
+
+func TestExportCSVFormulas(t *testing.T) {
+	repo := newExportFixture()
+	repo.rows[0].Workout = &models.Workout{ID: 1, Name: "=HYPERLINK(\"http://example.com\")", PerformedAt: time.Now()}
+	repo.rows[0].ExerciseName = "@SUM(A1)"
+	notes := "-2+3"
+	repo.rows[0].Set.Notes = &notes
+	service := NewExportService(repo)
+
+	var out bytes.Buffer
+	if err := service.Export(1, models.ExportFormatCSV, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+
+	// Text starting like a formula is shown as text; numbers and other text are left as they are
+	row := records[1]
+	if row[1] != "'=HYPERLINK(\"http://example.com\")" || row[3] != "'@SUM(A1)" || row[12] != "'-2+3" {
+		t.Errorf("expected formulas to be escaped, got %v", row)
+	}
+	if records[2][1] != "Push" || records[2][3] != "Bench Press" || records[2][6] != "102.5" {
+		t.Errorf("expected other cells unchanged, got %v", records[2])
+	}
+
+	// JSON keeps the values as they were entered
+	out.Reset()
+	if err := service.Export(1, models.ExportFormatJSON, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Contains(out.Bytes(), []byte(`"notes":"-2+3"`)) {
+		t.Errorf("expected JSON values unchanged, got %s", out.String())
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	service := NewExportService(newExportFixture())
+
+	var out bytes.Buffer
+	if err := service.Export(1, models.ExportFormatJSON, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var export struct {
+		ExportedAt time.Time                 `json:"exported_at"`
+		Exercises  []models.ExerciseResponse `json:"exercises"`
+		Workouts   []struct {
+			ID   int64                `json:"id"`
+			Name string               `json:"name"`
+			Sets []models.SetResponse `json:"sets"`
+		} `json:"workouts"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &export); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if export.ExportedAt.IsZero() || len(export.Exercises) != 1 || len(export.Workouts) != 2 {
+		t.Fatalf("unexpected export %s", out.String())
+	}
+	if export.Workouts[0].Name != "Push" || len(export.Workouts[0].Sets) != 2 || export.Workouts[0].Sets[1].Weight != 102.5 {
+		t.Errorf("unexpected first workout %+v", export.Workouts[0])
+	}
+	if export.Workouts[1].Sets == nil || len(export.Workouts[1].Sets) != 0 {
+		t.Errorf("expected the planned workout with no sets, got %+v", export.Workouts[1])
+	}
+}
+
+func TestExportError(t *testing.T) {
+	repo := newExportFixture()
+	repo.err = errors.New("connection reset")
+	service := NewExportService(repo)
+
+	var out bytes.Buffer
+	if err := service.Export(1, models.ExportFormatJSON, &out); err == nil {
+		t.Error("expected an error when the log cannot be read")
+	}
+}
@@ -0,0 +1,476 @@
+package service
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+// importRow is a set read from a line of an import file, or a rest period for the set before it
+type importRow struct {
+	line          int
+	key           string          // Rows with the same key belong to the same workout
+	workout       *models.Workout // The workout fields of the row
+	category      string          // Names workouts of formats without workout names
+	exercise      string
+	exerciseNotes string // Added to the first set of the exercise in a row
+	set           *models.Set
+	rest          *int // Rest after the previous set, on rows without a set
+}
+
+// importFormat reads the rows of one app's CSV export
+type importFormat struct {
+	columns []string // Lowercase columns that identify the format and must be present
+	parse   func(row csvRow, loc *time.Location) (*importRow, error)
+}
+
+// importFormats are the supported formats, in detection order
+var importFormats = []struct {
+	format models.ImportFormat
+	importFormat
+}{
+	{models.ImportFormatHevy, importFormat{[]string{"title", "start_time", "exercise_title", "set_index", "reps"}, parseHevyRow}},
+	{models.ImportFormatStrong, importFormat{[]string{"date", "workout name", "exercise name", "set order", "reps"}, parseStrongRow}},
+	{models.ImportFormatFitNotes, importFormat{[]string{"date", "exercise", "category", "reps"}, parseFitNotesRow}},
+}
+
+// csvRow gives access to the fields of a CSV record by lowercase column name
+type csvRow struct {
+	columns map[string]int
+	record  []string
+}
+
+// get returns the trimmed value of a column, or "" if the column or field is missing
+func (r csvRow) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+// getPrefix returns the value of the first column starting with prefix, such as "weight (kgs)"
+func (r csvRow) getPrefix(prefix string) string {
+	for column := range r.columns {
+		if strings.HasPrefix(column, prefix) {
+			return r.get(column)
+		}
+	}
+	return ""
+}
+
+// parseImportFile reads the workouts of a CSV export in format, or in the format detected from
+// its header when format is empty. Local times are read in loc. Lines that cannot be read are
+// reported and left out.
+func parseImportFile(r io.Reader, format models.ImportFormat, loc *time.Location) (models.ImportFormat, []*models.ImportWorkout, []string, error) {
+	reader, err := newImportReader(r)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return "", nil, nil, errors.New("invalid import: the file is empty or not a CSV file")
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := columns[name]; !ok {
+			columns[name] = i
+		}
+	}
+
+	spec, format, err := findImportFormat(format, columns)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	var rows []*importRow
+	var skipped []string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				skipped = append(skipped, fmt.Sprintf("line %d: %v", parseErr.Line, parseErr.Err))
+				continue
+			}
+			return "", nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		row, err := spec.parse(csvRow{columns: columns, record: record}, loc)
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+		row.line = line
+		rows = append(rows, row)
+	}
+
+	return format, groupImportRows(rows), skipped, nil
+}
+
+// newImportReader returns a CSV reader for the file, separated by semicolons when its header
+// has more semicolons than commas, as in exports made with a decimal comma
+func newImportReader(r io.Reader) (*csv.Reader, error) {
+	buffered := bufio.NewReader(r)
+	head, err := buffered.Peek(4096)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	firstLine, _, _ := strings.Cut(string(head), "\n")
+
+	reader := csv.NewReader(buffered)
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+	return reader, nil
+}
+
+// findImportFormat returns the requested format, or the first format whose columns are all
+// in the header, and checks that the header has the columns of the format
+func findImportFormat(format models.ImportFormat, columns map[string]int) (importFormat, models.ImportFormat, error) {
+	for _, f := range importFormats {
+		if format != "" && f.format != format {
+			continue
+		}
+		missing := slices.IndexFunc(f.columns, func(column string) bool {
+			_, ok := columns[column]
+			return !ok
+		})
+		if missing < 0 {
+			return f.importFormat, f.format, nil
+		}
+		if format != "" {
+			return importFormat{}, "", fmt.Errorf("invalid import: missing %q column for the %s format", f.columns[missing], format)
+		}
+	}
+	return importFormat{}, "", errors.New("invalid import: unrecognized file; export a CSV from Strong, Hevy or FitNotes")
+}
+
+// groupImportRows gathers rows into workouts, in order of performed_at, with their sets in
+// file order
+func groupImportRows(rows []*importRow) []*models.ImportWorkout {
+	var workouts []*models.ImportWorkout
+	byKey := make(map[string]*models.ImportWorkout)
+	categories := make(map[*models.ImportWorkout][]string)
+	for _, row := range rows {
+		workout, ok := byKey[row.key]
+		if !ok {
+			workout = &models.ImportWorkout{Workout: row.workout}
+			byKey[row.key] = workout
+			workouts = append(workouts, workout)
+		}
+		if row.category != "" && !slices.Contains(categories[workout], row.category) {
+			categories[workout] = append(categories[workout], row.category)
+		}
+
+		var last *models.ImportSet
+		if len(workout.Sets) > 0 {
+			last = workout.Sets[len(workout.Sets)-1]
+		}
+		if row.set == nil {
+			if last != nil && row.rest != nil {
+				last.Set.RestSeconds = row.rest
+			}
+			continue
+		}
+		if row.exerciseNotes != "" && row.set.Notes == nil && (last == nil || last.ExerciseName != row.exercise) {
+			row.set.Notes = &row.exerciseNotes
+		}
+		row.set.Position = len(workout.Sets) + 1
+		workout.Sets = append(workout.Sets, &models.ImportSet{Line: row.line, ExerciseName: row.exercise, Set: row.set})
+	}
+
+	for _, workout := range workouts {
+		if workout.Workout.Name == "" {
+			workout.Workout.Name = strings.Join(categories[workout], ", ")
+		}
+		if workout.Workout.Name == "" {
+			workout.Workout.Name = "Workout"
+		}
+	}
+	sort.SliceStable(workouts, func(i, j int) bool {
+		return workouts[i].Workout.PerformedAt.Before(workouts[j].Workout.PerformedAt)
+	})
+	return workouts
+}
+
+// parseStrongRow reads a line of a Strong export. Rest timer lines carry the rest after the
+// set before them.
+func parseStrongRow(row csvRow, loc *time.Location) (*importRow, error) {
+	date, err := parseImportTime(row.get("date"), loc, "2006-01-02 15:04:05", "2006-01-02 15:04")
+	if err != nil {
+		return nil, err
+	}
+	workout := &models.Workout{Name: row.get("workout name"), PerformedAt: date, StartedAt: &date}
+	if duration, ok := parseImportDuration(row.get("duration")); ok && duration > 0 {
+		finished := date.Add(time.Duration(duration) * time.Second)
+		workout.FinishedAt = &finished
+	}
+	if notes := row.get("workout notes"); notes != "" {
+		workout.Notes = &notes
+	}
+	result := &importRow{key: row.get("date") + "\x00" + workout.Name, workout: workout, exercise: row.get("exercise name")}
+
+	order := strings.ToLower(row.get("set order"))
+	if order == "rest timer" {
+		if seconds, ok := parseImportDuration(row.get("seconds")); ok && seconds > 0 {
+			result.rest = &seconds
+		}
+		return result, nil
+	}
+	if result.exercise == "" {
+		return nil, errors.New("missing exercise name")
+	}
+
+	setType := models.SetTypeWorking
+	switch order {
+	case "w":
+		setType = models.SetTypeWarmup
+	case "d":
+		setType = models.SetTypeDrop
+	case "f":
+		setType = models.SetTypeFailure
+	}
+
+	values := importValues{
+		weight:   row.get("weight"),
+		reps:     row.get("reps"),
+		distance: row.get("distance"),
+		duration: row.get("seconds"),
+		rpe:      row.get("rpe"),
+		notes:    row.get("notes"),
+	}
+	result.set, err = values.toSet(setType, date, 1000)
+	return result, err
+}
+
+// parseHevyRow reads a line of a Hevy export
+func parseHevyRow(row csvRow, loc *time.Location) (*importRow, error) {
+	layouts := []string{"2 Jan 2006, 15:04", "2 Jan 2006 15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"}
+	start, err := parseImportTime(row.get("start_time"), loc, layouts...)
+	if err != nil {
+		return nil, err
+	}
+	workout := &models.Workout{Name: row.get("title"), PerformedAt: start, StartedAt: &start}
+	if end, err := parseImportTime(row.get("end_time"), loc, layouts...); err == nil && end.After(start) {
+		workout.FinishedAt = &end
+	}
+	if notes := row.get("description"); notes != "" {
+		workout.Notes = &notes
+	}
+	result := &importRow{
+		key:           row.get("start_time") + "\x00" + workout.Name,
+		workout:       workout,
+		exercise:      row.get("exercise_title"),
+		exerciseNotes: row.get("exercise_notes"),
+	}
+	if result.exercise == "" {
+		return nil, errors.New("missing exercise title")
+	}
+
+	setType := models.SetTypeWorking
+	switch strings.ToLower(row.get("set_type")) {
+	case "warmup":
+		setType = models.SetTypeWarmup
+	case "dropset":
+		setType = models.SetTypeDrop
+	case "failure":
+		setType = models.SetTypeFailure
+	}
+
+	values := importValues{
+		weight:   row.getPrefix("weight_"),
+		reps:     row.get("reps"),
+		duration: row.get("duration_seconds"),
+		rpe:      row.get("rpe"),
+	}
+	metersPerUnit := 1000.0
+	if values.distance = row.get("distance_km"); values.distance == "" {
+		values.distance = row.get("distance_miles")
+		metersPerUnit = 1609.344
+	}
+	if result.set, err = values.toSet(setType, start, metersPerUnit); err != nil {
+		return nil, err
+	}
+
+	// Superset IDs start at 0
+	if superset, err := strconv.Atoi(row.get("superset_id")); err == nil && superset >= 0 {
+		group := superset + 1
+		result.set.GroupID = &group
+	}
+	return result, nil
+}
+
+// fitNotesDistanceUnits converts the distance units of FitNotes to meters
+var fitNotesDistanceUnits = map[string]float64{
+	"":   1000,
+	"km": 1000,
+	"m":  1,
+	"mi": 1609.344,
+	"ft": 0.3048,
+}
+
+// parseFitNotesRow reads a line of a FitNotes export. FitNotes records dates without a time,
+// so each day is one workout, set at noon, and named after the categories trained.
+func parseFitNotesRow(row csvRow, loc *time.Location) (*importRow, error) {
+	date, err := parseImportTime(row.get("date"), loc, "2006-01-02")
+	if err != nil {
+		return nil, err
+	}
+	date = date.Add(12 * time.Hour)
+	result := &importRow{
+		key:      row.get("date"),
+		workout:  &models.Workout{PerformedAt: date},
+		category: row.get("category"),
+		exercise: row.get("exercise"),
+	}
+	if result.exercise == "" {
+		return nil, errors.New("missing exercise")
+	}
+
+	metersPerUnit, ok := fitNotesDistanceUnits[strings.ToLower(row.get("distance unit"))]
+	if !ok {
+		return nil, fmt.Errorf("unknown distance unit %q", row.get("distance unit"))
+	}
+	values := importValues{
+		weight:   row.getPrefix("weight"),
+		reps:     row.get("reps"),
+		distance: row.get("distance"),
+		duration: row.get("time"),
+		notes:    row.get("comment"),
+	}
+	result.set, err = values.toSet(models.SetTypeWorking, date, metersPerUnit)
+	return result, err
+}
+
+// importValues holds the raw measurements of a set in an import file
+type importValues struct {
+	weight, reps, distance, duration, rpe, notes string
+}
+
+// toSet parses the measurements into a set performed at performedAt. Zero distances and
+// durations are left unset, and so is an RPE outside 1-10.
+func (v importValues) toSet(setType models.SetType, performedAt time.Time, metersPerUnit float64) (*models.Set, error) {
+	set := &models.Set{Type: setType, PerformedAt: performedAt}
+
+	var err error
+	if set.Weight, err = parseImportNumber(v.weight); err != nil || set.Weight < 0 {
+		return nil, fmt.Errorf("invalid weight %q", v.weight)
+	}
+	reps, err := parseImportNumber(v.reps)
+	if err != nil || reps < 0 {
+		return nil, fmt.Errorf("invalid reps %q", v.reps)
+	}
+	set.Reps = int(math.Round(reps))
+
+	distance, err := parseImportNumber(v.distance)
+	if err != nil || distance < 0 {
+		return nil, fmt.Errorf("invalid distance %q", v.distance)
+	}
+	if distance > 0 {
+		meters := round2(distance * metersPerUnit)
+		set.DistanceMeters = &meters
+	}
+
+	duration, ok := parseImportDuration(v.duration)
+	if !ok {
+		return nil, fmt.Errorf("invalid duration %q", v.duration)
+	}
+	if duration > 0 {
+		set.DurationSeconds = &duration
+	}
+
+	if rpe, err := parseImportNumber(v.rpe); err == nil && rpe >= 1 && rpe <= 10 {
+		rounded := int(math.Round(rpe))
+		set.RPE = &rounded
+	}
+	if v.notes != "" {
+		set.Notes = &v.notes
+	}
+	return set, nil
+}
+
+// parseImportNumber parses a decimal number, which may use a decimal comma. Empty is zero.
+func parseImportNumber(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if !strings.Contains(s, ".") {
+		s = strings.Replace(s, ",", ".", 1)
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// importDurationPart matches the parts of durations such as "1h 5m"
+var importDurationPart = regexp.MustCompile(`(\d+)\s*([hms])`)
+
+// parseImportDuration parses a duration in seconds, as "h:mm:ss" or "mm:ss", or as "1h 5m".
+// Empty is zero.
+func parseImportDuration(s string) (int, bool) {
+	if s == "" {
+		return 0, true
+	}
+	if seconds, err := parseImportNumber(s); err == nil {
+		return int(math.Round(seconds)), seconds >= 0
+	}
+
+	if strings.Contains(s, ":") {
+		seconds := 0
+		for _, part := range strings.Split(s, ":") {
+			n, err := strconv.Atoi(part)
+			if err != nil || n < 0 {
+				return 0, false
+			}
+			seconds = seconds*60 + n
+		}
+		return seconds, true
+	}
+
+	parts := importDurationPart.FindAllStringSubmatch(s, -1)
+	if parts == nil {
+		return 0, false
+	}
+	seconds := 0
+	for _, part := range parts {
+		n, _ := strconv.Atoi(part[1])
+		switch part[2] {
+		case "h":
+			seconds += n * 3600
+		case "m":
+			seconds += n * 60
+		default:
+			seconds += n
+		}
+	}
+	return seconds, true
+}
+
+// parseImportTime parses a local time in loc with the first matching layout
+func parseImportTime(s string, loc *time.Location, layouts ...string) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
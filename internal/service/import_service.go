@@ -0,0 +1,327 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+	"phoenix-alliance-be/internal/repository"
+)
+
+// ImportService defines the interface for importing training history from other apps
+type ImportService interface {
+	Import(userID int64, r io.Reader, opts models.ImportOptions) (*models.ImportResult, error)
+}
+
+type importService struct {
+	importRepo repository.ImportRepository
+	setRepo    repository.SetRepository
+	recordRepo repository.PersonalRecordRepository
+	userRepo   repository.UserRepository
+}
+
+// NewImportService creates a new import service
+func NewImportService(
+	importRepo repository.ImportRepository,
+	setRepo repository.SetRepository,
+	recordRepo repository.PersonalRecordRepository,
+	userRepo repository.UserRepository,
+) ImportService {
+	return &importService{
+		importRepo: importRepo,
+		setRepo:    setRepo,
+		recordRepo: recordRepo,
+		userRepo:   userRepo,
+	}
+}
+
+// Import reads a CSV export of another app and creates its workouts and sets, mapping its
+// exercise names onto the user's exercises and creating the missing ones. Local times in the
+// file are read in the user's time zone. Workouts already logged at the same time under the
+// same name are left out, so that a file can be imported again.
+func (s *importService) Import(userID int64, r io.Reader, opts models.ImportOptions) (*models.ImportResult, error) {
+	if opts.Format != "" && !opts.Format.IsValid() {
+		return nil, errors.New("invalid import: format must be 'strong', 'hevy', or 'fitnotes'")
+	}
+
+	format, workouts, skipped, err := parseImportFile(r, opts.Format, userLocation(s.userRepo, userID))
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid import: ") {
+			return nil, err
+		}
+		return nil, errors.New("failed to read import file")
+	}
+
+	result := &models.ImportResult{
+		Format:    format,
+		DryRun:    opts.DryRun,
+		Exercises: []models.ImportExerciseMatch{},
+		Skipped:   skipped,
+	}
+	if result.Skipped == nil {
+		result.Skipped = []string{}
+	}
+
+	workouts, result.DuplicateWorkouts, err = s.withoutDuplicates(userID, workouts)
+	if err != nil {
+		return nil, errors.New("failed to retrieve workouts")
+	}
+
+	matches, err := s.matchExercises(userID, workouts)
+	if err != nil {
+		return nil, errors.New("failed to retrieve exercises")
+	}
+
+	// Sets are checked against the type of the exercise they were matched to
+	batch := &models.ImportBatch{}
+	now := time.Now()
+	for _, workout := range workouts {
+		var sets []*models.ImportSet
+		for _, imported := range workout.Sets {
+			if msg := imported.Exercise.Type.ValidateSet(importSetValues(imported.Set)); msg != "" {
+				result.Skipped = append(result.Skipped, fmt.Sprintf("line %d: %s (%s)", imported.Line, msg, imported.ExerciseName))
+				continue
+			}
+			imported.Set.CreatedAt = now
+			sets = append(sets, imported)
+		}
+		if len(sets) == 0 {
+			continue
+		}
+
+		workout.Sets = sets
+		workout.Workout.UserID = userID
+		workout.Workout.Status = models.WorkoutStatusCompleted
+		workout.Workout.CreatedAt = now
+		batch.Workouts = append(batch.Workouts, workout)
+		result.Workouts++
+		result.Sets += len(sets)
+		for _, imported := range sets {
+			matches[strings.ToLower(imported.ExerciseName)].used = true
+		}
+	}
+
+	// Exercises whose sets were all skipped are neither reported nor created
+	for _, name := range matchOrder(workouts) {
+		match := matches[name]
+		if !match.used {
+			continue
+		}
+		if match.Created {
+			batch.Exercises = append(batch.Exercises, match.exercise)
+		}
+		result.Exercises = append(result.Exercises, match.ImportExerciseMatch)
+	}
+
+	if opts.DryRun || len(batch.Workouts) == 0 {
+		return result, nil
+	}
+	if err := s.importRepo.Create(userID, batch); err != nil {
+		return nil, errors.New("failed to import workouts")
+	}
+	for i, match := range result.Exercises {
+		result.Exercises[i].ExerciseID = matches[strings.ToLower(match.Name)].exercise.ID
+	}
+	s.replayPersonalRecords(userID, batch)
+
+	return result, nil
+}
+
+// replayPersonalRecords recomputes the records of every strength exercise an import added
+// sets to. Imported sets usually predate the ones logged in the app, so the records are
+// replayed from the whole history. The import is already stored, so failures are logged
+// instead of failing the request.
+func (s *importService) replayPersonalRecords(userID int64, batch *models.ImportBatch) {
+	touched := make(map[int64]bool)
+	for _, workout := range batch.Workouts {
+		for _, imported := range workout.Sets {
+			exercise := imported.Exercise
+			if !exercise.Type.CountsReps() || touched[exercise.ID] {
+				continue
+			}
+			touched[exercise.ID] = true
+
+			sets, err := s.setRepo.GetByExerciseIDAndUserID(exercise.ID, userID)
+			if err != nil {
+				log.Printf("Failed to load history for personal records (exercise %d): %v", exercise.ID, err)
+				continue
+			}
+			if err := s.recordRepo.ReplaceForExercise(userID, exercise.ID, replayPersonalRecords(userID, sets)); err != nil {
+				log.Printf("Failed to store personal records (exercise %d): %v", exercise.ID, err)
+			}
+		}
+	}
+}
+
+// withoutDuplicates leaves out the workouts the user already logged at the same time under
+// the same name, and returns how many there were
+func (s *importService) withoutDuplicates(userID int64, workouts []*models.ImportWorkout) ([]*models.ImportWorkout, int, error) {
+	if len(workouts) == 0 {
+		return workouts, 0, nil
+	}
+
+	times := make([]time.Time, len(workouts))
+	for i, workout := range workouts {
+		times[i] = workout.Workout.PerformedAt
+	}
+	existing, err := s.importRepo.FindWorkouts(userID, times)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	logged := make(map[string]bool, len(existing))
+	for _, workout := range existing {
+		logged[workoutKey(workout)] = true
+	}
+	var kept []*models.ImportWorkout
+	for _, workout := range workouts {
+		if !logged[workoutKey(workout.Workout)] {
+			kept = append(kept, workout)
+		}
+	}
+	return kept, len(workouts) - len(kept), nil
+}
+
+// workoutKey identifies a workout by when it was performed and its name
+func workoutKey(workout *models.Workout) string {
+	return fmt.Sprintf("%d\x00%s", workout.PerformedAt.UnixNano(), strings.ToLower(workout.Name))
+}
+
+// exerciseMatch is the exercise an imported exercise name maps to
+type exerciseMatch struct {
+	models.ImportExerciseMatch
+	exercise *models.Exercise
+	used     bool // Some of its sets are imported
+}
+
+// matchExercises maps each exercise name of the workouts, case-insensitively, onto an exercise
+// the user can reference, or onto a new exercise of the type its sets measure. Names such as
+// "Bench Press (Barbell)" also match "Barbell Bench Press", the catalog's naming. Matches are
+// keyed by lowercase name.
+func (s *importService) matchExercises(userID int64, workouts []*models.ImportWorkout) (map[string]*exerciseMatch, error) {
+	names := matchOrder(workouts)
+	candidates := make([]string, 0, 2*len(names))
+	for _, name := range names {
+		candidates = append(candidates, name)
+		if alternative := catalogStyleName(name); alternative != "" {
+			candidates = append(candidates, alternative)
+		}
+	}
+
+	found, err := s.importRepo.FindExercises(userID, candidates)
+	if err != nil {
+		return nil, err
+	}
+	// Exercises in the user's library come first and take precedence
+	byName := make(map[string]*models.Exercise)
+	for _, exercise := range found {
+		for _, name := range []string{exercise.Name, derefString(exercise.CatalogName)} {
+			if key := strings.ToLower(name); key != "" && byName[key] == nil {
+				byName[key] = exercise
+			}
+		}
+	}
+
+	setsByName := make(map[string][]*models.Set)
+	spelling := make(map[string]string)
+	for _, workout := range workouts {
+		for _, imported := range workout.Sets {
+			key := strings.ToLower(imported.ExerciseName)
+			setsByName[key] = append(setsByName[key], imported.Set)
+			if _, ok := spelling[key]; !ok {
+				spelling[key] = imported.ExerciseName
+			}
+		}
+	}
+
+	matches := make(map[string]*exerciseMatch, len(names))
+	for _, name := range names {
+		exercise := byName[name]
+		if exercise == nil {
+			exercise = byName[catalogStyleName(name)]
+		}
+		match := &exerciseMatch{exercise: exercise}
+		if exercise == nil {
+			match.exercise = &models.Exercise{
+				UserID:    userID,
+				Name:      spelling[name],
+				Type:      inferExerciseType(setsByName[name]),
+				CreatedAt: time.Now(),
+			}
+			match.Created = true
+		}
+		match.Name = spelling[name]
+		match.ExerciseID = match.exercise.ID
+		match.ExerciseName = match.exercise.Name
+		matches[name] = match
+	}
+
+	for _, workout := range workouts {
+		for _, imported := range workout.Sets {
+			imported.Exercise = matches[strings.ToLower(imported.ExerciseName)].exercise
+		}
+	}
+	return matches, nil
+}
+
+// matchOrder returns the distinct lowercase exercise names of the workouts, in order of first use
+func matchOrder(workouts []*models.ImportWorkout) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, workout := range workouts {
+		for _, imported := range workout.Sets {
+			if name := strings.ToLower(imported.ExerciseName); !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// catalogStyleName turns a lowercase name with its equipment in parentheses, as in
+// "bench press (barbell)", into "barbell bench press". It returns "" for other names.
+func catalogStyleName(name string) string {
+	base, equipment, ok := strings.Cut(name, " (")
+	equipment, closed := strings.CutSuffix(equipment, ")")
+	if !ok || !closed || base == "" || equipment == "" {
+		return ""
+	}
+	return equipment + " " + base
+}
+
+// inferExerciseType guesses the type of a new exercise from the measurements of its sets
+func inferExerciseType(sets []*models.Set) models.ExerciseType {
+	var reps, weight, distance bool
+	for _, set := range sets {
+		reps = reps || set.Reps > 0
+		weight = weight || set.Weight > 0
+		distance = distance || set.DistanceMeters != nil
+	}
+
+	switch {
+	case reps && weight:
+		return models.ExerciseTypeWeight
+	case reps:
+		return models.ExerciseTypeBodyweight
+	case distance:
+		return models.ExerciseTypeCardio
+	default:
+		return models.ExerciseTypeTimed
+	}
+}
+
+// importSetValues returns the measurements of an imported set for validation
+func importSetValues(set *models.Set) *models.SetValues {
+	return &models.SetValues{
+		Weight:          set.Weight,
+		Reps:            set.Reps,
+		RPE:             set.RPE,
+		DistanceMeters:  set.DistanceMeters,
+		DurationSeconds: set.DurationSeconds,
+	}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+// mockImportRepository is a mock implementation of ImportRepository
+type mockImportRepository struct {
+	exercises []*models.Exercise
+	workouts  []*models.Workout
+	created   *models.ImportBatch
+}
+
+func (m *mockImportRepository) FindExercises(userID int64, names []string) ([]*models.Exercise, error) {
+	return m.exercises, nil
+}
+
+func (m *mockImportRepository) FindWorkouts(userID int64, performedAt []time.Time) ([]*models.Workout, error) {
+	return m.workouts, nil
+}
+
+func (m *mockImportRepository) Create(userID int64, batch *models.ImportBatch) error {
+	for i, exercise := range batch.Exercises {
+		exercise.ID = int64(100 + i)
+	}
+	m.created = batch
+	return nil
+}
+
+const strongExport = `Date,Workout Name,Duration,Exercise Name,Set Order,Weight,Reps,Distance,Seconds,Notes,Workout Notes,RPE
+2024-05-13 18:00:00,Push,1h 5m,Bench Press (Barbell),W,60,10,0,0,,Felt strong,
+2024-05-13 18:00:00,Push,1h 5m,Bench Press (Barbell),1,100,5,0,0,Paused,Felt strong,8.5
+2024-05-13 18:00:00,Push,1h 5m,Bench Press (Barbell),Rest Timer,0,0,0,180,,Felt strong,
+2024-05-13 18:00:00,Push,1h 5m,Dips,1,0,12,0,0,,Felt strong,
+2024-05-15 07:30:00,Run,30m,Running,1,0,0,5.2,1800,,,
+2024-05-15 07:30:00,Run,30m,Running,2,0,abc,0,0,,,
+`
+
+const hevyExport = `"title","start_time","end_time","description","exercise_title","superset_id","exercise_notes","set_index","set_type","weight_lbs","reps","distance_miles","duration_seconds","rpe"
+"Upper","15 May 2024, 18:00","15 May 2024, 19:10","","Lat Pulldown (Cable)","0","Slow eccentrics","0","normal","120","10","","",""
+"Upper","15 May 2024, 18:00","15 May 2024, 19:10","","Lat Pulldown (Cable)","0","Slow eccentrics","1","dropset","90","8","","","9"
+"Upper","15 May 2024, 18:00","15 May 2024, 19:10","","Plank","","","0","normal","","","","60",""
+`
+
+const fitNotesExport = `Date;Exercise;Category;Weight (kgs);Reps;Distance;Distance Unit;Time;Comment
+2024-05-13;Barbell Squat;Legs;140,5;5;;;;
+2024-05-13;Crunches;Abs;;20;;;;
+2024-05-14;Cycling;Cardio;;;12;km;0:35:00;Easy
+`
+
+func TestParseImportFile(t *testing.T) {
+	t.Run("strong", func(t *testing.T) {
+		format, workouts, skipped, err := parseImportFile(strings.NewReader(strongExport), "", time.UTC)
+		if err != nil || format != models.ImportFormatStrong {
+			t.Fatalf("expected a Strong file, got %q (%v)", format, err)
+		}
+		if len(skipped) != 1 || !strings.HasPrefix(skipped[0], "line 7: invalid reps") {
+			t.Errorf("expected line 7 to be skipped, got %v", skipped)
+		}
+		if len(workouts) != 2 || len(workouts[0].Sets) != 3 || len(workouts[1].Sets) != 1 {
+			t.Fatalf("expected workouts of 3 and 1 sets, got %+v", workouts)
+		}
+
+		push := workouts[0]
+		if push.Workout.Name != "Push" || *push.Workout.Notes != "Felt strong" || push.Workout.FinishedAt.Sub(*push.Workout.StartedAt) != 65*time.Minute {
+			t.Errorf("unexpected workout %+v", push.Workout)
+		}
+		warmup, top := push.Sets[0].Set, push.Sets[1].Set
+		if warmup.Type != models.SetTypeWarmup || top.Type != models.SetTypeWorking || top.Position != 2 {
+			t.Errorf("unexpected set types %s and %s", warmup.Type, top.Type)
+		}
+		if *top.RPE != 9 || *top.Notes != "Paused" || top.RestSeconds == nil || *top.RestSeconds != 180 {
+			t.Errorf("expected the rest timer on the top set, got %+v", top)
+		}
+
+		run := workouts[1].Sets[0].Set
+		if *run.DistanceMeters != 5200 || *run.DurationSeconds != 1800 || run.Reps != 0 {
+			t.Errorf("unexpected cardio set %+v", run)
+		}
+	})
+
+	t.Run("hevy", func(t *testing.T) {
+		format, workouts, _, err := parseImportFile(strings.NewReader(hevyExport), "", time.UTC)
+		if err != nil || format != models.ImportFormatHevy {
+			t.Fatalf("expected a Hevy file, got %q (%v)", format, err)
+		}
+		if len(workouts) != 1 || len(workouts[0].Sets) != 3 {
+			t.Fatalf("expected 1 workout of 3 sets, got %+v", workouts)
+		}
+
+		sets := workouts[0].Sets
+		if sets[0].Set.Weight != 120 || *sets[0].Set.GroupID != 1 || *sets[0].Set.Notes != "Slow eccentrics" {
+			t.Errorf("unexpected first set %+v", sets[0].Set)
+		}
+		if sets[1].Set.Type != models.SetTypeDrop || sets[1].Set.Notes != nil {
+			t.Errorf("expected a drop set with the exercise notes on the first set only, got %+v", sets[1].Set)
+		}
+		if *sets[2].Set.DurationSeconds != 60 || sets[2].Set.GroupID != nil {
+			t.Errorf("unexpected plank set %+v", sets[2].Set)
+		}
+	})
+
+	t.Run("fitnotes", func(t *testing.T) {
+		format, workouts, skipped, err := parseImportFile(strings.NewReader(fitNotesExport), "", time.UTC)
+		if err != nil || format != models.ImportFormatFitNotes || len(skipped) != 0 {
+			t.Fatalf("expected a FitNotes file, got %q %v (%v)", format, skipped, err)
+		}
+		if len(workouts) != 2 || workouts[0].Workout.Name != "Legs, Abs" || workouts[0].Workout.PerformedAt.Hour() != 12 {
+			t.Fatalf("expected a day of legs and abs at noon, got %+v", workouts[0].Workout)
+		}
+		if workouts[0].Sets[0].Set.Weight != 140.5 {
+			t.Errorf("expected a decimal comma weight, got %v", workouts[0].Sets[0].Set.Weight)
+		}
+		ride := workouts[1].Sets[0].Set
+		if *ride.DistanceMeters != 12000 || *ride.DurationSeconds != 2100 || *ride.Notes != "Easy" {
+			t.Errorf("unexpected ride %+v", ride)
+		}
+	})
+
+	t.Run("unrecognized", func(t *testing.T) {
+		if _, _, _, err := parseImportFile(strings.NewReader("a,b,c\n1,2,3\n"), "", time.UTC); err == nil {
+			t.Error("expected an error for an unknown header")
+		}
+		if _, _, _, err := parseImportFile(strings.NewReader(strongExport), models.ImportFormatHevy, time.UTC); err == nil {
+			t.Error("expected an error when the header does not match the format")
+		}
+	})
+}
+
+func TestParseImportDuration(t *testing.T) {
+	tests := map[string]int{"": 0, "90": 90, "1h 5m": 3900, "45m": 2700, "1:02:03": 3723, "05:30": 330}
+	for input, want := range tests {
+		if got, ok := parseImportDuration(input); !ok || got != want {
+			t.Errorf("parseImportDuration(%q) = %d, %v; want %d", input, got, ok, want)
+		}
+	}
+	if _, ok := parseImportDuration("soon"); ok {
+		t.Error("expected an invalid duration")
+	}
+}
+
+func TestImport(t *testing.T) {
+	bench := "Barbell Bench Press"
+	repo := &mockImportRepository{
+		exercises: []*models.Exercise{
+			{ID: 1, Name: bench, CatalogName: &bench, Type: models.ExerciseTypeWeight},
+			// Dips are logged as a timed exercise, so the Strong sets with reps do not fit
+			{ID: 2, UserID: 1, Name: "dips", Type: models.ExerciseTypeTimed},
+		},
+		workouts: []*models.Workout{{Name: "run", PerformedAt: time.Date(2024, 5, 15, 7, 30, 0, 0, time.UTC)}},
+	}
+	// The history of an exercise is the sets of the import
+	setRepo := &mockSetRepository{
+		getByExerciseIDAndUserIDFunc: func(exerciseID, userID int64) ([]*models.Set, error) {
+			var sets []*models.Set
+			for _, workout := range repo.created.Workouts {
+				for _, imported := range workout.Sets {
+					if imported.Exercise.ID == exerciseID {
+						sets = append(sets, imported.Set)
+					}
+				}
+			}
+			return sets, nil
+		},
+	}
+	records := make(map[int64][]*models.PersonalRecord)
+	recordRepo := &mockPersonalRecordRepository{
+		replaceForExerciseFunc: func(userID, exerciseID int64, replaced []*models.PersonalRecord) error {
+			records[exerciseID] = replaced
+			return nil
+		},
+	}
+	service := NewImportService(repo, setRepo, recordRepo, &mockUserRepository{})
+
+	result, err := service.Import(1, strings.NewReader(strongExport), models.ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.created != nil || len(records) != 0 {
+		t.Fatal("expected a dry run to store nothing")
+	}
+	if result.Workouts != 1 || result.Sets != 2 || result.DuplicateWorkouts != 1 || len(result.Skipped) != 2 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if len(result.Exercises) != 1 || result.Exercises[0].ExerciseID != 1 || result.Exercises[0].Created {
+		t.Errorf("expected the bench press matched to the catalog, got %+v", result.Exercises)
+	}
+
+	repo.workouts = nil
+	result, err = service.Import(1, strings.NewReader(strongExport), models.ImportOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.created == nil || len(repo.created.Workouts) != 2 || len(repo.created.Exercises) != 1 {
+		t.Fatalf("expected 2 workouts and 1 new exercise stored, got %+v", repo.created)
+	}
+	running := repo.created.Exercises[0]
+	if running.Name != "Running" || running.Type != models.ExerciseTypeCardio {
+		t.Errorf("expected a new cardio exercise, got %+v", running)
+	}
+	if last := result.Exercises[len(result.Exercises)-1]; !last.Created || last.ExerciseID != 100 {
+		t.Errorf("expected the created exercise reported with its ID, got %+v", last)
+	}
+	if status := repo.created.Workouts[0].Workout.Status; status != models.WorkoutStatusCompleted {
+		t.Errorf("expected imported workouts to be completed, got %s", status)
+	}
+
+	// Records are replayed for the bench press, without its warm-up, but not for the run
+	benchRecords := recordTypes(records[1])
+	if len(records) != 1 || len(benchRecords) != 3 {
+		t.Fatalf("expected 3 bench press records, got %+v", records)
+	}
+	if record := benchRecords[models.PersonalRecordMaxWeight]; record.Value != 100 || record.PreviousValue != nil {
+		t.Errorf("unexpected max_weight record %+v", record)
+	}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+	"phoenix-alliance-be/internal/repository"
+)
+
+// PersonalRecordService defines the interface for personal record business logic
+type PersonalRecordService interface {
+	GetRecords(userID int64, currentOnly bool) ([]*models.PersonalRecordResponse, error)
+	GetExerciseRecords(userID, exerciseID int64, currentOnly bool) ([]*models.PersonalRecordResponse, error)
+}
+
+type personalRecordService struct {
+	recordRepo   repository.PersonalRecordRepository
+	exerciseRepo repository.ExerciseRepository
+}
+
+// NewPersonalRecordService creates a new personal record service
+func NewPersonalRecordService(
+	recordRepo repository.PersonalRecordRepository,
+	exerciseRepo repository.ExerciseRepository,
+) PersonalRecordService {
+	return &personalRecordService{
+		recordRepo:   recordRepo,
+		exerciseRepo: exerciseRepo,
+	}
+}
+
+// GetRecords retrieves the personal records of a user across all exercises
+func (s *personalRecordService) GetRecords(userID int64, currentOnly bool) ([]*models.PersonalRecordResponse, error) {
+	records, err := s.recordRepo.GetByUserID(userID, currentOnly)
+	if err != nil {
+		return nil, errors.New("failed to retrieve personal records")
+	}
+
+	return toPersonalRecordResponses(records), nil
+}
+
+// GetExerciseRecords retrieves the personal records of a user for an exercise
+func (s *personalRecordService) GetExerciseRecords(userID, exerciseID int64, currentOnly bool) ([]*models.PersonalRecordResponse, error) {
+	// Verify exercise belongs to user
+	if _, err := s.exerciseRepo.GetByIDAndUserID(exerciseID, userID); err != nil {
+		return nil, errors.New("exercise not found")
+	}
+
+	records, err := s.recordRepo.GetByExerciseIDAndUserID(exerciseID, userID, currentOnly)
+	if err != nil {
+		return nil, errors.New("failed to retrieve personal records")
+	}
+
+	return toPersonalRecordResponses(records), nil
+}
+
+func toPersonalRecordResponses(records []*models.PersonalRecord) []*models.PersonalRecordResponse {
+	responses := make([]*models.PersonalRecordResponse, len(records))
+	for i, record := range records {
+		responses[i] = record.ToResponse()
+	}
+	return responses
+}
+
+// replayPersonalRecords returns the records the sets of an exercise broke, replaying them
+// in the order they were performed. Warm-ups are left out. A workout keeps a single volume
+// record, held by the last of its sets that raised it.
+func replayPersonalRecords(userID int64, sets []*models.Set) []*models.PersonalRecord {
+	history := withoutWarmups(sets)
+	sort.SliceStable(history, func(i, j int) bool {
+		return performedBefore(history[i], history[j])
+	})
+
+	var records []*models.PersonalRecord
+	volumeRecords := make(map[int64]int)
+	for i, set := range history {
+		for _, record := range detectPersonalRecords(userID, set, history[:i]) {
+			if record.RecordType == models.PersonalRecordMaxVolume {
+				if j, ok := volumeRecords[set.WorkoutID]; ok {
+					records[j] = record
+					continue
+				}
+				volumeRecords[set.WorkoutID] = len(records)
+			}
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// performedBefore reports whether a set was performed before another, by ID when both were
+// performed at the same time
+func performedBefore(a, b *models.Set) bool {
+	if !a.PerformedAt.Equal(b.PerformedAt) {
+		return a.PerformedAt.Before(b.PerformedAt)
+	}
+	return a.ID < b.ID
+}
+
+// detectPersonalRecords compares a newly stored set against the sets of the same exercise
+// performed before it and returns the records it breaks. The new set must not be part of
+// history. A max_volume record carries the whole session volume, and replaces the one of
+// the workout any earlier set of it broke.
+func detectPersonalRecords(userID int64, set *models.Set, history []*models.Set) []*models.PersonalRecord {
+	var (
+		maxWeight    float64
+		maxE1RM      float64
+		maxRepsAtW   int
+		hasRepsAtW   bool
+		sessionVol   = set.Weight * float64(set.Reps)
+		otherVolumes = make(map[int64]float64)
+	)
+
+	for _, prev := range history {
+		if prev.Weight > maxWeight {
+			maxWeight = prev.Weight
+		}
+		if e1rm := estimateOneRepMax(prev, models.E1RMFormulaEpley); e1rm > maxE1RM {
+			maxE1RM = e1rm
+		}
+		// Reps done at the same or a heavier weight are what the new set has to beat
+		if prev.Weight >= set.Weight && (!hasRepsAtW || prev.Reps > maxRepsAtW) {
+			maxRepsAtW = prev.Reps
+			hasRepsAtW = true
+		}
+		if prev.WorkoutID == set.WorkoutID {
+			sessionVol += prev.Weight * float64(prev.Reps)
+		} else {
+			otherVolumes[prev.WorkoutID] += prev.Weight * float64(prev.Reps)
+		}
+	}
+
+	var maxVolume float64
+	for _, v := range otherVolumes {
+		if v > maxVolume {
+			maxVolume = v
+		}
+	}
+
+	first := len(history) == 0
+	now := time.Now()
+	newRecord := func(recordType models.PersonalRecordType, value, previous float64) *models.PersonalRecord {
+		record := &models.PersonalRecord{
+			UserID:     userID,
+			ExerciseID: set.ExerciseID,
+			WorkoutID:  set.WorkoutID,
+			SetID:      set.ID,
+			RecordType: recordType,
+			Value:      value,
+			Weight:     set.Weight,
+			Reps:       set.Reps,
+			AchievedAt: set.PerformedAt,
+			CreatedAt:  now,
+		}
+		if !first {
+			record.PreviousValue = &previous
+		}
+		return record
+	}
+
+	var records []*models.PersonalRecord
+	if set.Weight > maxWeight || first {
+		records = append(records, newRecord(models.PersonalRecordMaxWeight, set.Weight, maxWeight))
+	}
+	// Reps are only a record against an earlier set at the same or a heavier weight
+	if hasRepsAtW && set.Reps > maxRepsAtW {
+		records = append(records, newRecord(models.PersonalRecordMaxReps, float64(set.Reps), float64(maxRepsAtW)))
+	}
+	if e1rm := estimateOneRepMax(set, models.E1RMFormulaEpley); e1rm > maxE1RM {
+		records = append(records, newRecord(models.PersonalRecordMaxE1RM, e1rm, maxE1RM))
+	}
+	if sessionVol > maxVolume {
+		records = append(records, newRecord(models.PersonalRecordMaxVolume, sessionVol, maxVolume))
+	}
+
+	return records
+}
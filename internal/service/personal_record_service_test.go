@@ -0,0 +1,173 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+func recordTypes(records []*models.PersonalRecord) map[models.PersonalRecordType]*models.PersonalRecord {
+	types := make(map[models.PersonalRecordType]*models.PersonalRecord)
+	for _, record := range records {
+		types[record.RecordType] = record
+	}
+	return types
+}
+
+func TestDetectPersonalRecords(t *testing.T) {
+	userID := int64(1)
+	history := []*models.Set{
+		{ID: 1, WorkoutID: 10, ExerciseID: 5, Weight: 100, Reps: 5},
+		{ID: 2, WorkoutID: 10, ExerciseID: 5, Weight: 80, Reps: 10},
+		{ID: 3, WorkoutID: 11, ExerciseID: 5, Weight: 90, Reps: 3},
+	}
+
+	t.Run("first set sets every record but reps", func(t *testing.T) {
+		set := &models.Set{ID: 9, WorkoutID: 12, ExerciseID: 5, Weight: 60, Reps: 8}
+		records := recordTypes(detectPersonalRecords(userID, set, nil))
+
+		if len(records) != 3 {
+			t.Fatalf("expected 3 records, got %d", len(records))
+		}
+		if _, ok := records[models.PersonalRecordMaxReps]; ok {
+			t.Error("did not expect max_reps record without an earlier set to beat")
+		}
+		for _, record := range records {
+			if record.PreviousValue != nil {
+				t.Errorf("expected no previous value for %s", record.RecordType)
+			}
+			if record.SetID != set.ID || record.UserID != userID {
+				t.Errorf("expected record to reference set %d and user %d", set.ID, userID)
+			}
+		}
+	})
+
+	t.Run("heavier weight", func(t *testing.T) {
+		set := &models.Set{ID: 9, WorkoutID: 12, ExerciseID: 5, Weight: 105, Reps: 1}
+		records := recordTypes(detectPersonalRecords(userID, set, history))
+
+		record, ok := records[models.PersonalRecordMaxWeight]
+		if !ok {
+			t.Fatal("expected max_weight record")
+		}
+		if record.Value != 105 || record.PreviousValue == nil || *record.PreviousValue != 100 {
+			t.Errorf("unexpected max_weight record %+v", record)
+		}
+		if _, ok := records[models.PersonalRecordMaxE1RM]; ok {
+			t.Error("did not expect max_e1rm record for a heavy single")
+		}
+		if _, ok := records[models.PersonalRecordMaxVolume]; ok {
+			t.Error("did not expect max_volume record")
+		}
+	})
+
+	t.Run("more reps at a weight", func(t *testing.T) {
+		set := &models.Set{ID: 9, WorkoutID: 12, ExerciseID: 5, Weight: 80, Reps: 11}
+		records := recordTypes(detectPersonalRecords(userID, set, history))
+
+		record, ok := records[models.PersonalRecordMaxReps]
+		if !ok {
+			t.Fatal("expected max_reps record")
+		}
+		if record.Value != 11 || *record.PreviousValue != 10 {
+			t.Errorf("unexpected max_reps record %+v", record)
+		}
+		if _, ok := records[models.PersonalRecordMaxWeight]; ok {
+			t.Error("did not expect max_weight record")
+		}
+	})
+
+	t.Run("reps at a new top weight are not a record", func(t *testing.T) {
+		set := &models.Set{ID: 9, WorkoutID: 12, ExerciseID: 5, Weight: 105, Reps: 20}
+		records := recordTypes(detectPersonalRecords(userID, set, history))
+
+		if _, ok := records[models.PersonalRecordMaxReps]; ok {
+			t.Error("did not expect max_reps record without an earlier set to beat")
+		}
+	})
+
+	t.Run("reps are compared at the weight of the set", func(t *testing.T) {
+		// 100x6 beats 100x5; the 10 reps done at 80 do not count against it
+		set := &models.Set{ID: 9, WorkoutID: 12, ExerciseID: 5, Weight: 100, Reps: 6}
+		records := recordTypes(detectPersonalRecords(userID, set, history))
+
+		record, ok := records[models.PersonalRecordMaxReps]
+		if !ok {
+			t.Fatal("expected max_reps record")
+		}
+		if record.Weight != 100 || record.Value != 6 || *record.PreviousValue != 5 {
+			t.Errorf("unexpected max_reps record %+v", record)
+		}
+	})
+
+	t.Run("reps beaten by a heavier set are not a record", func(t *testing.T) {
+		set := &models.Set{ID: 9, WorkoutID: 12, ExerciseID: 5, Weight: 95, Reps: 4}
+		records := recordTypes(detectPersonalRecords(userID, set, history))
+
+		if _, ok := records[models.PersonalRecordMaxReps]; ok {
+			t.Error("did not expect max_reps record")
+		}
+	})
+
+	t.Run("session volume includes earlier sets of the same workout", func(t *testing.T) {
+		// 90x3 + 90x12 = 1350 beats 100x5 + 80x10 = 1300
+		set := &models.Set{ID: 9, WorkoutID: 11, ExerciseID: 5, Weight: 90, Reps: 12}
+		records := recordTypes(detectPersonalRecords(userID, set, history))
+
+		record, ok := records[models.PersonalRecordMaxVolume]
+		if !ok {
+			t.Fatal("expected max_volume record")
+		}
+		if record.Value != 1350 || *record.PreviousValue != 1300 {
+			t.Errorf("unexpected max_volume record %+v", record)
+		}
+	})
+}
+
+func TestReplayPersonalRecords(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 18, 0, 0, 0, time.UTC) }
+	// Out of order, as the sets of an import land before the ones logged in the app
+	sets := []*models.Set{
+		{ID: 3, WorkoutID: 12, ExerciseID: 5, Weight: 100, Reps: 6, PerformedAt: day(20)},
+		{ID: 1, WorkoutID: 10, ExerciseID: 5, Weight: 100, Reps: 5, PerformedAt: day(6)},
+		{ID: 2, WorkoutID: 11, ExerciseID: 5, Weight: 60, Reps: 10, PerformedAt: day(13), Type: models.SetTypeWarmup},
+	}
+
+	records := replayPersonalRecords(1, sets)
+	if len(records) != 6 {
+		t.Fatalf("expected 3 records for the first set and 3 for the last, got %d", len(records))
+	}
+	for _, record := range records[:3] {
+		if record.SetID != 1 || record.PreviousValue != nil {
+			t.Errorf("expected the earliest set to set the first records, got %+v", record)
+		}
+	}
+	last := recordTypes(records[3:])
+	if record, ok := last[models.PersonalRecordMaxReps]; !ok || record.SetID != 3 || *record.PreviousValue != 5 {
+		t.Errorf("expected the latest set to beat the reps of the first, got %+v", record)
+	}
+}
+
+func TestReplayPersonalRecordsVolumePerWorkout(t *testing.T) {
+	at := func(minute int) time.Time { return time.Date(2024, 5, 6, 18, minute, 0, 0, time.UTC) }
+	sets := []*models.Set{
+		{ID: 1, WorkoutID: 10, ExerciseID: 5, Weight: 100, Reps: 5, PerformedAt: at(0)},
+		{ID: 2, WorkoutID: 10, ExerciseID: 5, Weight: 100, Reps: 5, PerformedAt: at(5)},
+		{ID: 3, WorkoutID: 10, ExerciseID: 5, Weight: 100, Reps: 5, PerformedAt: at(10)},
+	}
+
+	// Every set raises the session volume, but the workout keeps a single volume record
+	var volume []*models.PersonalRecord
+	for _, record := range replayPersonalRecords(1, sets) {
+		if record.RecordType == models.PersonalRecordMaxVolume {
+			volume = append(volume, record)
+		}
+	}
+	if len(volume) != 1 {
+		t.Fatalf("expected one max_volume record, got %d", len(volume))
+	}
+	if volume[0].SetID != 3 || volume[0].Value != 1500 {
+		t.Errorf("expected the last set to hold the session volume, got %+v", volume[0])
+	}
+}
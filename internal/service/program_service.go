@@ -0,0 +1,299 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+	"phoenix-alliance-be/internal/repository"
+)
+
+// defaultProgressionIncrement is the load added by progressions when none is given (kg)
+const defaultProgressionIncrement = 2.5
+
+// ProgramService defines the interface for training program business logic
+type ProgramService interface {
+	CreateProgram(userID int64, req *models.ProgramCreateRequest) (*models.ProgramResponse, error)
+	GetPrograms(userID int64) ([]*models.ProgramResponse, error)
+	GetProgramByID(userID, programID int64) (*models.ProgramResponse, error)
+	UpdateProgram(userID, programID int64, req *models.ProgramUpdateRequest) (*models.ProgramResponse, error)
+	DeleteProgram(userID, programID int64) error
+	Enroll(userID, programID int64) (*models.ProgramEnrollment, error)
+	GetNextSession(userID, programID int64) (*models.SessionPrescription, error)
+	StartNextSession(userID, programID int64) (*models.ProgramSessionStartResponse, error)
+}
+
+type programService struct {
+	programRepo    repository.ProgramRepository
+	plannedSetRepo repository.PlannedSetRepository
+	exerciseRepo   repository.ExerciseRepository
+	setRepo        repository.SetRepository
+	workoutService WorkoutService
+}
+
+// NewProgramService creates a new program service
+func NewProgramService(
+	programRepo repository.ProgramRepository,
+	plannedSetRepo repository.PlannedSetRepository,
+	exerciseRepo repository.ExerciseRepository,
+	setRepo repository.SetRepository,
+	workoutService WorkoutService,
+) ProgramService {
+	return &programService{
+		programRepo:    programRepo,
+		plannedSetRepo: plannedSetRepo,
+		exerciseRepo:   exerciseRepo,
+		setRepo:        setRepo,
+		workoutService: workoutService,
+	}
+}
+
+// CreateProgram creates a new program for a user
+func (s *programService) CreateProgram(userID int64, req *models.ProgramCreateRequest) (*models.ProgramResponse, error) {
+	days, err := s.buildProgramDays(userID, req.Days)
+	if err != nil {
+		return nil, err
+	}
+
+	program := &models.Program{
+		UserID:      userID,
+		Name:        req.Name,
+		Description: req.Description,
+		Weeks:       req.Weeks,
+		DaysPerWeek: len(days),
+		Days:        days,
+		CreatedAt:   time.Now(),
+	}
+
+	if err := s.programRepo.Create(program); err != nil {
+		return nil, errors.New("failed to create program")
+	}
+
+	return program.ToResponse(), nil
+}
+
+// GetPrograms retrieves all programs for a user
+func (s *programService) GetPrograms(userID int64) ([]*models.ProgramResponse, error) {
+	programs, err := s.programRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve programs")
+	}
+
+	responses := make([]*models.ProgramResponse, len(programs))
+	for i, program := range programs {
+		responses[i] = program.ToResponse()
+	}
+
+	return responses, nil
+}
+
+// GetProgramByID retrieves a program by ID for a user
+func (s *programService) GetProgramByID(userID, programID int64) (*models.ProgramResponse, error) {
+	program, err := s.programRepo.GetByIDAndUserID(programID, userID)
+	if err != nil {
+		return nil, errors.New("program not found")
+	}
+
+	return program.ToResponse(), nil
+}
+
+// UpdateProgram updates an existing program for a user
+func (s *programService) UpdateProgram(userID, programID int64, req *models.ProgramUpdateRequest) (*models.ProgramResponse, error) {
+	// Verify program exists and belongs to the user
+	program, err := s.programRepo.GetByIDAndUserID(programID, userID)
+	if err != nil {
+		return nil, errors.New("program not found")
+	}
+
+	days, err := s.buildProgramDays(userID, req.Days)
+	if err != nil {
+		return nil, err
+	}
+
+	program.Name = req.Name
+	program.Description = req.Description
+	program.Weeks = req.Weeks
+	program.DaysPerWeek = len(days)
+	program.Days = days
+
+	if err := s.programRepo.Update(program); err != nil {
+		return nil, errors.New("failed to update program")
+	}
+
+	return program.ToResponse(), nil
+}
+
+// DeleteProgram performs a soft delete on a program for a user
+func (s *programService) DeleteProgram(userID, programID int64) error {
+	// Verify program exists and belongs to user
+	if _, err := s.programRepo.GetByIDAndUserID(programID, userID); err != nil {
+		return errors.New("program not found")
+	}
+	// Soft delete
+	if err := s.programRepo.Delete(programID, userID); err != nil {
+		if err.Error() == "program not found" {
+			return errors.New("program not found")
+		}
+		return errors.New("failed to delete program")
+	}
+
+	return nil
+}
+
+// Enroll starts the program for a user from week 1, day 1, replacing any active enrollment
+func (s *programService) Enroll(userID, programID int64) (*models.ProgramEnrollment, error) {
+	if _, err := s.programRepo.GetByIDAndUserID(programID, userID); err != nil {
+		return nil, errors.New("program not found")
+	}
+
+	enrollment := &models.ProgramEnrollment{
+		ProgramID: programID,
+		UserID:    userID,
+		StartedAt: time.Now(),
+	}
+
+	if err := s.programRepo.Enroll(enrollment); err != nil {
+		return nil, errors.New("failed to enroll in program")
+	}
+
+	return enrollment, nil
+}
+
+// GetNextSession computes the prescription of the next session of the user's active enrollment
+func (s *programService) GetNextSession(userID, programID int64) (*models.SessionPrescription, error) {
+	_, session, err := s.nextSession(userID, programID)
+	return session, err
+}
+
+// StartNextSession creates a workout with planned sets for the next session and advances the enrollment
+func (s *programService) StartNextSession(userID, programID int64) (*models.ProgramSessionStartResponse, error) {
+	enrollment, session, err := s.nextSession(userID, programID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("%s - W%dD%d", session.Name, session.Week, session.Day)
+	workout, err := s.workoutService.CreateWorkout(userID, &models.WorkoutCreateRequest{Name: name})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	plannedSets := []*models.PlannedSet{}
+	for _, exercise := range session.Exercises {
+		for _, set := range exercise.Sets {
+			plannedSets = append(plannedSets, &models.PlannedSet{
+				WorkoutID:    workout.ID,
+				ExerciseID:   exercise.ExerciseID,
+				Position:     len(plannedSets),
+				TargetReps:   set.TargetReps,
+				TargetWeight: set.TargetWeight,
+				CreatedAt:    now,
+			})
+		}
+	}
+
+	if err := s.plannedSetRepo.CreateBatch(plannedSets); err != nil {
+		// Do not leave an empty workout behind
+		_ = s.workoutService.DeleteWorkout(userID, workout.ID)
+		return nil, errors.New("failed to create planned sets")
+	}
+
+	if err := s.programRepo.AdvanceEnrollment(enrollment.ID, enrollment.CompletedSessions+1); err != nil {
+		_ = s.workoutService.DeleteWorkout(userID, workout.ID)
+		return nil, errors.New("failed to advance program")
+	}
+
+	return &models.ProgramSessionStartResponse{
+		Workout:     workout,
+		Session:     session,
+		PlannedSets: plannedSets,
+	}, nil
+}
+
+// nextSession resolves the active enrollment and computes its next session
+func (s *programService) nextSession(userID, programID int64) (*models.ProgramEnrollment, *models.SessionPrescription, error) {
+	program, err := s.programRepo.GetByIDAndUserID(programID, userID)
+	if err != nil {
+		return nil, nil, errors.New("program not found")
+	}
+
+	enrollment, err := s.programRepo.GetActiveEnrollment(programID, userID)
+	if err != nil {
+		return nil, nil, errors.New("not enrolled in program")
+	}
+
+	if len(program.Days) == 0 {
+		return nil, nil, errors.New("program has no sessions")
+	}
+
+	week := enrollment.CompletedSessions/len(program.Days) + 1
+	if week > program.Weeks {
+		return nil, nil, errors.New("program completed")
+	}
+	day := program.Days[enrollment.CompletedSessions%len(program.Days)]
+
+	session := &models.SessionPrescription{
+		ProgramID:    program.ID,
+		EnrollmentID: enrollment.ID,
+		Week:         week,
+		Day:          day.DayNumber,
+		Name:         day.Name,
+		Exercises:    make([]*models.ExercisePrescription, len(day.Exercises)),
+	}
+
+	for i, exercise := range day.Exercises {
+		history, err := s.setRepo.GetByExerciseIDAndUserID(exercise.ExerciseID, userID)
+		if err != nil {
+			return nil, nil, errors.New("failed to retrieve sets")
+		}
+		// Progression is driven by working sets only
+		session.Exercises[i] = prescribeExercise(exercise, week, withoutWarmups(history))
+	}
+
+	return enrollment, session, nil
+}
+
+// buildProgramDays verifies exercise ownership and converts requests to program days
+func (s *programService) buildProgramDays(userID int64, reqs []models.ProgramDayRequest) ([]*models.ProgramDay, error) {
+	verified := make(map[int64]bool)
+	days := make([]*models.ProgramDay, len(reqs))
+	for i, dayReq := range reqs {
+		day := &models.ProgramDay{
+			DayNumber: i + 1,
+			Name:      dayReq.Name,
+			Exercises: make([]*models.ProgramExercise, len(dayReq.Exercises)),
+		}
+
+		for j, req := range dayReq.Exercises {
+			if !verified[req.ExerciseID] {
+				if _, err := s.exerciseRepo.GetByIDAndUserID(req.ExerciseID, userID); err != nil {
+					return nil, errors.New("exercise not found")
+				}
+				verified[req.ExerciseID] = true
+			}
+
+			increment := defaultProgressionIncrement
+			if req.Increment != nil {
+				increment = *req.Increment
+			}
+
+			day.Exercises[j] = &models.ProgramExercise{
+				ExerciseID:         req.ExerciseID,
+				Position:           j,
+				Sets:               req.Sets,
+				Reps:               req.Reps,
+				RepMax:             req.RepMax,
+				Progression:        req.Progression,
+				Increment:          increment,
+				StartWeight:        req.StartWeight,
+				TrainingMaxPercent: req.TrainingMaxPercent,
+			}
+		}
+
+		days[i] = day
+	}
+
+	return days, nil
+}
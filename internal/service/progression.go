@@ -0,0 +1,178 @@
+package service
+
+import (
+	"phoenix-alliance-be/internal/models"
+)
+
+// defaultTrainingMaxPercent is the share of e1RM used as training max for waves
+const defaultTrainingMaxPercent = 90.0
+
+// waveWeek describes one week of a 5/3/1-style wave as percentages of the training max
+type waveWeek struct {
+	percents [3]float64
+	reps     [3]int
+	amrap    bool // Last set is as many reps as possible
+}
+
+// waveCycle is the 4-week 5/3/1 cycle; the fourth week is a deload
+var waveCycle = []waveWeek{
+	{percents: [3]float64{65, 75, 85}, reps: [3]int{5, 5, 5}, amrap: true},
+	{percents: [3]float64{70, 80, 90}, reps: [3]int{3, 3, 3}, amrap: true},
+	{percents: [3]float64{75, 85, 95}, reps: [3]int{5, 3, 1}, amrap: true},
+	{percents: [3]float64{40, 50, 60}, reps: [3]int{5, 5, 5}},
+}
+
+// prescribeExercise computes the targets of a program exercise for the given
+// 1-based program week from the user's logged sets of that exercise.
+func prescribeExercise(exercise *models.ProgramExercise, week int, history []*models.Set) *models.ExercisePrescription {
+	prescription := &models.ExercisePrescription{
+		ExerciseID:  exercise.ExerciseID,
+		Progression: exercise.Progression,
+	}
+
+	switch exercise.Progression {
+	case models.ProgressionWave:
+		prescription.Sets = prescribeWave(exercise, week, history)
+	case models.ProgressionDouble:
+		prescription.Sets = prescribeDouble(exercise, lastSession(history))
+	default:
+		prescription.Sets = prescribeLinear(exercise, lastSession(history))
+	}
+
+	return prescription
+}
+
+// prescribeLinear adds the increment when every target set was completed at the
+// working weight last session, otherwise repeats the working weight.
+func prescribeLinear(exercise *models.ProgramExercise, last []*models.Set) []models.PrescribedSet {
+	weight := exercise.StartWeight
+	if working, sets := workingSets(last); sets != nil {
+		next := working
+		if completedSets(sets, exercise.Reps) >= exercise.Sets {
+			next = roundToIncrement(working+exercise.Increment, plateIncrement)
+		}
+		weight = &next
+	}
+
+	return repeatSets(exercise.Sets, exercise.Reps, nil, weight)
+}
+
+// prescribeDouble works up the rep range at a fixed weight; once every target set
+// reaches the top of the range the load goes up and reps restart at the bottom.
+func prescribeDouble(exercise *models.ProgramExercise, last []*models.Set) []models.PrescribedSet {
+	repMax := exercise.Reps
+	if exercise.RepMax != nil && *exercise.RepMax > repMax {
+		repMax = *exercise.RepMax
+	}
+
+	weight := exercise.StartWeight
+	if working, sets := workingSets(last); sets != nil {
+		next := working
+		if completedSets(sets, repMax) >= exercise.Sets {
+			next = roundToIncrement(working+exercise.Increment, plateIncrement)
+		}
+		weight = &next
+	}
+
+	return repeatSets(exercise.Sets, exercise.Reps, &repMax, weight)
+}
+
+// prescribeWave applies the week's percentages to a training max derived from the
+// best e1RM, or from the start weight when there is no history, in which case it
+// grows by the increment every completed cycle.
+func prescribeWave(exercise *models.ProgramExercise, week int, history []*models.Set) []models.PrescribedSet {
+	if week < 1 {
+		week = 1
+	}
+	cycle := waveCycle[(week-1)%len(waveCycle)]
+
+	var trainingMax float64
+	if e1rm := bestOneRepMax(history, models.E1RMFormulaEpley); e1rm > 0 {
+		percent := defaultTrainingMaxPercent
+		if exercise.TrainingMaxPercent != nil {
+			percent = *exercise.TrainingMaxPercent
+		}
+		trainingMax = e1rm * percent / 100
+	} else if exercise.StartWeight != nil {
+		trainingMax = *exercise.StartWeight + float64((week-1)/len(waveCycle))*exercise.Increment
+	}
+
+	sets := make([]models.PrescribedSet, len(cycle.percents))
+	for i, percent := range cycle.percents {
+		sets[i] = models.PrescribedSet{
+			SetNumber:  i + 1,
+			TargetReps: cycle.reps[i],
+			AMRAP:      cycle.amrap && i == len(cycle.percents)-1,
+		}
+		if trainingMax > 0 {
+			weight := roundToIncrement(trainingMax*percent/100, plateIncrement)
+			sets[i].TargetWeight = &weight
+		}
+	}
+
+	return sets
+}
+
+// repeatSets builds identical set targets
+func repeatSets(count, reps int, repsMax *int, weight *float64) []models.PrescribedSet {
+	sets := make([]models.PrescribedSet, count)
+	for i := range sets {
+		sets[i] = models.PrescribedSet{
+			SetNumber:     i + 1,
+			TargetReps:    reps,
+			TargetRepsMax: repsMax,
+			TargetWeight:  weight,
+		}
+	}
+	return sets
+}
+
+// lastSession returns the sets of the most recent workout in history
+func lastSession(history []*models.Set) []*models.Set {
+	var latest *models.Set
+	for _, set := range history {
+		if latest == nil || set.PerformedAt.After(latest.PerformedAt) {
+			latest = set
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+
+	var sets []*models.Set
+	for _, set := range history {
+		if set.WorkoutID == latest.WorkoutID {
+			sets = append(sets, set)
+		}
+	}
+	return sets
+}
+
+// workingSets returns the heaviest weight of a session and the sets done at it
+func workingSets(session []*models.Set) (float64, []*models.Set) {
+	var working float64
+	for _, set := range session {
+		if set.Weight > working {
+			working = set.Weight
+		}
+	}
+
+	var sets []*models.Set
+	for _, set := range session {
+		if set.Weight == working {
+			sets = append(sets, set)
+		}
+	}
+	return working, sets
+}
+
+// completedSets counts the sets that reached the target reps
+func completedSets(sets []*models.Set, reps int) int {
+	count := 0
+	for _, set := range sets {
+		if set.Reps >= reps {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+func TestPrescribeLinear(t *testing.T) {
+	now := time.Now()
+	exercise := &models.ProgramExercise{ExerciseID: 1, Sets: 3, Reps: 5, Progression: models.ProgressionLinear, Increment: 2.5, StartWeight: floatPtr(60)}
+
+	t.Run("no history uses start weight", func(t *testing.T) {
+		p := prescribeExercise(exercise, 1, nil)
+		if len(p.Sets) != 3 || *p.Sets[0].TargetWeight != 60 || p.Sets[0].TargetReps != 5 {
+			t.Errorf("unexpected prescription %+v", p.Sets)
+		}
+	})
+
+	t.Run("completed session adds increment", func(t *testing.T) {
+		history := []*models.Set{
+			{WorkoutID: 1, Weight: 95, Reps: 5, PerformedAt: now.Add(-72 * time.Hour)},
+			{WorkoutID: 2, Weight: 100, Reps: 5, PerformedAt: now},
+			{WorkoutID: 2, Weight: 100, Reps: 5, PerformedAt: now},
+			{WorkoutID: 2, Weight: 100, Reps: 6, PerformedAt: now},
+		}
+		p := prescribeExercise(exercise, 2, history)
+		if *p.Sets[0].TargetWeight != 102.5 {
+			t.Errorf("expected 102.5, got %.2f", *p.Sets[0].TargetWeight)
+		}
+	})
+
+	t.Run("missed reps repeats weight", func(t *testing.T) {
+		history := []*models.Set{
+			{WorkoutID: 2, Weight: 100, Reps: 5, PerformedAt: now},
+			{WorkoutID: 2, Weight: 100, Reps: 4, PerformedAt: now},
+			{WorkoutID: 2, Weight: 100, Reps: 3, PerformedAt: now},
+		}
+		p := prescribeExercise(exercise, 2, history)
+		if *p.Sets[0].TargetWeight != 100 {
+			t.Errorf("expected 100, got %.2f", *p.Sets[0].TargetWeight)
+		}
+	})
+}
+
+func TestPrescribeDouble(t *testing.T) {
+	now := time.Now()
+	exercise := &models.ProgramExercise{ExerciseID: 1, Sets: 2, Reps: 8, RepMax: intPtr(12), Progression: models.ProgressionDouble, Increment: 5}
+
+	t.Run("below top of range keeps weight", func(t *testing.T) {
+		history := []*models.Set{
+			{WorkoutID: 2, Weight: 20, Reps: 12, PerformedAt: now},
+			{WorkoutID: 2, Weight: 20, Reps: 10, PerformedAt: now},
+		}
+		p := prescribeExercise(exercise, 1, history)
+		if *p.Sets[0].TargetWeight != 20 || p.Sets[0].TargetReps != 8 || *p.Sets[0].TargetRepsMax != 12 {
+			t.Errorf("unexpected prescription %+v", p.Sets[0])
+		}
+	})
+
+	t.Run("top of range adds load", func(t *testing.T) {
+		history := []*models.Set{
+			{WorkoutID: 2, Weight: 20, Reps: 12, PerformedAt: now},
+			{WorkoutID: 2, Weight: 20, Reps: 12, PerformedAt: now},
+		}
+		p := prescribeExercise(exercise, 1, history)
+		if *p.Sets[0].TargetWeight != 25 {
+			t.Errorf("expected 25, got %.2f", *p.Sets[0].TargetWeight)
+		}
+	})
+}
+
+func TestPrescribeWave(t *testing.T) {
+	exercise := &models.ProgramExercise{ExerciseID: 1, Sets: 3, Reps: 5, Progression: models.ProgressionWave, Increment: 5, StartWeight: floatPtr(100)}
+
+	tests := []struct {
+		week    int
+		weights [3]float64
+		reps    [3]int
+		amrap   bool
+	}{
+		{week: 1, weights: [3]float64{65, 75, 85}, reps: [3]int{5, 5, 5}, amrap: true},
+		{week: 3, weights: [3]float64{75, 85, 95}, reps: [3]int{5, 3, 1}, amrap: true},
+		{week: 4, weights: [3]float64{40, 50, 60}, reps: [3]int{5, 5, 5}, amrap: false},
+		// Second cycle: training max 105
+		{week: 5, weights: [3]float64{67.5, 80, 90}, reps: [3]int{5, 5, 5}, amrap: true},
+	}
+
+	for _, tt := range tests {
+		p := prescribeExercise(exercise, tt.week, nil)
+		for i, set := range p.Sets {
+			if *set.TargetWeight != tt.weights[i] || set.TargetReps != tt.reps[i] {
+				t.Errorf("week %d set %d: expected %.1fx%d, got %.1fx%d", tt.week, i+1, tt.weights[i], tt.reps[i], *set.TargetWeight, set.TargetReps)
+			}
+		}
+		if p.Sets[2].AMRAP != tt.amrap {
+			t.Errorf("week %d: expected amrap %v on last set", tt.week, tt.amrap)
+		}
+	}
+
+	t.Run("training max from e1RM", func(t *testing.T) {
+		// Epley e1RM of 150x1 is 150, 90% training max is 135
+		history := []*models.Set{{WorkoutID: 1, Weight: 150, Reps: 1}}
+		p := prescribeExercise(exercise, 2, history)
+		// 90% of 135 = 121.5 -> 122.5
+		if *p.Sets[2].TargetWeight != 122.5 {
+			t.Errorf("expected 122.5, got %.2f", *p.Sets[2].TargetWeight)
+		}
+	})
+}
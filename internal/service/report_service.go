@@ -0,0 +1,176 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+	"phoenix-alliance-be/internal/report"
+	"phoenix-alliance-be/internal/repository"
+)
+
+// ReportService defines the interface for printable PDF reports
+type ReportService interface {
+	WorkoutReport(userID, workoutID int64, w io.Writer) error
+	ProgressReport(userID int64, query *models.ReportQuery, w io.Writer) error
+}
+
+type reportService struct {
+	workoutRepo  repository.WorkoutRepository
+	setRepo      repository.SetRepository
+	exerciseRepo repository.ExerciseRepository
+	userRepo     repository.UserRepository
+}
+
+// NewReportService creates a new report service
+func NewReportService(
+	workoutRepo repository.WorkoutRepository,
+	setRepo repository.SetRepository,
+	exerciseRepo repository.ExerciseRepository,
+	userRepo repository.UserRepository,
+) ReportService {
+	return &reportService{
+		workoutRepo:  workoutRepo,
+		setRepo:      setRepo,
+		exerciseRepo: exerciseRepo,
+		userRepo:     userRepo,
+	}
+}
+
+// WorkoutReport writes the sheet of a workout as a PDF to w. Nothing is written on error.
+func (s *reportService) WorkoutReport(userID, workoutID int64, w io.Writer) error {
+	workout, err := s.workoutRepo.GetByIDAndUserID(workoutID, userID)
+	if err != nil {
+		return errors.New("workout not found")
+	}
+
+	sets, err := s.setRepo.GetByWorkoutID(workoutID)
+	if err != nil {
+		return errors.New("failed to retrieve sets")
+	}
+
+	volume, err := s.workoutRepo.GetVolume(workoutID)
+	if err != nil {
+		return errors.New("failed to calculate volume")
+	}
+
+	// Sets are grouped by exercise, in the order each exercise was first performed
+	data := &models.WorkoutReport{
+		Workout:  workout,
+		Location: userLocation(s.userRepo, userID),
+		Volume:   volume,
+	}
+	index := make(map[int64]int)
+	for _, set := range sets {
+		i, ok := index[set.ExerciseID]
+		if !ok {
+			exercise, err := s.exerciseRepo.GetByIDAndUserID(set.ExerciseID, userID)
+			if err != nil {
+				// The exercise may have been deleted after the workout was logged
+				if exercise, err = s.exerciseRepo.GetByID(set.ExerciseID); err != nil {
+					return errors.New("failed to retrieve exercises")
+				}
+			}
+			i = len(data.Exercises)
+			index[set.ExerciseID] = i
+			data.Exercises = append(data.Exercises, models.WorkoutReportExercise{Exercise: exercise})
+		}
+		data.Exercises[i].Sets = append(data.Exercises[i].Sets, set)
+	}
+
+	if err := report.WriteWorkout(w, data); err != nil {
+		return errors.New("failed to render report")
+	}
+	return nil
+}
+
+// ProgressReport writes a progress report of a period as a PDF to w, bucketed in the user's
+// time zone by day, week or month depending on the length of the period. The period defaults
+// to the last weeks up to now. Nothing is written on error.
+func (s *reportService) ProgressReport(userID int64, query *models.ReportQuery, w io.Writer) error {
+	loc := userLocation(s.userRepo, userID)
+	to := time.Now().In(loc)
+	if query.To != nil {
+		to = localBound(*query.To, query.DateOnly, loc)
+	}
+	from := models.ProgressBucketWeek.Add(models.ProgressBucketWeek.Start(to, loc), 1-models.DefaultReportWeeks)
+	if query.From != nil {
+		from = localBound(*query.From, query.DateOnly, loc)
+	}
+	if !from.Before(to) {
+		return errors.New("invalid report: from must be before to")
+	}
+	if to.Sub(from) > models.MaxReportDays*24*time.Hour {
+		return fmt.Errorf("invalid report: the period cannot be longer than %d days", models.MaxReportDays)
+	}
+
+	formula := query.Formula
+	if !formula.IsValid() {
+		formula = models.E1RMFormulaEpley
+	}
+	bucket := reportBucket(from, to)
+	data := &models.ProgressReport{
+		Location: loc,
+		From:     from,
+		To:       to,
+		Bucket:   bucket,
+		Formula:  formula,
+	}
+
+	var err error
+	if data.Volume, err = s.setRepo.GetVolumeRollup(userID, from, to, bucket, loc); err != nil {
+		return errors.New("failed to retrieve volume")
+	}
+	if data.TopExercises, err = s.setRepo.GetTopExercisesByVolume(userID, from, to, models.ReportTopExercises); err != nil {
+		return errors.New("failed to retrieve volume")
+	}
+
+	firstBucket := bucket.Start(from, loc)
+	for i, top := range data.TopExercises {
+		if i == models.ReportChartedExercises {
+			break
+		}
+		points, err := s.setRepo.GetProgressRollup(top.ExerciseID, userID, &models.ProgressRollup{
+			From:     from,
+			To:       to,
+			Bucket:   bucket,
+			Window:   1,
+			Formula:  formula,
+			Location: loc,
+		})
+		if err != nil {
+			return errors.New("failed to retrieve progress")
+		}
+		summary, err := s.setRepo.GetMetrics(top.ExerciseID, userID, &models.SetFilter{From: &from, To: &to}, formula)
+		if err != nil {
+			return errors.New("failed to calculate metrics")
+		}
+
+		exercise := models.ExerciseReport{ExerciseID: top.ExerciseID, ExerciseName: top.ExerciseName, Summary: summary}
+		for _, point := range points {
+			if !point.Date.Before(firstBucket) {
+				exercise.DataPoints = append(exercise.DataPoints, point)
+			}
+		}
+		data.Exercises = append(data.Exercises, exercise)
+	}
+
+	if err := report.WriteProgress(w, data); err != nil {
+		return errors.New("failed to render report")
+	}
+	return nil
+}
+
+// reportBucket picks the period of the data points of a report so that its charts stay
+// readable: days for up to a month, weeks for up to a year and months beyond
+func reportBucket(from, to time.Time) models.ProgressBucket {
+	switch days := to.Sub(from).Hours() / 24; {
+	case days <= 31:
+		return models.ProgressBucketDay
+	case days <= 366:
+		return models.ProgressBucketWeek
+	}
+	return models.ProgressBucketMonth
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+func TestReportBucket(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		to   time.Time
+		want models.ProgressBucket
+	}{
+		{from.AddDate(0, 0, 31), models.ProgressBucketDay},
+		{from.AddDate(0, 0, 32), models.ProgressBucketWeek},
+		{from.AddDate(1, 0, 0), models.ProgressBucketWeek},
+		{from.AddDate(2, 0, 0), models.ProgressBucketMonth},
+	}
+	for _, tt := range tests {
+		if got := reportBucket(from, tt.to); got != tt.want {
+			t.Errorf("reportBucket to %s = %s; want %s", tt.to.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+func TestWorkoutReport(t *testing.T) {
+	workoutRepo := &mockWorkoutRepository{
+		getByIDAndUserIDFunc: func(id, userID int64) (*models.Workout, error) {
+			if id != 1 {
+				return nil, errors.New("sql: no rows in result set")
+			}
+			return &models.Workout{ID: 1, UserID: userID, Name: "Legs", PerformedAt: time.Now()}, nil
+		},
+	}
+	setRepo := &mockSetRepository{
+		getByWorkoutIDFunc: func(workoutID int64) ([]*models.Set, error) {
+			return []*models.Set{
+				{ExerciseID: 10, Weight: 100, Reps: 5},
+				{ExerciseID: 20, Weight: 50, Reps: 10},
+				{ExerciseID: 10, Weight: 105, Reps: 5},
+			}, nil
+		},
+	}
+	lookups := 0
+	exerciseRepo := &mockExerciseRepository{
+		getByIDAndUserIDFunc: func(id, userID int64) (*models.Exercise, error) {
+			lookups++
+			return &models.Exercise{ID: id, Name: "Exercise", Type: models.ExerciseTypeWeight}, nil
+		},
+	}
+	service := NewReportService(workoutRepo, setRepo, exerciseRepo, &mockUserRepository{})
+
+	var out bytes.Buffer
+	if err := service.WorkoutReport(1, 1, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(out.Bytes(), []byte("%PDF-")) {
+		t.Error("expected a PDF document")
+	}
+	if lookups != 2 {
+		t.Errorf("expected each exercise looked up once, got %d lookups", lookups)
+	}
+
+	out.Reset()
+	if err := service.WorkoutReport(1, 2, &out); err == nil || err.Error() != "workout not found" {
+		t.Errorf("expected workout not found, got %v", err)
+	}
+	if out.Len() != 0 {
+		t.Error("expected nothing written for a missing workout")
+	}
+}
+
+func TestProgressReport(t *testing.T) {
+	var bucket models.ProgressBucket
+	var rollups int
+	setRepo := &mockSetRepository{
+		getVolumeRollupFunc: func(userID int64, from, to time.Time, b models.ProgressBucket, loc *time.Location) ([]models.VolumeDataPoint, error) {
+			bucket = b
+			return []models.VolumeDataPoint{{Date: from, TotalVolume: 1000, TotalSets: 4}}, nil
+		},
+		getTopExercisesByVolumeFunc: func(userID int64, from, to time.Time, limit int) ([]models.ExerciseVolume, error) {
+			exercises := make([]models.ExerciseVolume, limit)
+			for i := range exercises {
+				exercises[i] = models.ExerciseVolume{ExerciseID: int64(i + 1), ExerciseName: "Exercise", TotalVolume: 100, TotalSets: 1}
+			}
+			return exercises, nil
+		},
+		getProgressRollupFunc: func(exerciseID, userID int64, rollup *models.ProgressRollup) ([]models.ProgressDataPoint, error) {
+			rollups++
+			if rollup.Window != 1 || rollup.Formula != models.E1RMFormulaEpley {
+				t.Errorf("unexpected rollup %+v", rollup)
+			}
+			return []models.ProgressDataPoint{{Date: rollup.From, TotalVolume: 100, EstimatedOneRepMax: 50}}, nil
+		},
+	}
+	service := NewReportService(&mockWorkoutRepository{}, setRepo, &mockExerciseRepository{}, &mockUserRepository{})
+
+	var out bytes.Buffer
+	if err := service.ProgressReport(1, &models.ReportQuery{}, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(out.Bytes(), []byte("%PDF-")) {
+		t.Error("expected a PDF document")
+	}
+	if bucket != models.ProgressBucketWeek || rollups != models.ReportChartedExercises {
+		t.Errorf("expected weekly charts of %d exercises, got %s and %d", models.ReportChartedExercises, bucket, rollups)
+	}
+
+	from := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := service.ProgressReport(1, &models.ReportQuery{From: &from, To: &to, DateOnly: true}, &out)
+	if err == nil || err.Error() != "invalid report: the period cannot be longer than 1830 days" {
+		t.Errorf("expected the period to be rejected, got %v", err)
+	}
+}
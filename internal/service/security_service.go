@@ -0,0 +1,153 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+	"phoenix-alliance-be/internal/repository"
+)
+
+// SecurityService defines the interface for login brute-force protection and the audit trail
+// of login events
+type SecurityService interface {
+	BeginLogin(email string, client *models.ClientInfo) (*models.LoginAttempt, time.Duration, error)
+	RecordLoginFailure(attempt *models.LoginAttempt)
+	RecordLoginSuccess(attempt *models.LoginAttempt, user *models.UserResponse) error
+	GetEvents(userID int64, params *models.ListParams) (*models.ListResponse[*models.SecurityEvent], error)
+	DeleteExpired() error
+}
+
+type securityService struct {
+	securityRepo repository.SecurityRepository
+	userRepo     repository.UserRepository
+}
+
+// NewSecurityService creates a new security service
+func NewSecurityService(securityRepo repository.SecurityRepository, userRepo repository.UserRepository) SecurityService {
+	return &securityService{
+		securityRepo: securityRepo,
+		userRepo:     userRepo,
+	}
+}
+
+// BeginLogin counts a login attempt as a failure of the account of the email, known or not, and
+// of the client's IP before the password is checked, so that concurrent attempts cannot get past
+// the free attempts. When the account or the IP is locked out the attempt is refused, and the
+// returned duration is how long to wait; attempts while locked out are recorded but not counted.
+func (s *securityService) BeginLogin(email string, client *models.ClientInfo) (*models.LoginAttempt, time.Duration, error) {
+	now := time.Now()
+	attempt := &models.LoginAttempt{Email: email, Client: client}
+	freeAttempts := []int{models.AccountFreeLoginAttempts, models.IPFreeLoginAttempts}
+	for i, key := range throttleKeys(email, client) {
+		throttle, reserved, err := s.securityRepo.ReserveAttempt(key, now, models.LoginFailureWindow, freeAttempts[i])
+		if err != nil {
+			s.release(attempt.Reserved)
+			return nil, 0, errors.New("failed to check login attempts")
+		}
+		if !reserved {
+			s.release(attempt.Reserved)
+			s.recordEvent(models.EventLoginBlocked, s.userIDByEmail(email), email, client, now)
+
+			wait := time.Second
+			if throttle.LockedUntil != nil {
+				wait = max(wait, throttle.LockedUntil.Sub(now))
+			}
+			return nil, wait, nil
+		}
+		attempt.Reserved = append(attempt.Reserved, throttle)
+	}
+
+	return attempt, 0, nil
+}
+
+// RecordLoginFailure records a failed login in the audit trail; BeginLogin already counted it
+func (s *securityService) RecordLoginFailure(attempt *models.LoginAttempt) {
+	s.recordEvent(models.EventLoginFailed, s.userIDByEmail(attempt.Email), attempt.Email, attempt.Client, time.Now())
+}
+
+// RecordLoginSuccess clears the failed logins of an account and takes back the attempt counted
+// against the IP. Earlier failures of the IP are kept, so that an attacker cannot reset them by
+// logging in to an account of their own.
+func (s *securityService) RecordLoginSuccess(attempt *models.LoginAttempt, user *models.UserResponse) error {
+	accountKey := accountThrottleKey(attempt.Email)
+	if err := s.securityRepo.ClearThrottle(accountKey); err != nil {
+		return errors.New("failed to record login")
+	}
+	for _, throttle := range attempt.Reserved {
+		if throttle.Key == accountKey {
+			continue
+		}
+		if err := s.securityRepo.ReleaseAttempt(throttle); err != nil {
+			return errors.New("failed to record login")
+		}
+	}
+
+	s.recordEvent(models.EventLoginSucceeded, &user.ID, user.Email, attempt.Client, time.Now())
+	return nil
+}
+
+// GetEvents retrieves a page of the security events of a user
+func (s *securityService) GetEvents(userID int64, params *models.ListParams) (*models.ListResponse[*models.SecurityEvent], error) {
+	events, next, err := s.securityRepo.ListEvents(userID, params)
+	if err != nil {
+		return nil, errors.New("failed to get security events")
+	}
+	return models.NewListResponse(events, next), nil
+}
+
+// DeleteExpired drops forgotten failures and events past their retention period
+func (s *securityService) DeleteExpired() error {
+	return s.securityRepo.DeleteExpired(time.Now())
+}
+
+// release takes back attempts reserved on throttles before the login was refused; a failure is
+// logged, as the attempt is refused anyway
+func (s *securityService) release(reserved []*models.LoginThrottle) {
+	for _, throttle := range reserved {
+		if err := s.securityRepo.ReleaseAttempt(throttle); err != nil {
+			log.Printf("Failed to release a login attempt of %s: %v", throttle.Key, err)
+		}
+	}
+}
+
+// recordEvent adds an event to the audit trail; a failure is logged rather than failing the login
+func (s *securityService) recordEvent(eventType models.SecurityEventType, userID *int64, email string, client *models.ClientInfo, at time.Time) {
+	userAgent := client.UserAgent
+	if len(userAgent) > models.MaxUserAgentLength {
+		userAgent = strings.ToValidUTF8(userAgent[:models.MaxUserAgentLength], "")
+	}
+
+	err := s.securityRepo.CreateEvent(&models.SecurityEvent{
+		UserID:    userID,
+		Type:      eventType,
+		Email:     email,
+		IPAddress: client.IPAddress,
+		UserAgent: userAgent,
+		CreatedAt: at,
+	})
+	if err != nil {
+		log.Printf("Failed to record %s event: %v", eventType, err)
+	}
+}
+
+// userIDByEmail returns the ID of the user of an email, nil when there is none
+func (s *securityService) userIDByEmail(email string) *int64 {
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		return nil
+	}
+	return &user.ID
+}
+
+// throttleKeys returns the throttle keys of a login attempt: its account, then its IP
+func throttleKeys(email string, client *models.ClientInfo) []string {
+	return []string{accountThrottleKey(email), "ip:" + client.IPAddress}
+}
+
+// accountThrottleKey returns the throttle key of the account of an email, whatever its case
+func accountThrottleKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+	"phoenix-alliance-be/internal/repository"
+)
+
+func newTestSecurityService() SecurityService {
+	userRepo := &mockUserRepository{
+		getByEmailFunc: func(email string) (*models.User, error) {
+			if email == "user@example.com" {
+				return &models.User{ID: 1, Email: email}, nil
+			}
+			return nil, errors.New("user not found")
+		},
+	}
+	return NewSecurityService(repository.NewMemorySecurityRepository(), userRepo)
+}
+
+func TestLoginLockout(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{4, 0},
+		{5, 30 * time.Second},
+		{6, time.Minute},
+		{8, 4 * time.Minute},
+		{12, time.Hour},
+		{100, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := models.LoginLockout(tt.failures, 5); got != tt.want {
+			t.Errorf("LoginLockout(%d, 5) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+// failLogin makes a login attempt with a wrong password and returns how long it was refused for
+func failLogin(service SecurityService, email string, client *models.ClientInfo) time.Duration {
+	attempt, wait, _ := service.BeginLogin(email, client)
+	if attempt != nil {
+		service.RecordLoginFailure(attempt)
+	}
+	return wait
+}
+
+func TestAccountLockout(t *testing.T) {
+	service := newTestSecurityService()
+	client := &models.ClientInfo{IPAddress: "203.0.113.7", UserAgent: "test"}
+
+	for i := 0; i < models.AccountFreeLoginAttempts-1; i++ {
+		if wait := failLogin(service, "user@example.com", client); wait != 0 {
+			t.Fatalf("expected attempts to be allowed before the limit, got a wait of %v", wait)
+		}
+	}
+
+	// The email is matched whatever its case; the last free attempt starts the lockout
+	if wait := failLogin(service, "User@Example.com", client); wait != 0 {
+		t.Fatalf("expected the last free attempt to be allowed, got a wait of %v", wait)
+	}
+	attempt, wait, err := service.BeginLogin("user@example.com", client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempt != nil || wait <= 25*time.Second || wait > 30*time.Second {
+		t.Errorf("expected a 30s lockout, got %v", wait)
+	}
+
+	// Other accounts from another IP are not affected
+	other := &models.ClientInfo{IPAddress: "198.51.100.1"}
+	attempt, wait, _ = service.BeginLogin("other@example.com", other)
+	if attempt == nil || wait != 0 {
+		t.Fatalf("expected other accounts to be allowed, got a wait of %v", wait)
+	}
+
+	// A successful login clears the failures of the account
+	service.RecordLoginSuccess(attempt, &models.UserResponse{ID: 2, Email: "other@example.com"})
+	for i := 0; i < models.AccountFreeLoginAttempts; i++ {
+		if wait := failLogin(service, "other@example.com", other); wait != 0 {
+			t.Fatalf("expected the failures of the account to be cleared, got a wait of %v", wait)
+		}
+	}
+}
+
+func TestConcurrentLoginAttempts(t *testing.T) {
+	service := newTestSecurityService()
+
+	// Attempts racing each other cannot get past the free attempts of the account
+	var wg sync.WaitGroup
+	var allowed atomic.Int32
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			client := &models.ClientInfo{IPAddress: fmt.Sprintf("203.0.113.%d", i)}
+			if attempt, _, _ := service.BeginLogin("user@example.com", client); attempt != nil {
+				allowed.Add(1)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := allowed.Load(); got != models.AccountFreeLoginAttempts {
+		t.Errorf("expected %d attempts to be allowed, got %d", models.AccountFreeLoginAttempts, got)
+	}
+}
+
+func TestIPLockout(t *testing.T) {
+	service := newTestSecurityService()
+	client := &models.ClientInfo{IPAddress: "203.0.113.7"}
+
+	// Logging in to an account of one's own does not take back the IP's failures
+	attempt, _, _ := service.BeginLogin("user@example.com", client)
+	service.RecordLoginSuccess(attempt, &models.UserResponse{ID: 1, Email: "user@example.com"})
+
+	// Credential stuffing tries many accounts once each
+	emails := []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com"}
+	for i := 0; i < models.IPFreeLoginAttempts; i++ {
+		if wait := failLogin(service, emails[i%len(emails)], client); wait != 0 {
+			t.Fatalf("expected attempt %d to be allowed, got a wait of %v", i+1, wait)
+		}
+	}
+
+	if wait := failLogin(service, "new@example.com", client); wait == 0 {
+		t.Error("expected the IP to be locked out")
+	}
+	if wait := failLogin(service, "new@example.com", &models.ClientInfo{IPAddress: "198.51.100.1"}); wait != 0 {
+		t.Errorf("expected other IPs to be allowed, got a wait of %v", wait)
+	}
+}
+
+func TestSecurityEvents(t *testing.T) {
+	service := newTestSecurityService()
+	client := &models.ClientInfo{IPAddress: "203.0.113.7", UserAgent: "Mozilla/5.0"}
+
+	for i := 0; i < models.AccountFreeLoginAttempts+1; i++ {
+		failLogin(service, "user@example.com", client)
+	}
+	failLogin(service, "unknown@example.com", client)
+
+	params := &models.ListParams{Limit: 4, Sort: "created_at", Desc: true}
+	page, err := service.GetEvents(1, params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Data) != 4 || page.NextCursor == nil {
+		t.Fatalf("expected a first page of 4 events, got %d", len(page.Data))
+	}
+	if first := page.Data[0]; first.Type != models.EventLoginBlocked || first.IPAddress != "203.0.113.7" || first.UserAgent != "Mozilla/5.0" {
+		t.Errorf("expected the blocked attempt first, got %+v", first)
+	}
+
+	params.Cursor, _ = models.DecodeCursor(*page.NextCursor)
+	page, err = service.GetEvents(1, params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Data) != 2 || page.NextCursor != nil {
+		t.Fatalf("expected a last page of 2 events, got %d", len(page.Data))
+	}
+	for _, event := range page.Data {
+		if event.Type != models.EventLoginFailed || event.Email != "user@example.com" {
+			t.Errorf("unexpected event %+v", event)
+		}
+	}
+}
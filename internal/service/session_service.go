@@ -0,0 +1,197 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"phoenix-alliance-be/internal/auth"
+	"phoenix-alliance-be/internal/models"
+	"phoenix-alliance-be/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// SessionService defines the interface for issuing, refreshing and revoking session tokens
+type SessionService interface {
+	Create(userID int64, email string) (*models.TokenPair, error)
+	Refresh(refreshToken string) (*models.TokenPair, error)
+	Logout(claims *auth.Claims) error
+	LogoutAll(userID int64) error
+	LogoutOthers(claims *auth.Claims) error
+	IsRevoked(jti string) (bool, error)
+	DeleteExpired() error
+}
+
+// TokenSettings holds the signing keys and lifetimes of session tokens
+type TokenSettings struct {
+	Keys          *auth.KeySet
+	AccessExpiry  time.Duration
+	RefreshExpiry time.Duration
+}
+
+type sessionService struct {
+	tokenRepo repository.RefreshTokenRepository
+	userRepo  repository.UserRepository
+	settings  TokenSettings
+}
+
+// NewSessionService creates a new session service
+func NewSessionService(
+	tokenRepo repository.RefreshTokenRepository,
+	userRepo repository.UserRepository,
+	settings TokenSettings,
+) SessionService {
+	return &sessionService{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+		settings:  settings,
+	}
+}
+
+// Create starts a session for an authenticated user with a new family of refresh tokens
+func (s *sessionService) Create(userID int64, email string) (*models.TokenPair, error) {
+	pair, token, err := s.issue(userID, email, uuid.NewString())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tokenRepo.Create(token); err != nil {
+		return nil, errors.New("failed to create session")
+	}
+
+	return pair, nil
+}
+
+// Refresh exchanges a refresh token for a new access token and a new refresh token. A refresh
+// token can be used once: when a used token is presented again, either it or its replacement
+// was stolen, so the whole session is revoked.
+func (s *sessionService) Refresh(refreshToken string) (*models.TokenPair, error) {
+	current, err := s.tokenRepo.GetByHash(auth.HashOpaqueToken(refreshToken))
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	if current.UsedAt != nil && current.RevokedAt == nil {
+		log.Printf("Refresh token reuse in session %s of user %d, revoking the session", current.FamilyID, current.UserID)
+		if err := s.tokenRepo.RevokeFamily(current.FamilyID); err != nil {
+			return nil, errors.New("failed to revoke session")
+		}
+		return nil, errors.New("invalid refresh token")
+	}
+	if !current.IsActive(time.Now()) {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	// The email in the access token follows the account, which may have changed
+	user, err := s.userRepo.GetByID(current.UserID)
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	pair, next, err := s.issue(user.ID, user.Email, current.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tokenRepo.Rotate(current, next); err != nil {
+		if err.Error() == "refresh token already used" {
+			// Another request rotated it first; treat it as reuse
+			if err := s.tokenRepo.RevokeFamily(current.FamilyID); err != nil {
+				return nil, errors.New("failed to revoke session")
+			}
+			return nil, errors.New("invalid refresh token")
+		}
+		return nil, errors.New("failed to refresh session")
+	}
+
+	return pair, nil
+}
+
+// Logout ends the session of an access token: the token is revoked along with its refresh
+// token family
+func (s *sessionService) Logout(claims *auth.Claims) error {
+	if err := s.tokenRepo.RevokeAccessToken(claims.ID, claims.UserID, claims.ExpiresAt.Time); err != nil {
+		return errors.New("failed to revoke session")
+	}
+
+	// Refresh tokens are deleted once expired, and then the denylist entry above is enough
+	token, err := s.tokenRepo.GetByAccessJTI(claims.ID)
+	if err != nil {
+		if err.Error() == "refresh token not found" {
+			return nil
+		}
+		return errors.New("failed to revoke session")
+	}
+	if err := s.tokenRepo.RevokeFamily(token.FamilyID); err != nil {
+		return errors.New("failed to revoke session")
+	}
+
+	return nil
+}
+
+// LogoutAll ends every session of a user, revoking all refresh tokens and the access tokens
+// issued with them
+func (s *sessionService) LogoutAll(userID int64) error {
+	if err := s.tokenRepo.RevokeAllForUser(userID); err != nil {
+		return errors.New("failed to revoke sessions")
+	}
+	return nil
+}
+
+// LogoutOthers ends every session of a user but the one of an access token
+func (s *sessionService) LogoutOthers(claims *auth.Claims) error {
+	token, err := s.tokenRepo.GetByAccessJTI(claims.ID)
+	if err != nil {
+		if err.Error() != "refresh token not found" {
+			return errors.New("failed to revoke sessions")
+		}
+		// The access token has no session to keep; it stays valid until it expires
+		return s.LogoutAll(claims.UserID)
+	}
+
+	if err := s.tokenRepo.RevokeOthersForUser(claims.UserID, token.FamilyID); err != nil {
+		return errors.New("failed to revoke sessions")
+	}
+	return nil
+}
+
+// IsRevoked reports whether an access token was revoked before it expired
+func (s *sessionService) IsRevoked(jti string) (bool, error) {
+	return s.tokenRepo.IsAccessTokenRevoked(jti)
+}
+
+// DeleteExpired drops the tokens that can no longer be used
+func (s *sessionService) DeleteExpired() error {
+	return s.tokenRepo.DeleteExpired(time.Now())
+}
+
+// issue generates an access token and a refresh token of a session family
+func (s *sessionService) issue(userID int64, email, familyID string) (*models.TokenPair, *models.RefreshToken, error) {
+	accessToken, claims, err := auth.GenerateToken(userID, email, s.settings.Keys, s.settings.AccessExpiry)
+	if err != nil {
+		return nil, nil, errors.New("failed to generate token")
+	}
+	refreshToken, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, nil, errors.New("failed to generate token")
+	}
+
+	now := time.Now()
+	token := &models.RefreshToken{
+		UserID:          userID,
+		TokenHash:       auth.HashOpaqueToken(refreshToken),
+		FamilyID:        familyID,
+		AccessJTI:       claims.ID,
+		AccessExpiresAt: claims.ExpiresAt.Time,
+		ExpiresAt:       now.Add(s.settings.RefreshExpiry),
+		CreatedAt:       now,
+	}
+	pair := &models.TokenPair{
+		Token:            accessToken,
+		ExpiresAt:        token.AccessExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: token.ExpiresAt,
+	}
+	return pair, token, nil
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"phoenix-alliance-be/internal/auth"
+	"phoenix-alliance-be/internal/models"
+)
+
+// mockRefreshTokenRepository is an in-memory implementation of RefreshTokenRepository
+type mockRefreshTokenRepository struct {
+	tokens  []*models.RefreshToken
+	revoked map[string]bool // Denylisted access token IDs
+}
+
+func newMockRefreshTokenRepository() *mockRefreshTokenRepository {
+	return &mockRefreshTokenRepository{revoked: make(map[string]bool)}
+}
+
+func (m *mockRefreshTokenRepository) Create(token *models.RefreshToken) error {
+	token.ID = int64(len(m.tokens) + 1)
+	m.tokens = append(m.tokens, token)
+	return nil
+}
+
+func (m *mockRefreshTokenRepository) find(match func(*models.RefreshToken) bool) (*models.RefreshToken, error) {
+	for _, token := range m.tokens {
+		if match(token) {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("refresh token not found")
+}
+
+func (m *mockRefreshTokenRepository) GetByHash(tokenHash string) (*models.RefreshToken, error) {
+	return m.find(func(t *models.RefreshToken) bool { return t.TokenHash == tokenHash })
+}
+
+func (m *mockRefreshTokenRepository) GetByAccessJTI(jti string) (*models.RefreshToken, error) {
+	return m.find(func(t *models.RefreshToken) bool { return t.AccessJTI == jti })
+}
+
+func (m *mockRefreshTokenRepository) Rotate(used, next *models.RefreshToken) error {
+	stored := m.tokens[used.ID-1]
+	if stored.UsedAt != nil || stored.RevokedAt != nil {
+		return errors.New("refresh token already used")
+	}
+	stored.UsedAt = &next.CreatedAt
+	return m.Create(next)
+}
+
+func (m *mockRefreshTokenRepository) revoke(match func(*models.RefreshToken) bool) error {
+	now := time.Now()
+	for _, token := range m.tokens {
+		if match(token) {
+			m.revoked[token.AccessJTI] = true
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *mockRefreshTokenRepository) RevokeFamily(familyID string) error {
+	return m.revoke(func(t *models.RefreshToken) bool { return t.FamilyID == familyID })
+}
+
+func (m *mockRefreshTokenRepository) RevokeAllForUser(userID int64) error {
+	return m.revoke(func(t *models.RefreshToken) bool { return t.UserID == userID })
+}
+
+func (m *mockRefreshTokenRepository) RevokeOthersForUser(userID int64, familyID string) error {
+	return m.revoke(func(t *models.RefreshToken) bool { return t.UserID == userID && t.FamilyID != familyID })
+}
+
+func (m *mockRefreshTokenRepository) RevokeAccessToken(jti string, userID int64, expiresAt time.Time) error {
+	m.revoked[jti] = true
+	return nil
+}
+
+func (m *mockRefreshTokenRepository) IsAccessTokenRevoked(jti string) (bool, error) {
+	return m.revoked[jti], nil
+}
+
+func (m *mockRefreshTokenRepository) DeleteExpired(before time.Time) error {
+	return nil
+}
+
+// testKeys signs the tokens of session tests
+var testKeys, _ = auth.NewKeySet(nil, nil, "secret")
+
+func newTestSessionService(repo *mockRefreshTokenRepository) SessionService {
+	userRepo := &mockUserRepository{
+		getByIDFunc: func(id int64) (*models.User, error) {
+			return &models.User{ID: id, Email: "user@example.com"}, nil
+		},
+	}
+	return NewSessionService(repo, userRepo, TokenSettings{
+		Keys:          testKeys,
+		AccessExpiry:  15 * time.Minute,
+		RefreshExpiry: 30 * 24 * time.Hour,
+	})
+}
+
+func TestRefresh(t *testing.T) {
+	repo := newMockRefreshTokenRepository()
+	service := newTestSessionService(repo)
+
+	first, err := service.Create(1, "user@example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.tokens[0].TokenHash != auth.HashOpaqueToken(first.RefreshToken) {
+		t.Error("expected the refresh token to be stored hashed")
+	}
+
+	second, err := service.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken || second.Token == first.Token {
+		t.Error("expected new tokens")
+	}
+	if repo.tokens[1].FamilyID != repo.tokens[0].FamilyID || repo.tokens[0].UsedAt == nil {
+		t.Errorf("expected the token rotated within its family, got %+v", repo.tokens)
+	}
+
+	// Presenting the first token again revokes the session, including the latest tokens
+	if _, err := service.Refresh(first.RefreshToken); err == nil || err.Error() != "invalid refresh token" {
+		t.Errorf("expected reuse to be rejected, got %v", err)
+	}
+	if _, err := service.Refresh(second.RefreshToken); err == nil {
+		t.Error("expected the session to be revoked after reuse")
+	}
+	claims, _ := auth.ValidateToken(second.Token, testKeys)
+	if revoked, _ := service.IsRevoked(claims.ID); !revoked {
+		t.Error("expected the latest access token to be revoked")
+	}
+
+	if _, err := service.Refresh("unknown"); err == nil || err.Error() != "invalid refresh token" {
+		t.Errorf("expected an unknown token to be rejected, got %v", err)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	repo := newMockRefreshTokenRepository()
+	service := newTestSessionService(repo)
+
+	phone, _ := service.Create(1, "user@example.com")
+	laptop, _ := service.Create(1, "user@example.com")
+	phoneClaims, _ := auth.ValidateToken(phone.Token, testKeys)
+	laptopClaims, _ := auth.ValidateToken(laptop.Token, testKeys)
+
+	if err := service.Logout(phoneClaims); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if revoked, _ := service.IsRevoked(phoneClaims.ID); !revoked {
+		t.Error("expected the access token to be revoked")
+	}
+	if _, err := service.Refresh(phone.RefreshToken); err == nil {
+		t.Error("expected the refresh token of the session to be revoked")
+	}
+	if revoked, _ := service.IsRevoked(laptopClaims.ID); revoked {
+		t.Error("expected other sessions to stay signed in")
+	}
+
+	if err := service.LogoutAll(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if revoked, _ := service.IsRevoked(laptopClaims.ID); !revoked {
+		t.Error("expected every session to be revoked")
+	}
+	if _, err := service.Refresh(laptop.RefreshToken); err == nil {
+		t.Error("expected every refresh token to be revoked")
+	}
+}
+
+func TestLogoutOthers(t *testing.T) {
+	repo := newMockRefreshTokenRepository()
+	service := newTestSessionService(repo)
+
+	phone, _ := service.Create(1, "user@example.com")
+	laptop, _ := service.Create(1, "user@example.com")
+	phoneClaims, _ := auth.ValidateToken(phone.Token, testKeys)
+	laptopClaims, _ := auth.ValidateToken(laptop.Token, testKeys)
+
+	if err := service.LogoutOthers(phoneClaims); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if revoked, _ := service.IsRevoked(laptopClaims.ID); !revoked {
+		t.Error("expected the other sessions to be revoked")
+	}
+	if _, err := service.Refresh(laptop.RefreshToken); err == nil {
+		t.Error("expected the refresh tokens of the other sessions to be revoked")
+	}
+	if revoked, _ := service.IsRevoked(phoneClaims.ID); revoked {
+		t.Error("expected the current session to stay signed in")
+	}
+	if _, err := service.Refresh(phone.RefreshToken); err != nil {
+		t.Errorf("expected the current session to keep refreshing, got %v", err)
+	}
+}
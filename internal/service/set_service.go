@@ -2,6 +2,8 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"log"
 	"time"
 
 	"phoenix-alliance-be/internal/models"
@@ -11,15 +13,24 @@ import (
 // SetService defines the interface for set business logic
 type SetService interface {
 	CreateSet(userID, workoutID int64, req *models.SetCreateRequest) (*models.SetResponse, error)
-	GetExerciseHistory(userID, exerciseID int64) (*models.ExerciseHistoryResponse, error)
-	GetExerciseProgress(userID, exerciseID int64, rangeType models.ProgressRange) (*models.ExerciseProgressResponse, error)
-	GetWorkoutSets(workoutID int64) ([]*models.SetResponse, error)
+	CreateSets(userID, workoutID int64, reqs []*models.SetCreateRequest) ([]*models.SetResponse, error)
+	GetExerciseHistory(userID, exerciseID int64, formula models.E1RMFormula, filter *models.SetFilter, include models.HistoryInclude) (*models.ExerciseHistoryResponse, error)
+	GetExerciseProgress(userID, exerciseID int64, query *models.ProgressQuery) (*models.ExerciseProgressResponse, error)
+	GetWorkoutSets(workoutID int64, filter *models.SetFilter) (*models.ListResponse[*models.SetResponse], error)
+	UpdateSet(userID, workoutID, setID int64, req *models.SetUpdateRequest) (*models.SetResponse, error)
+	DeleteSet(userID, workoutID, setID int64) error
+	ReorderSets(userID, workoutID int64, req *models.SetOrderRequest) ([]*models.SetResponse, error)
 }
 
+// invalidSetPrefix marks errors caused by a set that does not fit its exercise type
+const invalidSetPrefix = "invalid set: "
+
 type setService struct {
 	setRepo      repository.SetRepository
 	exerciseRepo repository.ExerciseRepository
 	workoutRepo  repository.WorkoutRepository
+	recordRepo   repository.PersonalRecordRepository
+	userRepo     repository.UserRepository
 }
 
 // NewSetService creates a new set service
@@ -27,262 +38,473 @@ func NewSetService(
 	setRepo repository.SetRepository,
 	exerciseRepo repository.ExerciseRepository,
 	workoutRepo repository.WorkoutRepository,
+	recordRepo repository.PersonalRecordRepository,
+	userRepo repository.UserRepository,
 ) SetService {
 	return &setService{
 		setRepo:      setRepo,
 		exerciseRepo: exerciseRepo,
 		workoutRepo:  workoutRepo,
+		recordRepo:   recordRepo,
+		userRepo:     userRepo,
 	}
 }
 
 // CreateSet creates a new set for a workout
 func (s *setService) CreateSet(userID, workoutID int64, req *models.SetCreateRequest) (*models.SetResponse, error) {
 	// Verify workout belongs to user
-	_, err := s.workoutRepo.GetByIDAndUserID(workoutID, userID)
+	workout, err := s.workoutRepo.GetByIDAndUserID(workoutID, userID)
 	if err != nil {
 		return nil, errors.New("workout not found")
 	}
 
 	// Verify exercise belongs to user
-	_, err = s.exerciseRepo.GetByIDAndUserID(req.ExerciseID, userID)
+	exercise, err := s.exerciseRepo.GetByIDAndUserID(req.ExerciseID, userID)
 	if err != nil {
 		return nil, errors.New("exercise not found")
 	}
 
+	// What a set must measure depends on the exercise type
+	if msg := exercise.Type.ValidateSet(&req.SetValues); msg != "" {
+		return nil, errors.New(invalidSetPrefix + msg)
+	}
+
 	// Create set
+	now := time.Now()
 	set := &models.Set{
 		WorkoutID:   workoutID,
-		ExerciseID:  req.ExerciseID,
-		Weight:      req.Weight,
-		Reps:        req.Reps,
-		RestSeconds: req.RestSeconds,
-		Notes:       req.Notes,
-		RPE:         req.RPE,
-		CreatedAt:   time.Now(),
+		PerformedAt: performedAt(workout, req.PerformedAt, now),
+		CreatedAt:   now,
+	}
+	applySetValues(set, &req.SetValues)
+	if req.Position != nil {
+		set.Position = *req.Position
 	}
 
 	if err := s.setRepo.Create(set); err != nil {
 		return nil, errors.New("failed to create set")
 	}
 
-	return set.ToResponse(), nil
+	response := set.ToResponse()
+	// Records are strength records, computed from load and reps of non-warm-up sets
+	if exercise.Type.CountsReps() && !set.IsWarmup() {
+		response.PersonalRecords = s.recordPersonalRecords(userID, set)
+	}
+
+	return response, nil
 }
 
-// GetExerciseHistory retrieves all sets for an exercise with metrics
-func (s *setService) GetExerciseHistory(userID, exerciseID int64) (*models.ExerciseHistoryResponse, error) {
-	// Verify exercise belongs to user
-	exercise, err := s.exerciseRepo.GetByIDAndUserID(exerciseID, userID)
+// CreateSets creates several sets for a workout in a single transaction. Every set is
+// validated before any is stored, so the batch is stored entirely or not at all.
+func (s *setService) CreateSets(userID, workoutID int64, reqs []*models.SetCreateRequest) ([]*models.SetResponse, error) {
+	// Verify workout belongs to user
+	workout, err := s.workoutRepo.GetByIDAndUserID(workoutID, userID)
 	if err != nil {
-		return nil, errors.New("exercise not found")
+		return nil, errors.New("workout not found")
 	}
 
-	// Get all sets for this exercise
-	sets, err := s.setRepo.GetByExerciseIDAndUserID(exerciseID, userID)
-	if err != nil {
-		return nil, errors.New("failed to retrieve sets")
+	// Each exercise is looked up once, however many sets use it
+	exercises := make(map[int64]*models.Exercise)
+	sets := make([]*models.Set, len(reqs))
+	now := time.Now()
+	for i, req := range reqs {
+		exercise, ok := exercises[req.ExerciseID]
+		if !ok {
+			var err error
+			if exercise, err = s.exerciseRepo.GetByIDAndUserID(req.ExerciseID, userID); err != nil {
+				return nil, fmt.Errorf("%sSet %d: Exercise not found", invalidSetPrefix, i+1)
+			}
+			exercises[req.ExerciseID] = exercise
+		}
+
+		if msg := exercise.Type.ValidateSet(&req.SetValues); msg != "" {
+			return nil, fmt.Errorf("%sSet %d: %s", invalidSetPrefix, i+1, msg)
+		}
+
+		sets[i] = &models.Set{
+			WorkoutID:   workoutID,
+			PerformedAt: performedAt(workout, req.PerformedAt, now),
+			CreatedAt:   now,
+		}
+		applySetValues(sets[i], &req.SetValues)
+		if req.Position != nil {
+			sets[i].Position = *req.Position
+		}
+	}
+
+	if err := s.setRepo.CreateBatch(sets); err != nil {
+		return nil, errors.New("failed to create sets")
 	}
 
-	// Convert to responses
-	setResponses := make([]*models.SetResponse, len(sets))
+	responses := make([]*models.SetResponse, len(sets))
 	for i, set := range sets {
-		setResponses[i] = set.ToResponse()
+		responses[i] = set.ToResponse()
+		if exercises[set.ExerciseID].Type.CountsReps() && !set.IsWarmup() {
+			// Later sets of the batch are already stored but must not count as history
+			responses[i].PersonalRecords = s.recordPersonalRecords(userID, set, sets[i+1:]...)
+		}
 	}
 
-	// Calculate metrics
-	metrics := calculateMetrics(sets)
+	return responses, nil
+}
 
-	response := &models.ExerciseHistoryResponse{
-		ExerciseID:   exerciseID,
-		ExerciseName: exercise.Name,
-		Sets:         setResponses,
-		Metrics:      metrics,
+// UpdateSet replaces the values of a set in a user's workout. Records the set held
+// are dropped and detected again from the new values.
+func (s *setService) UpdateSet(userID, workoutID, setID int64, req *models.SetUpdateRequest) (*models.SetResponse, error) {
+	// Verify workout belongs to user
+	if _, err := s.workoutRepo.GetByIDAndUserID(workoutID, userID); err != nil {
+		return nil, errors.New("workout not found")
 	}
 
-	return response, nil
-}
+	set, err := s.setRepo.GetByIDAndWorkoutID(setID, workoutID)
+	if err != nil {
+		return nil, errors.New("set not found")
+	}
 
-// GetExerciseProgress retrieves progress data for an exercise within a time range
-func (s *setService) GetExerciseProgress(userID, exerciseID int64, rangeType models.ProgressRange) (*models.ExerciseProgressResponse, error) {
-	// Verify exercise belongs to user
-	exercise, err := s.exerciseRepo.GetByIDAndUserID(exerciseID, userID)
+	exercise, err := s.exerciseRepo.GetByIDAndUserID(req.ExerciseID, userID)
 	if err != nil {
 		return nil, errors.New("exercise not found")
 	}
 
-	// Calculate date range
-	now := time.Now()
-	var startDate, endDate time.Time
-
-	switch rangeType {
-	case models.ProgressRangeWeek:
-		startDate = now.AddDate(0, 0, -7)
-		endDate = now
-	case models.ProgressRangeMonth:
-		startDate = now.AddDate(0, -1, 0)
-		endDate = now
-	case models.ProgressRangeYear:
-		startDate = now.AddDate(-1, 0, 0)
-		endDate = now
-	default:
-		return nil, errors.New("invalid range type")
-	}
-
-	// Get sets within date range
-	sets, err := s.setRepo.GetByExerciseIDAndDateRange(exerciseID, startDate, endDate)
-	if err != nil {
-		return nil, errors.New("failed to retrieve sets")
+	if msg := exercise.Type.ValidateSet(&req.SetValues); msg != "" {
+		return nil, errors.New(invalidSetPrefix + msg)
 	}
 
-	// Group sets by date and calculate data points
-	dataPoints := groupSetsByDate(sets)
+	applySetValues(set, &req.SetValues)
+	if req.PerformedAt != nil {
+		set.PerformedAt = *req.PerformedAt
+	}
+	if err := s.setRepo.Update(set); err != nil {
+		if err.Error() == "set not found" {
+			return nil, errors.New("set not found")
+		}
+		return nil, errors.New("failed to update set")
+	}
 
-	// Calculate summary metrics
-	summary := calculateMetrics(sets)
+	if err := s.recordRepo.DeleteBySetID(set.ID); err != nil {
+		log.Printf("Failed to remove personal records of updated set %d: %v", set.ID, err)
+	}
 
-	response := &models.ExerciseProgressResponse{
-		ExerciseID:   exerciseID,
-		ExerciseName: exercise.Name,
-		Range:        string(rangeType),
-		StartDate:    startDate,
-		EndDate:      endDate,
-		DataPoints:   dataPoints,
-		Summary:      summary,
+	response := set.ToResponse()
+	if exercise.Type.CountsReps() && !set.IsWarmup() {
+		response.PersonalRecords = s.recordPersonalRecords(userID, set)
 	}
 
 	return response, nil
 }
 
-// calculateMetrics calculates aggregated metrics from sets
-func calculateMetrics(sets []*models.Set) *models.ExerciseMetrics {
-	if len(sets) == 0 {
-		return nil
+// DeleteSet performs a soft delete on a set in a user's workout and drops the records it held
+func (s *setService) DeleteSet(userID, workoutID, setID int64) error {
+	// Verify workout belongs to user
+	if _, err := s.workoutRepo.GetByIDAndUserID(workoutID, userID); err != nil {
+		return errors.New("workout not found")
 	}
 
-	metrics := &models.ExerciseMetrics{
-		TotalSets: len(sets),
+	if err := s.setRepo.Delete(setID, workoutID); err != nil {
+		if err.Error() == "set not found" {
+			return errors.New("set not found")
+		}
+		return errors.New("failed to delete set")
 	}
 
-	var totalVolume float64
-	var totalWeight float64
-	var totalReps int
-	var totalRest int
-	var restCount int
-	var totalRPE int
-	var rpeCount int
-	var maxWeight float64
-	var maxReps int
-	var firstDate *time.Time
-	var lastDate *time.Time
+	// The set is already deleted, so failures are logged instead of failing the request
+	if err := s.recordRepo.DeleteBySetID(setID); err != nil {
+		log.Printf("Failed to remove personal records of deleted set %d: %v", setID, err)
+	}
 
-	for _, set := range sets {
-		volume := set.Weight * float64(set.Reps)
-		totalVolume += volume
-		totalWeight += set.Weight
-		totalReps += set.Reps
+	return nil
+}
 
-		if set.Weight > maxWeight {
-			maxWeight = set.Weight
-		}
-		if set.Reps > maxReps {
-			maxReps = set.Reps
-		}
+// ReorderSets sets the order of the sets in a user's workout and returns them in that order
+func (s *setService) ReorderSets(userID, workoutID int64, req *models.SetOrderRequest) ([]*models.SetResponse, error) {
+	// Verify workout belongs to user
+	if _, err := s.workoutRepo.GetByIDAndUserID(workoutID, userID); err != nil {
+		return nil, errors.New("workout not found")
+	}
 
-		if set.RestSeconds != nil {
-			totalRest += *set.RestSeconds
-			restCount++
+	if err := s.setRepo.Reorder(workoutID, req.SetIDs); err != nil {
+		if err.Error() == "invalid set order" {
+			return nil, errors.New("invalid set order")
 		}
+		return nil, errors.New("failed to reorder sets")
+	}
+
+	sets, err := s.setRepo.GetByWorkoutID(workoutID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve sets")
+	}
+
+	responses := make([]*models.SetResponse, len(sets))
+	for i, set := range sets {
+		responses[i] = set.ToResponse()
+	}
+
+	return responses, nil
+}
+
+// applySetValues copies the values of a set request onto a set; the type defaults to working
+func applySetValues(set *models.Set, values *models.SetValues) {
+	set.ExerciseID = values.ExerciseID
+	set.Weight = values.Weight
+	set.Reps = values.Reps
+	set.RestSeconds = values.RestSeconds
+	set.Notes = values.Notes
+	set.RPE = values.RPE
+	set.DistanceMeters = values.DistanceMeters
+	set.DurationSeconds = values.DurationSeconds
+	set.GroupID = values.GroupID
+	set.Type = values.Type
+	if set.Type == "" {
+		set.Type = models.SetTypeWorking
+	}
+}
+
+// performedAt returns when a new set was performed: the time the client sent, or now.
+// Sets logged without a time into a backdated workout take the workout's time instead.
+func performedAt(workout *models.Workout, requested *time.Time, now time.Time) time.Time {
+	if requested != nil {
+		return *requested
+	}
+	if workout.PerformedAt.Before(workout.CreatedAt) {
+		return workout.PerformedAt
+	}
+	return now
+}
+
+// recordPersonalRecords detects and stores the records broken by a new set, leaving
+// the pending sets out of its history. A set performed before others of the exercise
+// changes the records that followed it, so the records of the exercise are replayed
+// instead. The set is already stored, so failures are logged instead of failing the request.
+func (s *setService) recordPersonalRecords(userID int64, set *models.Set, pending ...*models.Set) []models.PersonalRecordType {
+	sets, err := s.setRepo.GetByExerciseIDAndUserID(set.ExerciseID, userID)
+	if err != nil {
+		log.Printf("Failed to load history for personal records (set %d): %v", set.ID, err)
+		return nil
+	}
 
-		if set.RPE != nil {
-			totalRPE += *set.RPE
-			rpeCount++
+	skip := map[int64]bool{set.ID: true}
+	for _, p := range pending {
+		skip[p.ID] = true
+	}
+
+	history := make([]*models.Set, 0, len(sets))
+	backdated := false
+	for _, prev := range withoutWarmups(sets) {
+		if !skip[prev.ID] {
+			history = append(history, prev)
+			backdated = backdated || performedBefore(set, prev)
 		}
+	}
+	if backdated {
+		return s.replayPersonalRecords(userID, set, append(history, set))
+	}
 
-		if firstDate == nil || set.CreatedAt.Before(*firstDate) {
-			firstDate = &set.CreatedAt
+	var types []models.PersonalRecordType
+	for _, record := range detectPersonalRecords(userID, set, history) {
+		if err := s.recordRepo.Create(record); err != nil {
+			log.Printf("Failed to store %s personal record (set %d): %v", record.RecordType, set.ID, err)
+			continue
 		}
-		if lastDate == nil || set.CreatedAt.After(*lastDate) {
-			lastDate = &set.CreatedAt
+		types = append(types, record.RecordType)
+	}
+
+	return types
+}
+
+// replayPersonalRecords replaces the records of the exercise of a set with the ones replayed
+// from its sets and returns the records the set holds
+func (s *setService) replayPersonalRecords(userID int64, set *models.Set, sets []*models.Set) []models.PersonalRecordType {
+	records := replayPersonalRecords(userID, sets)
+	if err := s.recordRepo.ReplaceForExercise(userID, set.ExerciseID, records); err != nil {
+		log.Printf("Failed to replay personal records (set %d): %v", set.ID, err)
+		return nil
+	}
+
+	var types []models.PersonalRecordType
+	for _, record := range records {
+		if record.SetID == set.ID {
+			types = append(types, record.RecordType)
 		}
 	}
+	return types
+}
 
-	metrics.TotalVolume = totalVolume
-	metrics.MaxWeight = maxWeight
-	metrics.MaxReps = maxReps
-	metrics.AverageWeight = totalWeight / float64(len(sets))
-	metrics.AverageReps = float64(totalReps) / float64(len(sets))
+// GetExerciseHistory retrieves a page of sets for an exercise with metrics over every matching set.
+// Metrics are aggregated in the database, so they never load more than the requested page of sets.
+func (s *setService) GetExerciseHistory(userID, exerciseID int64, formula models.E1RMFormula, filter *models.SetFilter, include models.HistoryInclude) (*models.ExerciseHistoryResponse, error) {
+	// Verify exercise belongs to user
+	exercise, err := s.exerciseRepo.GetByIDAndUserID(exerciseID, userID)
+	if err != nil {
+		return nil, errors.New("exercise not found")
+	}
 
-	if restCount > 0 {
-		avgRest := float64(totalRest) / float64(restCount)
-		metrics.AverageRest = &avgRest
+	response := &models.ExerciseHistoryResponse{
+		ExerciseID:   exerciseID,
+		ExerciseName: exercise.Name,
+		Sets:         []*models.SetResponse{},
 	}
 
-	if rpeCount > 0 {
-		avgRPE := float64(totalRPE) / float64(rpeCount)
-		metrics.AverageRPE = &avgRPE
+	if include.Sets {
+		// Get the requested page of sets
+		sets, next, err := s.setRepo.ListByExerciseIDAndUserID(exerciseID, userID, filter)
+		if err != nil {
+			return nil, errors.New("failed to retrieve sets")
+		}
+
+		// Convert to responses
+		for _, set := range sets {
+			response.Sets = append(response.Sets, set.ToResponse())
+		}
+		if next != "" {
+			response.NextCursor = &next
+		}
 	}
 
-	metrics.FirstRecordedAt = firstDate
-	metrics.LastRecordedAt = lastDate
+	if include.Metrics {
+		// Metrics cover all matching sets, not only the current page
+		response.Metrics, err = s.setRepo.GetMetrics(exerciseID, userID, filter, formula)
+		if err != nil {
+			return nil, errors.New("failed to calculate metrics")
+		}
+	}
 
-	return metrics
+	return response, nil
 }
 
-// groupSetsByDate groups sets by date and creates data points
-func groupSetsByDate(sets []*models.Set) []models.ProgressDataPoint {
-	if len(sets) == 0 {
-		return []models.ProgressDataPoint{}
+// GetExerciseProgress retrieves progress data for an exercise within a time range,
+// bucketed by day, week or month in the user's time zone
+func (s *setService) GetExerciseProgress(userID, exerciseID int64, query *models.ProgressQuery) (*models.ExerciseProgressResponse, error) {
+	// Verify exercise belongs to user
+	exercise, err := s.exerciseRepo.GetByIDAndUserID(exerciseID, userID)
+	if err != nil {
+		return nil, errors.New("exercise not found")
+	}
+
+	loc := userLocation(s.userRepo, userID)
+	bucket := query.Bucket
+	if bucket == "" {
+		bucket = models.ProgressBucketDay
+	}
+	window := query.Window
+	if window < 1 {
+		window = bucket.DefaultWindow()
 	}
 
-	// Group sets by date
-	dateMap := make(map[string][]*models.Set)
-	for _, set := range sets {
-		dateKey := set.CreatedAt.Format("2006-01-02")
-		dateMap[dateKey] = append(dateMap[dateKey], set)
+	// Calculate date range: explicit bounds win over the range relative to the end
+	endDate := time.Now().In(loc)
+	if query.To != nil {
+		endDate = localBound(*query.To, query.DateOnly, loc)
 	}
 
-	// Create data points
-	var dataPoints []models.ProgressDataPoint
-	for dateKey, daySets := range dateMap {
-		date, _ := time.Parse("2006-01-02", dateKey)
+	rangeName := query.Range
+	var startDate time.Time
+	if query.From != nil {
+		startDate = localBound(*query.From, query.DateOnly, loc)
+		rangeName = models.ProgressRangeCustom
+	} else {
+		switch query.Range {
+		case models.ProgressRangeWeek:
+			startDate = endDate.AddDate(0, 0, -7)
+		case models.ProgressRangeMonth:
+			startDate = endDate.AddDate(0, -1, 0)
+		case models.ProgressRangeYear:
+			startDate = endDate.AddDate(-1, 0, 0)
+		default:
+			return nil, errors.New("invalid range type")
+		}
+		if query.To != nil {
+			rangeName = models.ProgressRangeCustom
+		}
+	}
 
-		var totalVolume float64
-		var maxWeight float64
-		var totalRPE int
-		var rpeCount int
+	// The rollup starts with the buckets before the range so the first points have full rolling windows
+	firstBucket := bucket.Start(startDate, loc)
+	dataPoints, err := s.setRepo.GetProgressRollup(exerciseID, userID, &models.ProgressRollup{
+		From:           bucket.Add(firstBucket, 1-window),
+		To:             endDate,
+		Bucket:         bucket,
+		Window:         window,
+		Formula:        query.Formula,
+		IncludeWarmups: query.IncludeWarmups,
+		Location:       loc,
+	})
+	if err != nil {
+		return nil, errors.New("failed to retrieve progress")
+	}
 
-		for _, set := range daySets {
-			volume := set.Weight * float64(set.Reps)
-			totalVolume += volume
-			if set.Weight > maxWeight {
-				maxWeight = set.Weight
-			}
-			if set.RPE != nil {
-				totalRPE += *set.RPE
-				rpeCount++
-			}
+	inRange := []models.ProgressDataPoint{}
+	for _, dp := range dataPoints {
+		if !dp.Date.Before(firstBucket) && (query.FillEmpty || dp.TotalSets > 0) {
+			inRange = append(inRange, dp)
 		}
+	}
 
-		dp := models.ProgressDataPoint{
-			Date:        date,
-			TotalVolume: totalVolume,
-			MaxWeight:   maxWeight,
-			TotalSets:   len(daySets),
-		}
+	// Summary metrics cover the range only
+	summary, err := s.setRepo.GetMetrics(exerciseID, userID, &models.SetFilter{
+		From:           &startDate,
+		To:             &endDate,
+		IncludeWarmups: query.IncludeWarmups,
+	}, query.Formula)
+	if err != nil {
+		return nil, errors.New("failed to calculate metrics")
+	}
 
-		if rpeCount > 0 {
-			avgRPE := float64(totalRPE) / float64(rpeCount)
-			dp.AverageRPE = &avgRPE
-		}
+	response := &models.ExerciseProgressResponse{
+		ExerciseID:   exerciseID,
+		ExerciseName: exercise.Name,
+		Range:        string(rangeName),
+		Bucket:       bucket,
+		Window:       window,
+		Timezone:     loc.String(),
+		StartDate:    startDate,
+		EndDate:      endDate,
+		DataPoints:   inRange,
+		Summary:      summary,
+	}
+
+	return response, nil
+}
+
+// localBound places a requested range bound in loc. Calendar dates arrive as midnight
+// UTC and are moved to midnight of the same date in loc.
+func localBound(t time.Time, dateOnly bool, loc *time.Location) time.Time {
+	if !dateOnly {
+		return t.In(loc)
+	}
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
 
-		dataPoints = append(dataPoints, dp)
+// userLocation returns the time zone of a user, falling back to UTC
+func userLocation(userRepo repository.UserRepository, userID int64) *time.Location {
+	user, err := userRepo.GetByID(userID)
+	if err != nil {
+		log.Printf("Failed to load time zone of user %d: %v", userID, err)
+		return time.UTC
 	}
 
-	return dataPoints
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		log.Printf("Invalid time zone %q of user %d: %v", user.Timezone, userID, err)
+		return time.UTC
+	}
+	return loc
 }
 
-// GetWorkoutSets retrieves all sets for a workout
-func (s *setService) GetWorkoutSets(workoutID int64) ([]*models.SetResponse, error) {
-	sets, err := s.setRepo.GetByWorkoutID(workoutID)
+// withoutWarmups returns the sets that are not warm-ups
+func withoutWarmups(sets []*models.Set) []*models.Set {
+	filtered := make([]*models.Set, 0, len(sets))
+	for _, set := range sets {
+		if !set.IsWarmup() {
+			filtered = append(filtered, set)
+		}
+	}
+	return filtered
+}
+
+// GetWorkoutSets retrieves a page of sets for a workout
+func (s *setService) GetWorkoutSets(workoutID int64, filter *models.SetFilter) (*models.ListResponse[*models.SetResponse], error) {
+	sets, next, err := s.setRepo.ListByWorkoutID(workoutID, filter)
 	if err != nil {
 		return nil, errors.New("failed to retrieve sets")
 	}
@@ -292,5 +514,5 @@ func (s *setService) GetWorkoutSets(workoutID int64) ([]*models.SetResponse, err
 		responses[i] = set.ToResponse()
 	}
 
-	return responses, nil
+	return models.NewListResponse(responses, next), nil
 }
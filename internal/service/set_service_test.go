@@ -1,94 +1,694 @@
 package service
 
 import (
+	"errors"
 	"testing"
 	"time"
 
 	"phoenix-alliance-be/internal/models"
 )
 
-func TestCalculateMetrics(t *testing.T) {
-	now := time.Now()
-	sets := []*models.Set{
-		{
-			ID:         1,
-			Weight:     60.0,
-			Reps:       10,
-			RestSeconds: intPtr(120),
-			RPE:         intPtr(7),
-			CreatedAt:   now,
+// mockSetRepository is a mock implementation of SetRepository
+type mockSetRepository struct {
+	createFunc                      func(set *models.Set) error
+	createBatchFunc                 func(sets []*models.Set) error
+	getByIDFunc                     func(id int64) (*models.Set, error)
+	getByWorkoutIDFunc              func(workoutID int64) ([]*models.Set, error)
+	getByExerciseIDFunc             func(exerciseID int64) ([]*models.Set, error)
+	getByExerciseIDAndUserIDFunc    func(exerciseID, userID int64) ([]*models.Set, error)
+	getByExerciseIDAndDateRangeFunc func(exerciseID, userID int64, startDate, endDate time.Time) ([]*models.Set, error)
+	listByWorkoutIDFunc             func(workoutID int64, filter *models.SetFilter) ([]*models.Set, string, error)
+	listByExerciseIDAndUserIDFunc   func(exerciseID, userID int64, filter *models.SetFilter) ([]*models.Set, string, error)
+	getByIDAndWorkoutIDFunc         func(id, workoutID int64) (*models.Set, error)
+	updateFunc                      func(set *models.Set) error
+	deleteFunc                      func(id, workoutID int64) error
+	reorderFunc                     func(workoutID int64, setIDs []int64) error
+	getMetricsFunc                  func(exerciseID, userID int64, filter *models.SetFilter, formula models.E1RMFormula) (*models.ExerciseMetrics, error)
+	getProgressRollupFunc           func(exerciseID, userID int64, rollup *models.ProgressRollup) ([]models.ProgressDataPoint, error)
+	getVolumeRollupFunc             func(userID int64, from, to time.Time, bucket models.ProgressBucket, loc *time.Location) ([]models.VolumeDataPoint, error)
+	getTopExercisesByVolumeFunc     func(userID int64, from, to time.Time, limit int) ([]models.ExerciseVolume, error)
+	getMuscleGroupsLastTrainedFunc  func(userID int64) (map[string]time.Time, error)
+	getMuscleVolumeFunc             func(userID int64, from, to time.Time, loc *time.Location) ([]models.MuscleVolumeWeek, error)
+	getDailyTonnageFunc             func(userID int64, from, to time.Time, loc *time.Location) ([]models.DailyLoad, error)
+}
+
+func (m *mockSetRepository) Create(set *models.Set) error {
+	if m.createFunc != nil {
+		return m.createFunc(set)
+	}
+	return nil
+}
+
+func (m *mockSetRepository) CreateBatch(sets []*models.Set) error {
+	if m.createBatchFunc != nil {
+		return m.createBatchFunc(sets)
+	}
+	return nil
+}
+
+func (m *mockSetRepository) GetByID(id int64) (*models.Set, error) {
+	if m.getByIDFunc != nil {
+		return m.getByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *mockSetRepository) GetByWorkoutID(workoutID int64) ([]*models.Set, error) {
+	if m.getByWorkoutIDFunc != nil {
+		return m.getByWorkoutIDFunc(workoutID)
+	}
+	return nil, nil
+}
+
+func (m *mockSetRepository) GetByExerciseID(exerciseID int64) ([]*models.Set, error) {
+	if m.getByExerciseIDFunc != nil {
+		return m.getByExerciseIDFunc(exerciseID)
+	}
+	return nil, nil
+}
+
+func (m *mockSetRepository) GetByExerciseIDAndUserID(exerciseID, userID int64) ([]*models.Set, error) {
+	if m.getByExerciseIDAndUserIDFunc != nil {
+		return m.getByExerciseIDAndUserIDFunc(exerciseID, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockSetRepository) GetByExerciseIDAndDateRange(exerciseID, userID int64, startDate, endDate time.Time) ([]*models.Set, error) {
+	if m.getByExerciseIDAndDateRangeFunc != nil {
+		return m.getByExerciseIDAndDateRangeFunc(exerciseID, userID, startDate, endDate)
+	}
+	return nil, nil
+}
+
+func (m *mockSetRepository) ListByWorkoutID(workoutID int64, filter *models.SetFilter) ([]*models.Set, string, error) {
+	if m.listByWorkoutIDFunc != nil {
+		return m.listByWorkoutIDFunc(workoutID, filter)
+	}
+	return nil, "", nil
+}
+
+func (m *mockSetRepository) ListByExerciseIDAndUserID(exerciseID, userID int64, filter *models.SetFilter) ([]*models.Set, string, error) {
+	if m.listByExerciseIDAndUserIDFunc != nil {
+		return m.listByExerciseIDAndUserIDFunc(exerciseID, userID, filter)
+	}
+	return nil, "", nil
+}
+
+func (m *mockSetRepository) GetByIDAndWorkoutID(id, workoutID int64) (*models.Set, error) {
+	if m.getByIDAndWorkoutIDFunc != nil {
+		return m.getByIDAndWorkoutIDFunc(id, workoutID)
+	}
+	return nil, nil
+}
+
+func (m *mockSetRepository) Update(set *models.Set) error {
+	if m.updateFunc != nil {
+		return m.updateFunc(set)
+	}
+	return nil
+}
+
+func (m *mockSetRepository) Delete(id, workoutID int64) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(id, workoutID)
+	}
+	return nil
+}
+
+func (m *mockSetRepository) Reorder(workoutID int64, setIDs []int64) error {
+	if m.reorderFunc != nil {
+		return m.reorderFunc(workoutID, setIDs)
+	}
+	return nil
+}
+
+func (m *mockSetRepository) GetMetrics(exerciseID, userID int64, filter *models.SetFilter, formula models.E1RMFormula) (*models.ExerciseMetrics, error) {
+	if m.getMetricsFunc != nil {
+		return m.getMetricsFunc(exerciseID, userID, filter, formula)
+	}
+	return nil, nil
+}
+
+func (m *mockSetRepository) GetProgressRollup(exerciseID, userID int64, rollup *models.ProgressRollup) ([]models.ProgressDataPoint, error) {
+	if m.getProgressRollupFunc != nil {
+		return m.getProgressRollupFunc(exerciseID, userID, rollup)
+	}
+	return nil, nil
+}
+
+func (m *mockSetRepository) GetVolumeRollup(userID int64, from, to time.Time, bucket models.ProgressBucket, loc *time.Location) ([]models.VolumeDataPoint, error) {
+	if m.getVolumeRollupFunc != nil {
+		return m.getVolumeRollupFunc(userID, from, to, bucket, loc)
+	}
+	return nil, nil
+}
+
+func (m *mockSetRepository) GetTopExercisesByVolume(userID int64, from, to time.Time, limit int) ([]models.ExerciseVolume, error) {
+	if m.getTopExercisesByVolumeFunc != nil {
+		return m.getTopExercisesByVolumeFunc(userID, from, to, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockSetRepository) GetMuscleGroupsLastTrained(userID int64) (map[string]time.Time, error) {
+	if m.getMuscleGroupsLastTrainedFunc != nil {
+		return m.getMuscleGroupsLastTrainedFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *mockSetRepository) GetMuscleVolume(userID int64, from, to time.Time, loc *time.Location) ([]models.MuscleVolumeWeek, error) {
+	if m.getMuscleVolumeFunc != nil {
+		return m.getMuscleVolumeFunc(userID, from, to, loc)
+	}
+	return nil, nil
+}
+
+func (m *mockSetRepository) GetDailyTonnage(userID int64, from, to time.Time, loc *time.Location) ([]models.DailyLoad, error) {
+	if m.getDailyTonnageFunc != nil {
+		return m.getDailyTonnageFunc(userID, from, to, loc)
+	}
+	return nil, nil
+}
+
+// mockUserRepository is a mock implementation of UserRepository
+type mockUserRepository struct {
+	getByIDFunc          func(id int64) (*models.User, error)
+	getByEmailFunc       func(email string) (*models.User, error)
+	updatePasswordFunc   func(userID int64, passwordHash string) error
+	markVerifiedFunc     func(userID int64, email string, at time.Time) error
+	updateEmailFunc      func(user *models.User) error
+	scheduleDeletionFunc func(userID int64, at time.Time) error
+}
+
+func (m *mockUserRepository) Create(user *models.User) error {
+	return nil
+}
+
+func (m *mockUserRepository) GetByID(id int64) (*models.User, error) {
+	if m.getByIDFunc != nil {
+		return m.getByIDFunc(id)
+	}
+	return nil, errors.New("user not found")
+}
+
+func (m *mockUserRepository) GetByEmail(email string) (*models.User, error) {
+	if m.getByEmailFunc != nil {
+		return m.getByEmailFunc(email)
+	}
+	return nil, errors.New("user not found")
+}
+
+func (m *mockUserRepository) UpdateProfile(user *models.User) error {
+	return nil
+}
+
+func (m *mockUserRepository) UpdatePassword(userID int64, passwordHash string) error {
+	if m.updatePasswordFunc != nil {
+		return m.updatePasswordFunc(userID, passwordHash)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) MarkVerified(userID int64, email string, at time.Time) error {
+	if m.markVerifiedFunc != nil {
+		return m.markVerifiedFunc(userID, email, at)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) UpdateEmail(user *models.User) error {
+	if m.updateEmailFunc != nil {
+		return m.updateEmailFunc(user)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) ScheduleDeletion(userID int64, at time.Time) error {
+	if m.scheduleDeletionFunc != nil {
+		return m.scheduleDeletionFunc(userID, at)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) CancelDeletion(userID int64) error {
+	return nil
+}
+
+func (m *mockUserRepository) Delete(userID int64) error {
+	return nil
+}
+
+func (m *mockUserRepository) DeleteScheduled(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+
+func TestCreateSetExerciseType(t *testing.T) {
+	userID := int64(1)
+	workoutRepo := &mockWorkoutRepository{
+		getByIDAndUserIDFunc: func(id, uid int64) (*models.Workout, error) {
+			return &models.Workout{ID: id, UserID: uid}, nil
 		},
-		{
-			ID:         2,
-			Weight:     65.0,
-			Reps:       8,
-			RestSeconds: intPtr(120),
-			RPE:         intPtr(8),
-			CreatedAt:   now.Add(time.Minute),
+	}
+	exerciseRepo := &mockExerciseRepository{
+		getByIDAndUserIDFunc: func(id, uid int64) (*models.Exercise, error) {
+			return &models.Exercise{ID: id, UserID: uid, Name: "Rowing", Type: models.ExerciseTypeCardio}, nil
 		},
-		{
-			ID:         3,
-			Weight:     70.0,
-			Reps:       6,
-			RestSeconds: nil,
-			RPE:         intPtr(9),
-			CreatedAt:   now.Add(2 * time.Minute),
+	}
+
+	t.Run("cardio set with distance and duration", func(t *testing.T) {
+		created := false
+		setRepo := &mockSetRepository{
+			createFunc: func(set *models.Set) error {
+				created = true
+				if *set.DistanceMeters != 2000 || *set.DurationSeconds != 480 {
+					t.Errorf("unexpected set %+v", set)
+				}
+				return nil
+			},
+		}
+		// No record repository: cardio sets never produce strength records
+		svc := NewSetService(setRepo, exerciseRepo, workoutRepo, nil, nil)
+		distance := 2000.0
+		duration := 480
+		res, err := svc.CreateSet(userID, 10, &models.SetCreateRequest{SetValues: models.SetValues{ExerciseID: 5, DistanceMeters: &distance, DurationSeconds: &duration}})
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !created || res.PersonalRecords != nil {
+			t.Errorf("expected stored set without records, got %+v", res)
+		}
+	})
+
+	t.Run("cardio set with weight and reps", func(t *testing.T) {
+		svc := NewSetService(&mockSetRepository{}, exerciseRepo, workoutRepo, nil, nil)
+		_, err := svc.CreateSet(userID, 10, &models.SetCreateRequest{SetValues: models.SetValues{ExerciseID: 5, Weight: 60, Reps: 10}})
+
+		if err == nil || err.Error() != "invalid set: Cardio sets require distance_meters or duration_seconds" {
+			t.Errorf("expected invalid set error, got %v", err)
+		}
+	})
+}
+
+func TestCreateSetPersonalRecords(t *testing.T) {
+	userID := int64(1)
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 18, 0, 0, 0, time.UTC) }
+	workoutRepo := &mockWorkoutRepository{
+		getByIDAndUserIDFunc: func(id, uid int64) (*models.Workout, error) {
+			return &models.Workout{ID: id, UserID: uid}, nil
+		},
+	}
+	exerciseRepo := &mockExerciseRepository{
+		getByIDAndUserIDFunc: func(id, uid int64) (*models.Exercise, error) {
+			return &models.Exercise{ID: id, UserID: uid, Name: "Bench Press", Type: models.ExerciseTypeWeight}, nil
+		},
+	}
+	// A heavier set was logged since
+	later := &models.Set{ID: 1, WorkoutID: 11, ExerciseID: 5, Weight: 110, Reps: 3, PerformedAt: day(20)}
+	newSetRepo := func() *mockSetRepository {
+		setRepo := &mockSetRepository{}
+		setRepo.createFunc = func(set *models.Set) error {
+			set.ID = 2
+			setRepo.getByExerciseIDAndUserIDFunc = func(exerciseID, uid int64) ([]*models.Set, error) {
+				return []*models.Set{later, set}, nil
+			}
+			return nil
+		}
+		return setRepo
+	}
+
+	t.Run("backdated set replays the records", func(t *testing.T) {
+		var replayed []*models.PersonalRecord
+		recordRepo := &mockPersonalRecordRepository{
+			createFunc: func(record *models.PersonalRecord) error {
+				t.Errorf("expected no record to be added over the later ones, got %+v", record)
+				return nil
+			},
+			replaceForExerciseFunc: func(uid, exerciseID int64, records []*models.PersonalRecord) error {
+				replayed = records
+				return nil
+			},
+		}
+		svc := NewSetService(newSetRepo(), exerciseRepo, workoutRepo, recordRepo, nil)
+		performedAt := day(6)
+		res, err := svc.CreateSet(userID, 10, &models.SetCreateRequest{
+			SetValues: models.SetValues{ExerciseID: 5, Weight: 100, Reps: 5, PerformedAt: &performedAt},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		// The backdated set was the first, and the later one beat its weight
+		maxWeight := recordTypes(replayed[:3])[models.PersonalRecordMaxWeight]
+		if maxWeight == nil || maxWeight.SetID != 2 || maxWeight.PreviousValue != nil {
+			t.Errorf("expected the backdated set to set the first records, got %+v", replayed)
+		}
+		later := recordTypes(replayed[3:])[models.PersonalRecordMaxWeight]
+		if later == nil || later.SetID != 1 || *later.PreviousValue != 100 {
+			t.Errorf("expected the later set to beat the backdated one, got %+v", replayed)
+		}
+		if len(res.PersonalRecords) != 3 {
+			t.Errorf("expected the backdated set to hold 3 records, got %v", res.PersonalRecords)
+		}
+	})
+
+	t.Run("latest set adds its records", func(t *testing.T) {
+		var created []*models.PersonalRecord
+		recordRepo := &mockPersonalRecordRepository{
+			createFunc: func(record *models.PersonalRecord) error {
+				created = append(created, record)
+				return nil
+			},
+			replaceForExerciseFunc: func(uid, exerciseID int64, records []*models.PersonalRecord) error {
+				t.Error("expected the records not to be replayed")
+				return nil
+			},
+		}
+		svc := NewSetService(newSetRepo(), exerciseRepo, workoutRepo, recordRepo, nil)
+		performedAt := day(27)
+		_, err := svc.CreateSet(userID, 12, &models.SetCreateRequest{
+			SetValues: models.SetValues{ExerciseID: 5, Weight: 115, Reps: 1, PerformedAt: &performedAt},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		records := recordTypes(created)
+		if record := records[models.PersonalRecordMaxWeight]; record == nil || *record.PreviousValue != 110 {
+			t.Errorf("expected a max_weight record over the later set, got %+v", created)
+		}
+	})
+}
+
+func TestSetOwnership(t *testing.T) {
+	userID := int64(1)
+	workoutRepo := &mockWorkoutRepository{
+		getByIDAndUserIDFunc: func(id, uid int64) (*models.Workout, error) {
+			return nil, errors.New("workout not found")
 		},
 	}
+	setRepo := &mockSetRepository{
+		updateFunc: func(set *models.Set) error {
+			t.Error("expected set not to be updated")
+			return nil
+		},
+		deleteFunc: func(id, workoutID int64) error {
+			t.Error("expected set not to be deleted")
+			return nil
+		},
+		reorderFunc: func(workoutID int64, setIDs []int64) error {
+			t.Error("expected sets not to be reordered")
+			return nil
+		},
+	}
+	svc := NewSetService(setRepo, &mockExerciseRepository{}, workoutRepo, nil, nil)
+
+	_, err := svc.UpdateSet(userID, 10, 3, &models.SetUpdateRequest{SetValues: models.SetValues{ExerciseID: 5, Weight: 60, Reps: 10}})
+	if err == nil || err.Error() != "workout not found" {
+		t.Errorf("expected workout not found on update, got %v", err)
+	}
+
+	err = svc.DeleteSet(userID, 10, 3)
+	if err == nil || err.Error() != "workout not found" {
+		t.Errorf("expected workout not found on delete, got %v", err)
+	}
+
+	_, err = svc.ReorderSets(userID, 10, &models.SetOrderRequest{SetIDs: []int64{3, 2, 1}})
+	if err == nil || err.Error() != "workout not found" {
+		t.Errorf("expected workout not found on reorder, got %v", err)
+	}
+}
+
+func TestCreateSets(t *testing.T) {
+	userID := int64(1)
+	workoutRepo := &mockWorkoutRepository{
+		getByIDAndUserIDFunc: func(id, uid int64) (*models.Workout, error) {
+			return &models.Workout{ID: id, UserID: uid}, nil
+		},
+	}
+	lookups := 0
+	exerciseRepo := &mockExerciseRepository{
+		getByIDAndUserIDFunc: func(id, uid int64) (*models.Exercise, error) {
+			lookups++
+			if id != 5 {
+				return nil, errors.New("exercise not found")
+			}
+			return &models.Exercise{ID: id, UserID: uid, Name: "Rowing", Type: models.ExerciseTypeCardio}, nil
+		},
+	}
+	distance := 500.0
+
+	t.Run("stores every set in one batch", func(t *testing.T) {
+		lookups = 0
+		var stored []*models.Set
+		setRepo := &mockSetRepository{
+			createBatchFunc: func(sets []*models.Set) error {
+				stored = sets
+				for i, set := range sets {
+					set.ID = int64(i + 1)
+				}
+				return nil
+			},
+		}
+		// No record repository: cardio sets never produce strength records
+		svc := NewSetService(setRepo, exerciseRepo, workoutRepo, nil, nil)
+		reqs := []*models.SetCreateRequest{
+			{SetValues: models.SetValues{ExerciseID: 5, DistanceMeters: &distance}},
+			{SetValues: models.SetValues{ExerciseID: 5, DistanceMeters: &distance}},
+			{SetValues: models.SetValues{ExerciseID: 5, DistanceMeters: &distance}},
+		}
+		res, err := svc.CreateSets(userID, 10, reqs)
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(stored) != 3 || len(res) != 3 || res[2].ID != 3 {
+			t.Errorf("expected 3 stored sets, got %d stored and %+v", len(stored), res)
+		}
+		if lookups != 1 {
+			t.Errorf("expected the exercise to be looked up once, got %d", lookups)
+		}
+	})
+
+	t.Run("invalid set stores nothing", func(t *testing.T) {
+		setRepo := &mockSetRepository{
+			createBatchFunc: func(sets []*models.Set) error {
+				t.Error("expected no sets to be stored")
+				return nil
+			},
+		}
+		svc := NewSetService(setRepo, exerciseRepo, workoutRepo, nil, nil)
+		reqs := []*models.SetCreateRequest{
+			{SetValues: models.SetValues{ExerciseID: 5, DistanceMeters: &distance}},
+			{SetValues: models.SetValues{ExerciseID: 5, Weight: 60, Reps: 10}},
+		}
+		_, err := svc.CreateSets(userID, 10, reqs)
+
+		if err == nil || err.Error() != "invalid set: Set 2: Cardio sets require distance_meters or duration_seconds" {
+			t.Errorf("expected invalid set error for the second set, got %v", err)
+		}
+
+		reqs[1] = &models.SetCreateRequest{SetValues: models.SetValues{ExerciseID: 7, DistanceMeters: &distance}}
+		_, err = svc.CreateSets(userID, 10, reqs)
+
+		if err == nil || err.Error() != "invalid set: Set 2: Exercise not found" {
+			t.Errorf("expected exercise not found for the second set, got %v", err)
+		}
+	})
+}
+
+func TestPerformedAt(t *testing.T) {
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	yesterday := now.AddDate(0, 0, -1)
+	live := &models.Workout{PerformedAt: now.Add(-time.Hour), CreatedAt: now.Add(-time.Hour)}
+	backdated := &models.Workout{PerformedAt: yesterday, CreatedAt: now.Add(-time.Minute)}
+
+	if got := performedAt(live, nil, now); !got.Equal(now) {
+		t.Errorf("expected a live workout's set to be performed now, got %v", got)
+	}
+	if got := performedAt(backdated, nil, now); !got.Equal(yesterday) {
+		t.Errorf("expected a backdated workout's set to take the workout's time, got %v", got)
+	}
+	requested := yesterday.Add(10 * time.Minute)
+	if got := performedAt(backdated, &requested, now); !got.Equal(requested) {
+		t.Errorf("expected the requested time, got %v", got)
+	}
+}
 
-	metrics := calculateMetrics(sets)
+func TestProgressBucketStart(t *testing.T) {
+	bogota, err := time.LoadLocation("America/Bogota")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
 
-	if metrics == nil {
-		t.Fatal("Expected metrics, got nil")
+	// 22:30 on Wednesday May 15 in UTC-5 is already Thursday in UTC
+	late := time.Date(2024, 5, 16, 3, 30, 0, 0, time.UTC)
+	if got := models.ProgressBucketDay.Start(late, bogota); !got.Equal(time.Date(2024, 5, 15, 0, 0, 0, 0, bogota)) {
+		t.Errorf("expected the day to start at midnight in the user's time zone, got %v", got)
+	}
+	if got := models.ProgressBucketWeek.Start(late, bogota); !got.Equal(time.Date(2024, 5, 13, 0, 0, 0, 0, bogota)) {
+		t.Errorf("expected weeks to start on Monday, got %v", got)
+	}
+	if got := models.ProgressBucketMonth.Start(late, bogota); !got.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, bogota)) {
+		t.Errorf("expected months to start on the 1st, got %v", got)
+	}
+	if got := models.ProgressBucketMonth.Add(time.Date(2024, 5, 1, 0, 0, 0, 0, bogota), -3); !got.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, bogota)) {
+		t.Errorf("expected three months back to be February 1, got %v", got)
 	}
+}
 
-	if metrics.TotalSets != 3 {
-		t.Errorf("Expected TotalSets to be 3, got %d", metrics.TotalSets)
+func TestGetExerciseProgress(t *testing.T) {
+	bogota, err := time.LoadLocation("America/Bogota")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
 	}
 
-	expectedVolume := 60.0*10 + 65.0*8 + 70.0*6
-	if metrics.TotalVolume != expectedVolume {
-		t.Errorf("Expected TotalVolume to be %.2f, got %.2f", expectedVolume, metrics.TotalVolume)
+	userID := int64(1)
+	exerciseRepo := &mockExerciseRepository{
+		getByIDAndUserIDFunc: func(id, uid int64) (*models.Exercise, error) {
+			return &models.Exercise{ID: id, UserID: uid, Name: "Bench Press"}, nil
+		},
+	}
+	userRepo := &mockUserRepository{
+		getByIDFunc: func(id int64) (*models.User, error) {
+			return &models.User{ID: id, Timezone: "America/Bogota"}, nil
+		},
 	}
 
-	if metrics.MaxWeight != 70.0 {
-		t.Errorf("Expected MaxWeight to be 70.0, got %.2f", metrics.MaxWeight)
+	var rollup *models.ProgressRollup
+	var summaryFilter *models.SetFilter
+	setRepo := &mockSetRepository{
+		getProgressRollupFunc: func(exerciseID, uid int64, r *models.ProgressRollup) ([]models.ProgressDataPoint, error) {
+			rollup = r
+			// The week before the range, an empty week and a week with sets
+			return []models.ProgressDataPoint{
+				{Date: time.Date(2024, 4, 29, 0, 0, 0, 0, bogota), TotalSets: 3, RollingVolume: 750},
+				{Date: time.Date(2024, 5, 6, 0, 0, 0, 0, bogota), RollingVolume: 375},
+				{Date: time.Date(2024, 5, 13, 0, 0, 0, 0, bogota), TotalSets: 2, RollingVolume: 500},
+			}, nil
+		},
+		getMetricsFunc: func(exerciseID, uid int64, filter *models.SetFilter, formula models.E1RMFormula) (*models.ExerciseMetrics, error) {
+			summaryFilter = filter
+			return &models.ExerciseMetrics{TotalSets: 2, E1RMFormula: formula}, nil
+		},
 	}
+	service := NewSetService(setRepo, exerciseRepo, nil, nil, userRepo)
 
-	if metrics.MaxReps != 10 {
-		t.Errorf("Expected MaxReps to be 10, got %d", metrics.MaxReps)
+	from := time.Date(2024, 5, 8, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 5, 18, 0, 0, 0, 0, time.UTC)
+	query := &models.ProgressQuery{
+		Range:    models.ProgressRangeMonth,
+		From:     &from,
+		To:       &to,
+		DateOnly: true,
+		Bucket:   models.ProgressBucketWeek,
+		Window:   2,
+		Formula:  models.E1RMFormulaEpley,
 	}
 
-	expectedAvgWeight := (60.0 + 65.0 + 70.0) / 3.0
-	if metrics.AverageWeight != expectedAvgWeight {
-		t.Errorf("Expected AverageWeight to be %.2f, got %.2f", expectedAvgWeight, metrics.AverageWeight)
+	progress, err := service.GetExerciseProgress(userID, 1, query)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if progress.Range != string(models.ProgressRangeCustom) || progress.Timezone != "America/Bogota" {
+		t.Errorf("expected a custom range in the user's time zone, got %q in %q", progress.Range, progress.Timezone)
+	}
+	if !progress.StartDate.Equal(time.Date(2024, 5, 8, 0, 0, 0, 0, bogota)) || !progress.EndDate.Equal(time.Date(2024, 5, 18, 0, 0, 0, 0, bogota)) {
+		t.Errorf("expected dates read in the user's time zone, got %v to %v", progress.StartDate, progress.EndDate)
 	}
 
-	expectedAvgReps := float64(10+8+6) / 3.0
-	if metrics.AverageReps != expectedAvgReps {
-		t.Errorf("Expected AverageReps to be %.2f, got %.2f", expectedAvgReps, metrics.AverageReps)
+	// The rollup starts a window early so the first week in range has a full rolling average
+	if rollup == nil || !rollup.From.Equal(time.Date(2024, 4, 29, 0, 0, 0, 0, bogota)) || !rollup.To.Equal(progress.EndDate) {
+		t.Fatalf("unexpected rollup %+v", rollup)
+	}
+	if rollup.Bucket != models.ProgressBucketWeek || rollup.Window != 2 || rollup.Location.String() != "America/Bogota" {
+		t.Errorf("unexpected rollup %+v", rollup)
 	}
 
-	if metrics.AverageRest == nil {
-		t.Error("Expected AverageRest to be set")
-	} else {
-		expectedAvgRest := 120.0
-		if *metrics.AverageRest != expectedAvgRest {
-			t.Errorf("Expected AverageRest to be %.2f, got %.2f", expectedAvgRest, *metrics.AverageRest)
-		}
+	// Buckets before the range and, without fill_empty, empty buckets are left out
+	if len(progress.DataPoints) != 1 || progress.DataPoints[0].RollingVolume != 500 {
+		t.Errorf("expected only the week with sets, got %+v", progress.DataPoints)
+	}
+	query.FillEmpty = true
+	progress, err = service.GetExerciseProgress(userID, 1, query)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(progress.DataPoints) != 2 {
+		t.Errorf("expected the empty week with fill_empty, got %+v", progress.DataPoints)
 	}
 
-	if metrics.AverageRPE == nil {
-		t.Error("Expected AverageRPE to be set")
-	} else {
-		expectedAvgRPE := (7.0 + 8.0 + 9.0) / 3.0
-		if *metrics.AverageRPE != expectedAvgRPE {
-			t.Errorf("Expected AverageRPE to be %.2f, got %.2f", expectedAvgRPE, *metrics.AverageRPE)
-		}
+	// The summary covers the range only
+	if summaryFilter == nil || !summaryFilter.From.Equal(progress.StartDate) || !summaryFilter.To.Equal(progress.EndDate) {
+		t.Errorf("expected the summary over the range, got %+v", summaryFilter)
 	}
 }
 
-func intPtr(i int) *int {
-	return &i
+func TestGetExerciseHistoryMetricsOnly(t *testing.T) {
+	userID := int64(1)
+	exerciseRepo := &mockExerciseRepository{
+		getByIDAndUserIDFunc: func(id, uid int64) (*models.Exercise, error) {
+			return &models.Exercise{ID: id, UserID: uid, Name: "Bench Press"}, nil
+		},
+	}
+	setRepo := &mockSetRepository{
+		listByExerciseIDAndUserIDFunc: func(exerciseID, uid int64, filter *models.SetFilter) ([]*models.Set, string, error) {
+			t.Error("expected no sets to be loaded")
+			return nil, "", nil
+		},
+		getMetricsFunc: func(exerciseID, uid int64, filter *models.SetFilter, formula models.E1RMFormula) (*models.ExerciseMetrics, error) {
+			return &models.ExerciseMetrics{TotalSets: 20000, E1RMFormula: formula}, nil
+		},
+	}
+	service := NewSetService(setRepo, exerciseRepo, nil, nil, nil)
+
+	history, err := service.GetExerciseHistory(userID, 1, models.E1RMFormulaBrzycki, &models.SetFilter{}, models.HistoryInclude{Metrics: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if history.Metrics == nil || history.Metrics.TotalSets != 20000 || history.Metrics.E1RMFormula != models.E1RMFormulaBrzycki {
+		t.Errorf("expected metrics from the database, got %+v", history.Metrics)
+	}
+	if len(history.Sets) != 0 || history.NextCursor != nil {
+		t.Errorf("expected no sets, got %d", len(history.Sets))
+	}
 }
 
+func TestGetExerciseHistoryWithSets(t *testing.T) {
+	userID := int64(1)
+	exerciseRepo := &mockExerciseRepository{
+		getByIDAndUserIDFunc: func(id, uid int64) (*models.Exercise, error) {
+			return &models.Exercise{ID: id, UserID: uid, Name: "Bench Press"}, nil
+		},
+	}
+	pages := 0
+	setRepo := &mockSetRepository{
+		listByExerciseIDAndUserIDFunc: func(exerciseID, uid int64, filter *models.SetFilter) ([]*models.Set, string, error) {
+			pages++
+			return []*models.Set{{ID: 1, ExerciseID: exerciseID, Weight: 100, Reps: 5}}, "next", nil
+		},
+		getMetricsFunc: func(exerciseID, uid int64, filter *models.SetFilter, formula models.E1RMFormula) (*models.ExerciseMetrics, error) {
+			return &models.ExerciseMetrics{TotalSets: 20000, E1RMFormula: formula}, nil
+		},
+	}
+	service := NewSetService(setRepo, exerciseRepo, nil, nil, nil)
+
+	filter := &models.SetFilter{ListParams: models.ListParams{Limit: 1}}
+	history, err := service.GetExerciseHistory(userID, 1, models.E1RMFormulaEpley, filter, models.HistoryInclude{Sets: true, Metrics: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pages != 1 || len(history.Sets) != 1 || history.NextCursor == nil {
+		t.Errorf("expected one page of sets to be loaded, got %d loads and %d sets", pages, len(history.Sets))
+	}
+	if history.Metrics == nil || history.Metrics.TotalSets != 20000 {
+		t.Errorf("expected metrics over every matching set from the database, got %+v", history.Metrics)
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+	"phoenix-alliance-be/internal/repository"
+)
+
+// StatsService defines the interface for user-level training statistics
+type StatsService interface {
+	GetSummary(userID int64) (*models.UserSummaryResponse, error)
+}
+
+type statsService struct {
+	workoutRepo repository.WorkoutRepository
+	setRepo     repository.SetRepository
+	recordRepo  repository.PersonalRecordRepository
+	userRepo    repository.UserRepository
+}
+
+// NewStatsService creates a new stats service
+func NewStatsService(
+	workoutRepo repository.WorkoutRepository,
+	setRepo repository.SetRepository,
+	recordRepo repository.PersonalRecordRepository,
+	userRepo repository.UserRepository,
+) StatsService {
+	return &statsService{
+		workoutRepo: workoutRepo,
+		setRepo:     setRepo,
+		recordRepo:  recordRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// GetSummary builds the dashboard overview of a user, with weeks and months in the user's time zone
+func (s *statsService) GetSummary(userID int64) (*models.UserSummaryResponse, error) {
+	loc := userLocation(s.userRepo, userID)
+	now := time.Now().In(loc)
+	thisWeek := models.ProgressBucketWeek.Start(now, loc)
+	thisMonth := models.ProgressBucketMonth.Start(now, loc)
+	trendStart := models.ProgressBucketWeek.Add(thisWeek, 1-models.SummaryTrendWeeks)
+
+	summary := &models.UserSummaryResponse{Timezone: loc.String()}
+
+	days, err := s.workoutRepo.GetTrainingDays(userID, loc)
+	if err != nil {
+		return nil, errors.New("failed to retrieve workouts")
+	}
+	for _, day := range days {
+		summary.TotalWorkouts += day.Workouts
+		if !day.Date.Before(thisWeek) {
+			summary.WorkoutsThisWeek += day.Workouts
+		}
+		if !day.Date.Before(thisMonth) {
+			summary.WorkoutsThisMonth += day.Workouts
+		}
+	}
+	summary.CurrentStreak = weekStreak(days, now, loc)
+
+	summary.VolumeTrend, err = s.setRepo.GetVolumeRollup(userID, trendStart, now, models.ProgressBucketWeek, loc)
+	if err != nil {
+		return nil, errors.New("failed to retrieve volume")
+	}
+
+	summary.TopExercises, err = s.setRepo.GetTopExercisesByVolume(userID, trendStart, now, models.SummaryTopExercises)
+	if err != nil {
+		return nil, errors.New("failed to retrieve volume")
+	}
+
+	records, err := s.recordRepo.GetByUserID(userID, false)
+	if err != nil {
+		return nil, errors.New("failed to retrieve personal records")
+	}
+	if len(records) > models.SummaryRecentRecords {
+		records = records[:models.SummaryRecentRecords]
+	}
+	summary.RecentRecords = make([]*models.PersonalRecordResponse, len(records))
+	for i, record := range records {
+		summary.RecentRecords[i] = record.ToResponse()
+	}
+
+	lastTrained, err := s.setRepo.GetMuscleGroupsLastTrained(userID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve muscle groups")
+	}
+	summary.MuscleGroups = muscleGroupRecency(lastTrained, now, loc)
+
+	return summary, nil
+}
+
+// weekStreak counts the consecutive weeks with a workout up to the current one. The current
+// week does not break the streak before it is over.
+func weekStreak(days []models.TrainingDay, now time.Time, loc *time.Location) int {
+	trained := make(map[int64]bool)
+	for _, day := range days {
+		trained[models.ProgressBucketWeek.Start(day.Date, loc).Unix()] = true
+	}
+
+	week := models.ProgressBucketWeek.Start(now, loc)
+	if !trained[week.Unix()] {
+		week = models.ProgressBucketWeek.Add(week, -1)
+	}
+
+	streak := 0
+	for trained[week.Unix()] {
+		streak++
+		week = models.ProgressBucketWeek.Add(week, -1)
+	}
+	return streak
+}
+
+// muscleGroupRecency reports every muscle group, in catalog order, with the calendar days
+// in loc since it was last trained
+func muscleGroupRecency(lastTrained map[string]time.Time, now time.Time, loc *time.Location) []models.MuscleGroupRecency {
+	today := models.ProgressBucketDay.Start(now, loc)
+
+	recency := make([]models.MuscleGroupRecency, len(models.MuscleGroups))
+	for i, muscle := range models.MuscleGroups {
+		recency[i].Muscle = muscle
+		at, ok := lastTrained[muscle]
+		if !ok {
+			continue
+		}
+		day := models.ProgressBucketDay.Start(at, loc)
+		// Round to whole days so that DST changes do not shift the count
+		days := int(today.Sub(day).Hours()/24 + 0.5)
+		recency[i].LastTrainedAt = &at
+		recency[i].DaysSince = &days
+	}
+	return recency
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+// mockPersonalRecordRepository is a mock implementation of PersonalRecordRepository
+type mockPersonalRecordRepository struct {
+	createFunc             func(record *models.PersonalRecord) error
+	getByUserIDFunc        func(userID int64, currentOnly bool) ([]*models.PersonalRecord, error)
+	replaceForExerciseFunc func(userID, exerciseID int64, records []*models.PersonalRecord) error
+}
+
+func (m *mockPersonalRecordRepository) Create(record *models.PersonalRecord) error {
+	if m.createFunc != nil {
+		return m.createFunc(record)
+	}
+	return nil
+}
+
+func (m *mockPersonalRecordRepository) GetByUserID(userID int64, currentOnly bool) ([]*models.PersonalRecord, error) {
+	if m.getByUserIDFunc != nil {
+		return m.getByUserIDFunc(userID, currentOnly)
+	}
+	return nil, nil
+}
+
+func (m *mockPersonalRecordRepository) GetByExerciseIDAndUserID(exerciseID, userID int64, currentOnly bool) ([]*models.PersonalRecord, error) {
+	return nil, nil
+}
+
+func (m *mockPersonalRecordRepository) DeleteBySetID(setID int64) error {
+	return nil
+}
+
+func (m *mockPersonalRecordRepository) ReplaceForExercise(userID, exerciseID int64, records []*models.PersonalRecord) error {
+	if m.replaceForExerciseFunc != nil {
+		return m.replaceForExerciseFunc(userID, exerciseID, records)
+	}
+	return nil
+}
+
+func TestWeekStreak(t *testing.T) {
+	// Wednesday May 15, 2024
+	now := time.Date(2024, 5, 15, 18, 0, 0, 0, time.UTC)
+	day := func(month time.Month, d int) models.TrainingDay {
+		return models.TrainingDay{Date: time.Date(2024, month, d, 0, 0, 0, 0, time.UTC), Workouts: 1}
+	}
+
+	tests := []struct {
+		name string
+		days []models.TrainingDay
+		want int
+	}{
+		{"no workouts", nil, 0},
+		{"this week only", []models.TrainingDay{day(5, 13)}, 1},
+		{"current week still open", []models.TrainingDay{day(5, 12), day(5, 3), day(4, 22)}, 3},
+		{"gap ends the streak", []models.TrainingDay{day(5, 14), day(5, 8), day(4, 24)}, 2},
+		{"missed last week", []models.TrainingDay{day(5, 1)}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := weekStreak(tt.days, now, time.UTC); got != tt.want {
+				t.Errorf("expected a streak of %d weeks, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetSummary(t *testing.T) {
+	now := time.Now().UTC()
+	today := models.ProgressBucketDay.Start(now, time.UTC)
+	lastYear := today.AddDate(-1, 0, 0)
+
+	workoutRepo := &mockWorkoutRepository{
+		getTrainingDaysFunc: func(userID int64, loc *time.Location) ([]models.TrainingDay, error) {
+			return []models.TrainingDay{{Date: today, Workouts: 2}, {Date: lastYear, Workouts: 1}}, nil
+		},
+	}
+	var trendFrom time.Time
+	setRepo := &mockSetRepository{
+		getVolumeRollupFunc: func(userID int64, from, to time.Time, bucket models.ProgressBucket, loc *time.Location) ([]models.VolumeDataPoint, error) {
+			trendFrom = from
+			return []models.VolumeDataPoint{}, nil
+		},
+		getMuscleGroupsLastTrainedFunc: func(userID int64) (map[string]time.Time, error) {
+			return map[string]time.Time{"chest": now.AddDate(0, 0, -3)}, nil
+		},
+	}
+	recordRepo := &mockPersonalRecordRepository{
+		getByUserIDFunc: func(userID int64, currentOnly bool) ([]*models.PersonalRecord, error) {
+			records := make([]*models.PersonalRecord, 8)
+			for i := range records {
+				records[i] = &models.PersonalRecord{ID: int64(i + 1)}
+			}
+			return records, nil
+		},
+	}
+
+	// The user is not found, so the summary falls back to UTC
+	service := NewStatsService(workoutRepo, setRepo, recordRepo, &mockUserRepository{})
+
+	summary, err := service.GetSummary(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.WorkoutsThisWeek != 2 || summary.WorkoutsThisMonth != 2 || summary.TotalWorkouts != 3 || summary.CurrentStreak != 1 {
+		t.Errorf("unexpected workout counts %+v", summary)
+	}
+	if weeks := today.Sub(trendFrom).Hours() / 24 / 7; weeks < models.SummaryTrendWeeks-1 || weeks > models.SummaryTrendWeeks {
+		t.Errorf("expected the trend to cover %d weeks, starts %v", models.SummaryTrendWeeks, trendFrom)
+	}
+	if len(summary.RecentRecords) != models.SummaryRecentRecords || summary.RecentRecords[0].ID != 1 {
+		t.Errorf("expected the %d most recent records, got %d", models.SummaryRecentRecords, len(summary.RecentRecords))
+	}
+	if len(summary.MuscleGroups) != len(models.MuscleGroups) {
+		t.Fatalf("expected every muscle group, got %d", len(summary.MuscleGroups))
+	}
+	for _, muscle := range summary.MuscleGroups {
+		switch {
+		case muscle.Muscle == "chest" && (muscle.DaysSince == nil || *muscle.DaysSince != 3):
+			t.Errorf("expected chest trained 3 days ago, got %v", muscle.DaysSince)
+		case muscle.Muscle != "chest" && muscle.LastTrainedAt != nil:
+			t.Errorf("expected %s never trained", muscle.Muscle)
+		}
+	}
+}
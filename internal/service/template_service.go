@@ -0,0 +1,242 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"phoenix-alliance-be/internal/models"
+	"phoenix-alliance-be/internal/repository"
+)
+
+// plateIncrement is the smallest load jump used when prescribing weights (kg)
+const plateIncrement = 2.5
+
+// WorkoutTemplateService defines the interface for workout template business logic
+type WorkoutTemplateService interface {
+	CreateTemplate(userID int64, req *models.WorkoutTemplateCreateRequest) (*models.WorkoutTemplateResponse, error)
+	GetTemplates(userID int64) ([]*models.WorkoutTemplateResponse, error)
+	GetTemplateByID(userID, templateID int64) (*models.WorkoutTemplateResponse, error)
+	UpdateTemplate(userID, templateID int64, req *models.WorkoutTemplateUpdateRequest) (*models.WorkoutTemplateResponse, error)
+	DeleteTemplate(userID, templateID int64) error
+	StartTemplate(userID, templateID int64, req *models.TemplateStartRequest) (*models.StartedWorkoutResponse, error)
+	GetPlannedSets(userID, workoutID int64) ([]*models.PlannedSet, error)
+}
+
+type workoutTemplateService struct {
+	templateRepo   repository.WorkoutTemplateRepository
+	plannedSetRepo repository.PlannedSetRepository
+	exerciseRepo   repository.ExerciseRepository
+	workoutRepo    repository.WorkoutRepository
+	setRepo        repository.SetRepository
+}
+
+// NewWorkoutTemplateService creates a new workout template service
+func NewWorkoutTemplateService(
+	templateRepo repository.WorkoutTemplateRepository,
+	plannedSetRepo repository.PlannedSetRepository,
+	exerciseRepo repository.ExerciseRepository,
+	workoutRepo repository.WorkoutRepository,
+	setRepo repository.SetRepository,
+) WorkoutTemplateService {
+	return &workoutTemplateService{
+		templateRepo:   templateRepo,
+		plannedSetRepo: plannedSetRepo,
+		exerciseRepo:   exerciseRepo,
+		workoutRepo:    workoutRepo,
+		setRepo:        setRepo,
+	}
+}
+
+// CreateTemplate creates a new workout template for a user
+func (s *workoutTemplateService) CreateTemplate(userID int64, req *models.WorkoutTemplateCreateRequest) (*models.WorkoutTemplateResponse, error) {
+	exercises, err := s.buildTemplateExercises(userID, req.Exercises)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &models.WorkoutTemplate{
+		UserID:    userID,
+		Name:      req.Name,
+		Notes:     req.Notes,
+		Exercises: exercises,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.templateRepo.Create(template); err != nil {
+		return nil, errors.New("failed to create template")
+	}
+
+	return template.ToResponse(), nil
+}
+
+// GetTemplates retrieves all templates for a user
+func (s *workoutTemplateService) GetTemplates(userID int64) ([]*models.WorkoutTemplateResponse, error) {
+	templates, err := s.templateRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve templates")
+	}
+
+	responses := make([]*models.WorkoutTemplateResponse, len(templates))
+	for i, template := range templates {
+		responses[i] = template.ToResponse()
+	}
+
+	return responses, nil
+}
+
+// GetTemplateByID retrieves a template by ID for a user
+func (s *workoutTemplateService) GetTemplateByID(userID, templateID int64) (*models.WorkoutTemplateResponse, error) {
+	template, err := s.templateRepo.GetByIDAndUserID(templateID, userID)
+	if err != nil {
+		return nil, errors.New("template not found")
+	}
+
+	return template.ToResponse(), nil
+}
+
+// UpdateTemplate updates an existing template for a user
+func (s *workoutTemplateService) UpdateTemplate(userID, templateID int64, req *models.WorkoutTemplateUpdateRequest) (*models.WorkoutTemplateResponse, error) {
+	// Verify template exists and belongs to the user
+	template, err := s.templateRepo.GetByIDAndUserID(templateID, userID)
+	if err != nil {
+		return nil, errors.New("template not found")
+	}
+
+	exercises, err := s.buildTemplateExercises(userID, req.Exercises)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Name = req.Name
+	template.Notes = req.Notes
+	template.Exercises = exercises
+
+	if err := s.templateRepo.Update(template); err != nil {
+		return nil, errors.New("failed to update template")
+	}
+
+	return template.ToResponse(), nil
+}
+
+// DeleteTemplate performs a soft delete on a template for a user
+func (s *workoutTemplateService) DeleteTemplate(userID, templateID int64) error {
+	// Verify template exists and belongs to user
+	if _, err := s.templateRepo.GetByIDAndUserID(templateID, userID); err != nil {
+		return errors.New("template not found")
+	}
+	// Soft delete
+	if err := s.templateRepo.Delete(templateID, userID); err != nil {
+		if err.Error() == "template not found" {
+			return errors.New("template not found")
+		}
+		return errors.New("failed to delete template")
+	}
+
+	return nil
+}
+
+// StartTemplate creates a workout from a template with one planned set slot per target set
+func (s *workoutTemplateService) StartTemplate(userID, templateID int64, req *models.TemplateStartRequest) (*models.StartedWorkoutResponse, error) {
+	template, err := s.templateRepo.GetByIDAndUserID(templateID, userID)
+	if err != nil {
+		return nil, errors.New("template not found")
+	}
+
+	name := template.Name
+	if req != nil && req.Name != nil && *req.Name != "" {
+		name = *req.Name
+	}
+
+	workout := newWorkout(userID, &models.WorkoutCreateRequest{Name: name})
+	now := workout.CreatedAt
+	plannedSets := []*models.PlannedSet{}
+	for _, exercise := range template.Exercises {
+		targetWeight := exercise.TargetWeight
+		if targetWeight == nil && exercise.TargetPercentE1RM != nil {
+			targetWeight = s.weightFromE1RM(userID, exercise.ExerciseID, *exercise.TargetPercentE1RM)
+		}
+
+		for i := 0; i < exercise.TargetSets; i++ {
+			plannedSets = append(plannedSets, &models.PlannedSet{
+				ExerciseID:   exercise.ExerciseID,
+				Position:     len(plannedSets),
+				TargetReps:   exercise.TargetReps,
+				TargetWeight: targetWeight,
+				RestSeconds:  exercise.RestSeconds,
+				CreatedAt:    now,
+			})
+		}
+	}
+
+	// The workout is only stored along with its planned sets
+	if err := s.workoutRepo.CreateWithPlannedSets(workout, plannedSets); err != nil {
+		return nil, errors.New("failed to create workout")
+	}
+
+	return &models.StartedWorkoutResponse{
+		Workout:     workout.ToResponse(),
+		TemplateID:  template.ID,
+		PlannedSets: plannedSets,
+	}, nil
+}
+
+// GetPlannedSets retrieves the planned set slots of a workout for a user
+func (s *workoutTemplateService) GetPlannedSets(userID, workoutID int64) ([]*models.PlannedSet, error) {
+	// Verify workout belongs to user
+	if _, err := s.workoutRepo.GetByIDAndUserID(workoutID, userID); err != nil {
+		return nil, errors.New("workout not found")
+	}
+
+	plannedSets, err := s.plannedSetRepo.GetByWorkoutID(workoutID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve planned sets")
+	}
+	if plannedSets == nil {
+		plannedSets = []*models.PlannedSet{}
+	}
+
+	return plannedSets, nil
+}
+
+// buildTemplateExercises verifies exercise ownership and converts requests to template exercises
+func (s *workoutTemplateService) buildTemplateExercises(userID int64, reqs []models.TemplateExerciseRequest) ([]*models.TemplateExercise, error) {
+	verified := make(map[int64]bool)
+	exercises := make([]*models.TemplateExercise, len(reqs))
+	for i, req := range reqs {
+		if !verified[req.ExerciseID] {
+			if _, err := s.exerciseRepo.GetByIDAndUserID(req.ExerciseID, userID); err != nil {
+				return nil, errors.New("exercise not found")
+			}
+			verified[req.ExerciseID] = true
+		}
+
+		exercises[i] = &models.TemplateExercise{
+			ExerciseID:        req.ExerciseID,
+			Position:          i,
+			TargetSets:        req.TargetSets,
+			TargetReps:        req.TargetReps,
+			TargetWeight:      req.TargetWeight,
+			TargetPercentE1RM: req.TargetPercentE1RM,
+			RestSeconds:       req.RestSeconds,
+		}
+	}
+
+	return exercises, nil
+}
+
+// weightFromE1RM prescribes a load as a percentage of the user's best e1RM.
+// Returns nil when the user has no history for the exercise.
+func (s *workoutTemplateService) weightFromE1RM(userID, exerciseID int64, percent float64) *float64 {
+	sets, err := s.setRepo.GetByExerciseIDAndUserID(exerciseID, userID)
+	if err != nil || len(sets) == 0 {
+		return nil
+	}
+
+	e1rm := bestOneRepMax(withoutWarmups(sets), models.E1RMFormulaEpley)
+	if e1rm <= 0 {
+		return nil
+	}
+
+	weight := roundToIncrement(e1rm*percent/100, plateIncrement)
+	return &weight
+}
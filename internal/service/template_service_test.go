@@ -0,0 +1,241 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"phoenix-alliance-be/internal/models"
+)
+
+// mockWorkoutTemplateRepository is a mock implementation of WorkoutTemplateRepository
+type mockWorkoutTemplateRepository struct {
+	createFunc           func(template *models.WorkoutTemplate) error
+	getByIDAndUserIDFunc func(id, userID int64) (*models.WorkoutTemplate, error)
+	getByUserIDFunc      func(userID int64) ([]*models.WorkoutTemplate, error)
+	updateFunc           func(template *models.WorkoutTemplate) error
+	deleteFunc           func(id, userID int64) error
+}
+
+func (m *mockWorkoutTemplateRepository) Create(template *models.WorkoutTemplate) error {
+	if m.createFunc != nil {
+		return m.createFunc(template)
+	}
+	return nil
+}
+
+func (m *mockWorkoutTemplateRepository) GetByIDAndUserID(id, userID int64) (*models.WorkoutTemplate, error) {
+	if m.getByIDAndUserIDFunc != nil {
+		return m.getByIDAndUserIDFunc(id, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockWorkoutTemplateRepository) GetByUserID(userID int64) ([]*models.WorkoutTemplate, error) {
+	if m.getByUserIDFunc != nil {
+		return m.getByUserIDFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *mockWorkoutTemplateRepository) Update(template *models.WorkoutTemplate) error {
+	if m.updateFunc != nil {
+		return m.updateFunc(template)
+	}
+	return nil
+}
+
+func (m *mockWorkoutTemplateRepository) Delete(id, userID int64) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(id, userID)
+	}
+	return nil
+}
+
+// mockPlannedSetRepository is a mock implementation of PlannedSetRepository
+type mockPlannedSetRepository struct {
+	createBatchFunc    func(plannedSets []*models.PlannedSet) error
+	getByWorkoutIDFunc func(workoutID int64) ([]*models.PlannedSet, error)
+}
+
+func (m *mockPlannedSetRepository) CreateBatch(plannedSets []*models.PlannedSet) error {
+	if m.createBatchFunc != nil {
+		return m.createBatchFunc(plannedSets)
+	}
+	return nil
+}
+
+func (m *mockPlannedSetRepository) GetByWorkoutID(workoutID int64) ([]*models.PlannedSet, error) {
+	if m.getByWorkoutIDFunc != nil {
+		return m.getByWorkoutIDFunc(workoutID)
+	}
+	return nil, nil
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func TestCreateTemplate(t *testing.T) {
+	userID := int64(1)
+
+	t.Run("exercise not owned", func(t *testing.T) {
+		exerciseRepo := &mockExerciseRepository{
+			getByIDAndUserIDFunc: func(id, uid int64) (*models.Exercise, error) {
+				return nil, errors.New("exercise not found")
+			},
+		}
+		templateRepo := &mockWorkoutTemplateRepository{
+			createFunc: func(template *models.WorkoutTemplate) error {
+				t.Error("did not expect template to be created")
+				return nil
+			},
+		}
+
+		svc := NewWorkoutTemplateService(templateRepo, &mockPlannedSetRepository{}, exerciseRepo, &mockWorkoutRepository{}, &mockSetRepository{})
+		_, err := svc.CreateTemplate(userID, &models.WorkoutTemplateCreateRequest{
+			Name:      "Push A",
+			Exercises: []models.TemplateExerciseRequest{{ExerciseID: 9, TargetSets: 3, TargetReps: 5}},
+		})
+
+		if err == nil || err.Error() != "exercise not found" {
+			t.Fatalf("expected exercise not found error, got %v", err)
+		}
+	})
+
+	t.Run("exercises keep request order", func(t *testing.T) {
+		exerciseRepo := &mockExerciseRepository{
+			getByIDAndUserIDFunc: func(id, uid int64) (*models.Exercise, error) {
+				return &models.Exercise{ID: id, UserID: uid}, nil
+			},
+		}
+		templateRepo := &mockWorkoutTemplateRepository{
+			createFunc: func(template *models.WorkoutTemplate) error {
+				template.ID = 3
+				return nil
+			},
+		}
+
+		svc := NewWorkoutTemplateService(templateRepo, &mockPlannedSetRepository{}, exerciseRepo, &mockWorkoutRepository{}, &mockSetRepository{})
+		res, err := svc.CreateTemplate(userID, &models.WorkoutTemplateCreateRequest{
+			Name: "Push A",
+			Exercises: []models.TemplateExerciseRequest{
+				{ExerciseID: 7, TargetSets: 3, TargetReps: 5},
+				{ExerciseID: 4, TargetSets: 2, TargetReps: 10},
+			},
+		})
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(res.Exercises) != 2 || res.Exercises[0].ExerciseID != 7 || res.Exercises[1].Position != 1 {
+			t.Errorf("unexpected exercises %+v", res.Exercises)
+		}
+	})
+}
+
+func TestStartTemplate(t *testing.T) {
+	userID := int64(1)
+	templateID := int64(3)
+
+	template := &models.WorkoutTemplate{
+		ID:     templateID,
+		UserID: userID,
+		Name:   "Push A",
+		Exercises: []*models.TemplateExercise{
+			{ExerciseID: 7, TargetSets: 3, TargetReps: 5, TargetPercentE1RM: floatPtr(80), RestSeconds: intPtr(180)},
+			{ExerciseID: 4, TargetSets: 2, TargetReps: 10, TargetWeight: floatPtr(20)},
+		},
+	}
+
+	newService := func(plannedSetRepo *mockPlannedSetRepository, workoutRepo *mockWorkoutRepository) WorkoutTemplateService {
+		templateRepo := &mockWorkoutTemplateRepository{
+			getByIDAndUserIDFunc: func(id, uid int64) (*models.WorkoutTemplate, error) {
+				if id != templateID || uid != userID {
+					return nil, errors.New("template not found")
+				}
+				return template, nil
+			},
+		}
+		setRepo := &mockSetRepository{
+			getByExerciseIDAndUserIDFunc: func(exerciseID, uid int64) ([]*models.Set, error) {
+				if exerciseID == 7 {
+					// Epley e1RM: 100 * (1 + 5/30) = 116.67
+					return []*models.Set{{Weight: 100, Reps: 5}}, nil
+				}
+				return nil, nil
+			},
+		}
+		return NewWorkoutTemplateService(templateRepo, plannedSetRepo, &mockExerciseRepository{}, workoutRepo, setRepo)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		workoutRepo := &mockWorkoutRepository{
+			createWithPlannedSetsFunc: func(workout *models.Workout, plannedSets []*models.PlannedSet) error {
+				if workout.Name != "Push A" {
+					t.Errorf("expected workout name Push A, got %s", workout.Name)
+				}
+				workout.ID = 50
+				for _, plannedSet := range plannedSets {
+					plannedSet.WorkoutID = workout.ID
+				}
+				return nil
+			},
+		}
+		svc := newService(&mockPlannedSetRepository{}, workoutRepo)
+
+		res, err := svc.StartTemplate(userID, templateID, &models.TemplateStartRequest{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if res.Workout.ID != 50 {
+			t.Errorf("expected workout ID 50, got %d", res.Workout.ID)
+		}
+		if len(res.PlannedSets) != 5 {
+			t.Fatalf("expected 5 planned sets, got %d", len(res.PlannedSets))
+		}
+		if res.PlannedSets[0].WorkoutID != 50 {
+			t.Errorf("expected planned sets of workout 50, got %d", res.PlannedSets[0].WorkoutID)
+		}
+
+		first := res.PlannedSets[0]
+		// 80% of 116.67 = 93.33, rounded to 92.5
+		if first.TargetWeight == nil || *first.TargetWeight != 92.5 {
+			t.Errorf("expected target weight 92.5, got %v", first.TargetWeight)
+		}
+		last := res.PlannedSets[4]
+		if last.Position != 4 || last.ExerciseID != 4 || *last.TargetWeight != 20 {
+			t.Errorf("unexpected last planned set %+v", last)
+		}
+	})
+
+	t.Run("template not found", func(t *testing.T) {
+		svc := newService(&mockPlannedSetRepository{}, &mockWorkoutRepository{})
+
+		_, err := svc.StartTemplate(userID, 99, nil)
+		if err == nil || err.Error() != "template not found" {
+			t.Fatalf("expected template not found error, got %v", err)
+		}
+	})
+
+	t.Run("planned sets failure creates no workout", func(t *testing.T) {
+		workoutRepo := &mockWorkoutRepository{
+			createFunc: func(workout *models.Workout) error {
+				t.Error("expected the workout to be created with its planned sets")
+				return nil
+			},
+			createWithPlannedSetsFunc: func(workout *models.Workout, plannedSets []*models.PlannedSet) error {
+				return errors.New("db error")
+			},
+			deleteFunc: func(id, uid int64) error {
+				t.Error("expected no workout to be deleted")
+				return nil
+			},
+		}
+		svc := newService(&mockPlannedSetRepository{}, workoutRepo)
+
+		_, err := svc.StartTemplate(userID, templateID, nil)
+		if err == nil || err.Error() != "failed to create workout" {
+			t.Fatalf("expected failed to create workout error, got %v", err)
+		}
+	})
+}
@@ -12,16 +12,26 @@ import (
 // UserService defines the interface for user business logic
 type UserService interface {
 	CreateUser(req *models.UserCreateRequest) (*models.UserResponse, error)
-	LoginUser(req *models.UserLoginRequest, jwtSecret string, jwtExpiry int) (string, *models.UserResponse, error)
+	LoginUser(req *models.UserLoginRequest) (*models.UserResponse, error)
+	GetProfile(userID int64) (*models.UserResponse, error)
+	UpdateProfile(userID int64, req *models.UserProfileUpdateRequest) (*models.UserResponse, error)
+	ChangePassword(userID int64, req *models.PasswordChangeRequest) error
+	ChangeEmail(userID int64, req *models.EmailChangeRequest) (*models.UserResponse, error)
+	ScheduleDeletion(userID int64, req *models.AccountDeleteRequest) (*models.UserResponse, error)
+	CancelDeletion(userID int64) (*models.UserResponse, error)
+	DeleteAccount(userID int64) error
+	PurgeDeleted() (int64, error)
 }
 
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo      repository.UserRepository
+	deletionGrace time.Duration
 }
 
-// NewUserService creates a new user service
-func NewUserService(userRepo repository.UserRepository) UserService {
-	return &userService{userRepo: userRepo}
+// NewUserService creates a new user service. Deleted accounts can be restored for deletionGrace
+// before their data is purged.
+func NewUserService(userRepo repository.UserRepository, deletionGrace time.Duration) UserService {
+	return &userService{userRepo: userRepo, deletionGrace: deletionGrace}
 }
 
 // CreateUser creates a new user
@@ -42,8 +52,12 @@ func (s *userService) CreateUser(req *models.UserCreateRequest) (*models.UserRes
 	user := &models.User{
 		Email:     req.Email,
 		Password:  hashedPassword,
+		Timezone:  models.DefaultTimezone,
 		CreatedAt: time.Now(),
 	}
+	if req.Timezone != "" {
+		user.Timezone = req.Timezone
+	}
 
 	if err := s.userRepo.Create(user); err != nil {
 		return nil, errors.New("failed to create user")
@@ -52,25 +66,156 @@ func (s *userService) CreateUser(req *models.UserCreateRequest) (*models.UserRes
 	return user.ToResponse(), nil
 }
 
-// LoginUser authenticates a user and returns a JWT token
-func (s *userService) LoginUser(req *models.UserLoginRequest, jwtSecret string, jwtExpiry int) (string, *models.UserResponse, error) {
+// LoginUser checks the credentials of a user; sessions are started by the session service
+func (s *userService) LoginUser(req *models.UserLoginRequest) (*models.UserResponse, error) {
 	// Get user by email
 	user, err := s.userRepo.GetByEmail(req.Email)
 	if err != nil {
-		return "", nil, errors.New("invalid email or password")
+		return nil, errors.New("invalid email or password")
 	}
 
 	// Check password
 	if !auth.CheckPasswordHash(req.Password, user.Password) {
-		return "", nil, errors.New("invalid email or password")
+		return nil, errors.New("invalid email or password")
+	}
+
+	return user.ToResponse(), nil
+}
+
+// GetProfile retrieves the profile of a user
+func (s *userService) GetProfile(userID int64) (*models.UserResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	return user.ToResponse(), nil
+}
+
+// UpdateProfile updates the profile settings of a user
+func (s *userService) UpdateProfile(userID int64, req *models.UserProfileUpdateRequest) (*models.UserResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if req.Timezone != nil {
+		user.Timezone = *req.Timezone
+	}
+
+	if err := s.userRepo.UpdateProfile(user); err != nil {
+		return nil, errors.New("failed to update profile")
+	}
+
+	return user.ToResponse(), nil
+}
+
+// ChangePassword replaces the password of a user who knows the current one
+func (s *userService) ChangePassword(userID int64, req *models.PasswordChangeRequest) error {
+	user, err := s.authenticate(userID, req.CurrentPassword)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := auth.HashPassword(req.NewPassword)
+	if err != nil {
+		return errors.New("failed to hash password")
+	}
+	if err := s.userRepo.UpdatePassword(user.ID, hashedPassword); err != nil {
+		return errors.New("failed to change password")
+	}
+
+	return nil
+}
+
+// ChangeEmail changes the email address of a user who knows their password. The new address
+// is unverified until the user verifies it.
+func (s *userService) ChangeEmail(userID int64, req *models.EmailChangeRequest) (*models.UserResponse, error) {
+	user, err := s.authenticate(userID, req.Password)
+	if err != nil {
+		return nil, err
+	}
+	if req.Email == user.Email {
+		return user.ToResponse(), nil
+	}
+
+	if existing, _ := s.userRepo.GetByEmail(req.Email); existing != nil {
+		return nil, errors.New("user with this email already exists")
+	}
+
+	user.Email = req.Email
+	if err := s.userRepo.UpdateEmail(user); err != nil {
+		if err.Error() == "user with this email already exists" {
+			return nil, err
+		}
+		return nil, errors.New("failed to change email")
 	}
 
-	// Generate JWT token
-	token, err := auth.GenerateToken(user.ID, user.Email, jwtSecret, jwtExpiry)
+	return user.ToResponse(), nil
+}
+
+// ScheduleDeletion deletes the account of a user who knows their password once the grace period
+// ends. Until then, the user can log in and restore the account.
+func (s *userService) ScheduleDeletion(userID int64, req *models.AccountDeleteRequest) (*models.UserResponse, error) {
+	user, err := s.authenticate(userID, req.Password)
 	if err != nil {
-		return "", nil, errors.New("failed to generate token")
+		return nil, err
+	}
+
+	if user.DeletionScheduledAt == nil {
+		at := time.Now().Add(s.deletionGrace)
+		if err := s.userRepo.ScheduleDeletion(user.ID, at); err != nil {
+			return nil, errors.New("failed to delete account")
+		}
+		user.DeletionScheduledAt = &at
 	}
 
-	return token, user.ToResponse(), nil
+	return user.ToResponse(), nil
 }
 
+// CancelDeletion restores an account whose deletion is scheduled
+func (s *userService) CancelDeletion(userID int64) (*models.UserResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.DeletionScheduledAt == nil {
+		return nil, errors.New("account deletion not scheduled")
+	}
+
+	if err := s.userRepo.CancelDeletion(user.ID); err != nil {
+		return nil, errors.New("failed to restore account")
+	}
+	user.DeletionScheduledAt = nil
+
+	return user.ToResponse(), nil
+}
+
+// DeleteAccount deletes a user and all their data immediately, without a grace period
+func (s *userService) DeleteAccount(userID int64) error {
+	if err := s.userRepo.Delete(userID); err != nil {
+		if err.Error() == "user not found" {
+			return err
+		}
+		return errors.New("failed to delete account")
+	}
+	return nil
+}
+
+// PurgeDeleted deletes the accounts whose grace period has ended, with all their data, and
+// returns how many were deleted
+func (s *userService) PurgeDeleted() (int64, error) {
+	return s.userRepo.DeleteScheduled(time.Now())
+}
+
+// authenticate returns a user after checking their current password
+func (s *userService) authenticate(userID int64, password string) (*models.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if !auth.CheckPasswordHash(password, user.Password) {
+		return nil, errors.New("current password is incorrect")
+	}
+	return user, nil
+}
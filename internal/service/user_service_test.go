@@ -0,0 +1,114 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"phoenix-alliance-be/internal/auth"
+	"phoenix-alliance-be/internal/models"
+)
+
+// newTestUserRepository returns a user repository over one in-memory user and another address
+// that is taken
+func newTestUserRepository(user *models.User) *mockUserRepository {
+	return &mockUserRepository{
+		getByIDFunc: func(id int64) (*models.User, error) {
+			copied := *user
+			return &copied, nil
+		},
+		getByEmailFunc: func(email string) (*models.User, error) {
+			if email == "taken@example.com" {
+				return &models.User{ID: 2, Email: email}, nil
+			}
+			return nil, errors.New("user not found")
+		},
+		updatePasswordFunc: func(userID int64, passwordHash string) error {
+			user.Password = passwordHash
+			return nil
+		},
+		updateEmailFunc: func(u *models.User) error {
+			user.Email, user.VerifiedAt, u.VerifiedAt = u.Email, nil, nil
+			return nil
+		},
+		scheduleDeletionFunc: func(userID int64, at time.Time) error {
+			user.DeletionScheduledAt = &at
+			return nil
+		},
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	hash, _ := auth.HashPassword("old-password")
+	user := &models.User{ID: 1, Email: "user@example.com", Password: hash}
+	service := NewUserService(newTestUserRepository(user), 30*24*time.Hour)
+
+	err := service.ChangePassword(1, &models.PasswordChangeRequest{CurrentPassword: "wrong-password", NewPassword: "new-password"})
+	if err == nil || err.Error() != "current password is incorrect" {
+		t.Errorf("expected a wrong current password to be rejected, got %v", err)
+	}
+
+	if err := service.ChangePassword(1, &models.PasswordChangeRequest{CurrentPassword: "old-password", NewPassword: "new-password"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !auth.CheckPasswordHash("new-password", user.Password) {
+		t.Error("expected the password to be changed")
+	}
+}
+
+func TestChangeEmail(t *testing.T) {
+	hash, _ := auth.HashPassword("password")
+	verifiedAt := time.Now()
+	user := &models.User{ID: 1, Email: "user@example.com", Password: hash, VerifiedAt: &verifiedAt}
+	service := NewUserService(newTestUserRepository(user), 30*24*time.Hour)
+
+	if _, err := service.ChangeEmail(1, &models.EmailChangeRequest{Email: "new@example.com", Password: "wrong"}); err == nil {
+		t.Error("expected a wrong password to be rejected")
+	}
+	if _, err := service.ChangeEmail(1, &models.EmailChangeRequest{Email: "taken@example.com", Password: "password"}); err == nil || err.Error() != "user with this email already exists" {
+		t.Errorf("expected a taken address to be rejected, got %v", err)
+	}
+
+	changed, err := service.ChangeEmail(1, &models.EmailChangeRequest{Email: "new@example.com", Password: "password"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if changed.Email != "new@example.com" || changed.VerifiedAt != nil || user.Email != "new@example.com" {
+		t.Errorf("expected an unverified new address, got %+v", changed)
+	}
+}
+
+func TestScheduleDeletion(t *testing.T) {
+	hash, _ := auth.HashPassword("password")
+	user := &models.User{ID: 1, Email: "user@example.com", Password: hash}
+	service := NewUserService(newTestUserRepository(user), 30*24*time.Hour)
+
+	if _, err := service.CancelDeletion(1); err == nil || err.Error() != "account deletion not scheduled" {
+		t.Errorf("expected nothing to restore, got %v", err)
+	}
+	if _, err := service.ScheduleDeletion(1, &models.AccountDeleteRequest{Password: "wrong"}); err == nil {
+		t.Error("expected a wrong password to be rejected")
+	}
+
+	scheduled, err := service.ScheduleDeletion(1, &models.AccountDeleteRequest{Password: "password"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if scheduled.DeletionScheduledAt == nil || time.Until(*scheduled.DeletionScheduledAt) < 29*24*time.Hour {
+		t.Errorf("expected the deletion after the grace period, got %v", scheduled.DeletionScheduledAt)
+	}
+
+	// Deleting again keeps the first date
+	again, _ := service.ScheduleDeletion(1, &models.AccountDeleteRequest{Password: "password"})
+	if !again.DeletionScheduledAt.Equal(*scheduled.DeletionScheduledAt) {
+		t.Errorf("expected the date to stay %v, got %v", scheduled.DeletionScheduledAt, again.DeletionScheduledAt)
+	}
+
+	restored, err := service.CancelDeletion(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if restored.DeletionScheduledAt != nil {
+		t.Error("expected the deletion to be cancelled")
+	}
+}
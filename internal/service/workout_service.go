@@ -12,11 +12,16 @@ import (
 type WorkoutService interface {
 	CreateWorkout(userID int64, req *models.WorkoutCreateRequest) (*models.WorkoutResponse, error)
 	GetWorkoutByID(userID, workoutID int64) (*models.WorkoutResponse, error)
-	GetWorkouts(userID int64) ([]*models.WorkoutResponse, error)
+	GetWorkouts(userID int64, filter *models.WorkoutFilter) (*models.ListResponse[*models.WorkoutResponse], error)
 	UpdateWorkout(userID, workoutID int64, req *models.WorkoutUpdateRequest) (*models.WorkoutResponse, error)
 	DeleteWorkout(userID, workoutID int64) error
+	StartWorkout(userID, workoutID int64, req *models.WorkoutStartRequest) (*models.WorkoutResponse, error)
+	FinishWorkout(userID, workoutID int64, req *models.WorkoutFinishRequest) (*models.WorkoutResponse, error)
 }
 
+// invalidTransitionPrefix marks errors caused by a session that cannot move to the requested state
+const invalidTransitionPrefix = "invalid transition: "
+
 type workoutService struct {
 	workoutRepo repository.WorkoutRepository
 }
@@ -28,12 +33,7 @@ func NewWorkoutService(workoutRepo repository.WorkoutRepository) WorkoutService
 
 // CreateWorkout creates a new workout for a user
 func (s *workoutService) CreateWorkout(userID int64, req *models.WorkoutCreateRequest) (*models.WorkoutResponse, error) {
-	workout := &models.Workout{
-		UserID:    userID,
-		Name:      req.Name,
-		CreatedAt: time.Now(),
-	}
-
+	workout := newWorkout(userID, req)
 	if err := s.workoutRepo.Create(workout); err != nil {
 		return nil, errors.New("failed to create workout")
 	}
@@ -41,19 +41,48 @@ func (s *workoutService) CreateWorkout(userID int64, req *models.WorkoutCreateRe
 	return workout.ToResponse(), nil
 }
 
-// GetWorkoutByID retrieves a workout by ID for a user
+// newWorkout returns a planned workout for a user, performed now unless the request says otherwise
+func newWorkout(userID int64, req *models.WorkoutCreateRequest) *models.Workout {
+	now := time.Now()
+	workout := &models.Workout{
+		UserID:      userID,
+		Name:        req.Name,
+		Status:      models.WorkoutStatusPlanned,
+		PerformedAt: now,
+		CreatedAt:   now,
+	}
+	if req.PerformedAt != nil {
+		workout.PerformedAt = *req.PerformedAt
+	}
+	return workout
+}
+
+// GetWorkoutByID retrieves a workout by ID for a user, with the volume lifted and,
+// for finished sessions, the density (volume per minute)
 func (s *workoutService) GetWorkoutByID(userID, workoutID int64) (*models.WorkoutResponse, error) {
 	workout, err := s.workoutRepo.GetByIDAndUserID(workoutID, userID)
 	if err != nil {
 		return nil, errors.New("workout not found")
 	}
 
-	return workout.ToResponse(), nil
+	volume, err := s.workoutRepo.GetVolume(workoutID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve workout volume")
+	}
+
+	response := workout.ToResponse()
+	response.Volume = &volume
+	if duration := response.DurationSeconds; duration != nil && *duration > 0 {
+		density := volume / (float64(*duration) / 60)
+		response.Density = &density
+	}
+
+	return response, nil
 }
 
-// GetWorkouts retrieves all workouts for a user
-func (s *workoutService) GetWorkouts(userID int64) ([]*models.WorkoutResponse, error) {
-	workouts, err := s.workoutRepo.GetByUserID(userID)
+// GetWorkouts retrieves a page of workouts for a user
+func (s *workoutService) GetWorkouts(userID int64, filter *models.WorkoutFilter) (*models.ListResponse[*models.WorkoutResponse], error) {
+	workouts, next, err := s.workoutRepo.List(userID, filter)
 	if err != nil {
 		return nil, errors.New("failed to retrieve workouts")
 	}
@@ -61,7 +90,7 @@ func (s *workoutService) GetWorkouts(userID int64) ([]*models.WorkoutResponse, e
 	for i, workout := range workouts {
 		responses[i] = workout.ToResponse()
 	}
-	return responses, nil
+	return models.NewListResponse(responses, next), nil
 }
 
 // UpdateWorkout updates an existing workout for a user
@@ -72,8 +101,11 @@ func (s *workoutService) UpdateWorkout(userID, workoutID int64, req *models.Work
 		return nil, errors.New("workout not found")
 	}
 
-	// Update the workout name
+	// Update the workout name and, if given, when it was performed
 	workout.Name = req.Name
+	if req.PerformedAt != nil {
+		workout.PerformedAt = *req.PerformedAt
+	}
 
 	if err := s.workoutRepo.Update(workout); err != nil {
 		return nil, errors.New("failed to update workout")
@@ -98,3 +130,66 @@ func (s *workoutService) DeleteWorkout(userID, workoutID int64) error {
 
 	return nil
 }
+
+// StartWorkout starts a planned session, recording the start time and bodyweight
+func (s *workoutService) StartWorkout(userID, workoutID int64, req *models.WorkoutStartRequest) (*models.WorkoutResponse, error) {
+	workout, err := s.workoutRepo.GetByIDAndUserID(workoutID, userID)
+	if err != nil {
+		return nil, errors.New("workout not found")
+	}
+
+	if !workout.Status.CanTransitionTo(models.WorkoutStatusInProgress) {
+		return nil, errors.New(invalidTransitionPrefix + "only planned workouts can be started, this one is " + string(workout.Status))
+	}
+
+	// A session is performed when it starts, not when it was planned
+	now := time.Now()
+	workout.Status = models.WorkoutStatusInProgress
+	workout.StartedAt = &now
+	workout.PerformedAt = now
+	if req.Bodyweight != nil {
+		workout.Bodyweight = req.Bodyweight
+	}
+
+	if err := s.workoutRepo.UpdateSession(workout); err != nil {
+		return nil, errors.New("failed to start workout")
+	}
+
+	return workout.ToResponse(), nil
+}
+
+// FinishWorkout completes a started session, or abandons a planned or started one,
+// recording the finish time and how the session went
+func (s *workoutService) FinishWorkout(userID, workoutID int64, req *models.WorkoutFinishRequest) (*models.WorkoutResponse, error) {
+	workout, err := s.workoutRepo.GetByIDAndUserID(workoutID, userID)
+	if err != nil {
+		return nil, errors.New("workout not found")
+	}
+
+	status := req.Status
+	if status == "" {
+		status = models.WorkoutStatusCompleted
+	}
+	if !workout.Status.CanTransitionTo(status) {
+		return nil, errors.New(invalidTransitionPrefix + "a workout that is " + string(workout.Status) + " cannot be " + string(status))
+	}
+
+	now := time.Now()
+	workout.Status = status
+	workout.FinishedAt = &now
+	if req.Bodyweight != nil {
+		workout.Bodyweight = req.Bodyweight
+	}
+	if req.SessionRPE != nil {
+		workout.SessionRPE = req.SessionRPE
+	}
+	if req.Notes != nil {
+		workout.Notes = req.Notes
+	}
+
+	if err := s.workoutRepo.UpdateSession(workout); err != nil {
+		return nil, errors.New("failed to finish workout")
+	}
+
+	return workout.ToResponse(), nil
+}
@@ -11,11 +11,17 @@ import (
 // mockWorkoutRepository is a mock implementation of WorkoutRepository
 type mockWorkoutRepository struct {
 	createFunc          func(workout *models.Workout) error
+	createWithPlannedSetsFunc func(workout *models.Workout, plannedSets []*models.PlannedSet) error
 	getByIDFunc         func(id int64) (*models.Workout, error)
 	getByIDAndUserIDFunc func(id, userID int64) (*models.Workout, error)
 	getByUserIDFunc     func(userID int64) ([]*models.Workout, error)
+	listFunc            func(userID int64, filter *models.WorkoutFilter) ([]*models.Workout, string, error)
 	updateFunc          func(workout *models.Workout) error
 	deleteFunc          func(id, userID int64) error
+	updateSessionFunc   func(workout *models.Workout) error
+	getVolumeFunc       func(id int64) (float64, error)
+	getTrainingDaysFunc func(userID int64, loc *time.Location) ([]models.TrainingDay, error)
+	getDailySessionLoadFunc func(userID int64, from, to time.Time, loc *time.Location) ([]models.DailyLoad, error)
 }
 
 func (m *mockWorkoutRepository) Create(workout *models.Workout) error {
@@ -25,6 +31,13 @@ func (m *mockWorkoutRepository) Create(workout *models.Workout) error {
 	return nil
 }
 
+func (m *mockWorkoutRepository) CreateWithPlannedSets(workout *models.Workout, plannedSets []*models.PlannedSet) error {
+	if m.createWithPlannedSetsFunc != nil {
+		return m.createWithPlannedSetsFunc(workout, plannedSets)
+	}
+	return nil
+}
+
 func (m *mockWorkoutRepository) GetByID(id int64) (*models.Workout, error) {
 	if m.getByIDFunc != nil {
 		return m.getByIDFunc(id)
@@ -46,6 +59,13 @@ func (m *mockWorkoutRepository) GetByUserID(userID int64) ([]*models.Workout, er
 	return nil, nil
 }
 
+func (m *mockWorkoutRepository) List(userID int64, filter *models.WorkoutFilter) ([]*models.Workout, string, error) {
+	if m.listFunc != nil {
+		return m.listFunc(userID, filter)
+	}
+	return nil, "", nil
+}
+
 func (m *mockWorkoutRepository) Update(workout *models.Workout) error {
 	if m.updateFunc != nil {
 		return m.updateFunc(workout)
@@ -53,6 +73,34 @@ func (m *mockWorkoutRepository) Update(workout *models.Workout) error {
 	return nil
 }
 
+func (m *mockWorkoutRepository) UpdateSession(workout *models.Workout) error {
+	if m.updateSessionFunc != nil {
+		return m.updateSessionFunc(workout)
+	}
+	return nil
+}
+
+func (m *mockWorkoutRepository) GetVolume(id int64) (float64, error) {
+	if m.getVolumeFunc != nil {
+		return m.getVolumeFunc(id)
+	}
+	return 0, nil
+}
+
+func (m *mockWorkoutRepository) GetTrainingDays(userID int64, loc *time.Location) ([]models.TrainingDay, error) {
+	if m.getTrainingDaysFunc != nil {
+		return m.getTrainingDaysFunc(userID, loc)
+	}
+	return nil, nil
+}
+
+func (m *mockWorkoutRepository) GetDailySessionLoad(userID int64, from, to time.Time, loc *time.Location) ([]models.DailyLoad, error) {
+	if m.getDailySessionLoadFunc != nil {
+		return m.getDailySessionLoadFunc(userID, from, to, loc)
+	}
+	return nil, nil
+}
+
 func (m *mockWorkoutRepository) Delete(id, userID int64) error {
 	if m.deleteFunc != nil {
 		return m.deleteFunc(id, userID)
@@ -167,59 +215,69 @@ func TestGetWorkoutByID(t *testing.T) {
 
 func TestGetWorkouts(t *testing.T) {
 	userID := int64(1)
+	filter := &models.WorkoutFilter{ListParams: models.ListParams{Limit: 2, Sort: "created_at", Desc: true}}
 
-	t.Run("success", func(t *testing.T) {
+	t.Run("success with next page", func(t *testing.T) {
 		mockRepo := &mockWorkoutRepository{
-			getByUserIDFunc: func(uid int64) ([]*models.Workout, error) {
+			listFunc: func(uid int64, f *models.WorkoutFilter) ([]*models.Workout, string, error) {
 				if uid != userID {
 					t.Fatalf("expected user %d, got %d", userID, uid)
 				}
+				if f != filter {
+					t.Fatalf("expected filter to be passed through")
+				}
 				return []*models.Workout{
 					{ID: 1, UserID: uid, Name: "A", CreatedAt: time.Now()},
 					{ID: 2, UserID: uid, Name: "B", CreatedAt: time.Now()},
-				}, nil
+				}, "next", nil
 			},
 		}
 
 		svc := NewWorkoutService(mockRepo)
-		res, err := svc.GetWorkouts(userID)
+		res, err := svc.GetWorkouts(userID, filter)
 
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
-		if len(res) != 2 {
-			t.Fatalf("expected 2 workouts, got %d", len(res))
+		if len(res.Data) != 2 {
+			t.Fatalf("expected 2 workouts, got %d", len(res.Data))
+		}
+		if res.NextCursor == nil || *res.NextCursor != "next" {
+			t.Fatalf("expected next cursor, got %v", res.NextCursor)
 		}
 	})
 
-	t.Run("empty list", func(t *testing.T) {
+	t.Run("last page", func(t *testing.T) {
 		mockRepo := &mockWorkoutRepository{
-			getByUserIDFunc: func(uid int64) ([]*models.Workout, error) {
-				return []*models.Workout{}, nil
+			listFunc: func(uid int64, f *models.WorkoutFilter) ([]*models.Workout, string, error) {
+				return []*models.Workout{}, "", nil
 			},
 		}
 		svc := NewWorkoutService(mockRepo)
-		res, err := svc.GetWorkouts(userID)
+		res, err := svc.GetWorkouts(userID, filter)
 
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
-		if res == nil {
+		if res.Data == nil {
 			t.Fatalf("expected empty slice, got nil")
 		}
-		if len(res) != 0 {
-			t.Fatalf("expected 0 workouts, got %d", len(res))
+		if len(res.Data) != 0 {
+			t.Fatalf("expected 0 workouts, got %d", len(res.Data))
+		}
+		if res.NextCursor != nil {
+			t.Fatalf("expected nil next cursor, got %q", *res.NextCursor)
 		}
 	})
 
 	t.Run("repo error", func(t *testing.T) {
 		mockRepo := &mockWorkoutRepository{
-			getByUserIDFunc: func(uid int64) ([]*models.Workout, error) {
-				return nil, errors.New("db down")
+			listFunc: func(uid int64, f *models.WorkoutFilter) ([]*models.Workout, string, error) {
+				return nil, "", errors.New("db down")
 			},
 		}
 		svc := NewWorkoutService(mockRepo)
-		res, err := svc.GetWorkouts(userID)
+		res, err := svc.GetWorkouts(userID, filter)
 
 		if err == nil || err.Error() != "failed to retrieve workouts" {
 			t.Fatalf("expected failed to retrieve workouts, got %v", err)
@@ -396,3 +454,76 @@ func TestDeleteWorkout(t *testing.T) {
 		}
 	})
 }
+
+func TestWorkoutSession(t *testing.T) {
+	userID := int64(1)
+	workoutID := int64(10)
+	workout := &models.Workout{ID: workoutID, UserID: userID, Status: models.WorkoutStatusPlanned}
+	mockRepo := &mockWorkoutRepository{
+		getByIDAndUserIDFunc: func(id, uid int64) (*models.Workout, error) {
+			copied := *workout
+			return &copied, nil
+		},
+		updateSessionFunc: func(w *models.Workout) error {
+			workout = w
+			return nil
+		},
+	}
+	svc := NewWorkoutService(mockRepo)
+
+	t.Run("finish before start", func(t *testing.T) {
+		_, err := svc.FinishWorkout(userID, workoutID, &models.WorkoutFinishRequest{})
+		if err == nil || err.Error() != "invalid transition: a workout that is planned cannot be completed" {
+			t.Fatalf("expected invalid transition, got %v", err)
+		}
+	})
+
+	t.Run("start then finish", func(t *testing.T) {
+		bodyweight := 82.5
+		res, err := svc.StartWorkout(userID, workoutID, &models.WorkoutStartRequest{Bodyweight: &bodyweight})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if res.Status != models.WorkoutStatusInProgress || res.StartedAt == nil || *res.Bodyweight != bodyweight {
+			t.Errorf("expected started workout, got %+v", res)
+		}
+
+		rpe := 8
+		res, err = svc.FinishWorkout(userID, workoutID, &models.WorkoutFinishRequest{SessionRPE: &rpe})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if res.Status != models.WorkoutStatusCompleted || res.FinishedAt == nil || res.DurationSeconds == nil || *res.SessionRPE != rpe {
+			t.Errorf("expected completed workout, got %+v", res)
+		}
+	})
+
+	t.Run("start twice", func(t *testing.T) {
+		_, err := svc.StartWorkout(userID, workoutID, &models.WorkoutStartRequest{})
+		if err == nil || err.Error() != "invalid transition: only planned workouts can be started, this one is completed" {
+			t.Fatalf("expected invalid transition, got %v", err)
+		}
+	})
+}
+
+func TestGetWorkoutByIDDensity(t *testing.T) {
+	started := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+	finished := started.Add(50 * time.Minute)
+	mockRepo := &mockWorkoutRepository{
+		getByIDAndUserIDFunc: func(id, uid int64) (*models.Workout, error) {
+			return &models.Workout{ID: id, UserID: uid, Status: models.WorkoutStatusCompleted, StartedAt: &started, FinishedAt: &finished}, nil
+		},
+		getVolumeFunc: func(id int64) (float64, error) {
+			return 5000, nil
+		},
+	}
+	svc := NewWorkoutService(mockRepo)
+
+	res, err := svc.GetWorkoutByID(1, 10)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if *res.DurationSeconds != 3000 || *res.Volume != 5000 || *res.Density != 100 {
+		t.Errorf("expected 3000s, volume 5000 and density 100, got %+v", res)
+	}
+}
@@ -0,0 +1,5 @@
+-- Drop personal_records table
+DROP INDEX IF EXISTS idx_personal_records_current;
+DROP INDEX IF EXISTS idx_personal_records_exercise_id;
+DROP INDEX IF EXISTS idx_personal_records_user_id;
+DROP TABLE IF EXISTS personal_records;
@@ -0,0 +1,21 @@
+-- Create personal_records table
+CREATE TABLE IF NOT EXISTS personal_records (
+    id_personal_record BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id_user) ON DELETE CASCADE,
+    exercise_id BIGINT NOT NULL REFERENCES exercises(id_exercise) ON DELETE CASCADE,
+    workout_id BIGINT NOT NULL REFERENCES workouts(id_workout) ON DELETE CASCADE,
+    set_id BIGINT NOT NULL REFERENCES sets(id_set) ON DELETE CASCADE,
+    record_type VARCHAR(32) NOT NULL CHECK (record_type IN ('max_weight', 'max_reps', 'max_e1rm', 'max_volume')),
+    value DECIMAL(12, 2) NOT NULL,
+    weight DECIMAL(10, 2) NOT NULL,
+    reps INTEGER NOT NULL,
+    previous_value DECIMAL(12, 2),
+    is_current BOOLEAN NOT NULL DEFAULT TRUE,
+    achieved_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_personal_records_user_id ON personal_records(user_id, achieved_at DESC);
+CREATE INDEX IF NOT EXISTS idx_personal_records_exercise_id ON personal_records(exercise_id, record_type, achieved_at DESC);
+CREATE INDEX IF NOT EXISTS idx_personal_records_current ON personal_records(user_id, exercise_id, record_type) WHERE is_current;
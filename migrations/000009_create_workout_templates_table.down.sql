@@ -0,0 +1,7 @@
+-- Drop workout template tables
+DROP INDEX IF EXISTS idx_planned_sets_workout_id;
+DROP INDEX IF EXISTS idx_template_exercises_template_id;
+DROP INDEX IF EXISTS idx_workout_templates_user_id;
+DROP TABLE IF EXISTS planned_sets;
+DROP TABLE IF EXISTS template_exercises;
+DROP TABLE IF EXISTS workout_templates;
@@ -0,0 +1,39 @@
+-- Create workout_templates table
+CREATE TABLE IF NOT EXISTS workout_templates (
+    id_template BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id_user) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create template_exercises table (ordered exercise prescriptions of a template)
+CREATE TABLE IF NOT EXISTS template_exercises (
+    id_template_exercise BIGSERIAL PRIMARY KEY,
+    template_id BIGINT NOT NULL REFERENCES workout_templates(id_template) ON DELETE CASCADE,
+    exercise_id BIGINT NOT NULL REFERENCES exercises(id_exercise) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position >= 0),
+    target_sets INTEGER NOT NULL CHECK (target_sets > 0),
+    target_reps INTEGER NOT NULL CHECK (target_reps > 0),
+    target_weight DECIMAL(10, 2) CHECK (target_weight >= 0),
+    target_percent_e1rm DECIMAL(5, 2) CHECK (target_percent_e1rm > 0),
+    rest_seconds INTEGER CHECK (rest_seconds >= 0)
+);
+
+-- Create planned_sets table (set slots prescribed for a workout but not yet performed)
+CREATE TABLE IF NOT EXISTS planned_sets (
+    id_planned_set BIGSERIAL PRIMARY KEY,
+    workout_id BIGINT NOT NULL REFERENCES workouts(id_workout) ON DELETE CASCADE,
+    exercise_id BIGINT NOT NULL REFERENCES exercises(id_exercise) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position >= 0),
+    target_reps INTEGER NOT NULL CHECK (target_reps > 0),
+    target_weight DECIMAL(10, 2) CHECK (target_weight >= 0),
+    rest_seconds INTEGER CHECK (rest_seconds >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_workout_templates_user_id ON workout_templates(user_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_template_exercises_template_id ON template_exercises(template_id, position);
+CREATE INDEX IF NOT EXISTS idx_planned_sets_workout_id ON planned_sets(workout_id, position);
@@ -0,0 +1,9 @@
+-- Drop program tables
+DROP INDEX IF EXISTS idx_program_enrollments_active;
+DROP INDEX IF EXISTS idx_program_exercises_day_id;
+DROP INDEX IF EXISTS idx_program_days_program_id;
+DROP INDEX IF EXISTS idx_programs_user_id;
+DROP TABLE IF EXISTS program_enrollments;
+DROP TABLE IF EXISTS program_exercises;
+DROP TABLE IF EXISTS program_days;
+DROP TABLE IF EXISTS programs;
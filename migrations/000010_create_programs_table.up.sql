@@ -0,0 +1,51 @@
+-- Create programs table
+CREATE TABLE IF NOT EXISTS programs (
+    id_program BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id_user) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    weeks INTEGER NOT NULL CHECK (weeks > 0),
+    days_per_week INTEGER NOT NULL CHECK (days_per_week > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create program_days table (one training session per day of the week cycle)
+CREATE TABLE IF NOT EXISTS program_days (
+    id_program_day BIGSERIAL PRIMARY KEY,
+    program_id BIGINT NOT NULL REFERENCES programs(id_program) ON DELETE CASCADE,
+    day_number INTEGER NOT NULL CHECK (day_number > 0),
+    name VARCHAR(255) NOT NULL,
+    UNIQUE (program_id, day_number)
+);
+
+-- Create program_exercises table (exercise prescriptions with their progression rule)
+CREATE TABLE IF NOT EXISTS program_exercises (
+    id_program_exercise BIGSERIAL PRIMARY KEY,
+    program_day_id BIGINT NOT NULL REFERENCES program_days(id_program_day) ON DELETE CASCADE,
+    exercise_id BIGINT NOT NULL REFERENCES exercises(id_exercise) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position >= 0),
+    sets INTEGER NOT NULL CHECK (sets > 0),
+    reps INTEGER NOT NULL CHECK (reps > 0),
+    rep_max INTEGER CHECK (rep_max > 0),
+    progression VARCHAR(16) NOT NULL CHECK (progression IN ('linear', 'double', 'wave')),
+    increment DECIMAL(10, 2) NOT NULL DEFAULT 2.5 CHECK (increment >= 0),
+    start_weight DECIMAL(10, 2) CHECK (start_weight >= 0),
+    training_max_percent DECIMAL(5, 2) CHECK (training_max_percent > 0)
+);
+
+-- Create program_enrollments table
+CREATE TABLE IF NOT EXISTS program_enrollments (
+    id_enrollment BIGSERIAL PRIMARY KEY,
+    program_id BIGINT NOT NULL REFERENCES programs(id_program) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id_user) ON DELETE CASCADE,
+    completed_sessions INTEGER NOT NULL DEFAULT 0 CHECK (completed_sessions >= 0),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    started_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_programs_user_id ON programs(user_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_program_days_program_id ON program_days(program_id, day_number);
+CREATE INDEX IF NOT EXISTS idx_program_exercises_day_id ON program_exercises(program_day_id, position);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_program_enrollments_active ON program_enrollments(program_id, user_id) WHERE active;
@@ -0,0 +1,25 @@
+-- Remove distance and duration from sets table. Sets without reps, such as cardio and timed
+-- sets, cannot be kept once reps must be positive, so the rollback refuses to run while there
+-- are any rather than delete them.
+DO $$
+BEGIN
+  IF EXISTS (SELECT 1 FROM sets WHERE reps = 0) THEN
+    RAISE EXCEPTION 'cannot roll back: % sets have no reps; export or remove them first',
+      (SELECT COUNT(*) FROM sets WHERE reps = 0);
+  END IF;
+END $$;
+
+ALTER TABLE sets DROP CONSTRAINT IF EXISTS sets_reps_check;
+ALTER TABLE sets ADD CONSTRAINT sets_reps_check CHECK (reps > 0);
+ALTER TABLE sets DROP COLUMN IF EXISTS duration_seconds;
+ALTER TABLE sets DROP COLUMN IF EXISTS distance_meters;
+
+-- Remove catalog metadata from exercises table
+DROP INDEX IF EXISTS idx_exercises_secondary_muscles;
+DROP INDEX IF EXISTS idx_exercises_primary_muscles;
+DROP INDEX IF EXISTS idx_exercises_user_id_type;
+ALTER TABLE exercises DROP COLUMN IF EXISTS movement_pattern;
+ALTER TABLE exercises DROP COLUMN IF EXISTS equipment;
+ALTER TABLE exercises DROP COLUMN IF EXISTS secondary_muscles;
+ALTER TABLE exercises DROP COLUMN IF EXISTS primary_muscles;
+ALTER TABLE exercises DROP COLUMN IF EXISTS exercise_type;
@@ -0,0 +1,21 @@
+-- Add catalog metadata to exercises table
+ALTER TABLE exercises
+  ADD COLUMN IF NOT EXISTS exercise_type VARCHAR(20) NOT NULL DEFAULT 'weight'
+    CHECK (exercise_type IN ('weight', 'bodyweight', 'cardio', 'flexibility', 'timed')),
+  ADD COLUMN IF NOT EXISTS primary_muscles TEXT[] NOT NULL DEFAULT '{}',
+  ADD COLUMN IF NOT EXISTS secondary_muscles TEXT[] NOT NULL DEFAULT '{}',
+  ADD COLUMN IF NOT EXISTS equipment VARCHAR(30),
+  ADD COLUMN IF NOT EXISTS movement_pattern VARCHAR(30);
+
+-- Create indexes for catalog filters
+CREATE INDEX IF NOT EXISTS idx_exercises_user_id_type ON exercises(user_id, exercise_type);
+CREATE INDEX IF NOT EXISTS idx_exercises_primary_muscles ON exercises USING GIN (primary_muscles);
+CREATE INDEX IF NOT EXISTS idx_exercises_secondary_muscles ON exercises USING GIN (secondary_muscles);
+
+-- Add distance and duration to sets table; cardio and timed sets do not count reps
+ALTER TABLE sets
+  ADD COLUMN IF NOT EXISTS distance_meters DECIMAL(10, 2) CHECK (distance_meters >= 0),
+  ADD COLUMN IF NOT EXISTS duration_seconds INTEGER CHECK (duration_seconds >= 0);
+
+ALTER TABLE sets DROP CONSTRAINT IF EXISTS sets_reps_check;
+ALTER TABLE sets ADD CONSTRAINT sets_reps_check CHECK (reps >= 0);
@@ -0,0 +1,82 @@
+-- Drop the exercise catalog. Catalog entries a user adopted or logged data against are first
+-- copied into exercises of that user, under the user's alias, and the user's sets, records,
+-- templates and programs are moved to the copies. Copies of entries the user did not keep in
+-- their list are soft-deleted, so that only their history shows them.
+CREATE TEMPORARY TABLE catalog_copies (
+    catalog_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    copy_id BIGINT,
+    PRIMARY KEY (catalog_id, user_id)
+);
+
+INSERT INTO catalog_copies (catalog_id, user_id)
+SELECT exercise_id, user_id FROM user_catalog_exercises
+UNION
+SELECT s.exercise_id, w.user_id
+FROM sets s
+INNER JOIN workouts w ON s.workout_id = w.id_workout
+INNER JOIN exercises e ON s.exercise_id = e.id_exercise
+WHERE e.user_id IS NULL
+UNION
+SELECT p.exercise_id, p.user_id
+FROM personal_records p
+INNER JOIN exercises e ON p.exercise_id = e.id_exercise
+WHERE e.user_id IS NULL
+UNION
+SELECT ps.exercise_id, w.user_id
+FROM planned_sets ps
+INNER JOIN workouts w ON ps.workout_id = w.id_workout
+INNER JOIN exercises e ON ps.exercise_id = e.id_exercise
+WHERE e.user_id IS NULL
+UNION
+SELECT te.exercise_id, t.user_id
+FROM template_exercises te
+INNER JOIN workout_templates t ON te.template_id = t.id_template
+INNER JOIN exercises e ON te.exercise_id = e.id_exercise
+WHERE e.user_id IS NULL
+UNION
+SELECT pe.exercise_id, p.user_id
+FROM program_exercises pe
+INNER JOIN program_days d ON pe.program_day_id = d.id_program_day
+INNER JOIN programs p ON d.program_id = p.id_program
+INNER JOIN exercises e ON pe.exercise_id = e.id_exercise
+WHERE e.user_id IS NULL;
+
+UPDATE catalog_copies SET copy_id = nextval(pg_get_serial_sequence('exercises', 'id_exercise'));
+
+INSERT INTO exercises (id_exercise, user_id, name, exercise_type, primary_muscles, secondary_muscles, equipment, movement_pattern, created_at, deleted_at)
+SELECT c.copy_id, c.user_id, COALESCE(a.alias, e.name), e.exercise_type, e.primary_muscles, e.secondary_muscles,
+       e.equipment, e.movement_pattern, COALESCE(a.created_at, CURRENT_TIMESTAMP),
+       CASE WHEN a.user_id IS NULL THEN CURRENT_TIMESTAMP END
+FROM catalog_copies c
+INNER JOIN exercises e ON c.catalog_id = e.id_exercise
+LEFT JOIN user_catalog_exercises a ON a.exercise_id = c.catalog_id AND a.user_id = c.user_id;
+
+UPDATE sets s SET exercise_id = c.copy_id
+FROM workouts w, catalog_copies c
+WHERE s.workout_id = w.id_workout AND c.catalog_id = s.exercise_id AND c.user_id = w.user_id;
+
+UPDATE personal_records p SET exercise_id = c.copy_id
+FROM catalog_copies c
+WHERE c.catalog_id = p.exercise_id AND c.user_id = p.user_id;
+
+UPDATE planned_sets ps SET exercise_id = c.copy_id
+FROM workouts w, catalog_copies c
+WHERE ps.workout_id = w.id_workout AND c.catalog_id = ps.exercise_id AND c.user_id = w.user_id;
+
+UPDATE template_exercises te SET exercise_id = c.copy_id
+FROM workout_templates t, catalog_copies c
+WHERE te.template_id = t.id_template AND c.catalog_id = te.exercise_id AND c.user_id = t.user_id;
+
+UPDATE program_exercises pe SET exercise_id = c.copy_id
+FROM program_days d, programs p, catalog_copies c
+WHERE pe.program_day_id = d.id_program_day AND d.program_id = p.id_program
+  AND c.catalog_id = pe.exercise_id AND c.user_id = p.user_id;
+
+DROP TABLE catalog_copies;
+
+-- Nothing references the catalog any more
+DROP TABLE IF EXISTS user_catalog_exercises;
+DELETE FROM exercises WHERE user_id IS NULL;
+DROP INDEX IF EXISTS idx_exercises_catalog_name;
+ALTER TABLE exercises ALTER COLUMN user_id SET NOT NULL;
@@ -0,0 +1,341 @@
+-- Allow system-owned exercises: a NULL user_id marks a catalog entry shared by all users
+ALTER TABLE exercises ALTER COLUMN user_id DROP NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exercises_catalog_name ON exercises(LOWER(name)) WHERE user_id IS NULL;
+
+-- Create user_catalog_exercises table: catalog entries a user adopted, optionally under an alias
+CREATE TABLE IF NOT EXISTS user_catalog_exercises (
+    user_id BIGINT NOT NULL REFERENCES users(id_user) ON DELETE CASCADE,
+    exercise_id BIGINT NOT NULL REFERENCES exercises(id_exercise) ON DELETE CASCADE,
+    alias VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, exercise_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_catalog_exercises_exercise_id ON user_catalog_exercises(exercise_id);
+
+-- Seed the catalog
+INSERT INTO exercises (name, exercise_type, primary_muscles, secondary_muscles, equipment, movement_pattern)
+VALUES
+  ('Barbell Bench Press', 'weight', '{chest}', '{triceps,front_delts}', 'barbell', 'horizontal_push'),
+  ('Barbell Incline Bench Press', 'weight', '{chest}', '{front_delts,triceps}', 'barbell', 'horizontal_push'),
+  ('Barbell Decline Bench Press', 'weight', '{chest}', '{triceps}', 'barbell', 'horizontal_push'),
+  ('Dumbbell Bench Press', 'weight', '{chest}', '{triceps,front_delts}', 'dumbbell', 'horizontal_push'),
+  ('Dumbbell Incline Bench Press', 'weight', '{chest}', '{front_delts,triceps}', 'dumbbell', 'horizontal_push'),
+  ('Dumbbell Decline Bench Press', 'weight', '{chest}', '{triceps}', 'dumbbell', 'horizontal_push'),
+  ('Smith Machine Bench Press', 'weight', '{chest}', '{triceps,front_delts}', 'smith_machine', 'horizontal_push'),
+  ('Smith Machine Incline Bench Press', 'weight', '{chest}', '{front_delts,triceps}', 'smith_machine', 'horizontal_push'),
+  ('Smith Machine Decline Bench Press', 'weight', '{chest}', '{triceps}', 'smith_machine', 'horizontal_push'),
+  ('Close-Grip Bench Press', 'weight', '{triceps}', '{chest,front_delts}', 'barbell', 'horizontal_push'),
+  ('Paused Bench Press', 'weight', '{chest}', '{triceps,front_delts}', 'barbell', 'horizontal_push'),
+  ('Spoto Press', 'weight', '{chest}', '{triceps,front_delts}', 'barbell', 'horizontal_push'),
+  ('Larsen Press', 'weight', '{chest}', '{triceps,front_delts}', 'barbell', 'horizontal_push'),
+  ('Floor Press', 'weight', '{triceps}', '{chest}', 'barbell', 'horizontal_push'),
+  ('Dumbbell Floor Press', 'weight', '{triceps}', '{chest}', 'dumbbell', 'horizontal_push'),
+  ('Board Press', 'weight', '{triceps}', '{chest}', 'barbell', 'horizontal_push'),
+  ('Pin Press', 'weight', '{triceps}', '{chest}', 'barbell', 'horizontal_push'),
+  ('Machine Chest Press', 'weight', '{chest}', '{triceps,front_delts}', 'machine', 'horizontal_push'),
+  ('Incline Machine Chest Press', 'weight', '{chest}', '{front_delts,triceps}', 'machine', 'horizontal_push'),
+  ('Hammer Strength Chest Press', 'weight', '{chest}', '{triceps}', 'machine', 'horizontal_push'),
+  ('Cable Chest Press', 'weight', '{chest}', '{triceps}', 'cable', 'horizontal_push'),
+  ('Push-Up', 'bodyweight', '{chest}', '{triceps,front_delts}', 'bodyweight', 'horizontal_push'),
+  ('Incline Push-Up', 'bodyweight', '{chest}', '{triceps}', 'bodyweight', 'horizontal_push'),
+  ('Decline Push-Up', 'bodyweight', '{chest}', '{front_delts,triceps}', 'bodyweight', 'horizontal_push'),
+  ('Diamond Push-Up', 'bodyweight', '{triceps}', '{chest}', 'bodyweight', 'horizontal_push'),
+  ('Deficit Push-Up', 'bodyweight', '{chest}', '{triceps}', 'bodyweight', 'horizontal_push'),
+  ('Band Push-Up', 'weight', '{chest}', '{triceps}', 'band', 'horizontal_push'),
+  ('Dip', 'bodyweight', '{chest}', '{triceps,front_delts}', 'bodyweight', 'vertical_push'),
+  ('Weighted Dip', 'weight', '{chest}', '{triceps,front_delts}', 'other', 'vertical_push'),
+  ('Bench Dip', 'bodyweight', '{triceps}', '{chest}', 'bodyweight', 'vertical_push'),
+  ('Machine Dip', 'weight', '{triceps}', '{chest}', 'machine', 'vertical_push'),
+  ('Dumbbell Fly', 'weight', '{chest}', '{front_delts}', 'dumbbell', 'isolation'),
+  ('Incline Dumbbell Fly', 'weight', '{chest}', '{front_delts}', 'dumbbell', 'isolation'),
+  ('Cable Fly', 'weight', '{chest}', '{front_delts}', 'cable', 'isolation'),
+  ('Low-to-High Cable Fly', 'weight', '{chest}', '{front_delts}', 'cable', 'isolation'),
+  ('High-to-Low Cable Fly', 'weight', '{chest}', '{}', 'cable', 'isolation'),
+  ('Pec Deck', 'weight', '{chest}', '{}', 'machine', 'isolation'),
+  ('Dumbbell Pullover', 'weight', '{chest}', '{lats,triceps}', 'dumbbell', 'isolation'),
+  ('Svend Press', 'weight', '{chest}', '{}', 'other', 'isolation'),
+  ('Overhead Press', 'weight', '{front_delts}', '{side_delts,triceps}', 'barbell', 'vertical_push'),
+  ('Push Press', 'weight', '{front_delts}', '{triceps,quads}', 'barbell', 'vertical_push'),
+  ('Push Jerk', 'weight', '{front_delts}', '{triceps,quads}', 'barbell', 'vertical_push'),
+  ('Split Jerk', 'weight', '{front_delts}', '{triceps,quads,glutes}', 'barbell', 'vertical_push'),
+  ('Seated Barbell Overhead Press', 'weight', '{front_delts}', '{triceps}', 'barbell', 'vertical_push'),
+  ('Behind-the-Neck Press', 'weight', '{front_delts}', '{side_delts,triceps}', 'barbell', 'vertical_push'),
+  ('Z Press', 'weight', '{front_delts}', '{triceps,abs}', 'barbell', 'vertical_push'),
+  ('Dumbbell Shoulder Press', 'weight', '{front_delts}', '{side_delts,triceps}', 'dumbbell', 'vertical_push'),
+  ('Seated Dumbbell Shoulder Press', 'weight', '{front_delts}', '{side_delts,triceps}', 'dumbbell', 'vertical_push'),
+  ('Arnold Press', 'weight', '{front_delts}', '{side_delts,triceps}', 'dumbbell', 'vertical_push'),
+  ('Single-Arm Dumbbell Press', 'weight', '{front_delts}', '{triceps,obliques}', 'dumbbell', 'vertical_push'),
+  ('Kettlebell Press', 'weight', '{front_delts}', '{triceps}', 'kettlebell', 'vertical_push'),
+  ('Landmine Press', 'weight', '{front_delts}', '{chest,triceps}', 'other', 'vertical_push'),
+  ('Machine Shoulder Press', 'weight', '{front_delts}', '{triceps}', 'machine', 'vertical_push'),
+  ('Smith Machine Overhead Press', 'weight', '{front_delts}', '{triceps}', 'smith_machine', 'vertical_push'),
+  ('Pike Push-Up', 'bodyweight', '{front_delts}', '{triceps}', 'bodyweight', 'vertical_push'),
+  ('Handstand Push-Up', 'bodyweight', '{front_delts}', '{triceps,traps}', 'bodyweight', 'vertical_push'),
+  ('Dumbbell Lateral Raise', 'weight', '{side_delts}', '{}', 'dumbbell', 'isolation'),
+  ('Cable Lateral Raise', 'weight', '{side_delts}', '{}', 'cable', 'isolation'),
+  ('Machine Lateral Raise', 'weight', '{side_delts}', '{}', 'machine', 'isolation'),
+  ('Lean-Away Lateral Raise', 'weight', '{side_delts}', '{}', 'dumbbell', 'isolation'),
+  ('Dumbbell Front Raise', 'weight', '{front_delts}', '{}', 'dumbbell', 'isolation'),
+  ('Plate Front Raise', 'weight', '{front_delts}', '{}', 'other', 'isolation'),
+  ('Cable Front Raise', 'weight', '{front_delts}', '{}', 'cable', 'isolation'),
+  ('Rear Delt Fly', 'weight', '{rear_delts}', '{upper_back}', 'dumbbell', 'isolation'),
+  ('Reverse Pec Deck', 'weight', '{rear_delts}', '{upper_back}', 'machine', 'isolation'),
+  ('Cable Rear Delt Fly', 'weight', '{rear_delts}', '{upper_back}', 'cable', 'isolation'),
+  ('Face Pull', 'weight', '{rear_delts}', '{upper_back,traps}', 'cable', 'horizontal_pull'),
+  ('Band Pull-Apart', 'weight', '{rear_delts}', '{upper_back}', 'band', 'isolation'),
+  ('Upright Row', 'weight', '{side_delts}', '{traps}', 'barbell', 'vertical_pull'),
+  ('Cable Upright Row', 'weight', '{side_delts}', '{traps}', 'cable', 'vertical_pull'),
+  ('Dumbbell Upright Row', 'weight', '{side_delts}', '{traps}', 'dumbbell', 'vertical_pull'),
+  ('Cuban Press', 'weight', '{rear_delts}', '{side_delts}', 'dumbbell', 'isolation'),
+  ('Y Raise', 'weight', '{side_delts}', '{traps}', 'dumbbell', 'isolation'),
+  ('Barbell Shrug', 'weight', '{traps}', '{}', 'barbell', 'isolation'),
+  ('Dumbbell Shrug', 'weight', '{traps}', '{}', 'dumbbell', 'isolation'),
+  ('Trap Bar Shrug', 'weight', '{traps}', '{}', 'trap_bar', 'isolation'),
+  ('Machine Shrug', 'weight', '{traps}', '{}', 'machine', 'isolation'),
+  ('Cable Shrug', 'weight', '{traps}', '{}', 'cable', 'isolation'),
+  ('Pull-Up', 'bodyweight', '{lats}', '{biceps,upper_back}', 'bodyweight', 'vertical_pull'),
+  ('Weighted Pull-Up', 'weight', '{lats}', '{biceps,upper_back}', 'other', 'vertical_pull'),
+  ('Chin-Up', 'bodyweight', '{lats}', '{biceps}', 'bodyweight', 'vertical_pull'),
+  ('Weighted Chin-Up', 'weight', '{lats}', '{biceps}', 'other', 'vertical_pull'),
+  ('Neutral-Grip Pull-Up', 'bodyweight', '{lats}', '{biceps,forearms}', 'bodyweight', 'vertical_pull'),
+  ('Assisted Pull-Up', 'weight', '{lats}', '{biceps}', 'machine', 'vertical_pull'),
+  ('Band-Assisted Pull-Up', 'bodyweight', '{lats}', '{biceps}', 'band', 'vertical_pull'),
+  ('Lat Pulldown', 'weight', '{lats}', '{biceps,upper_back}', 'cable', 'vertical_pull'),
+  ('Close-Grip Lat Pulldown', 'weight', '{lats}', '{biceps}', 'cable', 'vertical_pull'),
+  ('Wide-Grip Lat Pulldown', 'weight', '{lats}', '{upper_back}', 'cable', 'vertical_pull'),
+  ('Single-Arm Lat Pulldown', 'weight', '{lats}', '{biceps}', 'cable', 'vertical_pull'),
+  ('Machine Lat Pulldown', 'weight', '{lats}', '{biceps}', 'machine', 'vertical_pull'),
+  ('Straight-Arm Pulldown', 'weight', '{lats}', '{triceps}', 'cable', 'isolation'),
+  ('Muscle-Up', 'bodyweight', '{lats}', '{chest,triceps}', 'bodyweight', 'vertical_pull'),
+  ('Barbell Row', 'weight', '{upper_back}', '{lats,biceps,rear_delts}', 'barbell', 'horizontal_pull'),
+  ('Pendlay Row', 'weight', '{upper_back}', '{lats,lower_back}', 'barbell', 'horizontal_pull'),
+  ('Yates Row', 'weight', '{lats}', '{upper_back,biceps}', 'barbell', 'horizontal_pull'),
+  ('Seal Row', 'weight', '{upper_back}', '{lats,rear_delts}', 'barbell', 'horizontal_pull'),
+  ('T-Bar Row', 'weight', '{upper_back}', '{lats,biceps}', 'other', 'horizontal_pull'),
+  ('Chest-Supported T-Bar Row', 'weight', '{upper_back}', '{lats,rear_delts}', 'machine', 'horizontal_pull'),
+  ('Dumbbell Row', 'weight', '{lats}', '{upper_back,biceps}', 'dumbbell', 'horizontal_pull'),
+  ('Chest-Supported Dumbbell Row', 'weight', '{upper_back}', '{lats,rear_delts}', 'dumbbell', 'horizontal_pull'),
+  ('Kroc Row', 'weight', '{lats}', '{upper_back,forearms}', 'dumbbell', 'horizontal_pull'),
+  ('Kettlebell Row', 'weight', '{lats}', '{upper_back}', 'kettlebell', 'horizontal_pull'),
+  ('Seated Cable Row', 'weight', '{upper_back}', '{lats,biceps}', 'cable', 'horizontal_pull'),
+  ('Wide-Grip Cable Row', 'weight', '{upper_back}', '{rear_delts}', 'cable', 'horizontal_pull'),
+  ('Single-Arm Cable Row', 'weight', '{lats}', '{upper_back}', 'cable', 'horizontal_pull'),
+  ('Machine Row', 'weight', '{upper_back}', '{lats,biceps}', 'machine', 'horizontal_pull'),
+  ('Meadows Row', 'weight', '{lats}', '{upper_back,rear_delts}', 'other', 'horizontal_pull'),
+  ('Inverted Row', 'bodyweight', '{upper_back}', '{lats,biceps}', 'bodyweight', 'horizontal_pull'),
+  ('Smith Machine Row', 'weight', '{upper_back}', '{lats}', 'smith_machine', 'horizontal_pull'),
+  ('Band Row', 'weight', '{upper_back}', '{lats}', 'band', 'horizontal_pull'),
+  ('Barbell Curl', 'weight', '{biceps}', '{forearms}', 'barbell', 'isolation'),
+  ('EZ-Bar Curl', 'weight', '{biceps}', '{forearms}', 'ez_bar', 'isolation'),
+  ('Dumbbell Curl', 'weight', '{biceps}', '{forearms}', 'dumbbell', 'isolation'),
+  ('Hammer Curl', 'weight', '{biceps}', '{forearms}', 'dumbbell', 'isolation'),
+  ('Cross-Body Hammer Curl', 'weight', '{biceps}', '{forearms}', 'dumbbell', 'isolation'),
+  ('Incline Dumbbell Curl', 'weight', '{biceps}', '{}', 'dumbbell', 'isolation'),
+  ('Concentration Curl', 'weight', '{biceps}', '{}', 'dumbbell', 'isolation'),
+  ('Preacher Curl', 'weight', '{biceps}', '{}', 'ez_bar', 'isolation'),
+  ('Dumbbell Preacher Curl', 'weight', '{biceps}', '{}', 'dumbbell', 'isolation'),
+  ('Machine Preacher Curl', 'weight', '{biceps}', '{}', 'machine', 'isolation'),
+  ('Spider Curl', 'weight', '{biceps}', '{}', 'dumbbell', 'isolation'),
+  ('Cable Curl', 'weight', '{biceps}', '{forearms}', 'cable', 'isolation'),
+  ('Bayesian Cable Curl', 'weight', '{biceps}', '{}', 'cable', 'isolation'),
+  ('Reverse Curl', 'weight', '{forearms}', '{biceps}', 'ez_bar', 'isolation'),
+  ('Zottman Curl', 'weight', '{biceps}', '{forearms}', 'dumbbell', 'isolation'),
+  ('Drag Curl', 'weight', '{biceps}', '{}', 'barbell', 'isolation'),
+  ('Band Curl', 'weight', '{biceps}', '{}', 'band', 'isolation'),
+  ('Triceps Pushdown', 'weight', '{triceps}', '{}', 'cable', 'isolation'),
+  ('Rope Pushdown', 'weight', '{triceps}', '{}', 'cable', 'isolation'),
+  ('Single-Arm Cable Pushdown', 'weight', '{triceps}', '{}', 'cable', 'isolation'),
+  ('Overhead Cable Triceps Extension', 'weight', '{triceps}', '{}', 'cable', 'isolation'),
+  ('Dumbbell Overhead Triceps Extension', 'weight', '{triceps}', '{}', 'dumbbell', 'isolation'),
+  ('Skull Crusher', 'weight', '{triceps}', '{}', 'ez_bar', 'isolation'),
+  ('Dumbbell Skull Crusher', 'weight', '{triceps}', '{}', 'dumbbell', 'isolation'),
+  ('JM Press', 'weight', '{triceps}', '{chest}', 'barbell', 'isolation'),
+  ('Tate Press', 'weight', '{triceps}', '{}', 'dumbbell', 'isolation'),
+  ('Triceps Kickback', 'weight', '{triceps}', '{}', 'dumbbell', 'isolation'),
+  ('Cable Triceps Kickback', 'weight', '{triceps}', '{}', 'cable', 'isolation'),
+  ('Machine Triceps Extension', 'weight', '{triceps}', '{}', 'machine', 'isolation'),
+  ('Band Pushdown', 'weight', '{triceps}', '{}', 'band', 'isolation'),
+  ('Wrist Curl', 'weight', '{forearms}', '{}', 'barbell', 'isolation'),
+  ('Reverse Wrist Curl', 'weight', '{forearms}', '{}', 'barbell', 'isolation'),
+  ('Dumbbell Wrist Curl', 'weight', '{forearms}', '{}', 'dumbbell', 'isolation'),
+  ('Farmer''s Walk', 'weight', '{forearms}', '{traps,abs}', 'dumbbell', 'carry'),
+  ('Trap Bar Carry', 'weight', '{forearms}', '{traps,abs}', 'trap_bar', 'carry'),
+  ('Suitcase Carry', 'weight', '{obliques}', '{forearms}', 'dumbbell', 'carry'),
+  ('Kettlebell Front Rack Carry', 'weight', '{abs}', '{upper_back}', 'kettlebell', 'carry'),
+  ('Overhead Carry', 'weight', '{front_delts}', '{abs,traps}', 'dumbbell', 'carry'),
+  ('Yoke Carry', 'weight', '{full_body}', '{traps,abs}', 'other', 'carry'),
+  ('Sandbag Carry', 'weight', '{full_body}', '{upper_back,abs}', 'other', 'carry'),
+  ('Plate Pinch', 'timed', '{forearms}', '{}', 'other', 'isolation'),
+  ('Dead Hang', 'timed', '{forearms}', '{lats}', 'bodyweight', 'isolation'),
+  ('Back Squat', 'weight', '{quads}', '{glutes,adductors,lower_back}', 'barbell', 'squat'),
+  ('Low-Bar Back Squat', 'weight', '{quads}', '{glutes,hamstrings,lower_back}', 'barbell', 'squat'),
+  ('Paused Back Squat', 'weight', '{quads}', '{glutes,adductors}', 'barbell', 'squat'),
+  ('Box Squat', 'weight', '{quads}', '{glutes,hamstrings}', 'barbell', 'squat'),
+  ('Pin Squat', 'weight', '{quads}', '{glutes}', 'barbell', 'squat'),
+  ('Front Squat', 'weight', '{quads}', '{glutes,upper_back,abs}', 'barbell', 'squat'),
+  ('Zercher Squat', 'weight', '{quads}', '{glutes,upper_back,abs}', 'barbell', 'squat'),
+  ('Overhead Squat', 'weight', '{quads}', '{glutes,front_delts,abs}', 'barbell', 'squat'),
+  ('Safety Bar Squat', 'weight', '{quads}', '{glutes,upper_back}', 'other', 'squat'),
+  ('Smith Machine Squat', 'weight', '{quads}', '{glutes}', 'smith_machine', 'squat'),
+  ('Hack Squat', 'weight', '{quads}', '{glutes}', 'machine', 'squat'),
+  ('Belt Squat', 'weight', '{quads}', '{glutes,adductors}', 'machine', 'squat'),
+  ('Pendulum Squat', 'weight', '{quads}', '{glutes}', 'machine', 'squat'),
+  ('Leg Press', 'weight', '{quads}', '{glutes,adductors}', 'machine', 'squat'),
+  ('Single-Leg Leg Press', 'weight', '{quads}', '{glutes}', 'machine', 'squat'),
+  ('Goblet Squat', 'weight', '{quads}', '{glutes,abs}', 'dumbbell', 'squat'),
+  ('Kettlebell Goblet Squat', 'weight', '{quads}', '{glutes,abs}', 'kettlebell', 'squat'),
+  ('Dumbbell Squat', 'weight', '{quads}', '{glutes}', 'dumbbell', 'squat'),
+  ('Trap Bar Squat', 'weight', '{quads}', '{glutes}', 'trap_bar', 'squat'),
+  ('Bodyweight Squat', 'bodyweight', '{quads}', '{glutes}', 'bodyweight', 'squat'),
+  ('Jump Squat', 'bodyweight', '{quads}', '{glutes,calves}', 'bodyweight', 'squat'),
+  ('Pistol Squat', 'bodyweight', '{quads}', '{glutes}', 'bodyweight', 'squat'),
+  ('Sissy Squat', 'bodyweight', '{quads}', '{}', 'bodyweight', 'squat'),
+  ('Wall Sit', 'timed', '{quads}', '{glutes}', 'bodyweight', 'squat'),
+  ('Barbell Lunge', 'weight', '{quads}', '{glutes,adductors}', 'barbell', 'lunge'),
+  ('Dumbbell Lunge', 'weight', '{quads}', '{glutes,adductors}', 'dumbbell', 'lunge'),
+  ('Walking Lunge', 'weight', '{quads}', '{glutes,hamstrings}', 'dumbbell', 'lunge'),
+  ('Reverse Lunge', 'weight', '{glutes}', '{quads,hamstrings}', 'dumbbell', 'lunge'),
+  ('Lateral Lunge', 'weight', '{adductors}', '{quads,glutes}', 'dumbbell', 'lunge'),
+  ('Bulgarian Split Squat', 'weight', '{quads}', '{glutes,adductors}', 'dumbbell', 'lunge'),
+  ('Barbell Bulgarian Split Squat', 'weight', '{quads}', '{glutes,adductors}', 'barbell', 'lunge'),
+  ('Split Squat', 'weight', '{quads}', '{glutes}', 'dumbbell', 'lunge'),
+  ('Front-Foot-Elevated Split Squat', 'weight', '{quads}', '{glutes}', 'dumbbell', 'lunge'),
+  ('Step-Up', 'weight', '{quads}', '{glutes}', 'dumbbell', 'lunge'),
+  ('Barbell Step-Up', 'weight', '{quads}', '{glutes}', 'barbell', 'lunge'),
+  ('Smith Machine Split Squat', 'weight', '{quads}', '{glutes}', 'smith_machine', 'lunge'),
+  ('Bodyweight Lunge', 'bodyweight', '{quads}', '{glutes}', 'bodyweight', 'lunge'),
+  ('Cossack Squat', 'bodyweight', '{adductors}', '{quads,glutes}', 'bodyweight', 'lunge'),
+  ('Deadlift', 'weight', '{hamstrings}', '{glutes,lower_back,traps,forearms}', 'barbell', 'hinge'),
+  ('Sumo Deadlift', 'weight', '{glutes}', '{quads,adductors,hamstrings,lower_back}', 'barbell', 'hinge'),
+  ('Paused Deadlift', 'weight', '{hamstrings}', '{glutes,lower_back}', 'barbell', 'hinge'),
+  ('Deficit Deadlift', 'weight', '{hamstrings}', '{glutes,quads,lower_back}', 'barbell', 'hinge'),
+  ('Block Pull', 'weight', '{glutes}', '{hamstrings,traps,lower_back}', 'barbell', 'hinge'),
+  ('Rack Pull', 'weight', '{upper_back}', '{glutes,traps,lower_back}', 'barbell', 'hinge'),
+  ('Snatch-Grip Deadlift', 'weight', '{hamstrings}', '{upper_back,traps,glutes}', 'barbell', 'hinge'),
+  ('Trap Bar Deadlift', 'weight', '{quads}', '{glutes,hamstrings,traps}', 'trap_bar', 'hinge'),
+  ('Romanian Deadlift', 'weight', '{hamstrings}', '{glutes,lower_back}', 'barbell', 'hinge'),
+  ('Dumbbell Romanian Deadlift', 'weight', '{hamstrings}', '{glutes,lower_back}', 'dumbbell', 'hinge'),
+  ('Single-Leg Romanian Deadlift', 'weight', '{hamstrings}', '{glutes}', 'dumbbell', 'hinge'),
+  ('Stiff-Leg Deadlift', 'weight', '{hamstrings}', '{glutes,lower_back}', 'barbell', 'hinge'),
+  ('Good Morning', 'weight', '{hamstrings}', '{lower_back,glutes}', 'barbell', 'hinge'),
+  ('Kettlebell Swing', 'weight', '{glutes}', '{hamstrings,lower_back}', 'kettlebell', 'hinge'),
+  ('Kettlebell Deadlift', 'weight', '{glutes}', '{hamstrings}', 'kettlebell', 'hinge'),
+  ('Cable Pull-Through', 'weight', '{glutes}', '{hamstrings}', 'cable', 'hinge'),
+  ('Barbell Hip Thrust', 'weight', '{glutes}', '{hamstrings}', 'barbell', 'hinge'),
+  ('Machine Hip Thrust', 'weight', '{glutes}', '{hamstrings}', 'machine', 'hinge'),
+  ('Single-Leg Hip Thrust', 'bodyweight', '{glutes}', '{hamstrings}', 'bodyweight', 'hinge'),
+  ('Glute Bridge', 'bodyweight', '{glutes}', '{hamstrings}', 'bodyweight', 'hinge'),
+  ('Barbell Glute Bridge', 'weight', '{glutes}', '{hamstrings}', 'barbell', 'hinge'),
+  ('Back Extension', 'bodyweight', '{lower_back}', '{glutes,hamstrings}', 'bodyweight', 'hinge'),
+  ('Weighted Back Extension', 'weight', '{lower_back}', '{glutes,hamstrings}', 'other', 'hinge'),
+  ('Reverse Hyperextension', 'weight', '{glutes}', '{lower_back,hamstrings}', 'machine', 'hinge'),
+  ('Glute-Ham Raise', 'bodyweight', '{hamstrings}', '{glutes,calves}', 'bodyweight', 'hinge'),
+  ('Nordic Hamstring Curl', 'bodyweight', '{hamstrings}', '{}', 'bodyweight', 'isolation'),
+  ('Power Clean', 'weight', '{full_body}', '{traps,glutes,quads}', 'barbell', 'hinge'),
+  ('Hang Clean', 'weight', '{full_body}', '{traps,glutes}', 'barbell', 'hinge'),
+  ('Clean and Jerk', 'weight', '{full_body}', '{quads,front_delts,traps}', 'barbell', 'hinge'),
+  ('Power Snatch', 'weight', '{full_body}', '{traps,glutes,front_delts}', 'barbell', 'hinge'),
+  ('Snatch', 'weight', '{full_body}', '{quads,traps,front_delts}', 'barbell', 'hinge'),
+  ('Hang Snatch', 'weight', '{full_body}', '{traps,glutes}', 'barbell', 'hinge'),
+  ('Clean Pull', 'weight', '{traps}', '{hamstrings,glutes}', 'barbell', 'hinge'),
+  ('Snatch Pull', 'weight', '{traps}', '{hamstrings,glutes}', 'barbell', 'hinge'),
+  ('Dumbbell Snatch', 'weight', '{full_body}', '{glutes,front_delts}', 'dumbbell', 'hinge'),
+  ('Kettlebell Clean', 'weight', '{full_body}', '{glutes,forearms}', 'kettlebell', 'hinge'),
+  ('Kettlebell Snatch', 'weight', '{full_body}', '{glutes,front_delts}', 'kettlebell', 'hinge'),
+  ('Thruster', 'weight', '{quads}', '{front_delts,glutes,triceps}', 'barbell', 'squat'),
+  ('Dumbbell Thruster', 'weight', '{quads}', '{front_delts,glutes}', 'dumbbell', 'squat'),
+  ('Turkish Get-Up', 'weight', '{full_body}', '{front_delts,abs,glutes}', 'kettlebell', 'rotation'),
+  ('Leg Extension', 'weight', '{quads}', '{}', 'machine', 'isolation'),
+  ('Single-Leg Leg Extension', 'weight', '{quads}', '{}', 'machine', 'isolation'),
+  ('Lying Leg Curl', 'weight', '{hamstrings}', '{calves}', 'machine', 'isolation'),
+  ('Seated Leg Curl', 'weight', '{hamstrings}', '{}', 'machine', 'isolation'),
+  ('Standing Leg Curl', 'weight', '{hamstrings}', '{}', 'machine', 'isolation'),
+  ('Hip Adduction Machine', 'weight', '{adductors}', '{}', 'machine', 'isolation'),
+  ('Hip Abduction Machine', 'weight', '{abductors}', '{glutes}', 'machine', 'isolation'),
+  ('Cable Hip Abduction', 'weight', '{abductors}', '{glutes}', 'cable', 'isolation'),
+  ('Cable Kickback', 'weight', '{glutes}', '{hamstrings}', 'cable', 'isolation'),
+  ('Copenhagen Plank', 'timed', '{adductors}', '{obliques}', 'bodyweight', 'isolation'),
+  ('Standing Calf Raise', 'weight', '{calves}', '{}', 'machine', 'isolation'),
+  ('Seated Calf Raise', 'weight', '{calves}', '{}', 'machine', 'isolation'),
+  ('Leg Press Calf Raise', 'weight', '{calves}', '{}', 'machine', 'isolation'),
+  ('Smith Machine Calf Raise', 'weight', '{calves}', '{}', 'smith_machine', 'isolation'),
+  ('Single-Leg Calf Raise', 'bodyweight', '{calves}', '{}', 'bodyweight', 'isolation'),
+  ('Dumbbell Calf Raise', 'weight', '{calves}', '{}', 'dumbbell', 'isolation'),
+  ('Tibialis Raise', 'bodyweight', '{calves}', '{}', 'bodyweight', 'isolation'),
+  ('Plank', 'timed', '{abs}', '{obliques}', 'bodyweight', 'isolation'),
+  ('Weighted Plank', 'timed', '{abs}', '{obliques}', 'other', 'isolation'),
+  ('Side Plank', 'timed', '{obliques}', '{abs}', 'bodyweight', 'isolation'),
+  ('Hollow Body Hold', 'timed', '{abs}', '{}', 'bodyweight', 'isolation'),
+  ('L-Sit', 'timed', '{abs}', '{quads,triceps}', 'bodyweight', 'isolation'),
+  ('Crunch', 'bodyweight', '{abs}', '{}', 'bodyweight', 'isolation'),
+  ('Cable Crunch', 'weight', '{abs}', '{}', 'cable', 'isolation'),
+  ('Machine Crunch', 'weight', '{abs}', '{}', 'machine', 'isolation'),
+  ('Decline Sit-Up', 'bodyweight', '{abs}', '{}', 'bodyweight', 'isolation'),
+  ('Sit-Up', 'bodyweight', '{abs}', '{}', 'bodyweight', 'isolation'),
+  ('Hanging Leg Raise', 'bodyweight', '{abs}', '{forearms}', 'bodyweight', 'isolation'),
+  ('Hanging Knee Raise', 'bodyweight', '{abs}', '{}', 'bodyweight', 'isolation'),
+  ('Captain''s Chair Leg Raise', 'bodyweight', '{abs}', '{}', 'machine', 'isolation'),
+  ('Lying Leg Raise', 'bodyweight', '{abs}', '{}', 'bodyweight', 'isolation'),
+  ('Toes-to-Bar', 'bodyweight', '{abs}', '{lats,forearms}', 'bodyweight', 'isolation'),
+  ('Ab Wheel Rollout', 'bodyweight', '{abs}', '{lats}', 'other', 'isolation'),
+  ('Dead Bug', 'bodyweight', '{abs}', '{}', 'bodyweight', 'isolation'),
+  ('Bird Dog', 'bodyweight', '{lower_back}', '{abs,glutes}', 'bodyweight', 'isolation'),
+  ('Pallof Press', 'weight', '{obliques}', '{abs}', 'cable', 'rotation'),
+  ('Cable Woodchop', 'weight', '{obliques}', '{abs}', 'cable', 'rotation'),
+  ('Russian Twist', 'bodyweight', '{obliques}', '{abs}', 'bodyweight', 'rotation'),
+  ('Landmine Rotation', 'weight', '{obliques}', '{abs,front_delts}', 'other', 'rotation'),
+  ('Dumbbell Side Bend', 'weight', '{obliques}', '{}', 'dumbbell', 'isolation'),
+  ('Mountain Climber', 'bodyweight', '{abs}', '{front_delts}', 'bodyweight', 'locomotion'),
+  ('Neck Curl', 'weight', '{neck}', '{}', 'other', 'isolation'),
+  ('Neck Extension', 'weight', '{neck}', '{}', 'other', 'isolation'),
+  ('Burpee', 'bodyweight', '{full_body}', '{chest,quads}', 'bodyweight', 'locomotion'),
+  ('Box Jump', 'bodyweight', '{quads}', '{glutes,calves}', 'bodyweight', 'squat'),
+  ('Broad Jump', 'bodyweight', '{glutes}', '{quads,calves}', 'bodyweight', 'hinge'),
+  ('Wall Ball', 'weight', '{quads}', '{front_delts,glutes}', 'other', 'squat'),
+  ('Sled Push', 'weight', '{quads}', '{glutes,calves}', 'other', 'locomotion'),
+  ('Sled Pull', 'weight', '{hamstrings}', '{glutes,upper_back}', 'other', 'locomotion'),
+  ('Battle Ropes', 'timed', '{front_delts}', '{abs,forearms}', 'other', 'isolation'),
+  ('Medicine Ball Slam', 'weight', '{abs}', '{lats,front_delts}', 'other', 'hinge'),
+  ('Tire Flip', 'weight', '{full_body}', '{glutes,upper_back}', 'other', 'hinge'),
+  ('Atlas Stone Lift', 'weight', '{full_body}', '{glutes,upper_back,biceps}', 'other', 'hinge'),
+  ('Log Press', 'weight', '{front_delts}', '{triceps,upper_back}', 'other', 'vertical_push'),
+  ('Axle Deadlift', 'weight', '{hamstrings}', '{forearms,glutes}', 'other', 'hinge'),
+  ('Running', 'cardio', '{quads}', '{calves}', 'bodyweight', 'locomotion'),
+  ('Treadmill Running', 'cardio', '{quads}', '{calves}', 'machine', 'locomotion'),
+  ('Walking', 'cardio', '{quads}', '{}', 'bodyweight', 'locomotion'),
+  ('Incline Treadmill Walk', 'cardio', '{glutes}', '{calves}', 'machine', 'locomotion'),
+  ('Hiking', 'cardio', '{quads}', '{glutes}', 'bodyweight', 'locomotion'),
+  ('Cycling', 'cardio', '{quads}', '{}', 'bodyweight', 'locomotion'),
+  ('Stationary Bike', 'cardio', '{quads}', '{}', 'machine', 'locomotion'),
+  ('Assault Bike', 'cardio', '{full_body}', '{}', 'machine', 'locomotion'),
+  ('Spin Bike', 'cardio', '{quads}', '{}', 'machine', 'locomotion'),
+  ('Rowing Machine', 'cardio', '{upper_back}', '{quads}', 'machine', 'locomotion'),
+  ('Ski Erg', 'cardio', '{lats}', '{triceps}', 'machine', 'locomotion'),
+  ('Elliptical', 'cardio', '{quads}', '{}', 'machine', 'locomotion'),
+  ('Stair Climber', 'cardio', '{glutes}', '{quads}', 'machine', 'locomotion'),
+  ('Swimming', 'cardio', '{full_body}', '{}', 'bodyweight', 'locomotion'),
+  ('Jump Rope', 'cardio', '{calves}', '{}', 'other', 'locomotion'),
+  ('Sprints', 'cardio', '{hamstrings}', '{glutes}', 'bodyweight', 'locomotion'),
+  ('Hill Sprints', 'cardio', '{glutes}', '{hamstrings}', 'bodyweight', 'locomotion'),
+  ('Rucking', 'cardio', '{full_body}', '{}', 'bodyweight', 'locomotion'),
+  ('Hamstring Stretch', 'flexibility', '{hamstrings}', '{}', 'bodyweight', NULL),
+  ('Hip Flexor Stretch', 'flexibility', '{quads}', '{}', 'bodyweight', NULL),
+  ('Quad Stretch', 'flexibility', '{quads}', '{}', 'bodyweight', NULL),
+  ('Pigeon Pose', 'flexibility', '{glutes}', '{}', 'bodyweight', NULL),
+  ('Couch Stretch', 'flexibility', '{quads}', '{}', 'bodyweight', NULL),
+  ('Standing Calf Stretch', 'flexibility', '{calves}', '{}', 'bodyweight', NULL),
+  ('Doorway Chest Stretch', 'flexibility', '{chest}', '{}', 'bodyweight', NULL),
+  ('Cross-Body Shoulder Stretch', 'flexibility', '{rear_delts}', '{}', 'bodyweight', NULL),
+  ('Overhead Triceps Stretch', 'flexibility', '{triceps}', '{}', 'bodyweight', NULL),
+  ('Lat Stretch', 'flexibility', '{lats}', '{}', 'bodyweight', NULL),
+  ('Child''s Pose', 'flexibility', '{lower_back}', '{}', 'bodyweight', NULL),
+  ('Cat-Cow', 'flexibility', '{lower_back}', '{}', 'bodyweight', NULL),
+  ('Thoracic Rotation', 'flexibility', '{upper_back}', '{}', 'bodyweight', NULL),
+  ('Butterfly Stretch', 'flexibility', '{adductors}', '{}', 'bodyweight', NULL),
+  ('90/90 Hip Stretch', 'flexibility', '{glutes}', '{}', 'bodyweight', NULL),
+  ('Deep Squat Hold', 'flexibility', '{adductors}', '{}', 'bodyweight', NULL),
+  ('Neck Stretch', 'flexibility', '{neck}', '{}', 'bodyweight', NULL),
+  ('Wrist Stretch', 'flexibility', '{forearms}', '{}', 'bodyweight', NULL)
+ON CONFLICT DO NOTHING;
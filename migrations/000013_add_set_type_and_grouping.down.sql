@@ -0,0 +1,5 @@
+-- Remove set type, group and position from sets table
+DROP INDEX IF EXISTS idx_sets_workout_id_position;
+ALTER TABLE sets DROP COLUMN IF EXISTS position;
+ALTER TABLE sets DROP COLUMN IF EXISTS group_id;
+ALTER TABLE sets DROP COLUMN IF EXISTS set_type;
@@ -0,0 +1,17 @@
+-- Add set type, superset/circuit group and explicit position to sets table
+ALTER TABLE sets
+  ADD COLUMN IF NOT EXISTS set_type VARCHAR(20) NOT NULL DEFAULT 'working'
+    CHECK (set_type IN ('warmup', 'working', 'drop', 'amrap', 'failure')),
+  ADD COLUMN IF NOT EXISTS group_id INTEGER CHECK (group_id >= 1),
+  ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0;
+
+-- Number existing sets of each workout in the order they were logged
+UPDATE sets s
+SET position = ordered.position
+FROM (
+    SELECT id_set, ROW_NUMBER() OVER (PARTITION BY workout_id ORDER BY created_at, id_set) AS position
+    FROM sets
+) ordered
+WHERE s.id_set = ordered.id_set;
+
+CREATE INDEX IF NOT EXISTS idx_sets_workout_id_position ON sets(workout_id, position);
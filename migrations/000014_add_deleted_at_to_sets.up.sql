@@ -0,0 +1,6 @@
+-- Add deleted_at column to sets table for soft delete
+ALTER TABLE sets 
+  ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Create index for filtering non-deleted sets
+CREATE INDEX IF NOT EXISTS idx_sets_deleted_at ON sets(deleted_at) WHERE deleted_at IS NULL;
@@ -0,0 +1,7 @@
+-- Remove session columns from workouts table
+ALTER TABLE workouts DROP COLUMN IF EXISTS notes;
+ALTER TABLE workouts DROP COLUMN IF EXISTS session_rpe;
+ALTER TABLE workouts DROP COLUMN IF EXISTS bodyweight;
+ALTER TABLE workouts DROP COLUMN IF EXISTS finished_at;
+ALTER TABLE workouts DROP COLUMN IF EXISTS started_at;
+ALTER TABLE workouts DROP COLUMN IF EXISTS status;
@@ -0,0 +1,14 @@
+-- Add session state, timing and how the session went to workouts table
+ALTER TABLE workouts
+  ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'planned'
+    CHECK (status IN ('planned', 'in_progress', 'completed', 'abandoned')),
+  ADD COLUMN IF NOT EXISTS started_at TIMESTAMP WITH TIME ZONE,
+  ADD COLUMN IF NOT EXISTS finished_at TIMESTAMP WITH TIME ZONE,
+  ADD COLUMN IF NOT EXISTS bodyweight DECIMAL(10, 2) CHECK (bodyweight > 0),
+  ADD COLUMN IF NOT EXISTS session_rpe INTEGER CHECK (session_rpe >= 1 AND session_rpe <= 10),
+  ADD COLUMN IF NOT EXISTS notes TEXT;
+
+-- Workouts logged before sessions were tracked count as completed
+UPDATE workouts w
+SET status = 'completed'
+WHERE EXISTS (SELECT 1 FROM sets s WHERE s.workout_id = w.id_workout AND s.deleted_at IS NULL);
@@ -0,0 +1,5 @@
+-- Remove performed_at from workouts and sets tables
+DROP INDEX IF EXISTS idx_sets_exercise_id_performed_at;
+DROP INDEX IF EXISTS idx_workouts_user_id_performed_at;
+ALTER TABLE sets DROP COLUMN IF EXISTS performed_at;
+ALTER TABLE workouts DROP COLUMN IF EXISTS performed_at;
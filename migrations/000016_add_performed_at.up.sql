@@ -0,0 +1,18 @@
+-- Add the time a workout or set was performed, which can differ from when it was logged
+ALTER TABLE workouts ADD COLUMN IF NOT EXISTS performed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE sets ADD COLUMN IF NOT EXISTS performed_at TIMESTAMP WITH TIME ZONE;
+
+-- Everything logged so far was logged as it was performed
+UPDATE workouts SET performed_at = COALESCE(started_at, created_at, CURRENT_TIMESTAMP) WHERE performed_at IS NULL;
+UPDATE sets SET performed_at = COALESCE(created_at, CURRENT_TIMESTAMP) WHERE performed_at IS NULL;
+
+ALTER TABLE workouts
+  ALTER COLUMN performed_at SET DEFAULT CURRENT_TIMESTAMP,
+  ALTER COLUMN performed_at SET NOT NULL;
+ALTER TABLE sets
+  ALTER COLUMN performed_at SET DEFAULT CURRENT_TIMESTAMP,
+  ALTER COLUMN performed_at SET NOT NULL;
+
+-- Create indexes for history and date range queries
+CREATE INDEX IF NOT EXISTS idx_workouts_user_id_performed_at ON workouts(user_id, performed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_sets_exercise_id_performed_at ON sets(exercise_id, performed_at DESC);
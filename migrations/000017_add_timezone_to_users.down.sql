@@ -0,0 +1,2 @@
+-- Remove timezone from users table
+ALTER TABLE users DROP COLUMN IF EXISTS timezone;
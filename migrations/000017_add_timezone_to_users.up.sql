@@ -0,0 +1,2 @@
+-- Add the IANA time zone used to bucket a user's progress by day
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
@@ -0,0 +1,28 @@
+-- Create refresh_tokens table: one row per refresh token, rotated on every use. Tokens rotated
+-- from the same login share a family, which is revoked as a whole when a used token comes back.
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id_refresh_token BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id_user) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE, -- Hex SHA-256 of the token; the token itself is never stored
+    family_id UUID NOT NULL,
+    access_jti UUID NOT NULL, -- Access token issued with this refresh token
+    access_expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE, -- Set when the token is rotated
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_access_jti ON refresh_tokens(access_jti);
+
+-- Create revoked_tokens table: access tokens revoked before they expire, checked on every request
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti UUID PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id_user) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL, -- The row can be dropped once the token expires
+    revoked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
@@ -0,0 +1,5 @@
+-- Drop account_tokens table and the verification date of users
+DROP INDEX IF EXISTS idx_account_tokens_expires_at;
+DROP INDEX IF EXISTS idx_account_tokens_user_id;
+DROP TABLE IF EXISTS account_tokens;
+ALTER TABLE users DROP COLUMN IF EXISTS verified_at;
@@ -0,0 +1,18 @@
+-- Record when a user proved they own their email address
+ALTER TABLE users ADD COLUMN IF NOT EXISTS verified_at TIMESTAMP WITH TIME ZONE;
+
+-- Create account_tokens table: single-use tokens sent by email to reset a password or verify an
+-- address. Only the hash of a token is stored.
+CREATE TABLE IF NOT EXISTS account_tokens (
+    id_account_token BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id_user) ON DELETE CASCADE,
+    purpose VARCHAR(32) NOT NULL CHECK (purpose IN ('password_reset', 'email_verification')),
+    token_hash CHAR(64) NOT NULL UNIQUE, -- Hex SHA-256 of the token
+    email VARCHAR(255) NOT NULL, -- Address the token was sent to
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE, -- Set when the token is used or replaced by a newer one
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_tokens_user_id ON account_tokens(user_id, purpose);
+CREATE INDEX IF NOT EXISTS idx_account_tokens_expires_at ON account_tokens(expires_at);
@@ -0,0 +1,3 @@
+-- Remove deletion_scheduled_at from users table
+DROP INDEX IF EXISTS idx_users_deletion_scheduled_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deletion_scheduled_at;
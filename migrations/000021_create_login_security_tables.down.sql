@@ -0,0 +1,6 @@
+-- Drop security_events and login_throttles tables
+DROP INDEX IF EXISTS idx_security_events_created_at;
+DROP INDEX IF EXISTS idx_security_events_user_id;
+DROP TABLE IF EXISTS security_events;
+DROP INDEX IF EXISTS idx_login_throttles_last_failure_at;
+DROP TABLE IF EXISTS login_throttles;
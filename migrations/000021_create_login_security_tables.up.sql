@@ -0,0 +1,24 @@
+-- Create login_throttles table: failed logins per account email and per client IP, with the
+-- lockout they caused. Rows are dropped once the failures are forgotten.
+CREATE TABLE IF NOT EXISTS login_throttles (
+    throttle_key VARCHAR(320) PRIMARY KEY, -- "account:<email>" or "ip:<address>"
+    failures INTEGER NOT NULL DEFAULT 0, -- Consecutive failures within the failure window
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    locked_until TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_throttles_last_failure_at ON login_throttles(last_failure_at);
+
+-- Create security_events table: the audit trail of login attempts
+CREATE TABLE IF NOT EXISTS security_events (
+    id_security_event BIGSERIAL PRIMARY KEY,
+    user_id BIGINT REFERENCES users(id_user) ON DELETE CASCADE, -- Null for unknown emails
+    event_type VARCHAR(32) NOT NULL CHECK (event_type IN ('login_succeeded', 'login_failed', 'login_blocked')),
+    email VARCHAR(255) NOT NULL, -- Email the attempt was made with
+    ip_address VARCHAR(45) NOT NULL,
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_security_events_user_id ON security_events(user_id, created_at DESC, id_security_event DESC);
+CREATE INDEX IF NOT EXISTS idx_security_events_created_at ON security_events(created_at);
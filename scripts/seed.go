@@ -55,41 +55,43 @@ func main() {
 		log.Printf("Created test user: %s", seedEmail)
 	}
 
-	// Create some exercises
+	// Adopt some catalog exercises, aliased to the names the user knows them by
 	exercises := []struct {
-		name string
+		catalogName string
+		alias       *string
 	}{
-		{"Bench Press"},
-		{"Squat"},
-		{"Deadlift"},
-		{"Overhead Press"},
+		{"Barbell Bench Press", strPtr("Bench Press")},
+		{"Back Squat", strPtr("Squat")},
+		{"Deadlift", nil},
+		{"Overhead Press", nil},
 	}
 
 	var exerciseIDs []int64
 	for _, ex := range exercises {
 		var exerciseID int64
 		err = database.DB.QueryRow(`
-			INSERT INTO exercises (user_id, name, created_at)
+			SELECT id_exercise FROM exercises WHERE user_id IS NULL AND name = $1
+		`, ex.catalogName).Scan(&exerciseID)
+		if err != nil {
+			log.Printf("Error finding catalog exercise %s: %v", ex.catalogName, err)
+			continue
+		}
+
+		_, err = database.DB.Exec(`
+			INSERT INTO user_catalog_exercises (user_id, exercise_id, alias)
 			VALUES ($1, $2, $3)
-			ON CONFLICT DO NOTHING
-			RETURNING id_exercise
-		`, userID, ex.name, time.Now()).Scan(&exerciseID)
+			ON CONFLICT (user_id, exercise_id) DO UPDATE SET alias = EXCLUDED.alias
+		`, userID, exerciseID, ex.alias)
 		if err != nil {
-			// Exercise might already exist, try to get its ID
-			err = database.DB.QueryRow(`
-				SELECT id_exercise FROM exercises WHERE user_id = $1 AND name = $2
-			`, userID, ex.name).Scan(&exerciseID)
-			if err != nil {
-				log.Printf("Error creating/getting exercise %s: %v", ex.name, err)
-				continue
-			}
+			log.Printf("Error adopting exercise %s: %v", ex.catalogName, err)
+			continue
 		}
 		exerciseIDs = append(exerciseIDs, exerciseID)
-		log.Printf("Created/found exercise: %s (ID: %d)", ex.name, exerciseID)
+		log.Printf("Adopted catalog exercise: %s (ID: %d)", ex.catalogName, exerciseID)
 	}
 
 	if len(exerciseIDs) == 0 {
-		log.Fatalf("No exercises created. Cannot create workout.")
+		log.Fatalf("No exercises adopted. Cannot create workout.")
 	}
 
 	// Create a workout
@@ -119,10 +121,11 @@ func main() {
 
 	for i, s := range sets {
 		restSeconds := 120
+		performedAt := time.Now().Add(time.Duration(i) * time.Minute)
 		_, err = database.DB.Exec(`
-			INSERT INTO sets (workout_id, exercise_id, weight, reps, rest_seconds, rpe, created_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7)
-		`, workoutID, benchPressID, s.weight, s.reps, restSeconds, s.rpe, time.Now().Add(time.Duration(i)*time.Minute))
+			INSERT INTO sets (workout_id, exercise_id, weight, reps, rest_seconds, rpe, position, performed_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		`, workoutID, benchPressID, s.weight, s.reps, restSeconds, s.rpe, i+1, performedAt)
 		if err != nil {
 			log.Printf("Error creating set: %v", err)
 		} else {
@@ -137,6 +140,10 @@ func intPtr(i int) *int {
 	return &i
 }
 
+func strPtr(s string) *string {
+	return &s
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value